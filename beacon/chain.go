@@ -17,6 +17,10 @@ type chainStore struct {
 	Store
 	l             log.Logger
 	client        net.ProtocolClient
+	beaconID      string
+	syncPeers     int
+	peers         *peerScores
+	progress      *syncProgress
 	safe          *cryptoSafe
 	ticker        *ticker
 	done          chan bool
@@ -25,12 +29,17 @@ type chainStore struct {
 	lastInserted  chan *Beacon
 	requestSync   chan likeBeacon
 	nonSyncBeacon chan *Beacon
+	flush         chan bool
 }
 
-func newChainStore(l log.Logger, client net.ProtocolClient, safe *cryptoSafe, s Store, ticker *ticker) *chainStore {
+func newChainStore(l log.Logger, client net.ProtocolClient, beaconID string, safe *cryptoSafe, s Store, ticker *ticker, syncPeers int, peers *peerScores) *chainStore {
 	chain := &chainStore{
 		l:             l,
 		client:        client,
+		beaconID:      beaconID,
+		syncPeers:     syncPeers,
+		peers:         peers,
+		progress:      &syncProgress{clock: ticker.clock},
 		safe:          safe,
 		Store:         s,
 		done:          make(chan bool, 1),
@@ -40,6 +49,7 @@ func newChainStore(l log.Logger, client net.ProtocolClient, safe *cryptoSafe, s
 		requestSync:   make(chan likeBeacon, 10),
 		lastInserted:  make(chan *Beacon, 1),
 		nonSyncBeacon: make(chan *Beacon, 1),
+		flush:         make(chan bool, 1),
 	}
 	// TODO maybe look if it's worth having multiple workers there
 	go chain.runChainLoop()
@@ -47,10 +57,23 @@ func newChainStore(l log.Logger, client net.ProtocolClient, safe *cryptoSafe, s
 	return chain
 }
 
-func (c *chainStore) NewValidPartial(addr string, p *drand.PartialBeaconPacket) {
+// NewValidPartial passes to the aggregator a partial verified with the given
+// group info
+func (c *chainStore) NewValidPartial(addr string, p *drand.PartialBeaconPacket, info *cryptoInfo) {
 	c.newPartials <- partialInfo{
 		addr: addr,
 		p:    p,
+		info: info,
+	}
+}
+
+// flushPartials drops all the partial signatures not yet aggregated. It is
+// used when the group info changes for the upcoming rounds, since the partials
+// received so far may not be valid anymore.
+func (c *chainStore) flushPartials() {
+	select {
+	case c.flush <- true:
+	default:
 	}
 }
 
@@ -90,6 +113,9 @@ func (c *chainStore) runAggregator() {
 			}
 			caches = newCaches
 			break
+		case <-c.flush:
+			c.l.Debug("aggregator", "flush_partials", "caches", len(caches))
+			caches = nil
 		case partial := <-c.newPartials:
 			// look if we have info for this round first
 			pRound := partial.p.GetRound()
@@ -98,6 +124,12 @@ func (c *chainStore) runAggregator() {
 				c.l.Error("no_info_for", partial.p.GetRound())
 				break
 			}
+			if ginfo != partial.info {
+				// the group info changed since the partial was verified,
+				// e.g. the transition to a new group was cancelled
+				c.l.Debug("stale_partial", pRound, "from", partial.addr)
+				break
+			}
 
 			// look if we are already have a cache for this round
 			var cache *roundCache
@@ -226,11 +258,62 @@ func (c *chainStore) shouldSync(last *Beacon, newB likeBeacon) bool {
 }
 
 // RunSync is a blocking call that tries to sync chain to the highest height
-// found
+// found. When the node misses more than one segment of the chain, it syncs
+// from several peers at once if configured so.
 func (c *chainStore) RunSync(ctx context.Context) {
 	l, _ := c.Store.Last()
 	currRound := c.ticker.CurrentRound()
-	outCh, err := syncChain(ctx, c.l, c.safe, l, currRound, c.client)
+	c.progress.begin(l.Round, currRound)
+	defer c.progress.end()
+	c.runSync(ctx, l, currRound)
+}
+
+// Sync starts a sync of the chain up to the given round in the background, or
+// up to the current round if upTo is zero or later than the current round. It
+// returns ErrSyncRunning if the node is already syncing its chain.
+func (c *chainStore) Sync(upTo uint64) error {
+	l, err := c.Store.Last()
+	if err != nil {
+		return err
+	}
+	target := c.ticker.CurrentRound()
+	if upTo != 0 && upTo < target {
+		target = upTo
+	}
+	if target <= l.Round {
+		return fmt.Errorf("beacon: chain already at round %d", l.Round)
+	}
+	if !c.progress.beginAlone(l.Round, target) {
+		return ErrSyncRunning
+	}
+	go func() {
+		defer c.progress.end()
+		c.runSync(context.Background(), l, target)
+	}()
+	return nil
+}
+
+// SyncStatus returns the progress of the sync of the chain
+func (c *chainStore) SyncStatus() SyncStatus {
+	var current uint64
+	if l, err := c.Store.Last(); err == nil {
+		current = l.Round
+	}
+	st := c.progress.status(current)
+	if st.Syncing {
+		st.Peers = c.peers.syncing()
+	}
+	return st
+}
+
+func (c *chainStore) runSync(ctx context.Context, l *Beacon, toRound uint64) {
+	var outCh chan *Beacon
+	var err error
+	if c.syncPeers > 1 && toRound > l.Round+SyncSegmentSize {
+		outCh, err = syncChainParallel(ctx, c.l, c.safe, c.peers, l, toRound, c.client, c.beaconID, c.syncPeers)
+	} else {
+		outCh, err = syncChain(ctx, c.l, c.safe, c.peers, l, toRound, c.client, c.beaconID)
+	}
 	if err != nil {
 		c.l.Error("error_sync", err)
 		return
@@ -238,7 +321,6 @@ func (c *chainStore) RunSync(ctx context.Context) {
 	for newB := range outCh {
 		c.newBeaconCh <- newB
 	}
-	return
 }
 
 func (c *chainStore) AppendedBeaconNoSync() chan *Beacon {
@@ -248,6 +330,8 @@ func (c *chainStore) AppendedBeaconNoSync() chan *Beacon {
 type partialInfo struct {
 	addr string
 	p    *drand.PartialBeaconPacket
+	// info is the group info the partial was verified with
+	info *cryptoInfo
 }
 
 type beaconInfo struct {
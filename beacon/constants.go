@@ -9,3 +9,27 @@ var CheckSyncPeriod = 5 * time.Minute
 // Once a connection is made, we should not wait too much to receive new beacons
 // from one peer
 var MaxSyncWaitTime = 2 * time.Second
+
+// DefaultSyncMaxCount is the maximum number of beacons a node sends for a
+// single sync request, unless configured otherwise. The requesting node asks
+// the following beacons in another request.
+var DefaultSyncMaxCount uint64 = 10000
+
+// DefaultSyncMaxPerPeer is the maximum number of sync requests a node serves
+// at once to a single peer, unless configured otherwise
+var DefaultSyncMaxPerPeer = 4
+
+// MaxSignerWaitTime is the time a node waits for a partial signature from its
+// remote signer
+var MaxSignerWaitTime = 2 * time.Second
+
+// PeerBackoffFailures is the number of failed requests in a row after which a
+// node tries a peer last when syncing, for a while
+var PeerBackoffFailures = 3
+
+// PeerBackoffMin is the time a node tries a failing peer last, doubled at each
+// new failure up to PeerBackoffMax
+var PeerBackoffMin = 5 * time.Second
+
+// PeerBackoffMax is the maximum time a node tries a failing peer last
+var PeerBackoffMax = 5 * time.Minute
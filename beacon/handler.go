@@ -33,6 +33,25 @@ type Config struct {
 	Clock          clock.Clock
 	WaitBeforeSend time.Duration
 	Callback       func(*Beacon)
+	// History holds the previous groups of the chain, to verify the rounds
+	// they produced
+	History []*key.HistoryGroup
+	// Signer, if set, produces the partial signatures for the group instead
+	// of the Share, which can then be nil
+	Signer Signer
+	// BeaconID identifies the chain among the chains run by the node, empty
+	// for the default one
+	BeaconID string
+	// SyncPeers is the number of peers the node syncs from at once when it
+	// is far behind the chain. With less than two, it syncs from one peer at
+	// a time.
+	SyncPeers int
+	// SyncMaxCount is the maximum number of beacons sent for a single sync
+	// request, DefaultSyncMaxCount if zero
+	SyncMaxCount uint64
+	// SyncMaxPerPeer is the maximum number of sync requests served at once
+	// to a single peer, DefaultSyncMaxPerPeer if zero
+	SyncMaxPerPeer int
 }
 
 // Handler holds the logic to initiate, and react to the TBLS protocol. Each time
@@ -54,12 +73,16 @@ type Handler struct {
 	stopped   bool
 	l         log.Logger
 	callbacks *CallbackStore
+	// number of sync requests being served to each peer
+	syncs    map[string]int
+	syncLock sync.Mutex
+	peers    *peerScores
 }
 
 // NewHandler returns a fresh handler ready to serve and create randomness
 // beacon
 func NewHandler(c net.ProtocolClient, s Store, conf *Config, l log.Logger) (*Handler, error) {
-	if conf.Private == nil || conf.Share == nil || conf.Group == nil {
+	if conf.Private == nil || (conf.Share == nil && conf.Signer == nil) || conf.Group == nil {
 		return nil, errors.New("beacon: invalid configuration")
 	}
 	idx, exists := conf.Group.Index(conf.Private.Public)
@@ -70,7 +93,17 @@ func NewHandler(c net.ProtocolClient, s Store, conf *Config, l log.Logger) (*Han
 	// XXX change logging because of resharing
 	logger := l.With("index", idx)
 	safe := newCryptoSafe()
+	currentStart := StartRound(conf.Group)
+	for _, h := range conf.History {
+		if h.StartAt == currentStart || h.Group.PublicKey == nil {
+			continue
+		}
+		safe.addInfo(nil, conf.Private.Public, h.Group, h.StartAt)
+	}
 	safe.SetInfo(conf.Share, conf.Private.Public, conf.Group)
+	if conf.Signer != nil {
+		safe.SetSigner(conf.Signer, currentStart)
+	}
 	// genesis block at round 0, next block at round 1
 	// THIS is to change when one network wants to build on top of another
 	// network's chain. Note that if present it overwrites.
@@ -81,7 +114,8 @@ func NewHandler(c net.ProtocolClient, s Store, conf *Config, l log.Logger) (*Han
 	s.Put(b)
 	ticker := newTicker(conf.Clock, conf.Group.Period, conf.Group.GenesisTime)
 	callbacks := NewCallbackStore(s)
-	chain := newChainStore(logger, c, safe, callbacks, ticker)
+	peers := newPeerScores(conf.Clock)
+	chain := newChainStore(logger, c, conf.BeaconID, safe, callbacks, ticker, conf.SyncPeers, peers)
 	handler := &Handler{
 		conf:      conf,
 		client:    c,
@@ -92,6 +126,8 @@ func NewHandler(c net.ProtocolClient, s Store, conf *Config, l log.Logger) (*Han
 		close:     make(chan bool),
 		l:         logger,
 		callbacks: callbacks,
+		syncs:     make(map[string]int),
+		peers:     peers,
 	}
 	return handler, nil
 }
@@ -105,6 +141,15 @@ func (h *Handler) ProcessPartialBeacon(c context.Context, p *proto.PartialBeacon
 	addr := peer.Addr.String()
 	h.l.Debug("received", "request", "from", addr, "round", p.GetRound(), "prevround", p.GetPreviousRound())
 
+	// nodes older than the versioning of the protocol still take part in the
+	// beacon, so a running network can be upgraded node by node
+	if p.GetMetadata() != nil {
+		if err := net.CheckMetadata(p.GetMetadata()); err != nil {
+			h.l.Error("process_partial", addr, "incompatible_node", err)
+			return nil, fmt.Errorf("incompatible node: %v", err)
+		}
+	}
+
 	nextRound, _ := NextRound(h.conf.Clock.Now().Unix(), h.conf.Group.Period, h.conf.Group.GenesisTime)
 	currentRound := nextRound - 1
 
@@ -137,7 +182,11 @@ func (h *Handler) ProcessPartialBeacon(c context.Context, p *proto.PartialBeacon
 		h.l.Error("process_partial", addr, "same_index", "got", idx, "our", info.idx, "inadvance_packet?")
 		return new(proto.Empty), nil
 	}
-	h.chain.NewValidPartial(peer.Addr.String(), p)
+	if idx < info.group.Len() {
+		// the sender of the partial has the previous round
+		h.peers.seen(info.group.Public(idx).Address(), p.GetPreviousRound())
+	}
+	h.chain.NewValidPartial(peer.Addr.String(), p, info)
 	return new(proto.Empty), nil
 }
 
@@ -146,6 +195,25 @@ func (h *Handler) Store() Store {
 	return h.chain
 }
 
+// Peers returns what the node knows of the behavior of its peers, sorted by
+// address
+func (h *Handler) Peers() []PeerStats {
+	return h.peers.stats()
+}
+
+// SyncStatus returns the progress of the sync of the chain the node runs, if
+// any
+func (h *Handler) SyncStatus() SyncStatus {
+	return h.chain.SyncStatus()
+}
+
+// Sync starts syncing the chain from the peers up to the given round, or up to
+// the current round if upTo is zero. It returns ErrSyncRunning if the node
+// syncs its chain already.
+func (h *Handler) Sync(upTo uint64) error {
+	return h.chain.Sync(upTo)
+}
+
 // Start runs the beacon protocol (threshold BLS signature). The first round
 // will sign the message returned by the config.FirstRound() function. If the
 // genesis time specified in the group is already passed, Start returns an
@@ -201,7 +269,9 @@ func (h *Handler) Transition(prevGroup *key.Group) error {
 	return nil
 }
 
-func (h *Handler) TransitionNewGroup(newShare *key.Share, newGroup *key.Group) {
+// TransitionNewGroup makes the handler sign with the given share, as the given
+// identity, for the rounds of the new group.
+func (h *Handler) TransitionNewGroup(newShare *key.Share, newID *key.Identity, newGroup *key.Group) {
 	targetTime := newGroup.TransitionTime
 	tRound, tTime := NextRound(targetTime, h.conf.Group.Period, h.conf.Group.GenesisTime)
 	h.l.Debug("transition", "new_group", "at_round", tRound)
@@ -213,7 +283,16 @@ func (h *Handler) TransitionNewGroup(newShare *key.Share, newGroup *key.Group) {
 	if tTime != targetTime {
 		h.l.Fatal("transition_time", "invalid_offset", "expected_time", tTime, "got_time", targetTime)
 	}
-	h.safe.SetInfo(newShare, h.conf.Private.Public, newGroup)
+	h.safe.SetInfo(newShare, newID, newGroup)
+}
+
+// CancelTransition reverts a previous call to TransitionNewGroup with the
+// given group: the handler keeps signing with its current share after the
+// transition time.
+func (h *Handler) CancelTransition(newGroup *key.Group) {
+	h.safe.RemoveInfo(newGroup)
+	h.chain.flushPartials()
+	h.l.Info("transition", "cancelled", "transition_time", newGroup.TransitionTime)
 }
 
 // run will wait until it is supposed to start
@@ -281,14 +360,16 @@ func (h *Handler) broadcastNextPartial(beacon *Beacon) {
 		h.l.Error("no_info", currentRound, "BUG")
 		return
 	}
-	if info.share == nil {
+	if info.signer == nil {
 		h.l.Error("no_share", currentRound, "BUG", h.safe.String(), "not_synced_yet?")
 		return
 	}
 	msg := Message(currentRound, last.Signature)
-	currSig, err := key.Scheme.Sign(info.share.PrivateShare(), msg)
+	// a remote signer may be unreachable for a while, the node then misses
+	// the round
+	currSig, err := info.signer.SignPartial(msg)
 	if err != nil {
-		h.l.Fatal("beacon_round", fmt.Sprintf("creating signature: %s", err), "round", currentRound)
+		h.l.Error("beacon_round", fmt.Sprintf("creating signature: %s", err), "round", currentRound)
 		return
 	}
 	shortPub := info.pub.Eval(1).V.String()[14:19]
@@ -298,22 +379,29 @@ func (h *Handler) broadcastNextPartial(beacon *Beacon) {
 		PreviousRound: last.Round,
 		PreviousSig:   last.Signature,
 		PartialSig:    currSig,
+		Metadata:      net.NewMetadata(h.conf.BeaconID),
 	}
-	h.chain.NewValidPartial(h.addr, packet)
+	h.chain.NewValidPartial(h.addr, packet, info)
 	for _, id := range info.group.Nodes {
 		if info.id.Address() == id.Address() {
 			continue
 		}
 		go func(i *key.Identity) {
 			h.l.Debug("beacon_round", currentRound, "send_to", i.Address())
+			start := time.Now()
 			err := h.client.PartialBeacon(ctx, i, packet)
 			if err != nil {
 				h.l.Error("beacon_round", currentRound, "err_request", err, "from", i.Address())
+				// a peer replying out of round is reachable, it is only
+				// behind or ahead of this node
 				if strings.Contains(err.Error(), errOutOfRound) {
 					h.l.Error("beacon_round", currentRound, "node", i.Addr, "reply", "out-of-round")
+					return
 				}
+				h.peers.failure(i.Address())
 				return
 			}
+			h.peers.success(i.Address(), time.Since(start))
 		}(id)
 	}
 }
@@ -374,6 +462,7 @@ func shuffleNodes(nodes []*key.Identity) []*key.Identity {
 type cryptoInfo struct {
 	group   *key.Group
 	share   *key.Share
+	signer  Signer
 	pub     *share.PubPoly
 	idx     int
 	startAt uint64
@@ -390,6 +479,11 @@ func newCryptoSafe() *cryptoSafe {
 }
 
 func (c *cryptoSafe) SetInfo(share *key.Share, id *key.Identity, group *key.Group) {
+	c.addInfo(share, id, group, StartRound(group))
+}
+
+// addInfo sets the info of the given group, starting at the given round
+func (c *cryptoSafe) addInfo(share *key.Share, id *key.Identity, group *key.Group, startAt uint64) {
 	c.Lock()
 	defer c.Unlock()
 	info := new(cryptoInfo)
@@ -399,20 +493,43 @@ func (c *cryptoSafe) SetInfo(share *key.Share, id *key.Identity, group *key.Grou
 	if share != nil {
 		info.idx = share.Share.I
 		info.share = share
+		info.signer = NewShareSigner(share)
 	}
-	if group.TransitionTime != 0 {
-		time := group.TransitionTime
-		nRound, _ := NextRound(time, group.Period, group.GenesisTime)
-		info.startAt = nRound - 1
-	} else {
-		// group started at genesis time
-		info.startAt = 0
-	}
+	info.startAt = startAt
 	c.infos = append(c.infos, info)
 	// we sort reverse order so highest round are first
 	sort.Slice(c.infos, func(i, j int) bool { return c.infos[i].startAt > c.infos[j].startAt })
 }
 
+// SetSigner makes the node sign with the given signer for the group starting
+// at the given round
+func (c *cryptoSafe) SetSigner(signer Signer, startAt uint64) {
+	c.Lock()
+	defer c.Unlock()
+	for _, info := range c.infos {
+		if info.startAt == startAt {
+			info.signer = signer
+			info.idx = signer.Index()
+		}
+	}
+}
+
+// RemoveInfo removes the info set for the given group, starting at its
+// transition time.
+func (c *cryptoSafe) RemoveInfo(group *key.Group) {
+	c.Lock()
+	defer c.Unlock()
+	startAt := StartRound(group)
+	infos := c.infos[:0]
+	for _, info := range c.infos {
+		if info.startAt == startAt && info.group.Equal(group) {
+			continue
+		}
+		infos = append(infos, info)
+	}
+	c.infos = infos
+}
+
 func (c *cryptoSafe) GetInfo(round uint64) (*cryptoInfo, error) {
 	c.Lock()
 	defer c.Unlock()
@@ -430,7 +547,17 @@ func (c *cryptoSafe) String() string {
 	defer c.Unlock()
 	var out string
 	for _, info := range c.infos {
-		out += fmt.Sprintf(" {startAt: %d, sharenil? %v} ", info.startAt, info.share == nil)
+		out += fmt.Sprintf(" {startAt: %d, signernil? %v} ", info.startAt, info.signer == nil)
 	}
 	return out
 }
+
+// StartRound returns the first round the given group produces
+func StartRound(group *key.Group) uint64 {
+	if group.TransitionTime == 0 {
+		// group started at genesis time
+		return 0
+	}
+	nRound, _ := NextRound(group.TransitionTime, group.Period, group.GenesisTime)
+	return nRound - 1
+}
@@ -540,3 +540,37 @@ func (b *BeaconTest) CallbackFor(i int, fn func(*Beacon)) {
 	j := b.searchNode(i)
 	b.nodes[j].handler.callbacks.AddCallback(fn)
 }
+
+func TestCryptoSafeRemoveInfo(t *testing.T) {
+	n := 4
+	thr := 3
+	period := 2 * time.Second
+	shares, commits := dkgShares(n, thr)
+	privs, group := test.BatchIdentities(n)
+	group.Threshold = thr
+	group.Period = period
+	group.GenesisTime = 100
+	group.PublicKey = &key.DistPublic{Coefficients: commits}
+
+	newShares, newCommits := dkgShares(n, thr)
+	newGroup := key.NewGroup(group.Nodes, thr, group.GenesisTime)
+	newGroup.Period = period
+	newGroup.TransitionTime = group.GenesisTime + 10*int64(period.Seconds())
+	newGroup.PublicKey = &key.DistPublic{Coefficients: newCommits}
+
+	safe := newCryptoSafe()
+	safe.SetInfo(shares[0], privs[0].Public, group)
+	safe.SetInfo(newShares[0], privs[0].Public, newGroup)
+	transitionRound := StartRound(newGroup)
+	info, err := safe.GetInfo(transitionRound)
+	require.NoError(t, err)
+	require.Equal(t, newShares[0], info.share)
+
+	safe.RemoveInfo(newGroup)
+	info, err = safe.GetInfo(transitionRound)
+	require.NoError(t, err)
+	require.Equal(t, shares[0], info.share)
+	info, err = safe.GetInfo(transitionRound - 1)
+	require.NoError(t, err)
+	require.Equal(t, shares[0], info.share)
+}
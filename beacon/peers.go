@@ -0,0 +1,203 @@
+package beacon
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/drand/drand/key"
+	clock "github.com/jonboulle/clockwork"
+)
+
+// PeerStats is what a node knows of the behavior of one of its peers, from the
+// partial beacons it sends to it and the syncs it runs with it.
+type PeerStats struct {
+	Address string
+	// Latency is the moving average of the duration of the successful
+	// requests to the peer
+	Latency   time.Duration
+	Successes uint64
+	Failures  uint64
+	// LastRound is the last round the peer is known to have, from its partial
+	// beacons or the beacons it sent during a sync
+	LastRound uint64
+	// BackoffUntil is the time until which the node tries the peer last when
+	// syncing, after repeated failures, zero if it doesn't back off
+	BackoffUntil time.Time
+}
+
+// ErrorRate returns the fraction of the requests to the peer that failed
+func (p *PeerStats) ErrorRate() float64 {
+	total := p.Successes + p.Failures
+	if total == 0 {
+		return 0
+	}
+	return float64(p.Failures) / float64(total)
+}
+
+// cost is the expected duration of a request to the peer, counting a failed
+// request as a stalled sync
+func (p *PeerStats) cost() time.Duration {
+	return p.Latency + time.Duration(p.ErrorRate()*float64(MaxSyncWaitTime))
+}
+
+// latencyWeight is the weight of a new latency in the moving average
+const latencyWeight = 0.2
+
+type peerStat struct {
+	PeerStats
+	// number of failures since the last success
+	consecutive int
+}
+
+// peerScores tracks the behavior of the peers of a node, to order the peers to
+// sync from, the failing ones last. The partial beacons don't depend on the
+// scores, they are sent to every peer.
+type peerScores struct {
+	sync.Mutex
+	clock clock.Clock
+	peers map[string]*peerStat
+	// number of sync requests in progress to each peer
+	inUse map[string]int
+}
+
+func newPeerScores(c clock.Clock) *peerScores {
+	return &peerScores{
+		clock: c,
+		peers: make(map[string]*peerStat),
+		inUse: make(map[string]int),
+	}
+}
+
+func (p *peerScores) get(addr string) *peerStat {
+	s, ok := p.peers[addr]
+	if !ok {
+		s = &peerStat{PeerStats: PeerStats{Address: addr}}
+		p.peers[addr] = s
+	}
+	return s
+}
+
+// success records a request to the peer that succeeded in the given time
+func (p *peerScores) success(addr string, latency time.Duration) {
+	p.Lock()
+	defer p.Unlock()
+	s := p.get(addr)
+	if s.Successes == 0 {
+		s.Latency = latency
+	} else {
+		s.Latency += time.Duration(latencyWeight * float64(latency-s.Latency))
+	}
+	s.Successes++
+	s.consecutive = 0
+	s.BackoffUntil = time.Time{}
+}
+
+// failure records a failed request to the peer. After PeerBackoffFailures
+// failures in a row, the node backs off the peer for a time doubling at each
+// new failure, up to PeerBackoffMax.
+func (p *peerScores) failure(addr string) {
+	p.Lock()
+	defer p.Unlock()
+	s := p.get(addr)
+	s.Failures++
+	s.consecutive++
+	if s.consecutive < PeerBackoffFailures {
+		return
+	}
+	backoff := PeerBackoffMin
+	for i := PeerBackoffFailures; i < s.consecutive && backoff < PeerBackoffMax; i++ {
+		backoff *= 2
+	}
+	if backoff > PeerBackoffMax {
+		backoff = PeerBackoffMax
+	}
+	s.BackoffUntil = p.clock.Now().Add(backoff)
+}
+
+// seen records that the peer has the given round
+func (p *peerScores) seen(addr string, round uint64) {
+	p.Lock()
+	defer p.Unlock()
+	s := p.get(addr)
+	if round > s.LastRound {
+		s.LastRound = round
+	}
+}
+
+// backingOff returns true if the node tries the peer last at the given time
+func (s *peerStat) backingOff(now time.Time) bool {
+	return now.Before(s.BackoffUntil)
+}
+
+// use records that the node sends a sync request to the peer, until the
+// returned function is called
+func (p *peerScores) use(addr string) func() {
+	p.Lock()
+	defer p.Unlock()
+	p.inUse[addr]++
+	return func() {
+		p.Lock()
+		defer p.Unlock()
+		if p.inUse[addr]--; p.inUse[addr] <= 0 {
+			delete(p.inUse, addr)
+		}
+	}
+}
+
+// syncing returns the sorted addresses of the peers the node syncs from
+func (p *peerScores) syncing() []string {
+	p.Lock()
+	defer p.Unlock()
+	addrs := make([]string, 0, len(p.inUse))
+	for addr := range p.inUse {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	return addrs
+}
+
+// order returns the nodes ordered from the best peer to sync from the given
+// round to the worst: the peers the node backs off come last, preceded by the
+// peers known to be behind the round, and the others are ordered by the
+// expected duration of a request. Peers with the same score are shuffled.
+func (p *peerScores) order(nodes []*key.Identity, round uint64) []*key.Identity {
+	ids := shuffleNodes(nodes)
+	p.Lock()
+	defer p.Unlock()
+	now := p.clock.Now()
+	rank := func(id *key.Identity) (int, time.Duration) {
+		s, ok := p.peers[id.Address()]
+		switch {
+		case !ok:
+			return 0, 0
+		case s.backingOff(now):
+			return 2, s.cost()
+		case s.LastRound != 0 && s.LastRound <= round:
+			return 1, s.cost()
+		default:
+			return 0, s.cost()
+		}
+	}
+	sort.SliceStable(ids, func(i, j int) bool {
+		ri, ci := rank(ids[i])
+		rj, cj := rank(ids[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return ci < cj
+	})
+	return ids
+}
+
+// stats returns the stats of all the peers, sorted by address
+func (p *peerScores) stats() []PeerStats {
+	p.Lock()
+	defer p.Unlock()
+	stats := make([]PeerStats, 0, len(p.peers))
+	for _, s := range p.peers {
+		stats = append(stats, s.PeerStats)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Address < stats[j].Address })
+	return stats
+}
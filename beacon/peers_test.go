@@ -0,0 +1,62 @@
+package beacon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/drand/drand/test"
+	clock "github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerScores(t *testing.T) {
+	c := clock.NewFakeClock()
+	peers := newPeerScores(c)
+	_, group := test.BatchIdentities(4)
+	ids := group.Nodes
+	fast, slow, failing, behind := ids[0].Address(), ids[1].Address(), ids[2].Address(), ids[3].Address()
+
+	peers.success(fast, 10*time.Millisecond)
+	peers.success(slow, 500*time.Millisecond)
+	peers.success(behind, 10*time.Millisecond)
+	peers.seen(behind, 5)
+	peers.seen(behind, 3)
+	for i := 0; i < PeerBackoffFailures-1; i++ {
+		peers.failure(failing)
+		require.False(t, peers.get(failing).backingOff(c.Now()))
+	}
+	peers.failure(failing)
+	require.True(t, peers.get(failing).backingOff(c.Now()))
+
+	order := peers.order(ids, 10)
+	require.Equal(t, []string{fast, slow, behind, failing}, []string{
+		order[0].Address(), order[1].Address(), order[2].Address(), order[3].Address(),
+	})
+
+	// the backoff doubles at each new failure, until a success
+	c.Advance(PeerBackoffMin)
+	require.False(t, peers.get(failing).backingOff(c.Now()))
+	peers.failure(failing)
+	c.Advance(PeerBackoffMin)
+	require.True(t, peers.get(failing).backingOff(c.Now()))
+	c.Advance(PeerBackoffMin)
+	require.False(t, peers.get(failing).backingOff(c.Now()))
+	peers.failure(failing)
+	peers.success(failing, time.Millisecond)
+	require.False(t, peers.get(failing).backingOff(c.Now()))
+
+	stats := peers.stats()
+	require.Len(t, stats, 4)
+	for _, s := range stats {
+		switch s.Address {
+		case failing:
+			require.Equal(t, uint64(1), s.Successes)
+			require.Equal(t, uint64(PeerBackoffFailures+2), s.Failures)
+			require.InDelta(t, float64(PeerBackoffFailures+2)/float64(PeerBackoffFailures+3), s.ErrorRate(), 1e-9)
+		case behind:
+			require.Equal(t, uint64(5), s.LastRound)
+		case slow:
+			require.Equal(t, 500*time.Millisecond, s.Latency)
+		}
+	}
+}
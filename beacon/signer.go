@@ -0,0 +1,98 @@
+package beacon
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/drand/drand/key"
+	proto "github.com/drand/drand/protobuf/drand"
+)
+
+// Signer produces the partial signatures of the node over the beacons, with
+// its share of the distributed key. The share can be held in memory, or by a
+// remote signer process so the key material stays out of the daemon.
+type Signer interface {
+	// Index returns the index of the share in the group
+	Index() int
+	// SignPartial returns the partial signature over the given message
+	SignPartial(msg []byte) ([]byte, error)
+}
+
+// NewShareSigner returns a Signer using the given share
+func NewShareSigner(s *key.Share) Signer {
+	return &shareSigner{s}
+}
+
+type shareSigner struct {
+	share *key.Share
+}
+
+func (s *shareSigner) Index() int {
+	return s.share.Share.I
+}
+
+func (s *shareSigner) SignPartial(msg []byte) ([]byte, error) {
+	return key.Scheme.Sign(s.share.PrivateShare(), msg)
+}
+
+// NewRemoteSigner returns a Signer asking the partial signatures to a signer
+// process. It checks that the signer holds a share of the given distributed
+// key by verifying a first partial signature, which also gives the index of
+// the share.
+func NewRemoteSigner(c proto.SignerClient, pub *key.DistPublic) (Signer, error) {
+	r := &remoteSigner{client: c}
+	probe := make([]byte, 32)
+	if _, err := rand.Read(probe); err != nil {
+		return nil, err
+	}
+	partial, err := r.SignPartial(probe)
+	if err != nil {
+		return nil, fmt.Errorf("beacon: remote signer unreachable: %v", err)
+	}
+	if err := key.Scheme.VerifyPartial(pub.PubPoly(), probe, partial); err != nil {
+		return nil, errors.New("beacon: remote signer holds a share of another distributed key")
+	}
+	if r.index, err = key.Scheme.IndexOf(partial); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+type remoteSigner struct {
+	client proto.SignerClient
+	index  int
+}
+
+func (r *remoteSigner) Index() int {
+	return r.index
+}
+
+func (r *remoteSigner) SignPartial(msg []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), MaxSignerWaitTime)
+	defer cancel()
+	resp, err := r.client.SignPartial(ctx, &proto.SignPartialRequest{Msg: msg})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPartialSig(), nil
+}
+
+// NewSignerServer returns the service a signer process runs to give the
+// partial signatures of the given signer to its node.
+func NewSignerServer(s Signer) proto.SignerServer {
+	return &signerServer{s}
+}
+
+type signerServer struct {
+	signer Signer
+}
+
+func (s *signerServer) SignPartial(c context.Context, in *proto.SignPartialRequest) (*proto.SignPartialResponse, error) {
+	partial, err := s.signer.SignPartial(in.GetMsg())
+	if err != nil {
+		return nil, err
+	}
+	return &proto.SignPartialResponse{PartialSig: partial}, nil
+}
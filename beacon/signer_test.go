@@ -0,0 +1,54 @@
+package beacon
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/drand/drand/key"
+	"github.com/drand/drand/net"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteSigner(t *testing.T) {
+	n, thr := 4, 3
+	shares, commits := dkgShares(n, thr)
+	dir, err := ioutil.TempDir("", "drand-signer")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socket := path.Join(dir, "signer.sock")
+
+	listener, err := net.NewUnixSignerListener(NewSignerServer(NewShareSigner(shares[2])), socket)
+	require.NoError(t, err)
+	go listener.Start()
+	defer listener.Stop()
+	info, err := os.Stat(socket)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// the signer never removes anything else than a socket
+	other := path.Join(dir, "other")
+	require.NoError(t, ioutil.WriteFile(other, []byte("other"), 0600))
+	_, err = net.NewUnixSignerListener(NewSignerServer(NewShareSigner(shares[2])), other)
+	require.Error(t, err)
+	_, err = os.Stat(other)
+	require.NoError(t, err)
+
+	client, err := net.NewUnixSignerClient(socket)
+	require.NoError(t, err)
+	pub := &key.DistPublic{Coefficients: commits}
+	signer, err := NewRemoteSigner(client, pub)
+	require.NoError(t, err)
+	require.Equal(t, shares[2].Share.I, signer.Index())
+
+	msg := []byte("round")
+	partial, err := signer.SignPartial(msg)
+	require.NoError(t, err)
+	require.NoError(t, key.Scheme.VerifyPartial(pub.PubPoly(), msg, partial))
+
+	// a signer holding a share of another distributed key is refused
+	_, others := dkgShares(n, thr)
+	_, err = NewRemoteSigner(client, &key.DistPublic{Coefficients: others})
+	require.Error(t, err)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"io"
 	"path"
 	"sync"
 	"time"
@@ -25,9 +26,12 @@ type Store interface {
 	Last() (*Beacon, error)
 	Get(round uint64) (*Beacon, error)
 	Cursor(func(Cursor))
-	// XXX Misses a delete function
+	// Del deletes the beacon saved at this round, if any
+	Del(round uint64) error
+	// DelFrom deletes the beacons saved at this round and after, all at once,
+	// and returns how many it deleted
+	DelFrom(round uint64) (int, error)
 	Close()
-	del(rount uint64)
 }
 
 // Iterate over items in sorted key order. This starts from the
@@ -163,13 +167,46 @@ func (b *boltStore) Get(round uint64) (*Beacon, error) {
 	return beacon, err
 }
 
-func (b *boltStore) del(round uint64) {
-	b.db.Update(func(tx *bolt.Tx) error {
+// Snapshot implements the Snapshotter interface with a read-only transaction,
+// so the database can still be written during the copy.
+func (b *boltStore) Snapshot(fn func(size int64, db io.WriterTo) error) error {
+	return b.db.View(func(tx *bolt.Tx) error {
+		return fn(tx.Size(), tx)
+	})
+}
+
+func (b *boltStore) Del(round uint64) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(beaconBucket)
 		return bucket.Delete(roundToBytes(round))
 	})
 }
 
+// DelFrom implements the Store interface in a single transaction
+func (b *boltStore) DelFrom(round uint64) (int, error) {
+	var deleted int
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(beaconBucket)
+		// deleting while iterating makes the bolt cursor skip keys
+		var keys [][]byte
+		c := bucket.Cursor()
+		for k, _ := c.Seek(roundToBytes(round)); k != nil; k, _ = c.Next() {
+			keys = append(keys, k)
+		}
+		for _, k := range keys {
+			if err := bucket.Delete(k); err != nil {
+				return err
+			}
+		}
+		deleted = len(keys)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
 func (b *boltStore) Cursor(fn func(Cursor)) {
 	b.db.View(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(beaconBucket)
@@ -231,6 +268,48 @@ func (c *boltCursor) Last() *Beacon {
 	return b
 }
 
+// Snapshotter is implemented by the stores that can copy their database while
+// it is in use.
+type Snapshotter interface {
+	// Snapshot calls fn with the size of a consistent copy of the database,
+	// and with the copy to write.
+	Snapshot(fn func(size int64, db io.WriterTo) error) error
+}
+
+// ErrNoSnapshot is returned when the store can't copy its database
+var ErrNoSnapshot = errors.New("the storage backend can't copy its database while in use")
+
+// Snapshot calls fn with a consistent copy of the database of the store, if
+// the store, or the store it wraps, is a Snapshotter.
+func Snapshot(s Store, fn func(size int64, db io.WriterTo) error) error {
+	st := snapshotter(s)
+	if st == nil {
+		return ErrNoSnapshot
+	}
+	return st.Snapshot(fn)
+}
+
+// CanSnapshot returns true if the store, or the store it wraps, can copy its
+// database while in use.
+func CanSnapshot(s Store) bool {
+	return snapshotter(s) != nil
+}
+
+func snapshotter(s Store) Snapshotter {
+	for {
+		switch st := s.(type) {
+		case Snapshotter:
+			return st
+		case *chainStore:
+			s = st.Store
+		case *CallbackStore:
+			s = st.Store
+		default:
+			return nil
+		}
+	}
+}
+
 type CallbackStore struct {
 	Store
 	cbs []func(*Beacon)
@@ -0,0 +1,144 @@
+package beacon
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"sync"
+
+	bolt "github.com/coreos/bbolt"
+)
+
+// Names of the storage backends registered by default
+const (
+	BoltBackend    = "bolt"
+	LevelDBBackend = "leveldb"
+	MemoryBackend  = "memory"
+)
+
+// DefaultBackend is the storage backend drand uses unless told otherwise
+const DefaultBackend = BoltBackend
+
+// StoreBackend opens the beacon databases of a storage engine. A node keeps
+// the database of each of its chains in a folder of its own.
+type StoreBackend interface {
+	// Open opens the database kept in the given folder, creating it if it
+	// doesn't exist yet. A backend ignores the options of other engines.
+	Open(folder string, opts ...StoreOption) (Store, error)
+	// Path returns the file or folder where the database of the given folder
+	// is kept, or the empty string if the backend keeps nothing on disk.
+	Path(folder string) string
+}
+
+// StoreOptions holds the engine specific options of the storage backends
+type StoreOptions struct {
+	Bolt *bolt.Options
+}
+
+// StoreOption sets an option of the storage backends
+type StoreOption func(*StoreOptions)
+
+// WithBoltOptions applies boltdb specific options to the bolt backend
+func WithBoltOptions(opts *bolt.Options) StoreOption {
+	return func(o *StoreOptions) {
+		o.Bolt = opts
+	}
+}
+
+// NewStoreOptions returns the options resulting from the given ones
+func NewStoreOptions(opts ...StoreOption) *StoreOptions {
+	o := new(StoreOptions)
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+type storeBackend struct {
+	open func(folder string, opts *StoreOptions) (Store, error)
+	file string
+}
+
+func (s *storeBackend) Open(folder string, opts ...StoreOption) (Store, error) {
+	if err := os.MkdirAll(folder, 0740); err != nil {
+		return nil, err
+	}
+	return s.open(folder, NewStoreOptions(opts...))
+}
+
+func (s *storeBackend) Path(folder string) string {
+	return path.Join(folder, s.file)
+}
+
+// memoryBackend returns the same store for a given folder for the life of the
+// process, so the beacons of a chain outlive its store, e.g. during a
+// transition.
+type memoryBackend struct {
+	sync.Mutex
+	stores map[string]Store
+}
+
+func (m *memoryBackend) Open(folder string, _ ...StoreOption) (Store, error) {
+	m.Lock()
+	defer m.Unlock()
+	folder = path.Clean(folder)
+	if store, ok := m.stores[folder]; ok {
+		return store, nil
+	}
+	store := NewMemoryStore()
+	m.stores[folder] = store
+	return store, nil
+}
+
+func (m *memoryBackend) Path(string) string {
+	return ""
+}
+
+var backends = struct {
+	sync.Mutex
+	m map[string]StoreBackend
+}{
+	m: map[string]StoreBackend{
+		BoltBackend: &storeBackend{
+			open: func(folder string, opts *StoreOptions) (Store, error) { return NewBoltStore(folder, opts.Bolt) },
+			file: BoltFileName,
+		},
+		LevelDBBackend: &storeBackend{
+			open: func(folder string, _ *StoreOptions) (Store, error) { return NewLevelDBStore(folder, nil) },
+			file: LevelDBFolderName,
+		},
+		MemoryBackend: &memoryBackend{stores: make(map[string]Store)},
+	},
+}
+
+// RegisterStoreBackend makes the storage backend available under the given
+// name, replacing any backend registered under the same name.
+func RegisterStoreBackend(name string, b StoreBackend) {
+	backends.Lock()
+	defer backends.Unlock()
+	backends.m[name] = b
+}
+
+// GetStoreBackend returns the storage backend registered under the given name
+func GetStoreBackend(name string) (StoreBackend, error) {
+	backends.Lock()
+	defer backends.Unlock()
+	b, ok := backends.m[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown storage backend %q", name)
+	}
+	return b, nil
+}
+
+// StoreBackends returns the sorted names of the registered storage backends
+func StoreBackends() []string {
+	backends.Lock()
+	defer backends.Unlock()
+	names := make([]string, 0, len(backends.m))
+	for name := range backends.m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,182 @@
+package beacon
+
+import (
+	"path"
+	"sync"
+
+	"github.com/nikkolasg/slog"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// LevelDBFolderName is the name of the folder leveldb writes to
+const LevelDBFolderName = "drand.leveldb"
+
+// levelStore implements the Store interface using the LSM storage engine
+// leveldb (native golang implementation). Beacons are stored JSON-encoded under
+// their big-endian round, so the keys are sorted by round like in the boltdb
+// store.
+type levelStore struct {
+	sync.Mutex
+	db  *leveldb.DB
+	len int
+}
+
+// NewLevelDBStore returns a Store implementation using the leveldb storage
+// engine.
+func NewLevelDBStore(folder string, opts *opt.Options) (Store, error) {
+	db, err := leveldb.OpenFile(path.Join(folder, LevelDBFolderName), opts)
+	if err != nil {
+		return nil, err
+	}
+	// leveldb keeps no count of its keys
+	var baseLen = 0
+	iter := db.NewIterator(nil, nil)
+	for iter.Next() {
+		baseLen++
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &levelStore{
+		db:  db,
+		len: baseLen,
+	}, nil
+}
+
+func (l *levelStore) Len() int {
+	l.Lock()
+	defer l.Unlock()
+	return l.len
+}
+
+func (l *levelStore) Close() {
+	if err := l.db.Close(); err != nil {
+		slog.Debugf("leveldb store: %s", err)
+	}
+}
+
+// Put implements the Store interface. Saving a beacon of an existing round
+// overwrites it.
+func (l *levelStore) Put(b *Beacon) error {
+	buff, err := b.Marshal()
+	if err != nil {
+		return err
+	}
+	key := roundToBytes(b.Round)
+	l.Lock()
+	defer l.Unlock()
+	exists, err := l.db.Has(key, nil)
+	if err != nil {
+		return err
+	}
+	if err := l.db.Put(key, buff, nil); err != nil {
+		return err
+	}
+	if !exists {
+		l.len++
+	}
+	return nil
+}
+
+// Last returns the last beacon signature saved into the db
+func (l *levelStore) Last() (*Beacon, error) {
+	iter := l.db.NewIterator(nil, nil)
+	defer iter.Release()
+	if !iter.Last() {
+		if err := iter.Error(); err != nil {
+			return nil, err
+		}
+		return nil, ErrNoBeaconSaved
+	}
+	return decodeBeacon(iter.Value())
+}
+
+// Get returns the beacon saved at this round
+func (l *levelStore) Get(round uint64) (*Beacon, error) {
+	buff, err := l.db.Get(roundToBytes(round), nil)
+	if err == leveldb.ErrNotFound {
+		return nil, ErrNoBeaconSaved
+	} else if err != nil {
+		return nil, err
+	}
+	return decodeBeacon(buff)
+}
+
+func (l *levelStore) Del(round uint64) error {
+	key := roundToBytes(round)
+	l.Lock()
+	defer l.Unlock()
+	exists, err := l.db.Has(key, nil)
+	if err != nil || !exists {
+		return err
+	}
+	if err := l.db.Delete(key, nil); err != nil {
+		return err
+	}
+	l.len--
+	return nil
+}
+
+// DelFrom implements the Store interface with a single batch
+func (l *levelStore) DelFrom(round uint64) (int, error) {
+	l.Lock()
+	defer l.Unlock()
+	batch := new(leveldb.Batch)
+	iter := l.db.NewIterator(&util.Range{Start: roundToBytes(round)}, nil)
+	for iter.Next() {
+		batch.Delete(append([]byte{}, iter.Key()...))
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return 0, err
+	}
+	if err := l.db.Write(batch, nil); err != nil {
+		return 0, err
+	}
+	l.len -= batch.Len()
+	return batch.Len(), nil
+}
+
+// Cursor calls the function with a cursor over a snapshot of the db taken when
+// it is called.
+func (l *levelStore) Cursor(fn func(Cursor)) {
+	iter := l.db.NewIterator(nil, nil)
+	defer iter.Release()
+	fn(&levelCursor{iter})
+}
+
+type levelCursor struct {
+	iterator.Iterator
+}
+
+func (c *levelCursor) First() *Beacon {
+	return c.value(c.Iterator.First())
+}
+
+func (c *levelCursor) Next() *Beacon {
+	return c.value(c.Iterator.Next())
+}
+
+func (c *levelCursor) Seek(round uint64) *Beacon {
+	return c.value(c.Iterator.Seek(roundToBytes(round)))
+}
+
+func (c *levelCursor) Last() *Beacon {
+	return c.value(c.Iterator.Last())
+}
+
+func (c *levelCursor) value(ok bool) *Beacon {
+	if !ok {
+		return nil
+	}
+	b, err := decodeBeacon(c.Value())
+	if err != nil {
+		return nil
+	}
+	return b
+}
@@ -0,0 +1,167 @@
+package beacon
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// memStore implements the Store interface in memory. Like the boltdb store, it
+// keeps the encoded beacons, sorted by round, so the beacons it returns are
+// independent of the ones saved.
+type memStore struct {
+	sync.Mutex
+	rounds  []uint64
+	beacons map[uint64][]byte
+}
+
+// NewMemoryStore returns a Store keeping the beacons in memory. The chain is
+// lost when the process exits, and the node syncs it again from its peers at
+// the next start.
+func NewMemoryStore() Store {
+	return &memStore{beacons: make(map[uint64][]byte)}
+}
+
+func (m *memStore) Len() int {
+	m.Lock()
+	defer m.Unlock()
+	return len(m.rounds)
+}
+
+// Put implements the Store interface. Saving a beacon of an existing round
+// overwrites it.
+func (m *memStore) Put(b *Beacon) error {
+	buff, err := b.Marshal()
+	if err != nil {
+		return err
+	}
+	m.Lock()
+	defer m.Unlock()
+	if _, exists := m.beacons[b.Round]; !exists {
+		i := m.search(b.Round)
+		m.rounds = append(m.rounds, 0)
+		copy(m.rounds[i+1:], m.rounds[i:])
+		m.rounds[i] = b.Round
+	}
+	m.beacons[b.Round] = buff
+	return nil
+}
+
+func (m *memStore) Last() (*Beacon, error) {
+	m.Lock()
+	defer m.Unlock()
+	if len(m.rounds) == 0 {
+		return nil, ErrNoBeaconSaved
+	}
+	return decodeBeacon(m.beacons[m.rounds[len(m.rounds)-1]])
+}
+
+func (m *memStore) Get(round uint64) (*Beacon, error) {
+	m.Lock()
+	defer m.Unlock()
+	buff, ok := m.beacons[round]
+	if !ok {
+		return nil, ErrNoBeaconSaved
+	}
+	return decodeBeacon(buff)
+}
+
+// Cursor calls the function with a cursor over the beacons of the store. The
+// cursor looks the rounds up at each move, so the function can save new
+// beacons in the store, and the cursor sees them.
+func (m *memStore) Cursor(fn func(Cursor)) {
+	fn(&memCursor{m: m})
+}
+
+func (m *memStore) Close() {}
+
+func (m *memStore) Del(round uint64) error {
+	m.Lock()
+	defer m.Unlock()
+	if _, exists := m.beacons[round]; !exists {
+		return nil
+	}
+	delete(m.beacons, round)
+	i := m.search(round)
+	m.rounds = append(m.rounds[:i], m.rounds[i+1:]...)
+	return nil
+}
+
+func (m *memStore) DelFrom(round uint64) (int, error) {
+	m.Lock()
+	defer m.Unlock()
+	i := m.search(round)
+	for _, r := range m.rounds[i:] {
+		delete(m.beacons, r)
+	}
+	deleted := len(m.rounds) - i
+	m.rounds = m.rounds[:i]
+	return deleted, nil
+}
+
+// search returns the index of the first saved round greater or equal to the
+// given round
+func (m *memStore) search(round uint64) int {
+	return sort.Search(len(m.rounds), func(i int) bool { return m.rounds[i] >= round })
+}
+
+// memCursor remembers the round it points to rather than a position, since
+// the rounds of the store may change between two moves
+type memCursor struct {
+	m     *memStore
+	round uint64
+	done  bool
+}
+
+func (c *memCursor) First() *Beacon {
+	return c.seek(0)
+}
+
+func (c *memCursor) Next() *Beacon {
+	if c.done || c.round == math.MaxUint64 {
+		c.done = true
+		return nil
+	}
+	return c.seek(c.round + 1)
+}
+
+func (c *memCursor) Seek(round uint64) *Beacon {
+	return c.seek(round)
+}
+
+func (c *memCursor) Last() *Beacon {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.at(len(c.m.rounds) - 1)
+}
+
+// seek points the cursor to the first round greater or equal to the given one
+func (c *memCursor) seek(round uint64) *Beacon {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.at(c.m.search(round))
+}
+
+// at returns the beacon at the given position of the rounds of the store,
+// which must be locked
+func (c *memCursor) at(pos int) *Beacon {
+	if pos < 0 || pos >= len(c.m.rounds) {
+		c.done = true
+		return nil
+	}
+	c.done = false
+	c.round = c.m.rounds[pos]
+	b, err := decodeBeacon(c.m.beacons[c.round])
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+func decodeBeacon(buff []byte) (*Beacon, error) {
+	b := new(Beacon)
+	if err := b.Unmarshal(buff); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -1,11 +1,15 @@
 package beacon
 
 import (
+	"bytes"
+	"io"
+	"io/ioutil"
 	"os"
 	"path"
 	"testing"
 	"time"
 
+	bolt "github.com/coreos/bbolt"
 	"github.com/stretchr/testify/require"
 )
 
@@ -118,3 +122,132 @@ func TestStoreBolt(t *testing.T) {
 		}
 	})
 }
+
+func TestStoreBackends(t *testing.T) {
+	for _, name := range StoreBackends() {
+		t.Run(name, func(t *testing.T) {
+			tmp, err := ioutil.TempDir("", "drand-store")
+			require.NoError(t, err)
+			defer os.RemoveAll(tmp)
+			backend, err := GetStoreBackend(name)
+			require.NoError(t, err)
+			store, err := backend.Open(tmp)
+			require.NoError(t, err)
+
+			require.Equal(t, 0, store.Len())
+			_, err = store.Last()
+			require.Equal(t, ErrNoBeaconSaved, err)
+			_, err = store.Get(1)
+			require.Equal(t, ErrNoBeaconSaved, err)
+
+			// beacons come out sorted by round, whatever the order they are
+			// saved in
+			var beacons []*Beacon
+			for _, r := range []uint64{3, 1, 256, 2} {
+				b := &Beacon{
+					PreviousSig: []byte{byte(r - 1)},
+					Round:       r,
+					Signature:   []byte{byte(r)},
+				}
+				require.NoError(t, store.Put(b))
+				beacons = append(beacons, b)
+			}
+			require.NoError(t, store.Put(beacons[0]))
+			require.Equal(t, 4, store.Len())
+			last, err := store.Last()
+			require.NoError(t, err)
+			require.True(t, beacons[2].Equal(last))
+			b, err := store.Get(2)
+			require.NoError(t, err)
+			require.True(t, beacons[3].Equal(b))
+
+			var rounds []uint64
+			store.Cursor(func(c Cursor) {
+				for b := c.First(); b != nil; b = c.Next() {
+					rounds = append(rounds, b.Round)
+				}
+				require.Equal(t, uint64(256), c.Seek(4).Round)
+				require.Nil(t, c.Next())
+				require.Equal(t, uint64(256), c.Last().Round)
+			})
+			require.Equal(t, []uint64{1, 2, 3, 256}, rounds)
+
+			require.NoError(t, store.Del(256))
+			require.Equal(t, 3, store.Len())
+			last, err = store.Last()
+			require.NoError(t, err)
+			require.Equal(t, uint64(3), last.Round)
+
+			require.NoError(t, store.Put(beacons[2]))
+			deleted, err := store.DelFrom(3)
+			require.NoError(t, err)
+			require.Equal(t, 2, deleted)
+			require.Equal(t, 2, store.Len())
+			deleted, err = store.DelFrom(3)
+			require.NoError(t, err)
+			require.Equal(t, 0, deleted)
+			require.NoError(t, store.Put(beacons[0]))
+			store.Close()
+
+			// the beacons are found back, for the life of the process with
+			// the memory backend
+			store, err = backend.Open(tmp)
+			require.NoError(t, err)
+			defer store.Close()
+			require.Equal(t, 3, store.Len())
+			last, err = store.Last()
+			require.NoError(t, err)
+			require.Equal(t, uint64(3), last.Round)
+		})
+	}
+}
+
+func TestStoreBackendOptions(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "drand-store")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+	backend, err := GetStoreBackend(BoltBackend)
+	require.NoError(t, err)
+	// a read-only database can't be created
+	_, err = backend.Open(tmp, WithBoltOptions(&bolt.Options{ReadOnly: true}))
+	require.Error(t, err)
+	// the other backends ignore the options of bolt
+	backend, err = GetStoreBackend(LevelDBBackend)
+	require.NoError(t, err)
+	store, err := backend.Open(tmp, WithBoltOptions(&bolt.Options{ReadOnly: true}))
+	require.NoError(t, err)
+	store.Close()
+}
+
+func TestStoreSnapshot(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "drand-snapshot")
+	require.NoError(t, err)
+	defer os.RemoveAll(tmp)
+	store, err := NewBoltStore(tmp, nil)
+	require.NoError(t, err)
+	defer store.Close()
+	for r := uint64(1); r <= 10; r++ {
+		require.NoError(t, store.Put(&Beacon{Round: r, Signature: []byte{byte(r)}}))
+	}
+
+	// the snapshot is taken through the stores wrapping the database
+	copyFolder := path.Join(tmp, "copy")
+	require.NoError(t, os.MkdirAll(copyFolder, 0700))
+	err = Snapshot(NewCallbackStore(store), func(size int64, db io.WriterTo) error {
+		var buff bytes.Buffer
+		n, err := db.WriteTo(&buff)
+		require.Equal(t, size, n)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(path.Join(copyFolder, BoltFileName), buff.Bytes(), 0600)
+	})
+	require.NoError(t, err)
+	snapshot, err := NewBoltStore(copyFolder, nil)
+	require.NoError(t, err)
+	defer snapshot.Close()
+	require.Equal(t, 10, snapshot.Len())
+
+	err = Snapshot(NewMemoryStore(), func(int64, io.WriterTo) error { return nil })
+	require.Equal(t, ErrNoSnapshot, err)
+}
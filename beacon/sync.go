@@ -3,6 +3,8 @@ package beacon
 import (
 	"context"
 	"errors"
+	"fmt"
+	gnet "net"
 	"time"
 
 	"github.com/drand/drand/log"
@@ -12,45 +14,113 @@ import (
 )
 
 // SyncChain is the server side call that reply with the beacon in order to the
-// client requesting the syncing.
+// client requesting the syncing, up to the requested round if any. It sends at
+// most the maximum number of beacons per request, and serves a limited number
+// of requests at once to each peer.
 func (h *Handler) SyncChain(req *proto.SyncRequest, p proto.Protocol_SyncChainServer) error {
 	fromRound := req.GetFromRound()
+	toRound := req.GetToRound()
 	peer, _ := peer.FromContext(p.Context())
 	addr := peer.Addr.String()
-	last, _ := h.chain.Last()
-	h.l.Debug("received", "sync_request", "from", peer.Addr.String(), "from_round", fromRound, "head_at", last.Round)
+	last, err := h.chain.Last()
+	if err != nil {
+		return err
+	}
+	h.l.Debug("received", "sync_request", "from", addr, "from_round", fromRound, "to_round", toRound, "head_at", last.Round)
+	// as for partial beacons, unversioned nodes can still sync
+	if req.GetMetadata() != nil {
+		if err := net.CheckMetadata(req.GetMetadata()); err != nil {
+			h.l.Error("sync_request", addr, "incompatible_node", err)
+			return fmt.Errorf("incompatible node: %v", err)
+		}
+	}
 	if last.Round < fromRound {
 		return errors.New("no beacon stored above requested round")
 	}
-	defer h.l.Debug("sync_reply_leave", addr)
 	if fromRound == 0 {
-		last, err := h.chain.Last()
-		if err != nil {
-			return err
-		}
 		h.l.Debug("sync_chain_reply", addr, "from", fromRound, "reply-last", last.Round)
 		return p.Send(beaconToProto(last))
 	}
-	var err error
+	host := peerHost(addr)
+	if !h.startSync(host) {
+		h.l.Error("sync_request", addr, "too_many_syncs", h.syncMaxPerPeer())
+		return errTooManySyncs
+	}
+	defer h.endSync(host)
+
+	maxCount := h.syncMaxCount()
+	if count := req.GetMaxCount(); count != 0 && count < maxCount {
+		maxCount = count
+	}
+	var sent uint64
 	h.chain.Cursor(func(c Cursor) {
 		for beacon := c.Seek(fromRound); beacon != nil; beacon = c.Next() {
-			reply := beaconToProto(beacon)
-			nRound, _ := NextRound(h.conf.Clock.Now().Unix(), h.conf.Group.Period, h.conf.Group.GenesisTime)
-			l, _ := h.chain.Last()
-			h.l.Debug("sync_chain_reply", addr, "from", fromRound, "to", reply.Round, "head", nRound-1, "last_beacon", l.String())
-			if err = p.Send(reply); err != nil {
-				h.l.Debug("sync_chain_reply", "err", err)
+			if toRound != 0 && beacon.Round > toRound {
+				return
+			}
+			if err = p.Send(beaconToProto(beacon)); err != nil {
+				return
+			}
+			if sent++; sent >= maxCount {
 				return
 			}
-			fromRound = reply.Round
 		}
 	})
+	h.l.Debug("sync_chain_reply", addr, "from", fromRound, "sent", sent, "err", err)
 	return err
 }
 
+var errTooManySyncs = errors.New("too many sync requests from this node")
+
+// startSync returns false if the peer has already the maximum number of sync
+// requests being served
+func (h *Handler) startSync(host string) bool {
+	h.syncLock.Lock()
+	defer h.syncLock.Unlock()
+	if h.syncs[host] >= h.syncMaxPerPeer() {
+		return false
+	}
+	h.syncs[host]++
+	return true
+}
+
+func (h *Handler) endSync(host string) {
+	h.syncLock.Lock()
+	defer h.syncLock.Unlock()
+	if h.syncs[host]--; h.syncs[host] <= 0 {
+		delete(h.syncs, host)
+	}
+}
+
+func (h *Handler) syncMaxCount() uint64 {
+	if h.conf.SyncMaxCount == 0 {
+		return DefaultSyncMaxCount
+	}
+	return h.conf.SyncMaxCount
+}
+
+func (h *Handler) syncMaxPerPeer() int {
+	if h.conf.SyncMaxPerPeer == 0 {
+		return DefaultSyncMaxPerPeer
+	}
+	return h.conf.SyncMaxPerPeer
+}
+
+// peerHost returns the host of the address of a peer, since its port differs
+// for each connection
+func peerHost(addr string) string {
+	host, _, err := gnet.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
 // syncChain will sync from the given rounds, to the targeted round until either
-// the context closes or it exhausted the list of nodes to contact to.
-func syncChain(ctx context.Context, l log.Logger, safe *cryptoSafe, from *Beacon, toRound uint64, client net.ProtocolClient) (chan *Beacon, error) {
+// the context closes or it exhausted the list of nodes to contact to. The peers
+// are contacted from the best to the worst according to their scores, which
+// are updated with the outcome of each sync.
+func syncChain(ctx context.Context, l log.Logger, safe *cryptoSafe, peers *peerScores, from *Beacon, toRound uint64, client net.ProtocolClient, beaconID string) (chan *Beacon, error) {
 	outCh := make(chan *Beacon, toRound-from.Round)
 	fromRound := from.Round
 	defer l.Debug("sync_from", fromRound, "leaving")
@@ -61,25 +131,36 @@ func syncChain(ctx context.Context, l log.Logger, safe *cryptoSafe, from *Beacon
 		return nil, errors.New("no round info")
 	}
 	var lastBeacon = from
-	ids := shuffleNodes(info.group.Nodes)
+	ids := peers.order(info.group.Nodes, from.Round)
 	go func() {
 		defer close(outCh)
-		for _, id := range ids {
+		for i := 0; i < len(ids); i++ {
+			id := ids[i]
 			if id.Equal(info.id) {
 				continue
 			}
 			request := &proto.SyncRequest{
 				FromRound: lastBeacon.Round + 1,
+				ToRound:   toRound,
+				Metadata:  net.NewMetadata(beaconID),
 			}
 			l.Debug("sync_from", "try_sync", "to", id.Addr, "from_round", fromRound+1)
+			start := time.Now()
+			release := peers.use(id.Address())
 			cctx, ccancel := context.WithCancel(context.Background())
 			respCh, err := client.SyncChain(cctx, id, request)
 			if err != nil {
+				release()
+				ccancel()
 				l.Error("sync_from", fromRound+1, "error", err, "from", id.Address())
+				peers.failure(id.Address())
 				continue
 			}
-			func() {
+			before := lastBeacon.Round
+			var latency time.Duration
+			stopped := func() bool {
 				addr := id.Address()
+				defer release()
 				defer ccancel()
 				for {
 					select {
@@ -89,38 +170,53 @@ func syncChain(ctx context.Context, l log.Logger, safe *cryptoSafe, from *Beacon
 							// default proto beacon - that means channel is down
 							// so we log that as so
 							l.Debug("sync_from", addr, "from_round", fromRound, "sync_stopped")
-							return
+							return true
 						}
 
 						l.Debug("sync_from", addr, "from_round", fromRound, "got_round", proto.GetRound())
 						newBeacon := protoToBeacon(proto)
 						if !isAppendable(lastBeacon, newBeacon) {
 							l.Error("sync_from", addr, "from_round", fromRound, "want_round", lastBeacon.Round+1, "got_round", newBeacon.Round)
-							return
+							return false
 						}
 						info, err := safe.GetInfo(newBeacon.Round)
 						if err != nil {
 							l.Error("sync_from", addr, "invalid_round_info", newBeacon.Round)
-							return
+							return false
 						}
 						err = VerifyBeacon(info.pub.Commit(), newBeacon)
 						if err != nil {
 							l.Error("sync_from", addr, "invalid_beacon_sig", err, "round", newBeacon.Round)
-							return
+							return false
+						}
+						if latency == 0 {
+							latency = time.Since(start)
 						}
 						lastBeacon = newBeacon
 						outCh <- newBeacon
 
 					case <-time.After(MaxSyncWaitTime):
-						return
+						return false
 					case <-ctx.Done():
-						return
+						return false
 					}
 				}
 			}()
-			if lastBeacon.Round == toRound {
+			if lastBeacon.Round > before {
+				peers.seen(id.Address(), lastBeacon.Round)
+				peers.success(id.Address(), latency)
+			}
+			if !stopped && ctx.Err() == nil {
+				peers.failure(id.Address())
+			}
+			if lastBeacon.Round >= toRound {
 				return
 			}
+			if stopped && lastBeacon.Round > before {
+				// the peer sends a limited number of beacons per request: ask
+				// it the following ones
+				i--
+			}
 		}
 	}()
 	return outCh, nil
@@ -0,0 +1,281 @@
+package beacon
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/drand/drand/key"
+	"github.com/drand/drand/log"
+	"github.com/drand/drand/net"
+	proto "github.com/drand/drand/protobuf/drand"
+)
+
+// SyncSegmentSize is the number of rounds requested to a single peer when the
+// chain is synced from several peers at once. A node syncs from several peers
+// only when it misses more than one segment.
+var SyncSegmentSize uint64 = 1000
+
+// syncSegment is a range of rounds, bounds included, requested to one peer
+type syncSegment struct {
+	index int
+	from  uint64
+	to    uint64
+	// last is true for the segment ending at the head of the chain, which
+	// the peers may not have fully produced yet
+	last bool
+	// tried holds the addresses of the peers that failed to send the segment
+	tried map[string]bool
+}
+
+type segmentResult struct {
+	seg     *syncSegment
+	peer    string
+	beacons []*Beacon
+	err     error
+}
+
+// syncChainParallel syncs from the given round to the targeted round like
+// syncChain, but splits the missing rounds in segments requested to up to
+// the given number of peers at once. Each segment is verified on its own, then
+// the segments are stitched in order. A segment a peer fails to send entirely,
+// because of an error, an invalid beacon or a stall, is requested again to
+// another peer. The workers start with the best peers according to their
+// scores.
+func syncChainParallel(ctx context.Context, l log.Logger, safe *cryptoSafe, peers *peerScores, from *Beacon, toRound uint64, client net.ProtocolClient, beaconID string, parallel int) (chan *Beacon, error) {
+	if toRound <= from.Round {
+		outCh := make(chan *Beacon)
+		close(outCh)
+		return outCh, nil
+	}
+	outCh := make(chan *Beacon, toRound-from.Round)
+	info, err := safe.GetInfo(from.Round)
+	if err != nil {
+		l.Error("sync_no_round_info", from.Round)
+		return nil, errors.New("no round info")
+	}
+	var ids []*key.Identity
+	for _, id := range peers.order(info.group.Nodes, from.Round) {
+		if !id.Equal(info.id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		close(outCh)
+		return outCh, nil
+	}
+	if parallel > len(ids) {
+		parallel = len(ids)
+	}
+
+	var segments []*syncSegment
+	for start := from.Round + 1; start <= toRound; start += SyncSegmentSize {
+		end := start + SyncSegmentSize - 1
+		if end >= toRound {
+			end = toRound
+		}
+		segments = append(segments, &syncSegment{
+			index: len(segments),
+			from:  start,
+			to:    end,
+			last:  end == toRound,
+			tried: make(map[string]bool),
+		})
+	}
+	// a segment is only in the queue or with one worker at a time, so the
+	// queue never blocks
+	queue := make(chan *syncSegment, len(segments))
+	for _, seg := range segments {
+		queue <- seg
+	}
+	results := make(chan *segmentResult, len(segments))
+	cctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func(first int) {
+			defer wg.Done()
+			for {
+				select {
+				case seg := <-queue:
+					results <- fetchSegment(cctx, l, safe, peers, client, beaconID, seg, ids, first)
+				case <-cctx.Done():
+					return
+				}
+			}
+		}(i)
+	}
+
+	go func() {
+		defer close(outCh)
+		defer wg.Wait()
+		defer cancel()
+		l.Debug("sync_parallel", "start", "from_round", from.Round+1, "to_round", toRound, "segments", len(segments), "peers", parallel)
+		lastBeacon := from
+		pending := make(map[int]*segmentResult)
+		next := 0
+		for next < len(segments) {
+			var res *segmentResult
+			select {
+			case res = <-results:
+			case <-ctx.Done():
+				return
+			}
+			if res.err != nil {
+				l.Error("sync_parallel", "abort", "from_round", res.seg.from, "to_round", res.seg.to, "err", res.err)
+				return
+			}
+			pending[res.seg.index] = res
+			for done, ok := pending[next]; ok; done, ok = pending[next] {
+				delete(pending, next)
+				beacons := done.beacons
+				if len(beacons) > 0 && !isLinked(lastBeacon, beacons[0]) {
+					// both segments are valid on their own: ask the later one
+					// to another peer
+					l.Error("sync_parallel", "unlinked_segment", "from", done.peer, "from_round", done.seg.from, "last_round", lastBeacon.Round)
+					done.seg.tried[done.peer] = true
+					queue <- done.seg
+					break
+				}
+				for _, b := range beacons {
+					select {
+					case outCh <- b:
+					case <-ctx.Done():
+						return
+					}
+				}
+				if len(beacons) > 0 {
+					lastBeacon = beacons[len(beacons)-1]
+				}
+				next++
+			}
+		}
+		l.Debug("sync_parallel", "done", "last_round", lastBeacon.Round)
+	}()
+	return outCh, nil
+}
+
+// fetchSegment requests the segment to the peers that didn't fail it yet,
+// starting from the given index in the list, until one sends it entirely. The
+// last segment is accepted partially, since the peers may not have produced
+// its last rounds yet.
+func fetchSegment(ctx context.Context, l log.Logger, safe *cryptoSafe, peers *peerScores, client net.ProtocolClient, beaconID string, seg *syncSegment, ids []*key.Identity, first int) *segmentResult {
+	for i := range ids {
+		id := ids[(first+i)%len(ids)]
+		if seg.tried[id.Address()] {
+			continue
+		}
+		beacons, err := requestSegment(ctx, l, safe, peers, client, beaconID, seg, id)
+		if err == nil || (seg.last && len(beacons) > 0) {
+			return &segmentResult{seg: seg, peer: id.Address(), beacons: beacons}
+		}
+		if ctx.Err() != nil {
+			return &segmentResult{seg: seg, err: ctx.Err()}
+		}
+		l.Error("sync_segment", id.Address(), "from_round", seg.from, "to_round", seg.to, "got", len(beacons), "err", err)
+		seg.tried[id.Address()] = true
+	}
+	if seg.last {
+		// no peer has the rounds yet: they are produced by the group after
+		// the sync
+		return &segmentResult{seg: seg}
+	}
+	return &segmentResult{seg: seg, err: fmt.Errorf("no peer sent rounds %d to %d", seg.from, seg.to)}
+}
+
+// requestSegment returns the verified beacons of the segment sent by the peer.
+// It asks the peer the remaining beacons as long as it sends some, since a peer
+// sends a limited number of beacons per request. It returns an error, with the
+// beacons received so far, if the peer doesn't send the whole segment.
+func requestSegment(ctx context.Context, l log.Logger, safe *cryptoSafe, peers *peerScores, client net.ProtocolClient, beaconID string, seg *syncSegment, id *key.Identity) ([]*Beacon, error) {
+	beacons := make([]*Beacon, 0, seg.to-seg.from+1)
+	for {
+		got := len(beacons)
+		var err error
+		beacons, err = receiveSegment(ctx, safe, peers, client, beaconID, seg, id, beacons)
+		if err == nil {
+			l.Debug("sync_segment", id.Address(), "from_round", seg.from, "to_round", seg.to, "done")
+			return beacons, nil
+		}
+		if err != errSyncStopped || len(beacons) == got {
+			return beacons, err
+		}
+	}
+}
+
+var errSyncStopped = errors.New("sync stopped")
+
+// receiveSegment appends to the beacons the ones following them in the
+// segment, requested to the peer in a single request, and updates the score of
+// the peer. It returns errSyncStopped if the peer ends the request before the
+// end of the segment.
+func receiveSegment(ctx context.Context, safe *cryptoSafe, peers *peerScores, client net.ProtocolClient, beaconID string, seg *syncSegment, id *key.Identity, beacons []*Beacon) ([]*Beacon, error) {
+	start := time.Now()
+	got := len(beacons)
+	var latency time.Duration
+	beacons, err := func() ([]*Beacon, error) {
+		defer peers.use(id.Address())()
+		cctx, ccancel := context.WithCancel(ctx)
+		defer ccancel()
+		fromRound := seg.from + uint64(len(beacons))
+		request := &proto.SyncRequest{
+			FromRound: fromRound,
+			ToRound:   seg.to,
+			MaxCount:  seg.to - fromRound + 1,
+			Metadata:  net.NewMetadata(beaconID),
+		}
+		respCh, err := client.SyncChain(cctx, id, request)
+		if err != nil {
+			return beacons, err
+		}
+		for {
+			select {
+			case p := <-respCh:
+				if p == nil {
+					return beacons, errSyncStopped
+				}
+				b := protoToBeacon(p)
+				if b.Round != seg.from+uint64(len(beacons)) {
+					return beacons, fmt.Errorf("want round %d, got %d", seg.from+uint64(len(beacons)), b.Round)
+				}
+				if len(beacons) > 0 && !isLinked(beacons[len(beacons)-1], b) {
+					return beacons, fmt.Errorf("round %d doesn't follow the previous one", b.Round)
+				}
+				info, err := safe.GetInfo(b.Round)
+				if err != nil {
+					return beacons, fmt.Errorf("no round info for %d", b.Round)
+				}
+				if err := VerifyBeacon(info.pub.Commit(), b); err != nil {
+					return beacons, fmt.Errorf("invalid beacon at round %d: %v", b.Round, err)
+				}
+				if latency == 0 {
+					latency = time.Since(start)
+				}
+				beacons = append(beacons, b)
+				if b.Round == seg.to {
+					return beacons, nil
+				}
+			case <-time.After(MaxSyncWaitTime):
+				return beacons, errors.New("sync stalled")
+			case <-ctx.Done():
+				return beacons, ctx.Err()
+			}
+		}
+	}()
+	if len(beacons) > got {
+		peers.seen(id.Address(), beacons[len(beacons)-1].Round)
+		peers.success(id.Address(), latency)
+	}
+	if err != nil && err != errSyncStopped && ctx.Err() == nil {
+		peers.failure(id.Address())
+	}
+	return beacons, err
+}
+
+// isLinked returns true if the beacon is the one following the last beacon
+func isLinked(lastBeacon, newBeacon *Beacon) bool {
+	return isAppendable(lastBeacon, newBeacon) && bytes.Equal(newBeacon.PreviousSig, lastBeacon.Signature)
+}
@@ -0,0 +1,127 @@
+package beacon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/drand/drand/key"
+	"github.com/drand/drand/protobuf/drand"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncChainParallel(t *testing.T) {
+	n := 4
+	thr := n/2 + 1
+	period := 2 * time.Second
+	bt := NewBeaconTest(n, thr, period, time.Now().Unix())
+	defer bt.CleanUp()
+
+	// build a valid chain from the shares of the group
+	pubPoly := bt.group.PublicKey.PubPoly()
+	genesis, err := bt.nodes[0].handler.chain.Last()
+	require.NoError(t, err)
+	var toRound uint64 = 40
+	chain := []*Beacon{genesis}
+	for round := uint64(1); round <= toRound; round++ {
+		prev := chain[len(chain)-1]
+		msg := Message(round, prev.Signature)
+		var partials [][]byte
+		for _, s := range bt.shares[:thr] {
+			partial, err := key.Scheme.Sign(s.PrivateShare(), msg)
+			require.NoError(t, err)
+			partials = append(partials, partial)
+		}
+		sig, err := key.Scheme.Recover(pubPoly, msg, partials, thr, n)
+		require.NoError(t, err)
+		chain = append(chain, &Beacon{Round: round, PreviousSig: prev.Signature, Signature: sig})
+	}
+
+	// node 1 has the whole chain, node 2 stops at round 15, and node 3
+	// holds an invalid beacon at round 23
+	heads := map[int]uint64{1: toRound, 2: 15, 3: toRound}
+	for i, head := range heads {
+		for _, b := range chain[1 : head+1] {
+			if i == 3 && b.Round == 23 {
+				b = &Beacon{Round: b.Round, PreviousSig: b.PreviousSig, Signature: chain[22].Signature}
+			}
+			require.NoError(t, bt.nodes[i].handler.chain.Store.Put(b))
+		}
+	}
+	for i := 0; i < n; i++ {
+		// the peers send the segments in several requests
+		bt.nodes[i].handler.conf.SyncMaxCount = 3
+		bt.ServeBeacon(i)
+		bt.nodes[i].started = true
+	}
+
+	defer func(size uint64) { SyncSegmentSize = size }(SyncSegmentSize)
+	SyncSegmentSize = 7
+	h := bt.nodes[0].handler
+	outCh, err := syncChainParallel(context.Background(), h.l, h.safe, h.peers, genesis, toRound, h.client, "", n)
+	require.NoError(t, err)
+	var synced []*Beacon
+	for b := range outCh {
+		synced = append(synced, b)
+	}
+	require.Len(t, synced, int(toRound))
+	for i, b := range synced {
+		require.True(t, chain[i+1].Equal(b), "round %d", i+1)
+	}
+
+	// the sequential sync asks the following beacons to the peers as well
+	outCh, err = syncChain(context.Background(), h.l, h.safe, h.peers, genesis, toRound, h.client, "")
+	require.NoError(t, err)
+	synced = synced[:0]
+	for b := range outCh {
+		synced = append(synced, b)
+	}
+	require.Len(t, synced, int(toRound))
+
+	// the last segment is accepted partially when the peers didn't produce
+	// the current round yet
+	outCh, err = syncChainParallel(context.Background(), h.l, h.safe, h.peers, chain[30], toRound+1, h.client, "", n)
+	require.NoError(t, err)
+	synced = synced[:0]
+	for b := range outCh {
+		synced = append(synced, b)
+	}
+	require.Len(t, synced, int(toRound-30))
+	require.Equal(t, toRound, synced[len(synced)-1].Round)
+}
+
+func TestSyncChainLimits(t *testing.T) {
+	n := 2
+	bt := NewBeaconTest(n, n, 2*time.Second, time.Now().Unix())
+	defer bt.CleanUp()
+	server := bt.nodes[1].handler
+	for round := uint64(1); round <= 20; round++ {
+		require.NoError(t, server.chain.Store.Put(&Beacon{Round: round, Signature: []byte{byte(round)}}))
+	}
+	server.conf.SyncMaxCount = 8
+	server.conf.SyncMaxPerPeer = 1
+	for i := 0; i < n; i++ {
+		bt.ServeBeacon(i)
+		bt.nodes[i].started = true
+	}
+	peer := bt.nodes[1].private.Public
+	rounds := func(req *drand.SyncRequest) []uint64 {
+		respCh, err := bt.nodes[0].handler.client.SyncChain(context.Background(), peer, req)
+		require.NoError(t, err)
+		var rounds []uint64
+		for p := range respCh {
+			rounds = append(rounds, p.GetRound())
+		}
+		return rounds
+	}
+	require.Equal(t, []uint64{3, 4, 5, 6, 7, 8, 9, 10}, rounds(&drand.SyncRequest{FromRound: 3}))
+	require.Equal(t, []uint64{3, 4, 5}, rounds(&drand.SyncRequest{FromRound: 3, ToRound: 5}))
+	require.Equal(t, []uint64{3, 4}, rounds(&drand.SyncRequest{FromRound: 3, MaxCount: 2}))
+	require.Equal(t, []uint64{18, 19, 20}, rounds(&drand.SyncRequest{FromRound: 18, ToRound: 30}))
+
+	// a peer syncing already can't sync again before the end of its request
+	require.True(t, server.startSync("127.0.0.1"))
+	require.Empty(t, rounds(&drand.SyncRequest{FromRound: 3}))
+	server.endSync("127.0.0.1")
+	require.Len(t, rounds(&drand.SyncRequest{FromRound: 3}), 8)
+}
@@ -0,0 +1,96 @@
+package beacon
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	clock "github.com/jonboulle/clockwork"
+)
+
+// SyncStatus describes the sync of the chain a node runs, if any
+type SyncStatus struct {
+	// Syncing is true while the node syncs its chain from its peers
+	Syncing bool
+	// Target is the last round the sync fetches
+	Target uint64
+	// Current is the last round stored by the node
+	Current uint64
+	// Peers are the addresses of the peers the node currently syncs from
+	Peers []string
+	// Rate is the number of rounds stored per second since the sync started
+	Rate float64
+	// ETA is the estimated time left until the node reaches the target, zero
+	// if it can't be estimated yet
+	ETA time.Duration
+}
+
+// ErrSyncRunning is returned when a sync is requested while the node is
+// already syncing its chain
+var ErrSyncRunning = errors.New("beacon: sync already running")
+
+// syncProgress tracks the syncs the chain store runs. Several syncs may run at
+// once, in which case the progress spans all of them.
+type syncProgress struct {
+	sync.Mutex
+	clock   clock.Clock
+	running int
+	start   uint64
+	target  uint64
+	started time.Time
+}
+
+// begin records a new sync from the given round up to the target round
+func (s *syncProgress) begin(start, target uint64) {
+	s.Lock()
+	defer s.Unlock()
+	s.add(start, target)
+}
+
+// beginAlone records a new sync like begin, unless a sync is already running,
+// in which case it returns false
+func (s *syncProgress) beginAlone(start, target uint64) bool {
+	s.Lock()
+	defer s.Unlock()
+	if s.running > 0 {
+		return false
+	}
+	s.add(start, target)
+	return true
+}
+
+func (s *syncProgress) add(start, target uint64) {
+	if s.running == 0 {
+		s.start = start
+		s.target = target
+		s.started = s.clock.Now()
+	} else if target > s.target {
+		s.target = target
+	}
+	s.running++
+}
+
+func (s *syncProgress) end() {
+	s.Lock()
+	defer s.Unlock()
+	s.running--
+}
+
+// status returns the progress of the syncs given the last round stored
+func (s *syncProgress) status(current uint64) SyncStatus {
+	s.Lock()
+	defer s.Unlock()
+	st := SyncStatus{Current: current}
+	if s.running == 0 {
+		return st
+	}
+	st.Syncing = true
+	st.Target = s.target
+	if elapsed := s.clock.Since(s.started); elapsed > 0 && current > s.start {
+		st.Rate = float64(current-s.start) / elapsed.Seconds()
+	}
+	if st.Rate > 0 && s.target > current {
+		st.ETA = time.Duration(float64(s.target-current) / st.Rate * float64(time.Second))
+	}
+	return st
+}
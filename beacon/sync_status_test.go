@@ -0,0 +1,49 @@
+package beacon
+
+import (
+	"testing"
+	"time"
+
+	clock "github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncStatus(t *testing.T) {
+	c := clock.NewFakeClock()
+	progress := &syncProgress{clock: c}
+	require.Equal(t, SyncStatus{Current: 10}, progress.status(10))
+
+	progress.begin(10, 110)
+	require.False(t, progress.beginAlone(10, 200))
+	st := progress.status(10)
+	require.True(t, st.Syncing)
+	require.Equal(t, uint64(110), st.Target)
+	require.Zero(t, st.Rate)
+	require.Zero(t, st.ETA)
+
+	c.Advance(2 * time.Second)
+	st = progress.status(30)
+	require.Equal(t, float64(10), st.Rate)
+	require.Equal(t, 8*time.Second, st.ETA)
+
+	// a concurrent sync extends the target
+	progress.begin(30, 130)
+	progress.end()
+	st = progress.status(30)
+	require.True(t, st.Syncing)
+	require.Equal(t, uint64(130), st.Target)
+	progress.end()
+	require.False(t, progress.status(130).Syncing)
+	require.True(t, progress.beginAlone(130, 140))
+
+	peers := newPeerScores(c)
+	release1 := peers.use("b:1")
+	release2 := peers.use("a:1")
+	release3 := peers.use("b:1")
+	require.Equal(t, []string{"a:1", "b:1"}, peers.syncing())
+	release1()
+	release2()
+	require.Equal(t, []string{"b:1"}, peers.syncing())
+	release3()
+	require.Empty(t, peers.syncing())
+}
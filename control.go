@@ -1,8 +1,11 @@
 package main
 
 import (
+	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/drand/drand/core"
@@ -10,13 +13,42 @@ import (
 	"github.com/drand/drand/net"
 	control "github.com/drand/drand/protobuf/drand"
 
+	"github.com/BurntSushi/toml"
 	json "github.com/nikkolasg/hexjson"
 	"github.com/urfave/cli/v2"
 )
 
 func shareCmd(c *cli.Context) error {
+	if c.Bool(cancelFlag.Name) {
+		return cancelDKGCmd(c)
+	}
+	if c.Bool(cancelTransitionFlag.Name) {
+		return cancelTransitionCmd(c)
+	}
 	isResharing := c.IsSet(transitionFlag.Name) || c.IsSet(oldGroupFlag.Name)
 	isLeader := c.Bool(leaderFlag.Name)
+	if c.Args().Present() {
+		if isResharing || isLeader || c.IsSet(connectFlag.Name) {
+			fatal("drand: a group file can't be used with the leader, connect or transition flags")
+		}
+		return shareGroupCmd(c)
+	}
+	for _, flag := range []string{shareNodeFlag.Name, thresholdFlag.Name, secretFlag.Name} {
+		if !c.IsSet(flag) {
+			fatal("drand: share command needs the %s flag", flag)
+		}
+	}
+	var allowed []*control.Identity
+	if c.IsSet(allowFlag.Name) {
+		if !isLeader {
+			fatal("drand: only the leader can specify the allowed participants")
+		}
+		allowed = getAllowedKeys(c)
+	}
+
+	if isLeader && c.IsSet(autoAcceptHashFlag.Name) {
+		fatal("drand: only participants can accept the group of the leader")
+	}
 
 	var connectPeer net.Peer
 	if !isLeader {
@@ -68,25 +100,37 @@ func shareCmd(c *cli.Context) error {
 				"file will not be written out to the specified output. To get the" +
 				"group file once the setup phase is done, you can run the `drand show" +
 				"group` command")
-			groupP, shareErr = client.InitDKGLeader(nodes, thr, period, timeout, entropyInfo, secret, offset)
+			groupP, shareErr = waitLeaderSetup(client, func() (*control.GroupPacket, error) {
+				return client.InitDKGLeader(nodes, thr, period, timeout, entropyInfo, secret, offset, allowed)
+			})
 			fmt.Println(" --- got err", shareErr, "group", groupP)
 		} else {
 			fmt.Println("Participating to the setup of the DKG")
-			groupP, shareErr = client.InitDKG(connectPeer, nodes, thr, timeout, entropyInfo, secret)
+			groupP, shareErr = waitGroupAcceptance(c, client, func() (*control.GroupPacket, error) {
+				return client.InitDKG(connectPeer, nodes, thr, timeout, entropyInfo, secret)
+			})
 			fmt.Println(" --- got err", shareErr, "group", groupP)
 		}
 	} else {
 		// resharing case needs the previous group
 		var oldPath string
+		oldHash := c.String(groupHashFlag.Name)
 		if c.IsSet(transitionFlag.Name) {
 			// daemon will try to the load the one stored
 			oldPath = ""
 		} else if c.IsSet(oldGroupFlag.Name) {
-			var oldGroup = new(key.Group)
-			if err := key.Load(c.String(oldGroupFlag.Name), oldGroup); err != nil {
-				fatal("could not load drand from path", err)
-			}
 			oldPath = c.String(oldGroupFlag.Name)
+			if strings.HasPrefix(oldPath, "http://") || strings.HasPrefix(oldPath, "https://") {
+				// the daemon fetches the group and checks its hash
+				if oldHash == "" {
+					fatal("drand: the group-hash flag is required to fetch the old group from an url")
+				}
+			} else {
+				var oldGroup = new(key.Group)
+				if err := key.Load(oldPath, oldGroup); err != nil {
+					fatal("could not load drand from path: %v", err)
+				}
+			}
 		}
 
 		if isLeader {
@@ -95,14 +139,18 @@ func shareCmd(c *cli.Context) error {
 				offset = c.Int(beaconOffset.Name)
 			}
 			fmt.Println("Initiating the resharing as a leader")
-			groupP, shareErr = client.InitReshareLeader(nodes, thr, timeout, secret, oldPath, offset)
+			groupP, shareErr = waitLeaderSetup(client, func() (*control.GroupPacket, error) {
+				return client.InitReshareLeader(nodes, thr, timeout, secret, oldPath, oldHash, offset, allowed)
+			})
 		} else {
 			fmt.Println("Participating to the resharing")
-			groupP, shareErr = client.InitReshare(connectPeer, nodes, thr, timeout, secret, oldPath)
+			groupP, shareErr = waitGroupAcceptance(c, client, func() (*control.GroupPacket, error) {
+				return client.InitReshare(connectPeer, nodes, thr, timeout, secret, oldPath, oldHash)
+			})
 		}
 	}
 	if shareErr != nil {
-		fatal("error setting up the network: %v", err)
+		fatal("error setting up the network: %v", shareErr)
 	}
 	group, err := core.ProtoToGroup(groupP)
 	if err != nil {
@@ -112,6 +160,232 @@ func shareCmd(c *cli.Context) error {
 	return nil
 }
 
+// setupStatusPeriod is the time between two updates of the participants that
+// joined the setup, printed by the leader
+var setupStatusPeriod = 5 * time.Second
+
+// waitLeaderSetup runs the setup as a leader and prints which participants
+// joined so far, and which allowed participants did not, until the setup is
+// done.
+func waitLeaderSetup(client *net.ControlClient, setup func() (*control.GroupPacket, error)) (*control.GroupPacket, error) {
+	type result struct {
+		group *control.GroupPacket
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		group, err := setup()
+		done <- result{group, err}
+	}()
+	ticker := time.NewTicker(setupStatusPeriod)
+	defer ticker.Stop()
+	var last string
+	for {
+		select {
+		case r := <-done:
+			return r.group, r.err
+		case <-ticker.C:
+		}
+		status, err := client.SetupStatus()
+		if err != nil {
+			// the group may be created already
+			continue
+		}
+		msg := fmt.Sprintf("%d/%d participants joined", len(status.GetJoined()), status.GetExpected())
+		if missing := status.GetMissing(); len(missing) > 0 {
+			addrs := make([]string, len(missing))
+			for i, id := range missing {
+				addrs[i] = id.GetAddress()
+			}
+			msg += ", waiting for " + strings.Join(addrs, ", ")
+		}
+		if msg != last {
+			fmt.Println(msg)
+			last = msg
+		}
+	}
+}
+
+// cancelDKGCmd aborts the setup or the DKG run by the daemon
+func cancelDKGCmd(c *cli.Context) error {
+	client := controlClient(c)
+	if err := client.CancelDKG(); err != nil {
+		fatal("drand: can't cancel the setup: %v", err)
+	}
+	fmt.Println("Setup cancelled, the node can run a new one")
+	return nil
+}
+
+// cancelTransitionCmd cancels the transition to the new group scheduled after
+// a resharing
+func cancelTransitionCmd(c *cli.Context) error {
+	client := controlClient(c)
+	if err := client.CancelTransition(); err != nil {
+		fatal("drand: can't cancel the transition: %v", err)
+	}
+	fmt.Println("Transition cancelled, the node keeps running with the old group")
+	return nil
+}
+
+// pendingGroupPeriod is the time between two checks of whether the daemon
+// received the group from the leader
+var pendingGroupPeriod = 1 * time.Second
+
+// waitGroupAcceptance runs the setup as a participant and, once the daemon
+// received the group from the leader, shows it to the operator who must
+// accept it before the DKG starts. With the auto-accept-hash flag, the group
+// is accepted only if it has the given hash.
+func waitGroupAcceptance(c *cli.Context, client *net.ControlClient, setup func() (*control.GroupPacket, error)) (*control.GroupPacket, error) {
+	type result struct {
+		group *control.GroupPacket
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		group, err := setup()
+		done <- result{group, err}
+	}()
+	ticker := time.NewTicker(pendingGroupPeriod)
+	defer ticker.Stop()
+	var answered bool
+	for {
+		select {
+		case r := <-done:
+			return r.group, r.err
+		case <-ticker.C:
+		}
+		if answered {
+			continue
+		}
+		groupP, err := client.PendingGroup()
+		if err != nil {
+			// group not received yet
+			continue
+		}
+		group, err := core.ProtoToGroup(groupP)
+		if err != nil {
+			fatal("drand: invalid group received from the leader: %v", err)
+		}
+		hash, err := group.ChainHash()
+		if err != nil {
+			fatal("drand: can't compute group chain hash: %v", err)
+		}
+		printGroupSummary(group, hash)
+		accept := askAcceptGroup(c, hash)
+		if err := client.AcceptGroup(hash, accept); err != nil {
+			fatal("drand: can't answer the group: %v", err)
+		}
+		answered = true
+		if accept {
+			fmt.Println("Group accepted, waiting for the DKG to finish")
+		}
+	}
+}
+
+// printGroupSummary prints the information of the group a participant must
+// accept, along with its chain hash
+func printGroupSummary(group *key.Group, hash string) {
+	fmt.Println("Group received from the leader:")
+	for i, n := range group.Nodes {
+		fmt.Printf("  %d: %s (tls: %v) key %s\n", i, n.Address(), n.IsTLS(), key.PointToString(n.Key))
+		if n.Operator != nil {
+			fmt.Printf("     operator: %s\n", n.Operator)
+		}
+	}
+	fmt.Printf("Threshold: %d\n", group.Threshold)
+	fmt.Printf("Period: %s\n", group.Period)
+	fmt.Printf("Genesis time: %s\n", time.Unix(group.GenesisTime, 0))
+	if group.TransitionTime != 0 {
+		fmt.Printf("Transition time: %s\n", time.Unix(group.TransitionTime, 0))
+	}
+	fmt.Printf("Chain hash: %s\n", hash)
+}
+
+// askAcceptGroup returns whether the group of the given chain hash is accepted,
+// either because it matches the pinned hash or because the operator said so.
+func askAcceptGroup(c *cli.Context, hash string) bool {
+	if c.IsSet(autoAcceptHashFlag.Name) {
+		if c.String(autoAcceptHashFlag.Name) != hash {
+			fmt.Println("Group hash does not match the expected one, rejecting the group")
+			return false
+		}
+		fmt.Println("Group hash matches the expected one, accepting the group")
+		return true
+	}
+	fmt.Printf("Do you accept this group? [y/N]: ")
+	reader := bufio.NewReader(os.Stdin)
+	answer, err := reader.ReadString('\n')
+	if err != nil {
+		fatal("drand: error reading: %v", err)
+	}
+	return strings.ToLower(strings.TrimSpace(answer)) == "y"
+}
+
+// getAllowedKeys loads the identities allowed to join the setup, from files
+// listing nodes like a group file or from single public key files.
+func getAllowedKeys(c *cli.Context) []*control.Identity {
+	var ids []*control.Identity
+	for _, p := range c.StringSlice(allowFlag.Name) {
+		var list = struct {
+			Nodes []*key.PublicTOML
+		}{}
+		if _, err := toml.DecodeFile(p, &list); err != nil {
+			fatal("drand: can't read allowed participants from %s: %v", p, err)
+		}
+		if len(list.Nodes) == 0 {
+			single := new(key.PublicTOML)
+			if _, err := toml.DecodeFile(p, single); err != nil {
+				fatal("drand: can't read allowed participant from %s: %v", p, err)
+			}
+			list.Nodes = append(list.Nodes, single)
+		}
+		for _, ptoml := range list.Nodes {
+			id := new(key.Identity)
+			if err := id.FromTOML(ptoml); err != nil {
+				fatal("drand: invalid allowed participant in %s: %v", p, err)
+			}
+			buff, _ := id.Key.MarshalBinary()
+			ids = append(ids, &control.Identity{
+				Address: id.Address(),
+				Key:     buff,
+				Tls:     id.IsTLS(),
+			})
+		}
+	}
+	return ids
+}
+
+// shareGroupCmd runs the DKG with the group file given in argument, built
+// offline by all operators with the group command.
+func shareGroupCmd(c *cli.Context) error {
+	group := getGroup(c)
+	groupPath, err := filepath.Abs(c.Args().First())
+	if err != nil {
+		fatal("drand: invalid group path: %v", err)
+	}
+	hash, err := group.ChainHash()
+	if err != nil {
+		fatal("drand: can't compute group chain hash: %v", err)
+	}
+	var timeout = core.DefaultDKGTimeout
+	if c.IsSet(timeoutFlag.Name) {
+		timeout = c.String(timeoutFlag.Name)
+	}
+	fmt.Printf("Participating to the DKG with the group of chain hash %s\n", hash)
+	fmt.Println("The DKG starts once all nodes confirmed they hold the same group")
+	client := controlClient(c)
+	groupP, err := client.InitDKGFromGroup(groupPath, timeout, entropyInfoFromReader(c))
+	if err != nil {
+		fatal("error running the DKG: %v", err)
+	}
+	finalGroup, err := core.ProtoToGroup(groupP)
+	if err != nil {
+		fatal("error interpreting the group from protobuf: %v", err)
+	}
+	groupOut(c, finalGroup)
+	return nil
+}
+
 func getShare(c *cli.Context) error {
 	client := controlClient(c)
 	resp, err := client.Share()
@@ -142,6 +416,11 @@ func showGroupCmd(c *cli.Context) error {
 		return err
 	}
 	groupOut(c, group)
+	hash, err := group.ChainHash()
+	if err != nil {
+		fatal("drand: can't compute group chain hash: %v", err)
+	}
+	fmt.Printf("Chain hash: %s\n", hash)
 	return nil
 }
 
@@ -176,6 +455,37 @@ func showPublicCmd(c *cli.Context) error {
 	return nil
 }
 
+func showPeersCmd(c *cli.Context) error {
+	client := controlClient(c)
+	resp, err := client.Peers()
+	if err != nil {
+		fatal("drand: could not request drand.peers: %s", err)
+	}
+	printJSON(resp)
+	return nil
+}
+
+func showSyncCmd(c *cli.Context) error {
+	client := controlClient(c)
+	resp, err := client.SyncStatus()
+	if err != nil {
+		fatal("drand: could not request drand.sync_status: %s", err)
+	}
+	printJSON(resp)
+	return nil
+}
+
+func syncCmd(c *cli.Context) error {
+	client := controlClient(c)
+	resp, err := client.StartSync(c.Uint64(upToFlag.Name))
+	if err != nil {
+		fatal("drand: could not start the sync: %s", err)
+	}
+	fmt.Printf("drand: syncing up to round %d\n", resp.GetTarget())
+	printJSON(resp)
+	return nil
+}
+
 func showShareCmd(c *cli.Context) error {
 	client := controlClient(c)
 	resp, err := client.Share()
@@ -202,7 +512,17 @@ func controlClient(c *cli.Context) *net.ControlClient {
 	if err != nil {
 		fatal("drand: can't instantiate control client: %s", err)
 	}
-	return client
+	return client.ForBeacon(beaconID(c))
+}
+
+// beaconID returns the beacon ID of the chain given by the flag, or the one of
+// the default chain.
+func beaconID(c *cli.Context) string {
+	id := c.String(idFlag.Name)
+	if err := core.CheckBeaconID(id); err != nil {
+		fatal("drand: %v", err)
+	}
+	return id
 }
 
 func printJSON(j interface{}) {
@@ -0,0 +1,355 @@
+package core
+
+import (
+	"archive/tar"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/drand/drand/beacon"
+	"github.com/drand/drand/fs"
+	"github.com/drand/drand/key"
+	control "github.com/drand/drand/protobuf/drand"
+)
+
+// A backup of a chain is a tar archive holding the key and group folders of
+// the chain, and a snapshot of its beacon database if it runs.
+
+// BackupDBFile is the name of the beacon database in a backup
+const BackupDBFile = "db/" + beacon.BoltFileName
+
+// backupChunkSize is the size of the chunks of the backup streamed to the
+// control client
+const backupChunkSize = 64 * 1024
+
+// Backup streams an archive of the key material of the chain and a consistent
+// snapshot of its beacon database. The database can only be copied while in
+// use with the boltdb storage backend: the backup is refused with the other
+// backends keeping the beacons on disk.
+func (d *Drand) Backup(in *control.BackupRequest, stream control.Control_BackupServer) error {
+	d.state.Lock()
+	b := d.beacon
+	d.state.Unlock()
+	var store beacon.Store
+	if b != nil {
+		var err error
+		if store, err = backupStore(d.opts, d.beaconID, b.Store()); err != nil {
+			return err
+		}
+	}
+	w := bufio.NewWriterSize(&backupStream{stream}, backupChunkSize)
+	tw := tar.NewWriter(w)
+	folder := d.opts.BeaconFolder(d.beaconID)
+	for _, sub := range []string{key.KeyFolderName, key.GroupFolderName} {
+		if err := addBackupFolder(tw, folder, sub); err != nil {
+			return fmt.Errorf("drand: can't backup %s folder: %v", sub, err)
+		}
+	}
+	if store != nil {
+		err := beacon.Snapshot(store, func(size int64, db io.WriterTo) error {
+			header := &tar.Header{
+				Name:    BackupDBFile,
+				Mode:    0600,
+				Size:    size,
+				ModTime: time.Now(),
+			}
+			if err := tw.WriteHeader(header); err != nil {
+				return err
+			}
+			_, err := db.WriteTo(tw)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("drand: can't backup beacon database: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	d.log.Info("backup", "done", "with_db", store != nil)
+	return w.Flush()
+}
+
+// backupStore returns the store of the chain to backup, or nil if its backend
+// keeps nothing on disk: the chain is then synced again from the other nodes
+// at the next start. The store must be able to copy its database while in use.
+func backupStore(c *Config, beaconID string, store beacon.Store) (beacon.Store, error) {
+	backend, err := beacon.GetStoreBackend(c.dbBackend)
+	if err != nil {
+		return nil, err
+	}
+	if backend.Path(c.BeaconDBFolder(beaconID)) == "" {
+		return nil, nil
+	}
+	if !beacon.CanSnapshot(store) {
+		return nil, fmt.Errorf("drand: can't backup the %s beacon database: %v", c.dbBackend, beacon.ErrNoSnapshot)
+	}
+	return store, nil
+}
+
+// backupStream sends the bytes written to it as chunks of the backup
+type backupStream struct {
+	stream control.Control_BackupServer
+}
+
+func (b *backupStream) Write(p []byte) (int, error) {
+	data := make([]byte, len(p))
+	copy(data, p)
+	if err := b.stream.Send(&control.BackupChunk{Data: data}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// addBackupFolder adds the files of the given subfolder of the base folder to
+// the archive, under their path relative to the base folder.
+func addBackupFolder(tw *tar.Writer, base, sub string) error {
+	return filepath.Walk(path.Join(base, sub), func(p string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			return nil
+		}
+		name, err := filepath.Rel(base, p)
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(name)
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+}
+
+// RestoreBackup installs the backup read from r as the chain of the given ID,
+// replacing its key and group folders and its beacon database. The backup must
+// hold a key pair, and if the node already has one, it must be the same: a
+// backup can't change the identity of a node. The beacons of the backup are
+// copied in a database of the storage backend of the config. The current files
+// are only deleted once the restored ones are in place. The daemon must be
+// stopped.
+func RestoreBackup(c *Config, beaconID string, r io.Reader) error {
+	backend, err := beacon.GetStoreBackend(c.dbBackend)
+	if err != nil {
+		return err
+	}
+	folder := c.BeaconFolder(beaconID)
+	if fs.CreateSecureFolder(folder) == "" {
+		return fmt.Errorf("drand: can't create folder %s", folder)
+	}
+	tmp, err := ioutil.TempDir(folder, "restore")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	if err := extractBackup(r, tmp); err != nil {
+		return fmt.Errorf("drand: invalid backup: %v", err)
+	}
+	if err := checkBackup(tmp, folder); err != nil {
+		return err
+	}
+
+	// the database of the backup is copied before touching the node, and
+	// dropped with the memory backend since nothing is kept on disk
+	dbFolder := c.BeaconDBFolder(beaconID)
+	db := backend.Path(dbFolder)
+	var dbTmp string
+	if exists, _ := fs.Exists(path.Join(tmp, BackupDBFile)); exists && db != "" {
+		if fs.CreateSecureFolder(dbFolder) == "" {
+			return fmt.Errorf("drand: can't create folder %s", dbFolder)
+		}
+		if dbTmp, err = ioutil.TempDir(dbFolder, "restore"); err != nil {
+			return err
+		}
+		defer os.RemoveAll(dbTmp)
+		from := path.Join(tmp, path.Dir(BackupDBFile))
+		if err := copyBeacons(from, dbTmp, backend, c.storeOpts...); err != nil {
+			return fmt.Errorf("drand: can't restore the beacon database: %v", err)
+		}
+	}
+
+	aside, err := ioutil.TempDir(folder, "replaced")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(aside)
+	undo, err := replaceFiles(tmp, folder, aside, key.KeyFolderName, key.GroupFolderName)
+	if err != nil {
+		return err
+	}
+	if dbTmp == "" {
+		return nil
+	}
+	dbAside, err := ioutil.TempDir(dbFolder, "replaced")
+	if err != nil {
+		undo()
+		return err
+	}
+	defer os.RemoveAll(dbAside)
+	if _, err := replaceFiles(dbTmp, dbFolder, dbAside, filepath.Base(db)); err != nil {
+		undo()
+		return err
+	}
+	return nil
+}
+
+// copyBeacons copies the beacons of the boltdb database of the from folder in
+// a database of the backend created in the to folder.
+func copyBeacons(from, to string, backend beacon.StoreBackend, opts ...beacon.StoreOption) error {
+	src, err := beacon.NewBoltStore(from, nil)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := backend.Open(to, opts...)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+	src.Cursor(func(c beacon.Cursor) {
+		for b := c.First(); b != nil && err == nil; b = c.Next() {
+			err = dst.Put(b)
+		}
+	})
+	return err
+}
+
+// replaceFiles moves the given files or folders of src to dst, once the current
+// ones are moved to the aside folder. If one can't be moved, all the files are
+// moved back where they were. Otherwise, it returns a function doing so.
+func replaceFiles(src, dst, aside string, names ...string) (func(), error) {
+	var replaced, added []string
+	undo := func() {
+		for _, name := range added {
+			os.Rename(path.Join(dst, name), path.Join(src, name))
+		}
+		for _, name := range replaced {
+			os.Rename(path.Join(aside, name), path.Join(dst, name))
+		}
+	}
+	for _, name := range names {
+		if exists, _ := fs.Exists(path.Join(dst, name)); !exists {
+			continue
+		}
+		if err := os.Rename(path.Join(dst, name), path.Join(aside, name)); err != nil {
+			undo()
+			return nil, err
+		}
+		replaced = append(replaced, name)
+	}
+	for _, name := range names {
+		if exists, _ := fs.Exists(path.Join(src, name)); !exists {
+			continue
+		}
+		if err := os.Rename(path.Join(src, name), path.Join(dst, name)); err != nil {
+			undo()
+			return nil, err
+		}
+		added = append(added, name)
+	}
+	return undo, nil
+}
+
+// extractBackup extracts the archive in the given folder, refusing any file
+// out of the folders of a backup.
+func extractBackup(r io.Reader, folder string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		name := path.Clean(header.Name)
+		if name != BackupDBFile && name != key.KeyFolderName && name != key.GroupFolderName &&
+			!strings.HasPrefix(name, key.KeyFolderName+"/") &&
+			!strings.HasPrefix(name, key.GroupFolderName+"/") {
+			return fmt.Errorf("unexpected file %s", header.Name)
+		}
+		target := path.Join(folder, name)
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(path.Dir(target), 0700); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, tr)
+			f.Close()
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unexpected file type for %s", header.Name)
+		}
+	}
+}
+
+// checkBackup checks that the backup extracted in the given folder holds the
+// identity of the node whose configuration is in the current folder.
+func checkBackup(backup, current string) error {
+	if exists, _ := fs.Exists(key.PrivateKeyFile(backup)); !exists {
+		return errors.New("drand: the backup holds no private key")
+	}
+	identity := new(key.Identity)
+	if err := key.Load(key.PublicKeyFile(backup), identity); err != nil {
+		return fmt.Errorf("drand: the backup holds no valid public key: %v", err)
+	}
+	pair, err := key.NewFileStore(backup).LoadKeyPair()
+	switch {
+	case err == key.ErrEncrypted:
+		// the private key can only be checked with its passphrase
+	case err != nil:
+		return fmt.Errorf("drand: the backup holds no valid private key: %v", err)
+	case !key.KeyGroup.Point().Mul(pair.Key, nil).Equal(identity.Key):
+		return errors.New("drand: the private key of the backup doesn't match its public key")
+	}
+	if exists, _ := fs.Exists(key.PublicKeyFile(current)); exists {
+		nodeIdentity := new(key.Identity)
+		if err := key.Load(key.PublicKeyFile(current), nodeIdentity); err != nil {
+			return fmt.Errorf("drand: can't load the public key of the node: %v", err)
+		}
+		if !nodeIdentity.Key.Equal(identity.Key) || nodeIdentity.Address() != identity.Address() {
+			return fmt.Errorf("drand: the backup is the one of %s, not of this node %s", identity.Address(), nodeIdentity.Address())
+		}
+	}
+	group, err := key.NewFileStore(backup).LoadGroup()
+	if err == nil && !group.Contains(identity) {
+		return errors.New("drand: the group of the backup doesn't contain its key")
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package core
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/drand/drand/beacon"
+	"github.com/drand/drand/key"
+	"github.com/drand/drand/net"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrandBackupRestore(t *testing.T) {
+	n := 4
+	thr := key.DefaultThreshold(n)
+	p := 1 * time.Second
+	dt := NewDrandTest(t, n, thr, p)
+	defer dt.Cleanup()
+	dir, err := ioutil.TempDir("", "drand-backup")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	group := dt.RunDKG()
+	dt.MoveToTime(group.GenesisTime)
+	for i := 0; i < 3; i++ {
+		dt.MoveTime(group.Period)
+	}
+
+	// the test nodes keep their keys in memory, so put the ones of the root
+	// on disk
+	root := dt.drands[dt.ids[0]]
+	root.opts.configFolder = path.Join(dir, "root")
+	store := key.NewFileStore(root.opts.configFolder)
+	require.NoError(t, store.SaveKeyPair(root.priv))
+	require.NoError(t, store.SaveGroup(group))
+	last, err := root.beacon.Store().Last()
+	require.NoError(t, err)
+
+	client, err := net.NewControlClient(root.opts.controlPort)
+	require.NoError(t, err)
+	var backup bytes.Buffer
+	require.NoError(t, client.Backup(&backup))
+
+	// restoring on a new machine
+	conf := NewConfig(WithConfigFolder(path.Join(dir, "restored")))
+	require.NoError(t, RestoreBackup(conf, DefaultBeaconID, bytes.NewReader(backup.Bytes())))
+	pair, err := key.NewFileStore(conf.ConfigFolder()).LoadKeyPair()
+	require.NoError(t, err)
+	require.True(t, root.priv.Public.Equal(pair.Public))
+	db, err := beacon.NewBoltStore(conf.DBFolder(), nil)
+	require.NoError(t, err)
+	restoredLast, err := db.Last()
+	require.NoError(t, err)
+	require.True(t, restoredLast.Round >= last.Round)
+	db.Close()
+
+	// restoring again on the same node
+	require.NoError(t, RestoreBackup(conf, DefaultBeaconID, bytes.NewReader(backup.Bytes())))
+
+	// a backup whose database can't be read leaves the node as it was
+	corrupted := replaceBackupFile(t, backup.Bytes(), BackupDBFile, []byte("not a database"))
+	require.Error(t, RestoreBackup(conf, DefaultBeaconID, bytes.NewReader(corrupted)))
+	pair, err = key.NewFileStore(conf.ConfigFolder()).LoadKeyPair()
+	require.NoError(t, err)
+	require.True(t, root.priv.Public.Equal(pair.Public))
+	_, err = key.NewFileStore(conf.ConfigFolder()).LoadGroup()
+	require.NoError(t, err)
+
+	// the beacons are restored in the storage backend of the node
+	levelConf := NewConfig(WithConfigFolder(path.Join(dir, "leveldb")), WithDbBackend(beacon.LevelDBBackend))
+	require.NoError(t, RestoreBackup(levelConf, DefaultBeaconID, bytes.NewReader(backup.Bytes())))
+	db, err = beacon.NewLevelDBStore(levelConf.DBFolder(), nil)
+	require.NoError(t, err)
+	restoredLast, err = db.Last()
+	require.NoError(t, err)
+	require.True(t, restoredLast.Round >= last.Round)
+	db.Close()
+
+	// the backup of another node is refused
+	other := NewConfig(WithConfigFolder(path.Join(dir, "other")))
+	require.NoError(t, key.NewFileStore(other.ConfigFolder()).SaveKeyPair(key.NewKeyPair("127.0.0.1:1234")))
+	require.Error(t, RestoreBackup(other, DefaultBeaconID, bytes.NewReader(backup.Bytes())))
+}
+
+func TestBackupStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "drand-backup")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	conf := NewConfig(WithConfigFolder(dir))
+	store, err := beacon.NewBoltStore(dir, nil)
+	require.NoError(t, err)
+	defer store.Close()
+	backup, err := backupStore(conf, DefaultBeaconID, store)
+	require.NoError(t, err)
+	require.Equal(t, store, backup)
+
+	// leveldb can't copy its database while in use
+	conf = NewConfig(WithConfigFolder(dir), WithDbBackend(beacon.LevelDBBackend))
+	store, err = beacon.NewLevelDBStore(dir, nil)
+	require.NoError(t, err)
+	defer store.Close()
+	_, err = backupStore(conf, DefaultBeaconID, store)
+	require.Error(t, err)
+
+	// the memory backend has nothing to backup
+	conf = NewConfig(WithConfigFolder(dir), WithDbBackend(beacon.MemoryBackend))
+	backup, err = backupStore(conf, DefaultBeaconID, beacon.NewMemoryStore())
+	require.NoError(t, err)
+	require.Nil(t, backup)
+}
+
+func TestReplaceFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "drand-replace")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	src, dst, aside := path.Join(dir, "src"), path.Join(dir, "dst"), path.Join(dir, "aside")
+	for _, folder := range []string{src, dst, aside} {
+		require.NoError(t, os.MkdirAll(folder, 0700))
+	}
+	require.NoError(t, ioutil.WriteFile(path.Join(src, "a"), []byte("new"), 0600))
+	require.NoError(t, ioutil.WriteFile(path.Join(dst, "a"), []byte("old"), 0600))
+	// the nested file can't be moved in, its folder doesn't exist in dst
+	require.NoError(t, os.MkdirAll(path.Join(src, "b"), 0700))
+	require.NoError(t, ioutil.WriteFile(path.Join(src, "b", "c"), []byte("new"), 0600))
+
+	_, err = replaceFiles(src, dst, aside, "a", "b/c")
+	require.Error(t, err)
+	content, err := ioutil.ReadFile(path.Join(dst, "a"))
+	require.NoError(t, err)
+	require.Equal(t, "old", string(content))
+
+	undo, err := replaceFiles(src, dst, aside, "a")
+	require.NoError(t, err)
+	content, err = ioutil.ReadFile(path.Join(dst, "a"))
+	require.NoError(t, err)
+	require.Equal(t, "new", string(content))
+	content, err = ioutil.ReadFile(path.Join(aside, "a"))
+	require.NoError(t, err)
+	require.Equal(t, "old", string(content))
+	undo()
+	content, err = ioutil.ReadFile(path.Join(dst, "a"))
+	require.NoError(t, err)
+	require.Equal(t, "old", string(content))
+}
+
+// replaceBackupFile returns the backup with the content of the given file
+// replaced
+func replaceBackupFile(t *testing.T, backup []byte, name string, content []byte) []byte {
+	var out bytes.Buffer
+	tr := tar.NewReader(bytes.NewReader(backup))
+	tw := tar.NewWriter(&out)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data, err := ioutil.ReadAll(tr)
+		require.NoError(t, err)
+		if header.Name == name {
+			data = content
+			header.Size = int64(len(content))
+		}
+		require.NoError(t, tw.WriteHeader(header))
+		_, err = tw.Write(data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return out.Bytes()
+}
@@ -104,6 +104,12 @@ func (c *Client) Group(addr string, secure bool) (*drand.GroupPacket, error) {
 	return c.client.Group(context.TODO(), &peerAddr{addr, secure}, &drand.GroupRequest{})
 }
 
+// GroupHistory returns the groups that produced the chain of the node at this
+// address, with the first round each of them produced.
+func (c *Client) GroupHistory(addr string, secure bool) (*drand.GroupHistoryResponse, error) {
+	return c.client.GroupHistory(context.TODO(), &peerAddr{addr, secure}, &drand.GroupHistoryRequest{})
+}
+
 func (c *Client) verify(public kyber.Point, resp *drand.PublicRandResponse) error {
 	prevSig := resp.GetPreviousSignature()
 	round := resp.GetRound()
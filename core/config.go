@@ -26,7 +26,8 @@ type Config struct {
 	grpcOpts     []grpc.DialOption
 	callOpts     []grpc.CallOption
 	dkgTimeout   time.Duration
-	boltOpts     *bolt.Options
+	storeOpts    []beacon.StoreOption
+	dbBackend    string
 	beaconCbs    []func(*beacon.Beacon)
 	dkgCallback  func(*key.Share)
 	insecure     bool
@@ -36,6 +37,10 @@ type Config struct {
 	logger       log.Logger
 	clock        clock.Clock
 	wait         time.Duration
+	signerSocket string
+	syncPeers    int
+	syncMax      uint64
+	syncPerPeer  int
 }
 
 // NewConfig returns the config to pass to drand with the default options set
@@ -56,6 +61,7 @@ func NewConfig(opts ...ConfigOption) *Config {
 		logger:      log.DefaultLogger,
 		clock:       clock.NewRealClock(),
 		wait:        DefaultWaitTime,
+		dbBackend:   beacon.DefaultBackend,
 	}
 	d.dbFolder = path.Join(d.configFolder, DefaultDbFolder)
 	for i := range opts {
@@ -75,6 +81,39 @@ func (d *Config) DBFolder() string {
 	return d.dbFolder
 }
 
+// BeaconFolder returns the folder holding the key material of the chain of
+// the given ID.
+func (d *Config) BeaconFolder(beaconID string) string {
+	if beaconID == DefaultBeaconID {
+		return d.configFolder
+	}
+	return path.Join(d.configFolder, MultiBeaconFolder, beaconID)
+}
+
+// DbBackend returns the name of the storage backend keeping the random beacons
+func (d *Config) DbBackend() string {
+	return d.dbBackend
+}
+
+// openBeaconStore opens the database of the chain of the given ID with the
+// storage backend of the config.
+func (d *Config) openBeaconStore(beaconID string) (beacon.Store, error) {
+	backend, err := beacon.GetStoreBackend(d.dbBackend)
+	if err != nil {
+		return nil, err
+	}
+	return backend.Open(d.BeaconDBFolder(beaconID), d.storeOpts...)
+}
+
+// BeaconDBFolder returns the folder of the database of the chain of the given
+// ID.
+func (d *Config) BeaconDBFolder(beaconID string) string {
+	if beaconID == DefaultBeaconID {
+		return d.dbFolder
+	}
+	return path.Join(d.dbFolder, beaconID)
+}
+
 // Certs returns all custom certs currently being trusted by drand.
 func (d *Config) Certs() *net.CertManager {
 	return d.certmanager
@@ -137,7 +176,33 @@ func WithDkgTimeout(t time.Duration) ConfigOption {
 // WithBoltOptions applies boltdb specific options when storing random beacons.
 func WithBoltOptions(opts *bolt.Options) ConfigOption {
 	return func(d *Config) {
-		d.boltOpts = opts
+		d.storeOpts = append(d.storeOpts, beacon.WithBoltOptions(opts))
+	}
+}
+
+// WithDbBackend sets the name of the storage backend keeping the random
+// beacons, among the ones registered in the beacon package.
+func WithDbBackend(name string) ConfigOption {
+	return func(d *Config) {
+		d.dbBackend = name
+	}
+}
+
+// WithParallelSync sets the number of peers the node syncs the chain from at
+// once when it is far behind. By default, it syncs from one peer at a time.
+func WithParallelSync(peers int) ConfigOption {
+	return func(d *Config) {
+		d.syncPeers = peers
+	}
+}
+
+// WithSyncLimits sets the maximum number of beacons the node sends for a
+// single sync request, and the maximum number of sync requests it serves at
+// once to a single peer. Zero keeps the default of the beacon package.
+func WithSyncLimits(maxCount uint64, maxPerPeer int) ConfigOption {
+	return func(d *Config) {
+		d.syncMax = maxCount
+		d.syncPerPeer = maxPerPeer
 	}
 }
 
@@ -181,6 +246,20 @@ func WithInsecure() ConfigOption {
 	}
 }
 
+// WithRemoteSigner makes drand ask the partial signatures of its share to the
+// signer process listening on the Unix socket at the given path. The share
+// then does not need to be in the configuration folder.
+func WithRemoteSigner(socket string) ConfigOption {
+	return func(d *Config) {
+		d.signerSocket = socket
+	}
+}
+
+// RemoteSigner returns the path of the socket of the remote signer, if any
+func (d *Config) RemoteSigner() string {
+	return d.signerSocket
+}
+
 // WithTLS registers the certificates and private key path so drand can accept
 // and issue connections using TLS.
 func WithTLS(certPath, keyPath string) ConfigOption {
@@ -22,6 +22,14 @@ func DefaultConfigFolder() string {
 // default it is relative to the DefaultConfigFolder path.
 const DefaultDbFolder = "db"
 
+// DefaultBeaconID is the ID of the default chain of a node, whose key
+// material and database live directly in the configuration folder.
+const DefaultBeaconID = ""
+
+// MultiBeaconFolder is the name of the folder, relative to the configuration
+// folder, holding one subfolder of key material per additional chain.
+const MultiBeaconFolder = "multibeacon"
+
 // DefaultBeaconPeriod is the period in which the beacon logic creates new
 // random beacon.
 const DefaultBeaconPeriod time.Duration = 1 * time.Minute
@@ -59,6 +67,11 @@ var MaxWaitPrepareDKG = 24 * 7 * 2 * time.Hour
 // process, we can afford to wait here.
 var DefaultSyncTime = 5 * time.Second
 
+// DefaultConfirmGroupPeriod is the time a node waits before sending again the
+// hash of its group to the members that have not confirmed it yet, during a
+// setup without leader.
+var DefaultConfirmGroupPeriod = 1 * time.Second
+
 // DefaultPushDKGTimeout is the time the leader waits for when pushing the
 // packet
 var DefaultPushDKGTimeout = 1 * time.Minute
@@ -75,6 +88,11 @@ var DefaultGenesisOffset = 2 * time.Minute
 // has to keep the same period.
 var DefaultResharingOffset = 30 * time.Second
 
+// DefaultTransitionCheckRounds is the number of rounds the nodes of the old
+// group wait for the new group to produce its first beacon after a resharing.
+// If it does not, they keep running with the old group.
+var DefaultTransitionCheckRounds = 3
+
 // Keep the most recents beacons
 var DefaultBeaconCacheLength = 10
 
@@ -51,26 +51,24 @@ func ProtoToGroup(g *proto.GroupPacket) (*key.Group, error) {
 	if len(dist.Coefficients) > 0 {
 		group.PublicKey = dist
 	}
+	group.TransitionSignature = g.GetTransitionSignature()
 	return group, nil
 }
 
-func groupToProto(g *key.Group) *proto.GroupPacket {
-	var out = new(proto.GroupPacket)
-	var ids = make([]*proto.Identity, len(g.Nodes))
-	for i, id := range g.Nodes {
-		key, _ := id.Key.MarshalBinary()
-		ids[i] = &proto.Identity{
-			Address: id.Address(),
-			Tls:     id.IsTLS(),
-			Key:     key,
-		}
+func groupToProto(g *key.Group) (*proto.GroupPacket, error) {
+	chainHash, err := g.ChainHash()
+	if err != nil {
+		return nil, err
 	}
-	out.Nodes = ids
+	var out = new(proto.GroupPacket)
+	out.Nodes = identitiesToProto(g.Nodes)
 	out.Period = uint32(g.Period.Seconds())
 	out.Threshold = uint32(g.Threshold)
 	out.GenesisTime = uint64(g.GenesisTime)
 	out.TransitionTime = uint64(g.TransitionTime)
 	out.GenesisSeed = g.GetGenesisSeed()
+	out.ChainHash = chainHash
+	out.TransitionSignature = g.TransitionSignature
 	if g.PublicKey != nil {
 		var coeffs = make([][]byte, len(g.PublicKey.Coefficients))
 		for i, c := range g.PublicKey.Coefficients {
@@ -79,7 +77,22 @@ func groupToProto(g *key.Group) *proto.GroupPacket {
 		}
 		out.DistKey = coeffs
 	}
-	return out
+	return out, nil
+}
+
+func identitiesToProto(list []*key.Identity) []*proto.Identity {
+	var ids = make([]*proto.Identity, len(list))
+	for i, id := range list {
+		key, _ := id.Key.MarshalBinary()
+		ids[i] = &proto.Identity{
+			Address:   id.Address(),
+			Tls:       id.IsTLS(),
+			Key:       key,
+			Signature: id.Signature,
+			Operator:  operatorToProto(id.Operator),
+		}
+	}
+	return ids
 }
 
 func protoToIdentity(n *proto.Identity) (*key.Identity, error) {
@@ -91,11 +104,38 @@ func protoToIdentity(n *proto.Identity) (*key.Identity, error) {
 	if err := public.UnmarshalBinary(n.GetKey()); err != nil {
 		return nil, err
 	}
-	return &key.Identity{
-		Addr: n.GetAddress(),
-		TLS:  n.Tls,
-		Key:  public,
-	}, nil
+	id := &key.Identity{
+		Addr:      n.GetAddress(),
+		TLS:       n.Tls,
+		Key:       public,
+		Signature: n.GetSignature(),
+	}
+	if o := n.GetOperator(); o != nil {
+		id.Operator = &key.Operator{
+			Name:      o.GetName(),
+			Contact:   o.GetContact(),
+			Region:    o.GetRegion(),
+			Website:   o.GetWebsite(),
+			Signature: o.GetSignature(),
+		}
+	}
+	if err := id.ValidOperator(); err != nil {
+		return nil, fmt.Errorf("node %s: %v", id.Address(), err)
+	}
+	return id, nil
+}
+
+func operatorToProto(o *key.Operator) *proto.Operator {
+	if o == nil {
+		return nil
+	}
+	return &proto.Operator{
+		Name:      o.Name,
+		Contact:   o.Contact,
+		Region:    o.Region,
+		Website:   o.Website,
+		Signature: o.Signature,
+	}
 }
 
 func beaconToProto(b *beacon.Beacon) *drand.PublicRandResponse {
@@ -4,18 +4,31 @@ import (
 	"testing"
 	"time"
 
+	"github.com/drand/drand/key"
 	"github.com/drand/drand/test"
 	"github.com/stretchr/testify/require"
 )
 
 func TestConvertGroup(t *testing.T) {
-	_, group := test.BatchIdentities(5)
+	privs, group := test.BatchIdentities(5)
+	for _, priv := range privs {
+		if priv.Public == group.Nodes[0] {
+			require.NoError(t, priv.SignOperator(&key.Operator{Name: "League", Region: "eu-west"}))
+		}
+	}
 	group.Period = 5 * time.Second
 	group.TransitionTime = time.Now().Unix()
 	group.GenesisTime = time.Now().Unix()
 
-	proto := groupToProto(group)
+	proto, err := groupToProto(group)
+	require.NoError(t, err)
 	received, err := ProtoToGroup(proto)
 	require.NoError(t, err)
 	require.True(t, received.Equal(group))
+	require.Equal(t, "League", received.Nodes[0].Operator.Name)
+
+	// the operator metadata can't be forged
+	proto.Nodes[0].Operator.Region = "us-east"
+	_, err = ProtoToGroup(proto)
+	require.Error(t, err)
 }
@@ -0,0 +1,228 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/drand/drand/beacon"
+	"github.com/drand/drand/key"
+	"github.com/drand/drand/log"
+	"github.com/drand/drand/net"
+)
+
+// Daemon runs the beacon chains of a node. Each chain is a Drand instance with
+// its own key material, group, share and database, identified by its beacon
+// ID. The chains share the listeners of the daemon, which route the incoming
+// requests to the chain of the beacon ID they carry.
+type Daemon struct {
+	opts    *Config
+	gateway net.Gateway
+	control net.ControlListener
+	log     log.Logger
+
+	state   sync.Mutex
+	beacons map[string]*Drand
+	// starting holds the IDs of the chains being added to the daemon
+	starting map[string]bool
+	exitCh   chan bool
+}
+
+var validBeaconID = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// reservedBeaconIDs can't name a chain since they would collide with the
+// routes of the REST API of the default chain
+var reservedBeaconIDs = []string{"public", "private", "info"}
+
+// CheckBeaconID returns an error if the given beacon ID can't name a chain.
+// The ID is used as a folder name, so only letters, digits, dashes and
+// underscores are allowed. The empty ID is the one of the default chain.
+func CheckBeaconID(beaconID string) error {
+	for _, reserved := range reservedBeaconIDs {
+		if strings.EqualFold(beaconID, reserved) {
+			return fmt.Errorf("invalid beacon id %q: the name is reserved", beaconID)
+		}
+	}
+	if beaconID == DefaultBeaconID || validBeaconID.MatchString(beaconID) {
+		return nil
+	}
+	return fmt.Errorf("invalid beacon id %q: only letters, digits, '-' and '_' are allowed", beaconID)
+}
+
+// StoredBeaconIDs returns the sorted IDs of the chains that have a key pair in
+// the configuration folder: the default chain, whose key pair is directly in
+// the folder, and the chains in the MultiBeaconFolder subfolder.
+func StoredBeaconIDs(c *Config) ([]string, error) {
+	var ids []string
+	if _, err := os.Stat(key.PrivateKeyFile(c.BeaconFolder(DefaultBeaconID))); err == nil {
+		ids = append(ids, DefaultBeaconID)
+	}
+	entries, err := ioutil.ReadDir(path.Join(c.ConfigFolder(), MultiBeaconFolder))
+	if os.IsNotExist(err) {
+		return ids, nil
+	} else if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if !e.IsDir() || CheckBeaconID(e.Name()) != nil {
+			continue
+		}
+		if _, err := os.Stat(key.PrivateKeyFile(c.BeaconFolder(e.Name()))); err == nil {
+			ids = append(ids, e.Name())
+		}
+	}
+	return ids, nil
+}
+
+// NewDaemon returns a daemon listening on the given public address of the
+// node, unless the config sets another listening address. It runs no chain
+// until one is added with NewBeacon or LoadBeacon.
+func NewDaemon(c *Config, addr string) (*Daemon, error) {
+	if c.insecure == false && (c.certPath == "" || c.keyPath == "") {
+		return nil, errors.New("config: need to set WithInsecure if no certificate and private key path given")
+	}
+	if _, err := beacon.GetStoreBackend(c.dbBackend); err != nil {
+		return nil, fmt.Errorf("config: %v", err)
+	}
+	dd := &Daemon{
+		opts:     c,
+		log:      c.Logger(),
+		beacons:  make(map[string]*Drand),
+		starting: make(map[string]bool),
+		exitCh:   make(chan bool, 1),
+	}
+	a := c.ListenAddress(addr)
+	if c.insecure {
+		dd.log.Info("network", "tls-disable")
+		dd.gateway = net.NewGrpcGatewayInsecure(a, dd, c.grpcOpts...)
+	} else {
+		dd.log.Info("network", "tls-enabled")
+		dd.gateway = net.NewGrpcGatewayFromCertManager(a, c.certPath, c.keyPath, c.certmanager, dd, c.grpcOpts...)
+	}
+	p := c.ControlPort()
+	dd.control = net.NewTCPGrpcControlListener(dd, p)
+	go dd.control.Start()
+	dd.log.Info("network_listen", a, "control_port", c.ControlPort())
+	dd.gateway.StartAll()
+	return dd, nil
+}
+
+// NewBeacon adds the chain of the given ID, whose key pair is in the given
+// store, to the daemon. The chain waits for a DKG to start its beacon.
+func (dd *Daemon) NewBeacon(beaconID string, s key.Store) (*Drand, error) {
+	d, err := dd.initBeacon(beaconID, s)
+	if err != nil {
+		return nil, err
+	}
+	dd.addBeacon(d)
+	return d, nil
+}
+
+// LoadBeacon adds the chain of the given ID to the daemon, restoring it from
+// the given store like LoadDrand.
+func (dd *Daemon) LoadBeacon(beaconID string, s key.Store) (*Drand, error) {
+	d, err := dd.initBeacon(beaconID, s)
+	if err != nil {
+		return nil, err
+	}
+	// the chain must be reachable to resume a DKG
+	dd.addBeacon(d)
+	if err := d.load(); err != nil {
+		dd.removeBeacon(beaconID)
+		return nil, err
+	}
+	return d, nil
+}
+
+// initBeacon reserves the beacon ID until the chain is added with addBeacon,
+// so two calls can't create the same chain.
+func (dd *Daemon) initBeacon(beaconID string, s key.Store) (*Drand, error) {
+	if err := CheckBeaconID(beaconID); err != nil {
+		return nil, fmt.Errorf("drand: %v", err)
+	}
+	dd.state.Lock()
+	_, exists := dd.beacons[beaconID]
+	if exists || dd.starting[beaconID] {
+		dd.state.Unlock()
+		return nil, fmt.Errorf("drand: beacon id %q already running", beaconID)
+	}
+	dd.starting[beaconID] = true
+	dd.state.Unlock()
+	d, err := initDrand(dd, beaconID, s)
+	if err != nil {
+		dd.state.Lock()
+		delete(dd.starting, beaconID)
+		dd.state.Unlock()
+		return nil, err
+	}
+	return d, nil
+}
+
+func (dd *Daemon) addBeacon(d *Drand) {
+	dd.state.Lock()
+	defer dd.state.Unlock()
+	delete(dd.starting, d.beaconID)
+	dd.beacons[d.beaconID] = d
+}
+
+func (dd *Daemon) removeBeacon(beaconID string) {
+	dd.state.Lock()
+	defer dd.state.Unlock()
+	delete(dd.beacons, beaconID)
+}
+
+// Beacon returns the chain of the given ID
+func (dd *Daemon) Beacon(beaconID string) (*Drand, error) {
+	dd.state.Lock()
+	defer dd.state.Unlock()
+	d, ok := dd.beacons[beaconID]
+	if !ok {
+		if beaconID == DefaultBeaconID {
+			return nil, errors.New("drand: no default beacon on this node, the beacon id must be given")
+		}
+		return nil, fmt.Errorf("drand: unknown beacon id %q", beaconID)
+	}
+	return d, nil
+}
+
+// BeaconIDs returns the sorted IDs of the chains run by the daemon
+func (dd *Daemon) BeaconIDs() []string {
+	dd.state.Lock()
+	defer dd.state.Unlock()
+	ids := make([]string, 0, len(dd.beacons))
+	for id := range dd.beacons {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Stop stops the beacons of all the chains and the listeners of the daemon.
+func (dd *Daemon) Stop() {
+	dd.state.Lock()
+	beacons := make([]*Drand, 0, len(dd.beacons))
+	for _, d := range dd.beacons {
+		beacons = append(beacons, d)
+	}
+	dd.state.Unlock()
+	for _, d := range beacons {
+		d.suspendDKG()
+		d.StopBeacon()
+	}
+	dd.state.Lock()
+	dd.gateway.StopAll()
+	dd.control.Stop()
+	dd.state.Unlock()
+	dd.exitCh <- true
+}
+
+// WaitExit returns a channel that signals when the daemon stops its operations
+func (dd *Daemon) WaitExit() chan bool {
+	return dd.exitCh
+}
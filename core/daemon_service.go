@@ -0,0 +1,314 @@
+package core
+
+import (
+	"context"
+
+	"github.com/drand/drand/net"
+	"github.com/drand/drand/protobuf/drand"
+)
+
+// The daemon serves the public, protocol and control services by routing each
+// request to the chain of the beacon ID it carries. Protocol messages carry it
+// in their metadata, other requests in their own field.
+
+var _ net.Service = (*Daemon)(nil)
+
+// FreshDKG routes the DKG packet to its chain
+func (dd *Daemon) FreshDKG(c context.Context, in *drand.DKGPacket) (*drand.Empty, error) {
+	d, err := dd.Beacon(in.GetMetadata().GetBeaconID())
+	if err != nil {
+		return nil, err
+	}
+	return d.FreshDKG(c, in)
+}
+
+// ReshareDKG routes the resharing packet to its chain
+func (dd *Daemon) ReshareDKG(c context.Context, in *drand.ResharePacket) (*drand.Empty, error) {
+	d, err := dd.Beacon(in.GetMetadata().GetBeaconID())
+	if err != nil {
+		return nil, err
+	}
+	return d.ReshareDKG(c, in)
+}
+
+// AbortSetup routes the abort notification to its chain
+func (dd *Daemon) AbortSetup(c context.Context, in *drand.AbortSetupPacket) (*drand.Empty, error) {
+	d, err := dd.Beacon(in.GetMetadata().GetBeaconID())
+	if err != nil {
+		return nil, err
+	}
+	return d.AbortSetup(c, in)
+}
+
+// PrepareDKGGroup routes the key of a participant to the chain it joins
+func (dd *Daemon) PrepareDKGGroup(c context.Context, in *drand.PrepareDKGPacket) (*drand.PrepareDKGResponse, error) {
+	d, err := dd.Beacon(in.GetMetadata().GetBeaconID())
+	if err != nil {
+		return nil, err
+	}
+	return d.PrepareDKGGroup(c, in)
+}
+
+// PushDKGGroup routes the group sent by the leader to its chain
+func (dd *Daemon) PushDKGGroup(c context.Context, in *drand.PushGroupPacket) (*drand.Empty, error) {
+	d, err := dd.Beacon(in.GetMetadata().GetBeaconID())
+	if err != nil {
+		return nil, err
+	}
+	return d.PushDKGGroup(c, in)
+}
+
+// ConfirmGroup routes the group hash to its chain
+func (dd *Daemon) ConfirmGroup(c context.Context, in *drand.GroupHashPacket) (*drand.GroupHashPacket, error) {
+	d, err := dd.Beacon(in.GetMetadata().GetBeaconID())
+	if err != nil {
+		return nil, err
+	}
+	return d.ConfirmGroup(c, in)
+}
+
+// NewGroupSilent routes the statement of an old node about a new group to its
+// chain
+func (dd *Daemon) NewGroupSilent(c context.Context, in *drand.NewGroupSilentPacket) (*drand.NewGroupSilentPacket, error) {
+	d, err := dd.Beacon(in.GetMetadata().GetBeaconID())
+	if err != nil {
+		return nil, err
+	}
+	return d.NewGroupSilent(c, in)
+}
+
+// PartialBeacon routes the partial beacon to its chain
+func (dd *Daemon) PartialBeacon(c context.Context, in *drand.PartialBeaconPacket) (*drand.Empty, error) {
+	d, err := dd.Beacon(in.GetMetadata().GetBeaconID())
+	if err != nil {
+		return nil, err
+	}
+	return d.PartialBeacon(c, in)
+}
+
+// SyncChain routes the sync request to its chain
+func (dd *Daemon) SyncChain(in *drand.SyncRequest, stream drand.Protocol_SyncChainServer) error {
+	d, err := dd.Beacon(in.GetMetadata().GetBeaconID())
+	if err != nil {
+		return err
+	}
+	return d.SyncChain(in, stream)
+}
+
+// PublicRand routes the request to its chain
+func (dd *Daemon) PublicRand(c context.Context, in *drand.PublicRandRequest) (*drand.PublicRandResponse, error) {
+	d, err := dd.Beacon(in.GetBeaconID())
+	if err != nil {
+		return nil, err
+	}
+	return d.PublicRand(c, in)
+}
+
+// PublicRandStream routes the request to its chain
+func (dd *Daemon) PublicRandStream(in *drand.PublicRandRequest, stream drand.Public_PublicRandStreamServer) error {
+	d, err := dd.Beacon(in.GetBeaconID())
+	if err != nil {
+		return err
+	}
+	return d.PublicRandStream(in, stream)
+}
+
+// PrivateRand routes the request to its chain
+func (dd *Daemon) PrivateRand(c context.Context, in *drand.PrivateRandRequest) (*drand.PrivateRandResponse, error) {
+	d, err := dd.Beacon(in.GetBeaconID())
+	if err != nil {
+		return nil, err
+	}
+	return d.PrivateRand(c, in)
+}
+
+// Group routes the request to its chain
+func (dd *Daemon) Group(c context.Context, in *drand.GroupRequest) (*drand.GroupPacket, error) {
+	d, err := dd.Beacon(in.GetBeaconID())
+	if err != nil {
+		return nil, err
+	}
+	return d.Group(c, in)
+}
+
+// GroupHistory routes the request to its chain
+func (dd *Daemon) GroupHistory(c context.Context, in *drand.GroupHistoryRequest) (*drand.GroupHistoryResponse, error) {
+	d, err := dd.Beacon(in.GetBeaconID())
+	if err != nil {
+		return nil, err
+	}
+	return d.GroupHistory(c, in)
+}
+
+// DistKey routes the request to its chain
+func (dd *Daemon) DistKey(c context.Context, in *drand.DistKeyRequest) (*drand.DistKeyResponse, error) {
+	d, err := dd.Beacon(in.GetBeaconID())
+	if err != nil {
+		return nil, err
+	}
+	return d.DistKey(c, in)
+}
+
+// Home routes the request to its chain
+func (dd *Daemon) Home(c context.Context, in *drand.HomeRequest) (*drand.HomeResponse, error) {
+	d, err := dd.Beacon(in.GetBeaconID())
+	if err != nil {
+		return nil, err
+	}
+	return d.Home(c, in)
+}
+
+// PingPong simply responds with a Pong message
+func (dd *Daemon) PingPong(c context.Context, in *drand.Ping) (*drand.Pong, error) {
+	return &drand.Pong{}, nil
+}
+
+// Shutdown stops the daemon with all its chains
+func (dd *Daemon) Shutdown(c context.Context, in *drand.ShutdownRequest) (*drand.ShutdownResponse, error) {
+	dd.Stop()
+	return nil, nil
+}
+
+// InitDKG routes the command to its chain
+func (dd *Daemon) InitDKG(c context.Context, in *drand.InitDKGPacket) (*drand.GroupPacket, error) {
+	d, err := dd.Beacon(in.GetBeaconID())
+	if err != nil {
+		return nil, err
+	}
+	return d.InitDKG(c, in)
+}
+
+// InitReshare routes the command to its chain
+func (dd *Daemon) InitReshare(c context.Context, in *drand.InitResharePacket) (*drand.GroupPacket, error) {
+	d, err := dd.Beacon(in.GetBeaconID())
+	if err != nil {
+		return nil, err
+	}
+	return d.InitReshare(c, in)
+}
+
+// Share routes the command to its chain
+func (dd *Daemon) Share(c context.Context, in *drand.ShareRequest) (*drand.ShareResponse, error) {
+	d, err := dd.Beacon(in.GetBeaconID())
+	if err != nil {
+		return nil, err
+	}
+	return d.Share(c, in)
+}
+
+// PublicKey routes the command to its chain
+func (dd *Daemon) PublicKey(c context.Context, in *drand.PublicKeyRequest) (*drand.PublicKeyResponse, error) {
+	d, err := dd.Beacon(in.GetBeaconID())
+	if err != nil {
+		return nil, err
+	}
+	return d.PublicKey(c, in)
+}
+
+// PrivateKey routes the command to its chain
+func (dd *Daemon) PrivateKey(c context.Context, in *drand.PrivateKeyRequest) (*drand.PrivateKeyResponse, error) {
+	d, err := dd.Beacon(in.GetBeaconID())
+	if err != nil {
+		return nil, err
+	}
+	return d.PrivateKey(c, in)
+}
+
+// CollectiveKey routes the command to its chain
+func (dd *Daemon) CollectiveKey(c context.Context, in *drand.CokeyRequest) (*drand.CokeyResponse, error) {
+	d, err := dd.Beacon(in.GetBeaconID())
+	if err != nil {
+		return nil, err
+	}
+	return d.CollectiveKey(c, in)
+}
+
+// GroupFile routes the command to its chain
+func (dd *Daemon) GroupFile(c context.Context, in *drand.GroupRequest) (*drand.GroupPacket, error) {
+	d, err := dd.Beacon(in.GetBeaconID())
+	if err != nil {
+		return nil, err
+	}
+	return d.GroupFile(c, in)
+}
+
+// SetupStatus routes the command to its chain
+func (dd *Daemon) SetupStatus(c context.Context, in *drand.SetupStatusRequest) (*drand.SetupStatusResponse, error) {
+	d, err := dd.Beacon(in.GetBeaconID())
+	if err != nil {
+		return nil, err
+	}
+	return d.SetupStatus(c, in)
+}
+
+// PendingGroup routes the command to its chain
+func (dd *Daemon) PendingGroup(c context.Context, in *drand.PendingGroupRequest) (*drand.GroupPacket, error) {
+	d, err := dd.Beacon(in.GetBeaconID())
+	if err != nil {
+		return nil, err
+	}
+	return d.PendingGroup(c, in)
+}
+
+// AcceptGroup routes the command to its chain
+func (dd *Daemon) AcceptGroup(c context.Context, in *drand.AcceptGroupRequest) (*drand.AcceptGroupResponse, error) {
+	d, err := dd.Beacon(in.GetBeaconID())
+	if err != nil {
+		return nil, err
+	}
+	return d.AcceptGroup(c, in)
+}
+
+// CancelDKG routes the command to its chain
+func (dd *Daemon) CancelDKG(c context.Context, in *drand.CancelDKGRequest) (*drand.CancelDKGResponse, error) {
+	d, err := dd.Beacon(in.GetBeaconID())
+	if err != nil {
+		return nil, err
+	}
+	return d.CancelDKG(c, in)
+}
+
+// CancelTransition routes the command to its chain
+func (dd *Daemon) CancelTransition(c context.Context, in *drand.CancelTransitionRequest) (*drand.CancelTransitionResponse, error) {
+	d, err := dd.Beacon(in.GetBeaconID())
+	if err != nil {
+		return nil, err
+	}
+	return d.CancelTransition(c, in)
+}
+
+// Backup routes the command to its chain
+func (dd *Daemon) Backup(in *drand.BackupRequest, stream drand.Control_BackupServer) error {
+	d, err := dd.Beacon(in.GetBeaconID())
+	if err != nil {
+		return err
+	}
+	return d.Backup(in, stream)
+}
+
+// Peers routes the command to its chain
+func (dd *Daemon) Peers(c context.Context, in *drand.PeersRequest) (*drand.PeersResponse, error) {
+	d, err := dd.Beacon(in.GetBeaconID())
+	if err != nil {
+		return nil, err
+	}
+	return d.Peers(c, in)
+}
+
+// SyncStatus routes the command to its chain
+func (dd *Daemon) SyncStatus(c context.Context, in *drand.SyncStatusRequest) (*drand.SyncStatusResponse, error) {
+	d, err := dd.Beacon(in.GetBeaconID())
+	if err != nil {
+		return nil, err
+	}
+	return d.SyncStatus(c, in)
+}
+
+// StartSync routes the command to its chain
+func (dd *Daemon) StartSync(c context.Context, in *drand.StartSyncRequest) (*drand.SyncStatusResponse, error) {
+	d, err := dd.Beacon(in.GetBeaconID())
+	if err != nil {
+		return nil, err
+	}
+	return d.StartSync(c, in)
+}
@@ -0,0 +1,155 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/drand/drand/beacon"
+	"github.com/drand/drand/key"
+	"github.com/drand/drand/net"
+	"github.com/drand/drand/protobuf/drand"
+	"github.com/drand/drand/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDaemonMultiBeacon(t *testing.T) {
+	n := 4
+	thr := key.DefaultThreshold(n)
+	// the chains don't need to keep their beacons on disk
+	drands, _, dir, _ := BatchNewDrand(n, true, WithDbBackend(beacon.MemoryBackend))
+	defer os.RemoveAll(dir)
+	defer CloseAllDrands(drands)
+
+	// every node runs a second chain, with its own key
+	otherID := "other"
+	others := make([]*key.Identity, n)
+	for i, d := range drands {
+		priv := key.NewKeyPair(d.priv.Public.Address())
+		s := test.NewKeyStore()
+		require.NoError(t, s.SaveKeyPair(priv))
+		_, err := d.daemon.NewBeacon(otherID, s)
+		require.NoError(t, err)
+		others[i] = priv.Public
+	}
+	_, err := drands[0].daemon.NewBeacon(otherID, test.NewKeyStore())
+	require.Error(t, err)
+	_, err = drands[0].daemon.NewBeacon("../escape", test.NewKeyStore())
+	require.Error(t, err)
+	// the routes of the REST API can't name a chain
+	for _, reserved := range []string{"public", "private", "info", "Info"} {
+		_, err = drands[0].daemon.NewBeacon(reserved, test.NewKeyStore())
+		require.Error(t, err)
+	}
+	require.Equal(t, []string{DefaultBeaconID, otherID}, drands[0].daemon.BeaconIDs())
+
+	// the chains run their own DKG, with different periods
+	runDKG := func(beaconID string, leader *key.Identity, period time.Duration) *key.Group {
+		secret := "thisisdkg"
+		groupCh := make(chan *key.Group, 1)
+		go func() {
+			client, err := net.NewControlClient(drands[0].opts.controlPort)
+			require.NoError(t, err)
+			groupP, err := client.ForBeacon(beaconID).InitDKGLeader(n, thr, period, testDkgTimeout, nil, secret, testBeaconOffset, nil)
+			require.NoError(t, err)
+			group, err := ProtoToGroup(groupP)
+			require.NoError(t, err)
+			groupCh <- group
+		}()
+		// make sure the leader is waiting
+		time.Sleep(1 * time.Second)
+		var wg sync.WaitGroup
+		for _, d := range drands[1:] {
+			wg.Add(1)
+			go func(d *Drand) {
+				defer wg.Done()
+				client, err := net.NewControlClient(d.opts.controlPort)
+				require.NoError(t, err)
+				client = client.ForBeacon(beaconID)
+				go acceptPendingGroup(t, client)
+				_, err = client.InitDKG(leader, n, thr, testDkgTimeout, nil, secret)
+				require.NoError(t, err)
+			}(d)
+		}
+		wg.Wait()
+		return <-groupCh
+	}
+	finalDefault := runDKG(DefaultBeaconID, drands[0].priv.Public, 2*time.Second)
+	finalOther := runDKG(otherID, others[0], 5*time.Second)
+	require.False(t, finalDefault.PublicKey.Equal(finalOther.PublicKey))
+
+	// the public endpoints route the requests by beacon id
+	client := net.NewGrpcClient()
+	peer := drands[1].priv.Public
+	for beaconID, expected := range map[string]*key.Group{DefaultBeaconID: finalDefault, otherID: finalOther} {
+		resp, err := client.Group(context.Background(), peer, &drand.GroupRequest{BeaconID: beaconID})
+		require.NoError(t, err)
+		received, err := ProtoToGroup(resp)
+		require.NoError(t, err)
+		require.Equal(t, expected.Period, received.Period)
+		require.True(t, expected.PublicKey.Equal(received.PublicKey))
+	}
+	_, err = client.Group(context.Background(), peer, &drand.GroupRequest{BeaconID: "unknown"})
+	require.Error(t, err)
+
+	resp, err := http.Get(fmt.Sprintf("http://%s/api/%s/info/group", peer.Address(), otherID))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, err = http.Get(fmt.Sprintf("http://%s/api/unknown/info/group", peer.Address()))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.NotEqual(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestDaemonUnknownDbBackend(t *testing.T) {
+	c := NewConfig(WithInsecure(), WithDbBackend("unknown"))
+	_, err := NewDaemon(c, "127.0.0.1:0")
+	require.Error(t, err)
+}
+
+func TestDaemonMemoryStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "drand-memory")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	c := NewConfig(WithConfigFolder(dir), WithDbBackend(beacon.MemoryBackend))
+
+	// the beacons of a chain outlive its store, e.g. during a transition
+	store, err := c.openBeaconStore("other")
+	require.NoError(t, err)
+	require.NoError(t, store.Put(&beacon.Beacon{Round: 1, Signature: []byte{1}}))
+	store.Close()
+	store, err = c.openBeaconStore("other")
+	require.NoError(t, err)
+	require.Equal(t, 1, store.Len())
+	store, err = c.openBeaconStore(DefaultBeaconID)
+	require.NoError(t, err)
+	require.Equal(t, 0, store.Len())
+
+	// nothing is created on disk
+	_, err = os.Stat(c.DBFolder())
+	require.True(t, os.IsNotExist(err))
+}
+
+// acceptPendingGroup accepts the group proposed to the node once it is received
+func acceptPendingGroup(t *testing.T, client *net.ControlClient) {
+	deadline := time.Now().Add(30 * time.Second)
+	for time.Now().Before(deadline) {
+		groupPacket, err := client.PendingGroup()
+		if err != nil {
+			time.Sleep(50 * time.Millisecond)
+			continue
+		}
+		group, err := ProtoToGroup(groupPacket)
+		require.NoError(t, err)
+		hash, err := group.ChainHash()
+		require.NoError(t, err)
+		require.NoError(t, client.AcceptGroup(hash, true))
+		return
+	}
+}
@@ -0,0 +1,270 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/drand/drand/dkg"
+	"github.com/drand/drand/key"
+	"github.com/drand/drand/log"
+	dkg_proto "github.com/drand/drand/protobuf/crypto/dkg"
+	"github.com/golang/protobuf/proto"
+)
+
+// dkgJournal saves the progress of the running DKG in the key store so the
+// protocol can be resumed if the node crashes in the middle of it. The state
+// is saved in the background, once for all the packets received during the
+// previous save, so the protocol never waits for the disk.
+type dkgJournal struct {
+	sync.Mutex
+	store key.Store
+	state *key.DKGState
+	l     log.Logger
+	// pending is true while a save of the state is scheduled
+	pending bool
+	// closed is true once the DKG ended or the node stopped
+	closed bool
+	// saving serializes the saves, and the saves with close
+	saving sync.Mutex
+}
+
+func newDKGJournal(store key.Store, state *key.DKGState, l log.Logger) *dkgJournal {
+	return &dkgJournal{store: store, state: state, l: l}
+}
+
+func (j *dkgJournal) Started(t time.Time) {
+	j.Lock()
+	defer j.Unlock()
+	j.state.StartedAt = t.Unix()
+	j.schedule()
+}
+
+func (j *dkgJournal) SentDeal(to int, p *dkg_proto.Packet) {
+	buff, err := proto.Marshal(p)
+	if err != nil {
+		j.l.Error("dkg_journal", "marshal_deal", "err", err)
+		return
+	}
+	j.Lock()
+	defer j.Unlock()
+	j.state.Deals[to] = buff
+	j.schedule()
+}
+
+func (j *dkgJournal) Received(p *dkg_proto.Packet) {
+	buff, err := proto.Marshal(p)
+	if err != nil {
+		j.l.Error("dkg_journal", "marshal_packet", "err", err)
+		return
+	}
+	j.Lock()
+	defer j.Unlock()
+	j.state.Received = append(j.state.Received, buff)
+	j.schedule()
+}
+
+// schedule saves the state in the background, unless a save that hasn't
+// copied the state yet is already scheduled. It must be called with the lock
+// held.
+func (j *dkgJournal) schedule() {
+	if j.pending || j.closed {
+		return
+	}
+	j.pending = true
+	go j.save()
+}
+
+func (j *dkgJournal) save() {
+	j.saving.Lock()
+	defer j.saving.Unlock()
+	j.Lock()
+	j.pending = false
+	if j.closed {
+		j.Unlock()
+		return
+	}
+	// the packets are never modified, only appended, so a copy of the
+	// containers is enough
+	state := *j.state
+	state.Deals = make(map[int][]byte, len(j.state.Deals))
+	for i, d := range j.state.Deals {
+		state.Deals[i] = d
+	}
+	state.Received = j.state.Received[:len(j.state.Received):len(j.state.Received)]
+	j.Unlock()
+	if err := j.store.SaveDKGState(&state); err != nil {
+		j.l.Error("dkg_journal", "save", "err", err)
+	}
+}
+
+// close stops saving the state, once the save in progress, if any, is done. It
+// returns false if the journal was already closed.
+func (j *dkgJournal) close() bool {
+	j.saving.Lock()
+	defer j.saving.Unlock()
+	j.Lock()
+	defer j.Unlock()
+	if j.closed {
+		return false
+	}
+	j.closed = true
+	return true
+}
+
+// progress decodes the packets saved in the state
+func (j *dkgJournal) progress() (*dkg.Progress, error) {
+	j.Lock()
+	defer j.Unlock()
+	p := &dkg.Progress{
+		Deals:    make(map[int]*dkg_proto.Packet, len(j.state.Deals)),
+		Received: make([]*dkg_proto.Packet, 0, len(j.state.Received)),
+	}
+	if j.state.StartedAt != 0 {
+		p.StartedAt = time.Unix(j.state.StartedAt, 0)
+	}
+	for i, buff := range j.state.Deals {
+		packet := new(dkg_proto.Packet)
+		if err := proto.Unmarshal(buff, packet); err != nil {
+			return nil, fmt.Errorf("drand: invalid saved deal: %s", err)
+		}
+		p.Deals[i] = packet
+	}
+	for _, buff := range j.state.Received {
+		packet := new(dkg_proto.Packet)
+		if err := proto.Unmarshal(buff, packet); err != nil {
+			return nil, fmt.Errorf("drand: invalid saved packet: %s", err)
+		}
+		p.Received = append(p.Received, packet)
+	}
+	return p, nil
+}
+
+// journalDKG derives the seed of our dealer polynomial, saves the
+// configuration of the DKG that is about to run in the store and sets the
+// journal recording its progress in the config.
+func (d *Drand) journalDKG(leader bool, conf *dkg.Config) error {
+	seed := dkg.NewSeed(conf.Reader, conf.UserReaderOnly)
+	conf.Seed = seed
+	state := &key.DKGState{
+		Leader:   leader,
+		Seed:     seed,
+		Timeout:  conf.Timeout,
+		NewGroup: conf.NewNodes,
+		OldGroup: conf.OldNodes,
+		Deals:    make(map[int][]byte),
+	}
+	conf.Journal = newDKGJournal(d.store, state, d.log)
+	return d.store.SaveDKGState(state)
+}
+
+// deleteDKGState deletes the state of the DKG that ended from the store,
+// unless the node stopped during the DKG: the state is then kept so the DKG
+// resumes at the next start.
+func (d *Drand) deleteDKGState(conf *dkg.Config) {
+	if j, ok := conf.Journal.(*dkgJournal); ok && !j.close() {
+		return
+	}
+	d.store.DeleteDKGState()
+}
+
+// suspendDKG stops the running DKG, if any, and keeps its state in the store
+// as it is, so the DKG resumes at the next start.
+func (d *Drand) suspendDKG() {
+	d.state.Lock()
+	conf := d.nextConf
+	d.state.Unlock()
+	if conf == nil {
+		return
+	}
+	if j, ok := conf.Journal.(*dkgJournal); ok {
+		j.close()
+	}
+	d.stopDKG()
+}
+
+// resumeDKG restarts the DKG that was running before the node stopped, from
+// the state saved in the store. It must be called after the share of the
+// current group, if any, has been loaded.
+func (d *Drand) resumeDKG(state *key.DKGState) error {
+	journal := newDKGJournal(d.store, state, d.log)
+	progress, err := journal.progress()
+	if err != nil {
+		return err
+	}
+	conf := &dkg.Config{
+		Suite:    key.KeyGroup.(dkg.Suite),
+		NewNodes: state.NewGroup,
+		OldNodes: state.OldGroup,
+		Key:      d.priv,
+		NewKey:   d.keyIn(state.NewGroup),
+		Timeout:  state.Timeout,
+		Clock:    d.opts.clock,
+		Seed:     state.Seed,
+		Journal:  journal,
+	}
+	_, newPresent := state.NewGroup.Index(conf.NewKey.Public)
+	oldIdx, oldPresent := 0, false
+	if state.IsResharing() {
+		oldIdx, oldPresent = state.OldGroup.Index(d.priv.Public)
+		nextHash, err := state.NewGroup.Hash()
+		if err != nil {
+			return err
+		}
+		nextChainHash, err := state.NewGroup.ChainHash()
+		if err != nil {
+			return err
+		}
+		d.state.Lock()
+		if oldPresent {
+			if d.share == nil {
+				d.state.Unlock()
+				return fmt.Errorf("drand: can't resume resharing without a share")
+			}
+			conf.Share = d.share
+		}
+		d.nextGroupHash = nextHash
+		d.nextChainHash = nextChainHash
+		d.nextGroup = state.NewGroup
+		d.nextOldPresent = oldPresent
+		// the partial signatures over the new group received before the
+		// restart are not journaled, so the new group may end up unsigned
+		if err := d.signNextGroup(state.NewGroup, conf.Share); err != nil {
+			d.state.Unlock()
+			return err
+		}
+		// an old node that did not send its deals yet waits for the first
+		// packet to do so
+		d.nextFirstReceived = len(progress.Deals) > 0
+		d.state.Unlock()
+	}
+	d.state.Lock()
+	d.nextConf = conf
+	d.state.Unlock()
+	if err := d.createDKG(conf); err != nil {
+		return err
+	}
+	d.log.Info("dkg_resume", "start", "resharing", state.IsResharing(), "received", len(progress.Received), "deals", len(progress.Deals))
+	d.dkg.Resume(progress)
+	if state.Leader && len(progress.Deals) == 0 {
+		if state.IsResharing() {
+			go d.startResharingAsLeader(conf, oldIdx)
+		} else {
+			go d.dkg.Start()
+		}
+	}
+
+	go func() {
+		if _, err := d.WaitDKG(conf); err != nil {
+			d.log.Error("dkg_resume", err)
+			return
+		}
+		d.log.Info("dkg_resume", "dkg_done")
+		if !state.IsResharing() {
+			d.StartBeacon(false)
+			return
+		}
+		d.transition(state.OldGroup, conf.Share, oldPresent, newPresent)
+	}()
+	return nil
+}
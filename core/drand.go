@@ -9,7 +9,6 @@ import (
 
 	"github.com/drand/drand/beacon"
 	"github.com/drand/drand/dkg"
-	"github.com/drand/drand/fs"
 	"github.com/drand/drand/key"
 	"github.com/drand/drand/log"
 	"github.com/drand/drand/net"
@@ -19,17 +18,21 @@ import (
 
 // Drand is the main logic of the program. It reads the keys / group file, it
 // can start the DKG, read/write shars to files and can initiate/respond to TBlS
-// signature requests.
+// signature requests. It runs one beacon chain of the daemon.
 type Drand struct {
 	opts *Config
-	priv *key.Pair
+	// beaconID identifies the chain among the chains of the daemon
+	beaconID string
+	daemon   *Daemon
+	priv     *key.Pair
+	// key pair replacing priv in the group of the next resharing, if any
+	nextPriv *key.Pair
 	// current group this drand node is using
 	group *key.Group
 	index int
 
 	store   key.Store
 	gateway net.Gateway
-	control net.ControlListener
 
 	// handle all callbacks when a new beacon is found
 	callbacks *callbackManager
@@ -38,6 +41,8 @@ type Drand struct {
 
 	dkg    *dkg.Handler
 	beacon *beacon.Handler
+	// client to the process holding the share, if any
+	signerClient drand.SignerClient
 	// dkg private share. can be nil if dkg not finished yet.
 	share *key.Share
 	// dkg public key. Can be nil if dkg not finished yet.
@@ -46,98 +51,220 @@ type Drand struct {
 	// manager is created and destroyed during a setup phase
 	manager  *setupManager
 	receiver *setupReceiver
+	// confirmer is set during a setup without leader, and by the leader while
+	// waiting for the acknowledgements of the group
+	confirmer *groupConfirmer
+	// inProgress is the setup or DKG started with InitDKG or InitReshare
+	inProgress *setupRun
+	// nextTransition is set after a resharing, until the new group runs
+	nextTransition *pendingTransition
 
 	// proposed next group hash for a resharing operation
 	nextGroupHash     string
+	nextChainHash     string // version 2 of nextGroupHash
 	nextGroup         *key.Group
 	nextConf          *dkg.Config
 	nextOldPresent    bool // true if we are in the old group
 	nextFirstReceived bool // false til receive 1st reshare packet
+	// partial signature of this node over the next group, if in the old group
+	nextPartial []byte
+	// partial signatures of the old nodes over the next group, by index
+	nextPartials map[int][]byte
 
 	// general logger
 	log log.Logger
 
 	// global state lock
-	state  sync.Mutex
-	exitCh chan bool
+	state sync.Mutex
 }
 
 // NewDrand returns an drand struct. It assumes the private key pair
-// has been generated and saved already.
+// has been generated and saved already. It runs the default chain of a new
+// daemon listening on the address of the key pair.
 func NewDrand(s key.Store, c *Config) (*Drand, error) {
-	d, err := initDrand(s, c)
+	dd, err := newDaemonFor(s, c)
 	if err != nil {
 		return nil, err
 	}
+	d, err := dd.NewBeacon(DefaultBeaconID, s)
+	if err != nil {
+		dd.Stop()
+		return nil, err
+	}
 	return d, nil
 }
 
-// initDrand inits the drand struct by loading the private key, and by creating the
-// gateway with the correct options.
-func initDrand(s key.Store, c *Config) (*Drand, error) {
-	logger := c.Logger()
-	if c.insecure == false && (c.certPath == "" || c.keyPath == "") {
-		return nil, errors.New("config: need to set WithInsecure if no certificate and private key path given")
-	}
+// newDaemonFor returns a daemon listening on the address of the key pair in
+// the given store.
+func newDaemonFor(s key.Store, c *Config) (*Daemon, error) {
 	priv, err := s.LoadKeyPair()
 	if err != nil {
 		return nil, err
 	}
+	return NewDaemon(c, priv.Public.Address())
+}
 
-	// trick to always set the listening address by default based on the
-	// identity. If there is an option to set the address, it will override the
-	// default set here..
+// initDrand inits the drand struct of the chain of the given ID by loading
+// the private key. The chain uses the gateway of the daemon.
+func initDrand(dd *Daemon, beaconID string, s key.Store) (*Drand, error) {
+	priv, err := s.LoadKeyPair()
+	if err != nil {
+		return nil, err
+	}
+	logger := dd.log
+	if beaconID != DefaultBeaconID {
+		logger = logger.With("beacon_id", beaconID)
+	}
 	d := &Drand{
 		store:     s,
 		priv:      priv,
-		opts:      c,
+		opts:      dd.opts,
+		beaconID:  beaconID,
+		daemon:    dd,
+		gateway:   dd.gateway,
 		log:       logger,
-		exitCh:    make(chan bool, 1),
 		callbacks: newCallbackManager(),
 		//cache:     newBeaconCache(logger),
 	}
+	if err := d.loadNextKey(); err != nil {
+		return nil, err
+	}
 	// every new beacon will be passed through the opts callbacks
 	d.callbacks.AddCallback(callbackID, d.opts.callbacks)
 	//d.callbacks.AddCallback(cacheID, d.cache.StoreTemp)
-
-	a := c.ListenAddress(priv.Public.Address())
-	if c.insecure {
-		d.log.Info("network", "tls-disable")
-		d.gateway = net.NewGrpcGatewayInsecure(a, d, d.opts.grpcOpts...)
-	} else {
-		d.log.Info("network", "tls-enabled")
-		d.gateway = net.NewGrpcGatewayFromCertManager(a, c.certPath, c.keyPath, c.certmanager, d, d.opts.grpcOpts...)
-	}
-	p := c.ControlPort()
-	d.control = net.NewTCPGrpcControlListener(d, p)
-	go d.control.Start()
-	d.log.Info("network_listen", a, "control_port", c.ControlPort())
-	d.gateway.StartAll()
 	return d, nil
 }
 
 // LoadDrand restores a drand instance that is ready to serve randomness, with a
-// pre-existing distributed share.
+// pre-existing distributed share. If a DKG was in progress when the node
+// stopped, it is resumed. In the case of a fresh DKG, or of a resharing where
+// this node is not part of the current group, there is no share to load and
+// the beacon starts once the DKG is finished. It runs the default chain of a
+// new daemon listening on the address of the key pair.
 func LoadDrand(s key.Store, c *Config) (*Drand, error) {
-	d, err := initDrand(s, c)
+	dd, err := newDaemonFor(s, c)
 	if err != nil {
 		return nil, err
 	}
-	d.group, err = s.LoadGroup()
+	d, err := dd.LoadBeacon(DefaultBeaconID, s)
 	if err != nil {
+		dd.Stop()
 		return nil, err
 	}
-	d.share, err = s.LoadShare()
+	return d, nil
+}
+
+// load restores the group, the share and the DKG in progress of the chain
+// from its store.
+func (d *Drand) load() error {
+	s := d.store
+	dkgState, err := s.LoadDKGState()
+	if err == key.ErrAbsent {
+		dkgState = nil
+	} else if err != nil {
+		return fmt.Errorf("drand: can't load dkg state: %s", err)
+	}
+	if dkgState != nil {
+		var holdsShare bool
+		if dkgState.IsResharing() {
+			_, holdsShare = dkgState.OldGroup.Index(d.priv.Public)
+		}
+		if !holdsShare {
+			return d.resumeDKG(dkgState)
+		}
+	}
+	d.group, err = s.LoadGroup()
 	if err != nil {
-		return nil, err
+		return err
+	}
+	// the node stopped after the resharing it rotated its key for
+	d.rotateKey(d.group)
+	d.share, err = s.LoadShare()
+	if err != nil && !d.usesRemoteSigner() {
+		return err
+	} else if err != nil {
+		// the share is held by the remote signer
+		d.share = nil
 	}
 	d.pub, err = s.LoadDistPublic()
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if err := d.initGroupHistory(); err != nil {
+		return err
 	}
 	d.log.Debug("serving", d.priv.Public.Address())
 	d.dkgDone = true
-	return d, nil
+	if dkgState != nil {
+		return d.resumeDKG(dkgState)
+	}
+	return nil
+}
+
+// loadNextKey loads the key pair generated to replace the current one at the
+// next resharing, if any.
+func (d *Drand) loadNextKey() error {
+	next, err := d.store.LoadNextKeyPair()
+	if err == key.ErrAbsent {
+		next = nil
+	} else if err != nil {
+		return fmt.Errorf("drand: can't load next key pair: %v", err)
+	}
+	d.state.Lock()
+	d.nextPriv = next
+	d.state.Unlock()
+	return nil
+}
+
+// reshareKey returns the key pair this node joins the group of the next
+// resharing with.
+func (d *Drand) reshareKey() *key.Pair {
+	if d.nextPriv != nil {
+		return d.nextPriv
+	}
+	return d.priv
+}
+
+// keyIn returns the key pair of this node in the given group: the next key
+// pair if the group was created with it, the current one otherwise.
+func (d *Drand) keyIn(group *key.Group) *key.Pair {
+	if d.nextPriv != nil {
+		if _, found := group.Index(d.nextPriv.Public); found {
+			return d.nextPriv
+		}
+	}
+	return d.priv
+}
+
+// rotateKey makes the next key pair the key pair of the node if the given
+// group, which the node now runs, was created with it. The previous key pair
+// is archived by the store.
+func (d *Drand) rotateKey(group *key.Group) {
+	if d.nextPriv == nil || d.keyIn(group) != d.nextPriv {
+		return
+	}
+	if err := d.store.RotateKeyPair(); err != nil {
+		d.log.Error("rotate_key", "err", err)
+		return
+	}
+	d.state.Lock()
+	d.priv = d.nextPriv
+	d.nextPriv = nil
+	d.state.Unlock()
+	d.log.Info("rotate_key", "done", "key", d.priv.Public.Key.String())
+}
+
+// initGroupHistory adds the current group to the group history if it is empty,
+// for nodes that ran their DKG before the history was kept.
+func (d *Drand) initGroupHistory() error {
+	history, err := d.store.LoadGroupHistory()
+	if err != nil {
+		return fmt.Errorf("drand: can't load group history: %v", err)
+	}
+	if len(history) > 0 {
+		return nil
+	}
+	return d.store.SaveHistoryGroup(d.group, beacon.StartRound(d.group))
 }
 
 // StartDKG starts the DKG protocol by sending the first packet of the DKG
@@ -165,28 +292,64 @@ func (d *Drand) WaitDKG(conf *dkg.Config) (*key.Group, error) {
 	d.state.Unlock()
 
 	d.log.Debug("dkg_start", time.Now().String())
+	var share *key.Share
 	select {
-	case share := <-waitCh:
-		s := key.Share(share)
-		d.share = &s
+	case dshare := <-waitCh:
+		s := key.Share(dshare)
+		share = &s
 	case err := <-errCh:
+		d.deleteDKGState(conf)
+		d.resetDKG()
 		return nil, fmt.Errorf("drand: error from dkg: %v", err)
 	}
 
+	d.state.Lock()
+	// XXX change that whole messup - too easy to forget things
+	group := d.dkg.QualifiedGroup()
+	group.Period = conf.NewNodes.Period
+	group.GenesisTime = conf.NewNodes.GenesisTime
+	group.TransitionTime = conf.NewNodes.TransitionTime
+	group.GenesisSeed = conf.NewNodes.GetGenesisSeed()
+	var err error
+	if conf.OldNodes != nil {
+		group.TransitionSignature, err = d.transitionSignature(conf.OldNodes, group)
+	}
+	d.state.Unlock()
+	if err != nil {
+		// clients following the chain from a trusted group reject a group
+		// the old one didn't sign: keep running with the old group
+		d.log.Error("dkg_end", "transition_signature", "err", err)
+		d.deleteDKGState(conf)
+		d.resetDKG()
+		return nil, fmt.Errorf("drand: can't recover the signature of the old group over the new group: %v", err)
+	}
+
 	d.state.Lock()
 	defer d.state.Unlock()
 
-	d.store.SaveShare(d.share)
+	d.share = share
+	if !d.usesRemoteSigner() {
+		d.store.SaveShare(d.share)
+	} else {
+		// a DKG resumed after the daemon restarted with a signer
+		d.log.Error("dkg_end", "share", "err", "not saved, the share is held by the remote signer")
+	}
 	d.store.SaveDistPublic(d.share.Public())
-	// XXX change that whole messup - too easy to forget things
-	d.group = d.dkg.QualifiedGroup()
-	d.group.Period = conf.NewNodes.Period
-	d.group.GenesisTime = conf.NewNodes.GenesisTime
-	d.group.TransitionTime = conf.NewNodes.TransitionTime
-	d.group.GenesisSeed = conf.NewNodes.GetGenesisSeed()
+	d.group = group
+	if conf.OldNodes != nil {
+		d.nextPartial = nil
+		d.nextPartials = nil
+	}
 
 	d.log.Debug("dkg_end", time.Now(), "certified", d.group.Len())
 	d.store.SaveGroup(d.group)
+	if err := d.store.SaveHistoryGroup(d.group, beacon.StartRound(d.group)); err != nil {
+		d.log.Error("dkg_end", "group_history", "err", err)
+	}
+	if err := d.store.SaveTranscript(d.dkg.Transcript()); err != nil {
+		d.log.Error("dkg_end", "transcript", "err", err)
+	}
+	d.deleteDKGState(conf)
 	d.opts.applyDkgCallback(d.share)
 	d.dkgDone = true
 	d.dkg = nil
@@ -194,6 +357,109 @@ func (d *Drand) WaitDKG(conf *dkg.Config) (*key.Group, error) {
 	return d.group, nil
 }
 
+// signNextGroup prepares the collection of the partial signatures of the old
+// group over the next group. As an old node, it signs the next group with its
+// share. It must be called with the lock held.
+func (d *Drand) signNextGroup(newGroup *key.Group, share *key.Share) error {
+	d.nextPartial = nil
+	d.nextPartials = make(map[int][]byte)
+	if share == nil {
+		return nil
+	}
+	msg, err := newGroup.TransitionMsg()
+	if err != nil {
+		return err
+	}
+	partial, err := key.Scheme.Sign(share.PrivateShare(), msg)
+	if err != nil {
+		return err
+	}
+	d.nextPartial = partial
+	d.nextPartials[share.PrivateShare().I] = partial
+	return nil
+}
+
+// addNextPartial verifies and stores the partial signature of an old node
+// over the next group. It must be called with the lock held.
+func (d *Drand) addNextPartial(partial []byte) error {
+	if d.nextPartials == nil || d.nextGroup == nil || d.nextConf == nil {
+		return errors.New("drand: no resharing in progress")
+	}
+	oldGroup := d.nextConf.OldNodes
+	if oldGroup.PublicKey == nil {
+		return errors.New("drand: no distributed key for the old group")
+	}
+	msg, err := d.nextGroup.TransitionMsg()
+	if err != nil {
+		return err
+	}
+	if err := key.Scheme.VerifyPartial(oldGroup.PublicKey.PubPoly(), msg, partial); err != nil {
+		return fmt.Errorf("drand: invalid partial signature over the new group: %v", err)
+	}
+	idx, err := key.Scheme.IndexOf(partial)
+	if err != nil {
+		return err
+	}
+	d.nextPartials[idx] = partial
+	return nil
+}
+
+// transitionSignature recovers the signature of the old group over the new
+// group from the partial signatures collected during the resharing. It fails
+// if the new group differs from the one the old nodes signed, i.e. if the
+// resharing left some nodes out. It must be called with the lock held.
+func (d *Drand) transitionSignature(oldGroup, newGroup *key.Group) ([]byte, error) {
+	if oldGroup.PublicKey == nil {
+		return nil, errors.New("drand: no distributed key for the old group")
+	}
+	if len(d.nextPartials) < oldGroup.Threshold {
+		return nil, fmt.Errorf("drand: only %d partial signatures over the new group, need %d", len(d.nextPartials), oldGroup.Threshold)
+	}
+	msg, err := newGroup.TransitionMsg()
+	if err != nil {
+		return nil, err
+	}
+	partials := make([][]byte, 0, len(d.nextPartials))
+	for _, p := range d.nextPartials {
+		partials = append(partials, p)
+	}
+	pub := oldGroup.PublicKey.PubPoly()
+	sig, err := key.Scheme.Recover(pub, msg, partials, oldGroup.Threshold, oldGroup.Len())
+	if err != nil {
+		return nil, err
+	}
+	if err := key.Scheme.VerifyRecovered(pub.Commit(), msg, sig); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// resetDKG clears the state of a DKG that did not finish, so a new one can
+// run.
+func (d *Drand) resetDKG() {
+	d.state.Lock()
+	defer d.state.Unlock()
+	d.dkg = nil
+	d.nextConf = nil
+	d.nextGroupHash = ""
+	d.nextChainHash = ""
+	d.nextGroup = nil
+	d.nextOldPresent = false
+	d.nextFirstReceived = false
+	d.nextPartial = nil
+	d.nextPartials = nil
+}
+
+// stopDKG stops the running dkg handler, if any
+func (d *Drand) stopDKG() {
+	d.state.Lock()
+	handler := d.dkg
+	d.state.Unlock()
+	if handler != nil {
+		handler.Stop()
+	}
+}
+
 // createDKG create the new dkg handler according to the nextConf field. If the
 // dkg is not nil, it does not do anything.
 func (d *Drand) createDKG(conf *dkg.Config) error {
@@ -230,48 +496,361 @@ func (d *Drand) StartBeacon(catchup bool) {
 	}
 }
 
+// pendingTransition holds what a node needs to keep using the old group after
+// a resharing, until the new group produced its first beacon.
+type pendingTransition struct {
+	oldGroup   *key.Group
+	oldShare   *key.Share
+	newGroup   *key.Group
+	oldPresent bool
+	newPresent bool
+	// cancel is closed when the operator cancels the transition
+	cancel    chan bool
+	cancelled bool
+	// done is closed once the transition is over or reverted
+	done chan bool
+	// chainHash is the hash of the new group. silent holds the indexes of the
+	// old nodes that stated the new group did not start and own is our own
+	// statement, once we made it.
+	chainHash string
+	silent    map[int]bool
+	own       *drand.NewGroupSilentPacket
+}
+
+// addSilent records the statement of a node of the old group that the new
+// group did not start. It must be called with the state lock held.
+func (pt *pendingTransition) addSilent(p *drand.NewGroupSilentPacket) error {
+	if p.GetChainHash() != pt.chainHash {
+		return errors.New("statement about another group")
+	}
+	index, err := verifyNewGroupSilent(pt.oldGroup, p)
+	if err != nil {
+		return err
+	}
+	pt.silent[index] = true
+	return nil
+}
+
+// signNewGroupSilent returns the statement, signed with the given key, that
+// the new group with the given hash did not start
+func signNewGroupSilent(priv *key.Pair, chainHash, beaconID string) (*drand.NewGroupSilentPacket, error) {
+	sig, err := key.AuthScheme.Sign(priv.Key, newGroupSilentMsg(chainHash))
+	if err != nil {
+		return nil, err
+	}
+	buff, _ := priv.Public.Key.MarshalBinary()
+	return &drand.NewGroupSilentPacket{
+		Key:       buff,
+		ChainHash: chainHash,
+		Signature: sig,
+		Metadata:  net.NewMetadata(beaconID),
+	}, nil
+}
+
+// verifyNewGroupSilent checks the statement is signed by a node of the given
+// old group and returns the index of the node.
+func verifyNewGroupSilent(oldGroup *key.Group, p *drand.NewGroupSilentPacket) (int, error) {
+	if err := net.CheckMetadata(p.GetMetadata()); err != nil {
+		return 0, err
+	}
+	pub := key.KeyGroup.Point()
+	if err := pub.UnmarshalBinary(p.GetKey()); err != nil {
+		return 0, fmt.Errorf("invalid key: %v", err)
+	}
+	index := indexOfKey(oldGroup.Nodes, pub)
+	if index < 0 {
+		return 0, errors.New("key not found in the old group")
+	}
+	if err := key.AuthScheme.Verify(pub, newGroupSilentMsg(p.GetChainHash()), p.GetSignature()); err != nil {
+		return 0, fmt.Errorf("invalid signature: %v", err)
+	}
+	return index, nil
+}
+
+// newGroupSilentMsg is the message a node of the old group signs with its
+// longterm key to state the new group with the given hash did not start
+func newGroupSilentMsg(chainHash string) []byte {
+	return []byte("drand-new-group-silent:" + chainHash)
+}
+
 // transition between an "old" group and a new group. This method is called
 // *after* a resharing dkg has proceed.
 // the new beacon syncs before the new network starts
 // and will start once the new network time kicks in. The old beacon will stop
 // just before the time of the new network.
+// Until the transition time, the operator can cancel the transition. After it,
+// the nodes of the old group check that the new group produces its first
+// beacon: otherwise they go back to the old group, once a threshold of them
+// agree the new group did not start.
 // TODO: due to current WaitDKG behavior, the old group is overwritten, so an
 // old node that fails during the time the resharing is done and the new network
 // comes up have to wait for the new network to comes in - that is to be fixed
-func (d *Drand) transition(oldGroup *key.Group, oldPresent, newPresent bool) {
+func (d *Drand) transition(oldGroup *key.Group, oldShare *key.Share, oldPresent, newPresent bool) {
+	d.state.Lock()
+	newGroup := d.group
+	newShare := d.share
+	chainHash, err := newGroup.ChainHash()
+	if err != nil {
+		d.log.Error("transition", "chain_hash", "err", err)
+	}
+	pt := &pendingTransition{
+		oldGroup:   oldGroup,
+		oldShare:   oldShare,
+		newGroup:   newGroup,
+		oldPresent: oldPresent,
+		newPresent: newPresent,
+		cancel:     make(chan bool),
+		done:       make(chan bool),
+		chainHash:  chainHash,
+		silent:     make(map[int]bool),
+	}
+	d.nextTransition = pt
+	d.state.Unlock()
+	defer func() {
+		d.state.Lock()
+		if d.nextTransition == pt {
+			d.nextTransition = nil
+		}
+		d.state.Unlock()
+		close(pt.done)
+	}()
+
 	// the node should stop a bit before the new round to avoid starting it at
 	// the same time as the new node
 	// NOTE: this limits the round time of drand - for now it is not a use
 	// case to go that fast
-	timeToStop := d.group.TransitionTime - 1
+	timeToStop := newGroup.TransitionTime - 1
 	if !newPresent {
-		//fmt.Printf(" OLD NODE STOPping %s\n", d.priv.Public.Address())
 		// an old node is leaving the network
-		if err := d.beacon.StopAt(timeToStop); err != nil {
-			d.log.Error("leaving_group", err)
-		} else {
-			d.log.Info("leaving_group", "done", "time", d.opts.clock.Now())
+		if !d.waitUntil(timeToStop, pt.cancel) {
+			d.revertTransition(pt)
+			return
 		}
+		d.StopBeacon()
+		d.log.Info("leaving_group", "done", "time", d.opts.clock.Now())
+	} else if oldPresent {
+		// tell the current beacon to stop just before the new network starts
+		d.beacon.TransitionNewGroup(newShare, d.keyIn(newGroup).Public, newGroup)
+	} else {
+		// a new node keeps the old group to verify the rounds it produced
+		if err := d.store.SaveHistoryGroup(oldGroup, beacon.StartRound(oldGroup)); err != nil {
+			d.log.Error("transition", "group_history", "err", err)
+		}
+		handler, err := d.newBeacon()
+		if err != nil {
+			d.log.Fatal("transition", "new_node", "err", err)
+		}
+		if err := handler.Transition(oldGroup); err != nil {
+			d.log.Error("sync_before", err)
+		}
+		d.log.Info("transition_new", "done")
+	}
+
+	if !d.waitUntil(newGroup.TransitionTime, pt.cancel) {
+		d.revertTransition(pt)
+		return
+	}
+	if !oldPresent {
+		d.rotateKey(newGroup)
+		return
+	}
+	// the old group keeps serving if the new one does not start
+	period := int64(newGroup.Period.Seconds())
+	checkTime := newGroup.TransitionTime + int64(DefaultTransitionCheckRounds)*period
+	if !d.waitUntil(checkTime, nil) {
+		return
+	}
+	if d.newGroupStarted(newGroup) || !d.agreeNewGroupSilent(pt) {
+		d.log.Info("transition", "new_group_started")
+		// the old key is kept until then in case the node goes back to
+		// the old group
+		d.rotateKey(newGroup)
 		return
 	}
+	d.log.Error("transition", "new_group_silent", "rounds", DefaultTransitionCheckRounds, "action", "keep_old_group")
+	d.revertTransition(pt)
+}
 
+// agreeNewGroupSilent states that the new group did not start and exchanges
+// this statement with the other nodes of the old group, until a threshold of
+// them agree. A node never goes back to the old group alone, since the other
+// nodes may have seen the new group: it returns false if the new group starts
+// in the meantime.
+func (d *Drand) agreeNewGroupSilent(pt *pendingTransition) bool {
+	own, err := signNewGroupSilent(d.priv, pt.chainHash, d.beaconID)
+	if err != nil {
+		d.log.Error("transition", "new_group_silent", "err", err)
+		return false
+	}
 	d.state.Lock()
-	newGroup := d.group
-	newShare := d.share
+	pt.own = own
+	if err := pt.addSilent(own); err != nil {
+		d.state.Unlock()
+		d.log.Error("transition", "new_group_silent", "err", err)
+		return false
+	}
 	d.state.Unlock()
 
-	// tell the current beacon to stop just before the new network starts
-	if oldPresent {
-		d.beacon.TransitionNewGroup(newShare, newGroup)
-	} else {
-		beacon, err := d.newBeacon()
-		if err != nil {
-			d.log.Fatal("transition", "new_node", "err", err)
+	ticker := time.NewTicker(DefaultConfirmGroupPeriod)
+	defer ticker.Stop()
+	for {
+		var missing []*key.Identity
+		d.state.Lock()
+		agreed := len(pt.silent)
+		for i, id := range pt.oldGroup.Nodes {
+			if !pt.silent[i] {
+				missing = append(missing, id)
+			}
 		}
-		if err := beacon.Transition(oldGroup); err != nil {
-			d.log.Error("sync_before", err)
+		d.state.Unlock()
+		if agreed >= pt.oldGroup.Threshold {
+			return true
+		}
+		d.log.Info("transition", "new_group_silent", "agreed", agreed, "threshold", pt.oldGroup.Threshold)
+		var wg sync.WaitGroup
+		for _, id := range missing {
+			wg.Add(1)
+			go func(id *key.Identity) {
+				defer wg.Done()
+				ctx, cancel := context.WithTimeout(context.Background(), DefaultDialTimeout)
+				defer cancel()
+				resp, err := d.gateway.ProtocolClient.NewGroupSilent(ctx, id, own)
+				if err != nil {
+					d.log.Debug("transition", "new_group_silent", "to", id.Address(), "err", err)
+					return
+				}
+				d.state.Lock()
+				err = pt.addSilent(resp)
+				d.state.Unlock()
+				if err != nil {
+					d.log.Error("transition", "new_group_silent", "from", id.Address(), "err", err)
+				}
+			}(id)
+		}
+		wg.Wait()
+		d.state.Lock()
+		agreed = len(pt.silent)
+		d.state.Unlock()
+		if agreed >= pt.oldGroup.Threshold {
+			return true
+		}
+		if d.newGroupStarted(pt.newGroup) {
+			return false
+		}
+		<-ticker.C
+	}
+}
+
+// waitUntil waits until the given unix time. It returns false if the given
+// channel is closed before.
+func (d *Drand) waitUntil(t int64, cancel chan bool) bool {
+	wait := time.Duration(t-d.opts.clock.Now().Unix()) * time.Second
+	if wait <= 0 {
+		return true
+	}
+	select {
+	case <-d.opts.clock.After(wait):
+		return true
+	case <-cancel:
+		return false
+	}
+}
+
+// newGroupStarted returns true if this node or one of the nodes of the given
+// group has a beacon produced by that group.
+func (d *Drand) newGroupStarted(newGroup *key.Group) bool {
+	nRound, _ := beacon.NextRound(newGroup.TransitionTime, newGroup.Period, newGroup.GenesisTime)
+	firstRound := nRound - 1
+	d.state.Lock()
+	handler := d.beacon
+	d.state.Unlock()
+	if handler != nil {
+		if last, err := handler.Store().Last(); err == nil && last.Round >= firstRound {
+			return true
+		}
+	}
+	for _, id := range newGroup.Nodes {
+		if id.Address() == d.priv.Public.Address() {
+			continue
+		}
+		if d.peerHasRound(id, firstRound) {
+			return true
 		}
-		d.log.Info("transition_new", "done")
+	}
+	return false
+}
+
+// peerHasRound returns true if the given node has the beacon of the given
+// round.
+func (d *Drand) peerHasRound(p net.Peer, round uint64) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultDialTimeout)
+	defer cancel()
+	beacons, err := d.gateway.ProtocolClient.SyncChain(ctx, p, &drand.SyncRequest{FromRound: round, Metadata: net.NewMetadata(d.beaconID)})
+	if err != nil {
+		d.log.Debug("transition_check", p.Address(), "err", err)
+		return false
+	}
+	b, ok := <-beacons
+	// the stream only stops once the channel is drained
+	go func() {
+		for range beacons {
+		}
+	}()
+	return ok && b.GetRound() >= round
+}
+
+// revertTransition goes back to the group and share used before the
+// resharing. A node that was not part of the old group stops its beacon and
+// forgets the new group.
+func (d *Drand) revertTransition(pt *pendingTransition) {
+	d.log.Info("transition", "revert", "transition_time", pt.newGroup.TransitionTime)
+	if !pt.oldPresent {
+		d.StopBeacon()
+		d.state.Lock()
+		defer d.state.Unlock()
+		d.group = nil
+		d.share = nil
+		d.pub = nil
+		d.dkgDone = false
+		if err := d.store.Reset(); err != nil {
+			d.log.Error("transition_revert", "reset", "err", err)
+		}
+		return
+	}
+
+	d.state.Lock()
+	pub := pt.oldShare.Public()
+	err := d.store.SaveGroup(pt.oldGroup)
+	if err == nil {
+		err = d.store.SaveShare(pt.oldShare)
+	}
+	if err == nil {
+		err = d.store.SaveDistPublic(pub)
+	}
+	if err != nil {
+		// the node would restart with the new group
+		d.state.Unlock()
+		d.log.Error("transition_revert", "save", "err", err)
+		return
+	}
+	d.group = pt.oldGroup
+	d.share = pt.oldShare
+	d.pub = pub
+	if err := d.store.DeleteHistoryGroup(beacon.StartRound(pt.newGroup)); err != nil {
+		d.log.Error("transition_revert", "group_history", "err", err)
+	}
+	handler := d.beacon
+	d.state.Unlock()
+
+	if pt.newPresent {
+		if handler != nil {
+			handler.CancelTransition(pt.newGroup)
+		}
+		return
+	}
+	if handler == nil {
+		// the node already left the group
+		d.StartBeacon(true)
 	}
 }
 
@@ -286,19 +865,14 @@ func (d *Drand) StopBeacon() {
 	d.beacon = nil
 }
 
-// Stop simply stops all drand operations.
+// Stop simply stops all drand operations, of all the chains of the daemon.
 func (d *Drand) Stop() {
-	d.StopBeacon()
-	d.state.Lock()
-	d.gateway.StopAll()
-	d.control.Stop()
-	d.state.Unlock()
-	d.exitCh <- true
+	d.daemon.Stop()
 }
 
 // WaitExit returns a channel that signals when drand stops its operations
 func (d *Drand) WaitExit() chan bool {
-	return d.exitCh
+	return d.daemon.WaitExit()
 }
 
 // isDKGDone returns true if the DKG protocol has already been executed. That
@@ -310,17 +884,36 @@ func (d *Drand) isDKGDone() bool {
 }
 
 func (d *Drand) newBeacon() (*beacon.Handler, error) {
-	fs.CreateSecureFolder(d.opts.DBFolder())
-	store, err := beacon.NewBoltStore(d.opts.dbFolder, d.opts.boltOpts)
+	store, err := d.opts.openBeaconStore(d.beaconID)
 	if err != nil {
 		return nil, err
 	}
 
+	history, err := d.store.LoadGroupHistory()
+	if err != nil {
+		d.log.Error("init_beacon", "group_history", "err", err)
+	}
 	conf := &beacon.Config{
-		Group:   d.group,
-		Private: d.priv,
-		Share:   d.share,
-		Clock:   d.opts.clock,
+		Group:          d.group,
+		Private:        d.keyIn(d.group),
+		Share:          d.share,
+		Clock:          d.opts.clock,
+		History:        history,
+		BeaconID:       d.beaconID,
+		SyncPeers:      d.opts.syncPeers,
+		SyncMaxCount:   d.opts.syncMax,
+		SyncMaxPerPeer: d.opts.syncPerPeer,
+	}
+	if d.usesRemoteSigner() {
+		signer, err := d.remoteSigner()
+		if err != nil && d.share == nil {
+			return nil, err
+		} else if err != nil {
+			// e.g. after a DKG resumed with a signer
+			d.log.Error("init_beacon", "remote_signer", "err", err, "fallback", "local_share")
+		} else {
+			conf.Signer = signer
+		}
 	}
 	beacon, err := beacon.NewHandler(d.gateway.ProtocolClient, store, conf, d.log)
 	if err != nil {
@@ -331,6 +924,25 @@ func (d *Drand) newBeacon() (*beacon.Handler, error) {
 	return d.beacon, nil
 }
 
+// usesRemoteSigner returns true if the share of the chain is held by the
+// signer process. Only the default chain can use one.
+func (d *Drand) usesRemoteSigner() bool {
+	return d.opts.RemoteSigner() != "" && d.beaconID == DefaultBeaconID
+}
+
+// remoteSigner returns the signer process holding the share of the current
+// group. It must be called with the lock held.
+func (d *Drand) remoteSigner() (beacon.Signer, error) {
+	if d.signerClient == nil {
+		client, err := net.NewUnixSignerClient(d.opts.RemoteSigner())
+		if err != nil {
+			return nil, err
+		}
+		d.signerClient = client
+	}
+	return beacon.NewRemoteSigner(d.signerClient, d.group.PublicKey)
+}
+
 func (d *Drand) beaconCallback(b *beacon.Beacon) {
 	d.opts.callbacks(b)
 }
@@ -339,15 +951,18 @@ func (d *Drand) beaconCallback(b *beacon.Beacon) {
 // instead of offloading that to an external struct without any vision of drand
 // internals, or implementing a big "Send" method directly on drand.
 func (d *Drand) sendDkgPacket(p net.Peer, pack *dkg_proto.Packet) error {
-	_, err := d.gateway.ProtocolClient.FreshDKG(context.TODO(), p, &drand.DKGPacket{Dkg: pack})
+	_, err := d.gateway.ProtocolClient.FreshDKG(context.TODO(), p, &drand.DKGPacket{Dkg: pack, Metadata: net.NewMetadata(d.beaconID)})
 	return err
 }
 
 func (d *Drand) sendResharePacket(p net.Peer, pack *dkg_proto.Packet) error {
 	// no concurrency to get nextHash since this is only used within a locked drand
 	reshare := &drand.ResharePacket{
-		Dkg:       pack,
-		GroupHash: d.nextGroupHash,
+		Dkg:              pack,
+		GroupHash:        d.nextGroupHash,
+		ChainHash:        d.nextChainHash,
+		PartialSignature: d.nextPartial,
+		Metadata:         net.NewMetadata(d.beaconID),
 	}
 	_, err := d.gateway.ProtocolClient.ReshareDKG(context.TODO(), p, reshare)
 	return err
@@ -6,9 +6,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/BurntSushi/toml"
+	"github.com/drand/drand/beacon"
 	"github.com/drand/drand/dkg"
 	"github.com/drand/drand/entropy"
 	"github.com/drand/drand/key"
@@ -29,42 +33,93 @@ func (d *Drand) InitDKG(c context.Context, in *control.InitDKGPacket) (*control.
 		return nil, errors.New("dkg phase already done - call reshare")
 	}
 	d.state.Unlock()
+	ctx, done, err := d.startSetup(isLeader, in.GetInfo().GetSecret(), d.priv)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	if in.GetGroup() != nil {
+		// the group has been agreed upon beforehand, no leader involved
+		return d.setupFromGroup(ctx, in)
+	}
 	if !isLeader {
 		// different logic for leader than the rest
-		return d.setupAutomaticDKG(c, in)
+		return d.setupAutomaticDKG(ctx, in)
 	}
 	d.log.Info("init_dkg", "begin", "time", d.opts.clock.Now().Unix(), "leader", true)
 
 	// setup the manager
 	newSetup := func() (*setupManager, error) {
-		return newDKGSetup(d.log, d.opts.clock, d.priv.Public, in.GetBeaconPeriod(), in.GetInfo())
+		return newDKGSetup(d.log, d.opts.clock, d.priv, in.GetBeaconPeriod(), in.GetInfo())
 	}
 
 	// expect the group
-	group, err := d.leaderRunSetup(in.GetInfo(), newSetup)
-	if err != nil {
+	group, err := d.leaderRunSetup(ctx, newSetup)
+	if err == errSetupCancelled {
+		return nil, err
+	} else if err != nil {
 		return nil, fmt.Errorf("drand: invalid setup configuration: %s", err)
 	}
 
-	protoGroup := groupToProto(group)
+	protoGroup, err := groupToProto(group)
+	if err != nil {
+		return nil, err
+	}
 	packet := &drand.PushGroupPacket{
 		NewGroup:    protoGroup,
 		SecretProof: in.GetInfo().GetSecret(),
+		Metadata:    dnet.NewMetadata(d.beaconID),
 	}
-	// send it to everyone in the group nodes
-	nodes := group.Nodes
-	if err := d.pushGroup(nodes, packet); err != nil {
+	// send it to everyone in the group nodes and wait for their
+	// acknowledgement, in time to run the DKG before the genesis time
+	if err := d.pushGroupAndWaitAcks(ctx, group, group.Nodes, packet, group.GenesisTime, in.GetInfo().GetTimeout()); err != nil {
 		return nil, err
 	}
 
-	finalGroup, err := d.runDKG(true, group, in.GetInfo().GetTimeout(), in.GetEntropy())
+	finalGroup, err := d.runDKG(ctx, true, group, in.GetInfo().GetTimeout(), in.GetEntropy())
 	if err != nil {
 		return nil, err
 	}
-	return groupToProto(finalGroup), nil
+	return groupToProto(finalGroup)
+}
+
+// errSetupCancelled is returned when the setup is cancelled by the operator or
+// by another node of the setup
+var errSetupCancelled = errors.New("drand: setup cancelled")
+
+// startSetup registers a new setup, and the DKG that follows, which CancelDKG
+// aborts through the returned context. The returned function must be called
+// once the setup is over.
+func (d *Drand) startSetup(leader bool, secret string, priv *key.Pair) (context.Context, func(), error) {
+	d.state.Lock()
+	defer d.state.Unlock()
+	if d.inProgress != nil {
+		return nil, nil, errors.New("drand: setup already in progress")
+	}
+	if d.usesRemoteSigner() {
+		// the daemon would hold the new share, and can't deal from the share
+		// of the signer when resharing
+		return nil, nil, errors.New("drand: can't run a DKG with a remote signer, restart the daemon without --signer")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &setupRun{
+		cancel: cancel,
+		doneCh: make(chan bool),
+		leader: leader,
+		secret: secret,
+		key:    priv,
+	}
+	d.inProgress = run
+	return ctx, func() {
+		cancel()
+		d.state.Lock()
+		d.inProgress = nil
+		d.state.Unlock()
+		close(run.doneCh)
+	}, nil
 }
 
-func (d *Drand) leaderRunSetup(in *control.SetupInfoPacket, newSetup func() (*setupManager, error)) (*key.Group, error) {
+func (d *Drand) leaderRunSetup(c context.Context, newSetup func() (*setupManager, error)) (*key.Group, error) {
 	// setup the manager
 	d.state.Lock()
 	if d.manager != nil {
@@ -94,6 +149,9 @@ func (d *Drand) leaderRunSetup(in *control.SetupInfoPacket, newSetup func() (*se
 			addr = append(addr, k.Address())
 		}
 		d.log.Debug("init_dkg", "setup_phase", "keys_received", "["+strings.Join(addr, "-")+"]")
+	case <-c.Done():
+		manager.StopPreemptively()
+		return nil, errSetupCancelled
 	case <-time.After(MaxWaitPrepareDKG):
 		d.log.Debug("init_dkg", "time_out")
 		manager.StopPreemptively()
@@ -102,12 +160,16 @@ func (d *Drand) leaderRunSetup(in *control.SetupInfoPacket, newSetup func() (*se
 	return group, nil
 }
 
-func (d *Drand) runDKG(leader bool, group *key.Group, timeout string, entropy *control.EntropyInfo) (*key.Group, error) {
+func (d *Drand) runDKG(c context.Context, leader bool, group *key.Group, timeout string, entropy *control.EntropyInfo) (*key.Group, error) {
 	// XXX not using the opts.Clock since that's something that happens anyway
 	// to use opts.Clock we need to have callbacks when the group is finished
 	// and then move on the clock of this time
 	// TODO change that
-	time.Sleep(DefaultSyncTime)
+	select {
+	case <-time.After(DefaultSyncTime):
+	case <-c.Done():
+		return nil, errSetupCancelled
+	}
 
 	reader, user := extractEntropy(entropy)
 	dkgConfig := &dkg.Config{
@@ -121,9 +183,16 @@ func (d *Drand) runDKG(leader bool, group *key.Group, timeout string, entropy *c
 	if err := setTimeout(dkgConfig, timeout); err != nil {
 		return nil, fmt.Errorf("drand: invalid timeout: %s", err)
 	}
+	if err := d.journalDKG(leader, dkgConfig); err != nil {
+		return nil, fmt.Errorf("drand: can't save dkg state: %s", err)
+	}
 	d.state.Lock()
 	d.nextConf = dkgConfig
 	d.state.Unlock()
+	if err := d.createDKG(dkgConfig); err != nil {
+		return nil, err
+	}
+	defer d.stopDKGOnCancel(c)()
 
 	if leader {
 		d.log.Info("init_dkg", "start_dkg_leader")
@@ -134,7 +203,9 @@ func (d *Drand) runDKG(leader bool, group *key.Group, timeout string, entropy *c
 
 	d.log.Info("init_dkg", "waiting_end_dkg")
 	finalGroup, err := d.WaitDKG(dkgConfig)
-	if err != nil {
+	if err != nil && c.Err() != nil {
+		return nil, errSetupCancelled
+	} else if err != nil {
 		return nil, fmt.Errorf("drand: err during DKG: %v", err)
 	}
 	d.log.Info("init_dkg", "dkg_done")
@@ -143,14 +214,16 @@ func (d *Drand) runDKG(leader bool, group *key.Group, timeout string, entropy *c
 	return finalGroup, nil
 }
 
-func (d *Drand) runResharing(leader bool, oldGroup, newGroup *key.Group, timeout string) (*key.Group, error) {
+func (d *Drand) runResharing(c context.Context, leader bool, oldGroup, newGroup *key.Group, timeout string) (*key.Group, error) {
 	oldIdx, oldPresent := oldGroup.Index(d.priv.Public)
-	_, newPresent := newGroup.Index(d.priv.Public)
+	newKey := d.keyIn(newGroup)
+	_, newPresent := newGroup.Index(newKey.Public)
 	dkgConfig := &dkg.Config{
 		Suite:    key.KeyGroup.(dkg.Suite),
 		NewNodes: newGroup,
 		OldNodes: oldGroup,
 		Key:      d.priv,
+		NewKey:   newKey,
 		Clock:    d.opts.clock,
 	}
 	if err := setTimeout(dkgConfig, timeout); err != nil {
@@ -177,14 +250,24 @@ func (d *Drand) runResharing(leader bool, oldGroup, newGroup *key.Group, timeout
 			return err
 		}
 		d.nextGroupHash = nextHash
+		if d.nextChainHash, err = newGroup.ChainHash(); err != nil {
+			return err
+		}
 		d.nextGroup = newGroup
 		d.nextConf = dkgConfig
 		d.nextOldPresent = oldPresent
-		return nil
+		return d.signNextGroup(newGroup, dkgConfig.Share)
 	}()
 	if err != nil {
 		return nil, err
 	}
+	if err := d.journalDKG(leader, dkgConfig); err != nil {
+		return nil, fmt.Errorf("drand: can't save dkg state: %s", err)
+	}
+	if err := d.createDKG(dkgConfig); err != nil {
+		return nil, err
+	}
+	defer d.stopDKGOnCancel(c)()
 
 	if leader {
 		d.log.Info("init_dkg", "start_dkg_leader")
@@ -193,16 +276,32 @@ func (d *Drand) runResharing(leader bool, oldGroup, newGroup *key.Group, timeout
 
 	d.log.Info("init_dkg", "wait_dkg_end")
 	finalGroup, err := d.WaitDKG(dkgConfig)
-	if err != nil {
+	if err != nil && c.Err() != nil {
+		return nil, errSetupCancelled
+	} else if err != nil {
 		return nil, fmt.Errorf("drand: err during DKG: %v", err)
 	}
 	d.log.Info("dkg_reshare", "finished")
 	// runs the transition of the beacon
-	go d.transition(oldGroup, oldPresent, newPresent)
+	go d.transition(oldGroup, dkgConfig.Share, oldPresent, newPresent)
 
 	return finalGroup, nil
 }
 
+// stopDKGOnCancel stops the running dkg handler if the setup is cancelled,
+// until the returned function is called.
+func (d *Drand) stopDKGOnCancel(c context.Context) func() {
+	over := make(chan bool)
+	go func() {
+		select {
+		case <-c.Done():
+			d.stopDKG()
+		case <-over:
+		}
+	}()
+	return func() { close(over) }
+}
+
 // This method sends the public key to the denoted leader address and then waits
 // to receive the group file. After receiving it, it starts the DKG process in
 // "waiting" mode, waiting for the leader to send the first packet.
@@ -220,7 +319,9 @@ func (d *Drand) setupAutomaticDKG(c context.Context, in *control.InitDKGPacket)
 		d.state.Unlock()
 		return nil, errors.New("drand: already waiting for an automatic setup")
 	}
-	receiver := newSetupReceiver(d.log, in.GetInfo())
+	d.inProgress.setPeers([]dnet.Peer{lpeer})
+	setup := key.SetupParams(n, thr, uint64(dkgTimeout.Seconds()), "")
+	receiver := newSetupReceiver(d.log, in.GetInfo(), setup)
 	d.receiver = receiver
 	d.state.Unlock()
 
@@ -230,12 +331,10 @@ func (d *Drand) setupAutomaticDKG(c context.Context, in *control.InitDKGPacket)
 		d.receiver = nil
 		d.state.Unlock()
 	}()
-	// send public key to leader
-	key, _ := d.priv.Public.Key.MarshalBinary()
-	id := &drand.Identity{
-		Address: d.priv.Public.Address(),
-		Key:     key,
-		Tls:     d.priv.Public.IsTLS(),
+	// send public key to leader, along with the proof we own it
+	id, err := d.signedIdentity(d.priv, setup)
+	if err != nil {
+		return nil, err
 	}
 	prep := &drand.PrepareDKGPacket{
 		Node:        id,
@@ -243,19 +342,25 @@ func (d *Drand) setupAutomaticDKG(c context.Context, in *control.InitDKGPacket)
 		Threshold:   uint32(thr),
 		DkgTimeout:  uint64(dkgTimeout.Seconds()),
 		SecretProof: in.GetInfo().GetSecret(),
+		Metadata:    dnet.NewMetadata(d.beaconID),
 	}
 
 	d.log.Debug("init_dkg", "send_key", "leader", lpeer.Address())
-	err = d.gateway.ProtocolClient.PrepareDKGGroup(context.Background(), lpeer, prep)
+	resp, err := d.gateway.ProtocolClient.PrepareDKGGroup(c, lpeer, prep)
 	if err != nil {
 		return nil, fmt.Errorf("drand: err when receiving group: %s", err)
 	}
+	d.trustLeader(resp, setup)
 
 	d.log.Debug("init_dkg", "wait_group")
 	var groupPacket *drand.GroupPacket
 	select {
 	case groupPacket = <-d.receiver.WaitGroup():
 		d.log.Debug("init_dkg", "received_group")
+	case err := <-d.receiver.WaitError():
+		return nil, fmt.Errorf("drand: invalid group from leader: %s", err)
+	case <-c.Done():
+		return nil, errSetupCancelled
 	case <-d.opts.clock.After(MaxWaitPrepareDKG):
 		d.log.Error("init_dkg", "wait_group", "timeout")
 		return nil, errors.New("wait_group timeouts from coordinator")
@@ -276,16 +381,278 @@ func (d *Drand) setupAutomaticDKG(c context.Context, in *control.InitDKGPacket)
 		fmt.Println("priv is ", d.priv.Public.String(), " but received group is ", group.String())
 		return nil, errors.New("drand: public key not found in group")
 	}
+	if err := d.acknowledgeGroup(c, lpeer, group, groupPacket); err != nil {
+		return nil, err
+	}
 	d.state.Lock()
 	d.index = index
 	d.state.Unlock()
 
 	// run the dkg !
-	finalGroup, err := d.runDKG(false, group, in.GetInfo().GetTimeout(), in.GetEntropy())
+	finalGroup, err := d.runDKG(c, false, group, in.GetInfo().GetTimeout(), in.GetEntropy())
+	if err != nil {
+		return nil, err
+	}
+	return groupToProto(finalGroup)
+}
+
+// setupFromGroup runs the DKG with a group agreed upon by all participants
+// beforehand. Each node sends the hash of its group to all the others and the
+// DKG only starts once every member confirmed the same hash.
+func (d *Drand) setupFromGroup(c context.Context, in *control.InitDKGPacket) (*control.GroupPacket, error) {
+	group, err := extractGroup(in.GetGroup())
+	if err != nil {
+		return nil, err
+	}
+	if group.PublicKey != nil {
+		return nil, errors.New("control: group already has a distributed key")
+	}
+	if group.Period == 0 {
+		return nil, errors.New("control: group without period")
+	}
+	if group.GenesisTime < d.opts.clock.Now().Unix() {
+		return nil, errors.New("control: group with genesis time in the past")
+	}
+	index, found := group.Index(d.priv.Public)
+	if !found {
+		return nil, errors.New("drand: public key not found in group")
+	}
+	confirmer, err := newGroupConfirmer(d.log, d.priv, group, group.Nodes, d.beaconID)
+	if err != nil {
+		return nil, err
+	}
+	d.state.Lock()
+	if d.confirmer != nil || d.manager != nil || d.receiver != nil {
+		d.state.Unlock()
+		return nil, errors.New("drand: setup already in progress")
+	}
+	d.confirmer = confirmer
+	d.index = index
+	d.state.Unlock()
+	// the confirmer keeps answering until the end of the DKG, for the nodes
+	// that did not receive our reply yet
+	defer func() {
+		d.state.Lock()
+		d.confirmer = nil
+		d.state.Unlock()
+	}()
+
+	d.log.Info("init_dkg", "begin", "leader", false, "group_hash", confirmer.hash)
+	if err := d.confirmGroup(c, confirmer); err != nil {
+		return nil, err
+	}
+	d.log.Info("init_dkg", "group_confirmed", "group_hash", confirmer.hash)
+	// every node starts sending its deals
+	finalGroup, err := d.runDKG(c, true, group, in.GetInfo().GetTimeout(), in.GetEntropy())
 	if err != nil {
 		return nil, err
 	}
-	return groupToProto(finalGroup), nil
+	return groupToProto(finalGroup)
+}
+
+// confirmGroup sends the hash of our group to every member that did not
+// confirm it yet, until all of them did.
+func (d *Drand) confirmGroup(c context.Context, confirmer *groupConfirmer) error {
+	timeout := time.After(MaxWaitPrepareDKG)
+	ticker := time.NewTicker(DefaultConfirmGroupPeriod)
+	defer ticker.Stop()
+	packet := confirmer.Packet()
+	for {
+		var wg sync.WaitGroup
+		for _, id := range confirmer.Missing() {
+			wg.Add(1)
+			go func(id *key.Identity) {
+				defer wg.Done()
+				ctx, cancel := context.WithTimeout(c, DefaultPushDKGTimeout)
+				defer cancel()
+				resp, err := d.gateway.ProtocolClient.ConfirmGroup(ctx, id, packet)
+				if err != nil {
+					d.log.Debug("confirm_group", "failed", "to", id.Address(), "err", err)
+					return
+				}
+				if err := confirmer.Received(resp); err != nil {
+					d.log.Error("confirm_group", "invalid_reply", "from", id.Address(), "err", err)
+				}
+			}(id)
+		}
+		wg.Wait()
+		select {
+		case <-confirmer.WaitConfirmed():
+			return nil
+		case err := <-confirmer.WaitError():
+			return fmt.Errorf("drand: inconsistent group: %s", err)
+		case <-c.Done():
+			return errSetupCancelled
+		case <-timeout:
+			return fmt.Errorf("drand: time out waiting for group confirmations from %s", addresses(confirmer.Missing()))
+		case <-ticker.C:
+		}
+	}
+}
+
+// pushGroupAndWaitAcks sends the group to the given nodes and waits until
+// each member of the group acknowledged it, once its operator accepted the
+// group. The DKG must still be able to finish before the given start time of
+// the group, the genesis or transition time: past the start time minus the DKG
+// timeout, the setup fails and the nodes are told to abort it, so the ones
+// that acknowledged the group don't wait for the DKG.
+func (d *Drand) pushGroupAndWaitAcks(c context.Context, group *key.Group, to []*key.Identity, packet *drand.PushGroupPacket, start int64, timeout string) error {
+	dkgTimeout, err := parseDKGTimeout(timeout)
+	if err != nil {
+		return fmt.Errorf("drand: invalid timeout: %s", err)
+	}
+	deadline := time.Unix(start, 0).Add(-dkgTimeout)
+	if !d.opts.clock.Now().Before(deadline) {
+		return errors.New("control: not enough time to run the DKG before the group starts")
+	}
+	priv := d.keyIn(group)
+	nodes := []*key.Identity{priv.Public}
+	for _, id := range group.Nodes {
+		if id.Address() != priv.Public.Address() {
+			nodes = append(nodes, id)
+		}
+	}
+	confirmer, err := newGroupConfirmer(d.log, priv, group, nodes, d.beaconID)
+	if err != nil {
+		return err
+	}
+	d.state.Lock()
+	if d.confirmer != nil {
+		d.state.Unlock()
+		return errors.New("drand: setup already in progress")
+	}
+	d.confirmer = confirmer
+	d.state.Unlock()
+	defer func() {
+		d.state.Lock()
+		d.confirmer = nil
+		d.state.Unlock()
+	}()
+
+	peers := make([]dnet.Peer, len(to))
+	for i, id := range to {
+		peers[i] = id
+	}
+	d.state.Lock()
+	run := d.inProgress
+	d.state.Unlock()
+	run.setPeers(peers)
+	run.setNodes(to)
+	err = d.pushGroup(c, to, packet)
+	if err == nil {
+		err = d.waitAcks(c, confirmer, deadline)
+	}
+	if err != nil && err != errSetupCancelled {
+		var others []dnet.Peer
+		for _, p := range peers {
+			if p.Address() != d.priv.Public.Address() {
+				others = append(others, p)
+			}
+		}
+		d.log.Info("push_group", "aborting", "notify", len(others), "err", err)
+		d.notifyAbort(run, others)
+	}
+	return err
+}
+
+// waitAcks waits until all the nodes acknowledged the group, at the latest
+// until the given deadline.
+func (d *Drand) waitAcks(c context.Context, confirmer *groupConfirmer, deadline time.Time) error {
+	d.log.Info("push_group", "wait_acks", "chain_hash", confirmer.chainHash, "deadline", deadline.Unix())
+	select {
+	case <-confirmer.WaitConfirmed():
+		d.log.Info("push_group", "group_acknowledged", "chain_hash", confirmer.chainHash)
+		return nil
+	case err := <-confirmer.WaitError():
+		return fmt.Errorf("drand: inconsistent group: %s", err)
+	case <-c.Done():
+		return errSetupCancelled
+	case <-d.opts.clock.After(deadline.Sub(d.opts.clock.Now())):
+		return fmt.Errorf("drand: group not acknowledged in time to run the DKG by %s", addresses(confirmer.Missing()))
+	case <-time.After(MaxWaitPrepareDKG):
+		return fmt.Errorf("drand: time out waiting for group acknowledgements from %s", addresses(confirmer.Missing()))
+	}
+}
+
+// acknowledgeGroup waits for the operator to accept the group received from
+// the leader, identified by its chain hash, and then sends the signed hash of
+// the group to the leader.
+func (d *Drand) acknowledgeGroup(c context.Context, leader dnet.Peer, group *key.Group, packet *drand.GroupPacket) error {
+	hash, err := group.ChainHash()
+	if err != nil {
+		return err
+	}
+	d.receiver.SetPending(packet, hash)
+	d.log.Info("setup", "wait_group_acceptance", "chain_hash", hash)
+	select {
+	case accept := <-d.receiver.WaitAccept():
+		if !accept {
+			return errors.New("drand: group rejected by the operator")
+		}
+	case <-c.Done():
+		return errSetupCancelled
+	case <-time.After(MaxWaitPrepareDKG):
+		return errors.New("drand: time out waiting for the group to be accepted")
+	}
+	ack, err := signGroupHash(d.keyIn(group), group, d.beaconID)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(c, DefaultPushDKGTimeout)
+	defer cancel()
+	if _, err := d.gateway.ProtocolClient.ConfirmGroup(ctx, leader, ack); err != nil {
+		return fmt.Errorf("drand: can't acknowledge group to leader: %s", err)
+	}
+	d.log.Info("setup", "group_acknowledged", "group_hash", hash)
+	return nil
+}
+
+func addresses(ids []*key.Identity) string {
+	addrs := make([]string, len(ids))
+	for i, id := range ids {
+		addrs[i] = id.Address()
+	}
+	return strings.Join(addrs, ", ")
+}
+
+// signedIdentity returns the protobuf version of the identity of the given key
+// pair, signed over the given setup parameters.
+func (d *Drand) signedIdentity(priv *key.Pair, setup []byte) (*drand.Identity, error) {
+	id, err := priv.SignedIdentity(setup)
+	if err != nil {
+		return nil, fmt.Errorf("drand: can't sign identity: %s", err)
+	}
+	return identitiesToProto([]*key.Identity{id})[0], nil
+}
+
+// trustLeader allows the leader that replied to our key to abort the setup.
+// The abort of an older leader, which doesn't send its identity, or of a
+// leader whose identity is invalid is rejected: the setup then times out.
+func (d *Drand) trustLeader(resp *drand.PrepareDKGResponse, setup []byte) {
+	if resp.GetLeader() == nil {
+		d.log.Info("init_dkg", "leader_without_identity")
+		return
+	}
+	leader, err := protoToIdentity(resp.GetLeader())
+	if err == nil {
+		err = leader.ValidSignature(setup)
+	}
+	if err != nil {
+		d.log.Error("init_dkg", "invalid_leader_identity", "err", err)
+		return
+	}
+	d.inProgress.setNodes([]*key.Identity{leader})
+}
+
+// verifyPossession checks that every node of the group sent by the leader
+// proved the possession of its private key for the same setup parameters.
+func verifyPossession(group *key.Group, setup []byte) error {
+	for _, n := range group.Nodes {
+		if err := n.ValidSignature(setup); err != nil {
+			return fmt.Errorf("control: node %s in group: %s", n.Address(), err)
+		}
+	}
+	return nil
 }
 
 // similar to setupAutomaticDKG but with additional verification and information
@@ -299,6 +666,10 @@ func (d *Drand) setupAutomaticResharing(c context.Context, oldGroup *key.Group,
 	if err != nil {
 		return nil, err
 	}
+	oldChainHash, err := oldGroup.ChainHash()
+	if err != nil {
+		return nil, err
+	}
 	// determine the leader's address
 	laddr := in.GetInfo().GetLeaderAddress()
 	lpeer := dnet.CreatePeer(laddr, in.GetInfo().GetLeaderTls())
@@ -307,7 +678,9 @@ func (d *Drand) setupAutomaticResharing(c context.Context, oldGroup *key.Group,
 		d.state.Unlock()
 		return nil, errors.New("drand: already waiting for an automatic setup")
 	}
-	receiver := newSetupReceiver(d.log, in.GetInfo())
+	d.inProgress.setPeers([]dnet.Peer{lpeer})
+	setup := key.SetupParams(n, thr, uint64(dkgTimeout.Seconds()), oldHash)
+	receiver := newSetupReceiver(d.log, in.GetInfo(), setup)
 	d.receiver = receiver
 	d.state.Unlock()
 
@@ -317,12 +690,10 @@ func (d *Drand) setupAutomaticResharing(c context.Context, oldGroup *key.Group,
 		d.receiver = nil
 		d.state.Unlock()
 	}()
-	// send public key to leader
-	key, _ := d.priv.Public.Key.MarshalBinary()
-	id := &drand.Identity{
-		Address: d.priv.Public.Address(),
-		Key:     key,
-		Tls:     d.priv.Public.IsTLS(),
+	// send public key to leader, along with the proof we own it
+	id, err := d.signedIdentity(d.reshareKey(), setup)
+	if err != nil {
+		return nil, err
 	}
 	prep := &drand.PrepareDKGPacket{
 		Node:              id,
@@ -331,6 +702,8 @@ func (d *Drand) setupAutomaticResharing(c context.Context, oldGroup *key.Group,
 		DkgTimeout:        uint64(dkgTimeout.Seconds()),
 		SecretProof:       in.GetInfo().GetSecret(),
 		PreviousGroupHash: oldHash,
+		PreviousChainHash: oldChainHash,
+		Metadata:          dnet.NewMetadata(d.beaconID),
 	}
 
 	// we wait only a certain amount of time for the prepare phase
@@ -338,15 +711,20 @@ func (d *Drand) setupAutomaticResharing(c context.Context, oldGroup *key.Group,
 	defer cancel()
 
 	// expect group
-	err = d.gateway.ProtocolClient.PrepareDKGGroup(nc, lpeer, prep)
+	resp, err := d.gateway.ProtocolClient.PrepareDKGGroup(nc, lpeer, prep)
 	if err != nil {
 		return nil, fmt.Errorf("drand: err when receiving group: %s", err)
 	}
+	d.trustLeader(resp, setup)
 
 	var groupPacket *drand.GroupPacket
 	select {
 	case groupPacket = <-d.receiver.WaitGroup():
 		d.log.Debug("setup_reshare", "received_group")
+	case err := <-d.receiver.WaitError():
+		return nil, fmt.Errorf("drand: invalid group from leader: %s", err)
+	case <-c.Done():
+		return nil, errSetupCancelled
 	case <-d.opts.clock.After(MaxWaitPrepareDKG):
 		d.log.Error("setup_reshare", "prepare_dkg_timeout")
 		return nil, errors.New("prepare_dkg_timeout")
@@ -371,20 +749,23 @@ func (d *Drand) setupAutomaticResharing(c context.Context, oldGroup *key.Group,
 		return nil, errors.New("control: old and new group have different genesis seed")
 	}
 
-	index, found := newGroup.Index(d.priv.Public)
+	index, found := newGroup.Index(d.keyIn(newGroup).Public)
 	if !found {
 		return nil, errors.New("drand: public key not found in group received from leader")
 	}
+	if err := d.acknowledgeGroup(c, lpeer, newGroup, groupPacket); err != nil {
+		return nil, err
+	}
 	d.state.Lock()
 	d.index = index
 	d.state.Unlock()
 
 	// run the dkg !
-	finalGroup, err := d.runResharing(false, oldGroup, newGroup, in.GetInfo().GetTimeout())
+	finalGroup, err := d.runResharing(c, false, oldGroup, newGroup, in.GetInfo().GetTimeout())
 	if err != nil {
 		return nil, err
 	}
-	return groupToProto(finalGroup), nil
+	return groupToProto(finalGroup)
 }
 
 // InitReshare receives information about the old and new group from which to
@@ -393,30 +774,46 @@ func (d *Drand) InitReshare(c context.Context, in *control.InitResharePacket) (*
 	var oldGroup *key.Group
 	var err error
 
-	d.state.Lock()
-	if oldGroup, err = extractGroup(in.Old); err != nil {
+	if in.GetOld().GetPath() == "" && in.GetOld().GetUrl() == "" {
 		// try to get the current group
-		if d.group == nil {
-			d.state.Unlock()
+		d.state.Lock()
+		oldGroup = d.group
+		d.state.Unlock()
+		if oldGroup == nil {
 			return nil, errors.New("drand: can't init-reshare if no old group provided")
 		}
 		d.log.With("module", "control").Debug("init_reshare", "using_stored_group")
-		oldGroup = d.group
+		if err := checkGroupHash(oldGroup, in.GetOld().GetHash()); err != nil {
+			return nil, err
+		}
+	} else if oldGroup, err = extractGroup(in.GetOld()); err != nil {
+		return nil, err
 	}
-	d.state.Unlock()
+	// a key pair generated with rotate-key replaces the current one in the
+	// new group
+	if err := d.loadNextKey(); err != nil {
+		return nil, err
+	}
+	ctx, done, err := d.startSetup(in.GetInfo().GetLeader(), in.GetInfo().GetSecret(), d.reshareKey())
+	if err != nil {
+		return nil, err
+	}
+	defer done()
 
 	if !in.GetInfo().GetLeader() {
 		d.log.Info("init_reshare", "begin", "leader", false)
-		return d.setupAutomaticResharing(c, oldGroup, in)
+		return d.setupAutomaticResharing(ctx, oldGroup, in)
 	}
 	d.log.Info("init_reshare", "begin", "leader", true, "time", d.opts.clock.Now())
 
 	newSetup := func() (*setupManager, error) {
-		return newReshareSetup(d.log, d.opts.clock, d.priv.Public, oldGroup, in)
+		return newReshareSetup(d.log, d.opts.clock, d.reshareKey(), oldGroup, in)
 	}
 
-	newGroup, err := d.leaderRunSetup(in.GetInfo(), newSetup)
-	if err != nil {
+	newGroup, err := d.leaderRunSetup(ctx, newSetup)
+	if err == errSetupCancelled {
+		return nil, err
+	} else if err != nil {
 		return nil, fmt.Errorf("drand: invalid setup configuration: %s", err)
 	}
 	// some assertions that should always be true but never too safe
@@ -453,27 +850,36 @@ func (d *Drand) InitReshare(c context.Context, in *control.InitResharePacket) (*
 		}
 		to = append(to, node)
 	}
-	protoGroup := groupToProto(newGroup)
+	protoGroup, err := groupToProto(newGroup)
+	if err != nil {
+		return nil, err
+	}
 	packet := &drand.PushGroupPacket{
 		SecretProof: in.GetInfo().GetSecret(),
 		NewGroup:    protoGroup,
+		Metadata:    dnet.NewMetadata(d.beaconID),
 	}
-	if err := d.pushGroup(to, packet); err != nil {
+	if err := d.pushGroupAndWaitAcks(ctx, newGroup, to, packet, newGroup.TransitionTime, in.GetInfo().GetTimeout()); err != nil {
 		d.log.Error("push_group", err)
-		return nil, errors.New("fail to push new group")
+		return nil, err
 	}
 
-	finalGroup, err := d.runResharing(true, oldGroup, newGroup, in.GetInfo().GetTimeout())
+	finalGroup, err := d.runResharing(ctx, true, oldGroup, newGroup, in.GetInfo().GetTimeout())
 	if err != nil {
 		return nil, err
 	}
-	return groupToProto(finalGroup), nil
+	return groupToProto(finalGroup)
 }
 
 func (d *Drand) startResharingAsLeader(dkgConf *dkg.Config, oidx int) {
 	d.log.With("module", "control").Debug("leader_reshare", "start signalling")
 	d.state.Lock()
-	msg := &control.ResharePacket{GroupHash: d.nextGroupHash}
+	msg := &control.ResharePacket{
+		GroupHash:        d.nextGroupHash,
+		ChainHash:        d.nextChainHash,
+		PartialSignature: d.nextPartial,
+		Metadata:         dnet.NewMetadata(d.beaconID),
+	}
 	// send resharing packet to signal start of the protocol to other old
 	// nodes
 	for i, p := range d.nextConf.OldNodes.Identities() {
@@ -497,10 +903,10 @@ func (d *Drand) SyncChain(req *drand.SyncRequest, stream drand.Protocol_SyncChai
 	d.state.Lock()
 	beacon := d.beacon
 	d.state.Unlock()
-	if beacon != nil {
-		beacon.SyncChain(req, stream)
+	if beacon == nil {
+		return nil
 	}
-	return nil
+	return beacon.SyncChain(req, stream)
 }
 
 // DistKey returns the distributed key corresponding to the current group
@@ -584,6 +990,73 @@ func (d *Drand) CollectiveKey(ctx context.Context, in *control.CokeyRequest) (*c
 	return &control.CokeyResponse{CoKey: protoKey}, nil
 }
 
+// Peers replies with the scores the beacon keeps of the peers of the node
+func (d *Drand) Peers(ctx context.Context, in *control.PeersRequest) (*control.PeersResponse, error) {
+	d.state.Lock()
+	b := d.beacon
+	d.state.Unlock()
+	if b == nil {
+		return nil, errors.New("drand: beacon not setup yet")
+	}
+	resp := new(control.PeersResponse)
+	now := d.opts.clock.Now()
+	for _, p := range b.Peers() {
+		var backoff int64
+		if p.BackoffUntil.After(now) {
+			backoff = p.BackoffUntil.Unix()
+		}
+		resp.Peers = append(resp.Peers, &control.PeerScore{
+			Address:      p.Address,
+			Latency:      uint64(p.Latency / time.Millisecond),
+			Successes:    p.Successes,
+			Failures:     p.Failures,
+			ErrorRate:    p.ErrorRate(),
+			LastRound:    p.LastRound,
+			BackoffUntil: backoff,
+		})
+	}
+	return resp, nil
+}
+
+// SyncStatus replies with the progress of the sync of the chain the beacon
+// runs, if any
+func (d *Drand) SyncStatus(ctx context.Context, in *control.SyncStatusRequest) (*control.SyncStatusResponse, error) {
+	d.state.Lock()
+	b := d.beacon
+	d.state.Unlock()
+	if b == nil {
+		return nil, errors.New("drand: beacon not setup yet")
+	}
+	return syncStatusToProto(b.SyncStatus()), nil
+}
+
+// StartSync makes the beacon sync its chain from its peers up to the requested
+// round, in the background, and replies with the progress of the sync.
+func (d *Drand) StartSync(ctx context.Context, in *control.StartSyncRequest) (*control.SyncStatusResponse, error) {
+	d.state.Lock()
+	b := d.beacon
+	d.state.Unlock()
+	if b == nil {
+		return nil, errors.New("drand: beacon not setup yet")
+	}
+	if err := b.Sync(in.GetUpTo()); err != nil {
+		return nil, fmt.Errorf("drand: can't sync: %v", err)
+	}
+	d.log.Info("sync", "started", "up_to", in.GetUpTo())
+	return syncStatusToProto(b.SyncStatus()), nil
+}
+
+func syncStatusToProto(st beacon.SyncStatus) *control.SyncStatusResponse {
+	return &control.SyncStatusResponse{
+		Syncing: st.Syncing,
+		Target:  st.Target,
+		Current: st.Current,
+		Peers:   st.Peers,
+		Rate:    st.Rate,
+		Eta:     uint64(st.ETA / time.Second),
+	}
+}
+
 // GroupFile replies with the distributed key in the response
 func (d *Drand) GroupFile(ctx context.Context, in *control.GroupRequest) (*control.GroupPacket, error) {
 	d.state.Lock()
@@ -591,8 +1064,158 @@ func (d *Drand) GroupFile(ctx context.Context, in *control.GroupRequest) (*contr
 	if d.group == nil {
 		return nil, errors.New("drand: no dkg group setup yet")
 	}
-	protoGroup := groupToProto(d.group)
-	return protoGroup, nil
+	return groupToProto(d.group)
+}
+
+// SetupStatus returns the participants that joined the setup this node runs
+// as a leader, and the allowed ones that did not join yet.
+func (d *Drand) SetupStatus(ctx context.Context, in *control.SetupStatusRequest) (*control.SetupStatusResponse, error) {
+	d.state.Lock()
+	manager := d.manager
+	d.state.Unlock()
+	if manager == nil {
+		return nil, errors.New("drand: no setup in progress")
+	}
+	joined, missing := manager.Status()
+	return &control.SetupStatusResponse{
+		Expected: uint32(manager.expected),
+		Joined:   identitiesToProto(joined),
+		Missing:  identitiesToProto(missing),
+	}, nil
+}
+
+// CancelDKG aborts the setup or the DKG in progress. The other nodes of a setup
+// with a leader are notified, and the node is ready to run a new setup
+// afterwards.
+func (d *Drand) CancelDKG(c context.Context, in *control.CancelDKGRequest) (*control.CancelDKGResponse, error) {
+	d.state.Lock()
+	run := d.inProgress
+	manager := d.manager
+	handler := d.dkg
+	d.state.Unlock()
+	if run == nil {
+		if handler == nil {
+			return nil, errors.New("drand: no setup or dkg in progress")
+		}
+		// dkg resumed after a restart
+		d.log.Info("cancel_dkg", "resumed_dkg")
+		handler.Stop()
+		return &control.CancelDKGResponse{}, nil
+	}
+	peers := run.getPeers()
+	if manager != nil {
+		// the group is not created yet, notify who joined so far
+		joined, _ := manager.Status()
+		peers = nil
+		for _, id := range joined {
+			if id.Address() != d.priv.Public.Address() {
+				peers = append(peers, id)
+			}
+		}
+	}
+	d.log.Info("cancel_dkg", "cancelling", "notify", len(peers))
+	d.notifyAbort(run, peers)
+	run.cancel()
+	select {
+	case <-run.doneCh:
+	case <-c.Done():
+		return nil, c.Err()
+	}
+	return &control.CancelDKGResponse{}, nil
+}
+
+// notifyAbort tells the given nodes of a setup with a leader that we cancelled
+// it. Nodes of a setup without leader keep waiting for us, to retry.
+func (d *Drand) notifyAbort(run *setupRun, peers []dnet.Peer) {
+	if run.secret == "" {
+		return
+	}
+	sig, err := key.AuthScheme.Sign(run.key.Key, abortSetupMsg(run.secret))
+	if err != nil {
+		d.log.Error("cancel_dkg", "cant_sign", "err", err)
+		return
+	}
+	buff, _ := run.key.Public.Key.MarshalBinary()
+	packet := &drand.AbortSetupPacket{
+		Key:         buff,
+		SecretProof: run.secret,
+		Metadata:    dnet.NewMetadata(d.beaconID),
+		Signature:   sig,
+	}
+	var wg sync.WaitGroup
+	for _, p := range peers {
+		wg.Add(1)
+		go func(p dnet.Peer) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), DefaultDialTimeout)
+			defer cancel()
+			if err := d.gateway.ProtocolClient.AbortSetup(ctx, p, packet); err != nil {
+				d.log.Debug("cancel_dkg", "notify_failed", "to", p.Address(), "err", err)
+			}
+		}(p)
+	}
+	wg.Wait()
+}
+
+// CancelTransition cancels the transition to the new group scheduled after a
+// resharing, before the transition time. The node keeps running with the old
+// group.
+func (d *Drand) CancelTransition(c context.Context, in *control.CancelTransitionRequest) (*control.CancelTransitionResponse, error) {
+	d.state.Lock()
+	pt := d.nextTransition
+	if pt == nil {
+		d.state.Unlock()
+		return nil, errors.New("drand: no transition scheduled")
+	}
+	if d.opts.clock.Now().Unix() >= pt.newGroup.TransitionTime {
+		d.state.Unlock()
+		return nil, errors.New("drand: transition time already passed")
+	}
+	if pt.cancelled {
+		d.state.Unlock()
+		return nil, errors.New("drand: transition already cancelled")
+	}
+	pt.cancelled = true
+	close(pt.cancel)
+	d.state.Unlock()
+	d.log.Info("cancel_transition", "transition_time", pt.newGroup.TransitionTime)
+	select {
+	case <-pt.done:
+	case <-c.Done():
+		return nil, c.Err()
+	}
+	return &control.CancelTransitionResponse{}, nil
+}
+
+// PendingGroup returns the group received from the leader, which the operator
+// must accept before the DKG starts.
+func (d *Drand) PendingGroup(ctx context.Context, in *control.PendingGroupRequest) (*control.GroupPacket, error) {
+	d.state.Lock()
+	receiver := d.receiver
+	d.state.Unlock()
+	if receiver == nil {
+		return nil, errors.New("drand: no setup in progress")
+	}
+	group := receiver.Pending()
+	if group == nil {
+		return nil, errors.New("drand: no group received from the leader yet")
+	}
+	return group, nil
+}
+
+// AcceptGroup records the decision of the operator about the group received
+// from the leader.
+func (d *Drand) AcceptGroup(ctx context.Context, in *control.AcceptGroupRequest) (*control.AcceptGroupResponse, error) {
+	d.state.Lock()
+	receiver := d.receiver
+	d.state.Unlock()
+	if receiver == nil {
+		return nil, errors.New("drand: no setup in progress")
+	}
+	if err := receiver.Accept(in.GetHash(), in.GetAccept()); err != nil {
+		return nil, fmt.Errorf("drand: %s", err)
+	}
+	return &control.AcceptGroupResponse{}, nil
 }
 
 func (d *Drand) Shutdown(ctx context.Context, in *control.ShutdownRequest) (*control.ShutdownResponse, error) {
@@ -602,15 +1225,26 @@ func (d *Drand) Shutdown(ctx context.Context, in *control.ShutdownRequest) (*con
 
 func extractGroup(i *control.GroupInfo) (*key.Group, error) {
 	var g = &key.Group{}
-	switch x := i.Location.(type) {
+	switch x := i.GetLocation().(type) {
 	case *control.GroupInfo_Path:
 		// search group file via local filesystem path
 		if err := key.Load(x.Path, g); err != nil {
 			return nil, err
 		}
+	case *control.GroupInfo_Url:
+		// the content of the url is not trusted, the operator must pin it
+		if i.GetHash() == "" {
+			return nil, errors.New("control: group hash required to fetch a group from an url")
+		}
+		if err := fetchGroup(x.Url, g); err != nil {
+			return nil, fmt.Errorf("control: can't fetch group from %s: %s", x.Url, err)
+		}
 	default:
 		return nil, errors.New("control: can't allow new empty group")
 	}
+	if err := checkGroupHash(g, i.GetHash()); err != nil {
+		return nil, err
+	}
 	// run a few checks on the proposed group
 	if g.Len() < 4 {
 		return nil, errors.New("control: can't accept group with fewer than 4 members")
@@ -622,6 +1256,48 @@ func extractGroup(i *control.GroupInfo) (*key.Group, error) {
 	return g, nil
 }
 
+// fetchGroup downloads the group file in TOML format at the given url
+func fetchGroup(url string, g *key.Group) error {
+	client := &http.Client{Timeout: DefaultDialTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	gt := g.TOMLValue()
+	if _, err := toml.DecodeReader(io.LimitReader(resp.Body, maxGroupFileSize), gt); err != nil {
+		return err
+	}
+	return g.FromTOML(gt)
+}
+
+// maxGroupFileSize is the maximum size of a group file fetched from an url
+const maxGroupFileSize = 1 << 20
+
+// checkGroupHash returns an error if a hash is expected and the group does
+// not have it. The expected hash is the chain hash of the group: the first
+// version of the hash doesn't cover the distributed key, the period, the
+// transition time nor the genesis seed, so it can't pin a group.
+func checkGroupHash(g *key.Group, expected string) error {
+	if expected == "" {
+		return nil
+	}
+	hash, err := g.ChainHash()
+	if err != nil {
+		return err
+	}
+	if hash == expected {
+		return nil
+	}
+	if v1, err := g.Hash(); err == nil && v1 == expected {
+		return errors.New("control: the group hash doesn't cover all the group fields, pin its chain hash instead")
+	}
+	return fmt.Errorf("control: group has chain hash %s instead of the expected %s", hash, expected)
+}
+
 func extractEntropy(i *control.EntropyInfo) (io.Reader, bool) {
 	if i == nil {
 		return nil, false
@@ -632,20 +1308,29 @@ func extractEntropy(i *control.EntropyInfo) (io.Reader, bool) {
 }
 
 func setTimeout(c *dkg.Config, timeoutStr string) error {
+	timeout, err := parseDKGTimeout(timeoutStr)
+	if err != nil {
+		return err
+	}
+	c.Timeout = timeout
+	return nil
+}
+
+// parseDKGTimeout parses the timeout of a DKG, DefaultDKGTimeout if empty
+func parseDKGTimeout(timeoutStr string) (time.Duration, error) {
 	// try parsing the timeout
 	timeout, err := time.ParseDuration(timeoutStr)
 	if err != nil {
 		if timeoutStr != "" {
-			return fmt.Errorf("invalid timeout: %s", err)
+			return 0, fmt.Errorf("invalid timeout: %s", err)
 		}
 		timeout, _ = time.ParseDuration(DefaultDKGTimeout)
 	}
-	c.Timeout = timeout
-	return nil
+	return timeout, nil
 }
 
-func (d *Drand) pushGroup(to []*key.Identity, packet *drand.PushGroupPacket) error {
-	ctx, cancel := context.WithCancel(context.Background())
+func (d *Drand) pushGroup(c context.Context, to []*key.Identity, packet *drand.PushGroupPacket) error {
+	ctx, cancel := context.WithCancel(c)
 	var tooLate = make(chan bool, 1)
 	var success = make(chan string, len(to))
 	go func() {
@@ -675,6 +1360,9 @@ func (d *Drand) pushGroup(to []*key.Identity, packet *drand.PushGroupPacket) err
 		case <-tooLate:
 			cancel()
 			return errors.New("push group timeout")
+		case <-c.Done():
+			cancel()
+			return errSetupCancelled
 		}
 	}
 	d.log.Info("push_dkg", "sending_group", "done")
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/drand/drand/protobuf/drand"
+	"github.com/drand/drand/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractGroupURL(t *testing.T) {
+	_, group := test.BatchIdentities(5)
+	hash, err := group.ChainHash()
+	require.NoError(t, err)
+	var buff bytes.Buffer
+	require.NoError(t, toml.NewEncoder(&buff).Encode(group.TOML()))
+	// the same group with another period has the same first version hash
+	tampered := *group
+	tampered.Period = group.Period + time.Second
+	var tamperedBuff bytes.Buffer
+	require.NoError(t, toml.NewEncoder(&tamperedBuff).Encode(tampered.TOML()))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/group.toml":
+			w.Write(buff.Bytes())
+		case "/tampered.toml":
+			w.Write(tamperedBuff.Bytes())
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	url := server.URL + "/group.toml"
+
+	fetched, err := extractGroup(&drand.GroupInfo{
+		Location: &drand.GroupInfo_Url{Url: url},
+		Hash:     hash,
+	})
+	require.NoError(t, err)
+	require.True(t, group.Equal(fetched))
+
+	// the hash must be pinned
+	_, err = extractGroup(&drand.GroupInfo{
+		Location: &drand.GroupInfo_Url{Url: url},
+	})
+	require.Error(t, err)
+
+	// and match the fetched group
+	_, err = extractGroup(&drand.GroupInfo{
+		Location: &drand.GroupInfo_Url{Url: url},
+		Hash:     "deadbeef",
+	})
+	require.Error(t, err)
+
+	_, err = extractGroup(&drand.GroupInfo{
+		Location: &drand.GroupInfo_Url{Url: server.URL + "/missing.toml"},
+		Hash:     hash,
+	})
+	require.Error(t, err)
+
+	// the chain hash pins the fields the first version of the hash misses
+	_, err = extractGroup(&drand.GroupInfo{
+		Location: &drand.GroupInfo_Url{Url: server.URL + "/tampered.toml"},
+		Hash:     hash,
+	})
+	require.Error(t, err)
+	v1, err := group.Hash()
+	require.NoError(t, err)
+	_, err = extractGroup(&drand.GroupInfo{
+		Location: &drand.GroupInfo_Url{Url: url},
+		Hash:     v1,
+	})
+	require.Error(t, err)
+}
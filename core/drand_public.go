@@ -9,6 +9,7 @@ import (
 	"github.com/drand/drand/ecies"
 	"github.com/drand/drand/entropy"
 	"github.com/drand/drand/key"
+	"github.com/drand/drand/net"
 	"github.com/drand/drand/protobuf/drand"
 	"google.golang.org/grpc/peer"
 )
@@ -23,10 +24,61 @@ func (d *Drand) FreshDKG(c context.Context, in *drand.DKGPacket) (*drand.Empty,
 	if d.dkg == nil {
 		return nil, errors.New("drand: no dkg running")
 	}
+	if err := net.CheckMetadata(in.GetMetadata()); err != nil {
+		return nil, fmt.Errorf("drand: incompatible dkg packet: %v", err)
+	}
 	d.dkg.Process(c, in.Dkg)
 	return new(drand.Empty), nil
 }
 
+// AbortSetup is called by a node of the setup with a leader we run, that
+// cancelled it. A participant leaving before the group is created is simply
+// removed from the setup, otherwise the setup is cancelled. A leader cancelling
+// the setup after a participant left notifies the other participants. The
+// abort must be signed by the key the node joined the setup with: a joined
+// participant or a member of the group for a leader, the leader for a
+// participant.
+func (d *Drand) AbortSetup(c context.Context, in *drand.AbortSetupPacket) (*drand.Empty, error) {
+	d.state.Lock()
+	run := d.inProgress
+	manager := d.manager
+	d.state.Unlock()
+	if run == nil || run.secret == "" {
+		return nil, errors.New("drand: no setup in progress")
+	}
+	if in.GetSecretProof() != run.secret {
+		return nil, errors.New("drand: invalid secret")
+	}
+	pub := key.KeyGroup.Point()
+	if err := pub.UnmarshalBinary(in.GetKey()); err != nil {
+		return nil, fmt.Errorf("drand: invalid key: %s", err)
+	}
+	var id *key.Identity
+	if manager != nil {
+		joined, _ := manager.Status()
+		id = findIdentity(joined, pub)
+	} else {
+		id = run.node(pub)
+	}
+	if id == nil {
+		return nil, errors.New("drand: abort from a node outside the setup")
+	}
+	if err := key.AuthScheme.Verify(id.Key, abortSetupMsg(run.secret), in.GetSignature()); err != nil {
+		return nil, fmt.Errorf("drand: invalid abort signature: %s", err)
+	}
+	if manager != nil {
+		d.log.Info("abort_setup", "participant_left", "key", key.PointToString(pub))
+		manager.Left(pub)
+		return new(drand.Empty), nil
+	}
+	d.log.Info("abort_setup", "cancelled_by", key.PointToString(pub))
+	if run.leader {
+		go d.notifyAbort(run, run.getPeers())
+	}
+	run.cancel()
+	return new(drand.Empty), nil
+}
+
 // Reshare is called when a resharing protocol is in progress
 func (d *Drand) ReshareDKG(c context.Context, in *drand.ResharePacket) (*drand.Empty, error) {
 	d.state.Lock()
@@ -35,12 +87,26 @@ func (d *Drand) ReshareDKG(c context.Context, in *drand.ResharePacket) (*drand.E
 	if d.nextGroupHash == "" {
 		return nil, fmt.Errorf("drand %s: can't reshare because InitReshare has not been called", d.priv.Public.Addr)
 	}
+	if err := net.CheckMetadata(in.GetMetadata()); err != nil {
+		return nil, fmt.Errorf("drand: incompatible reshare packet: %v", err)
+	}
 
-	// check that we are resharing to the new group that we expect
-	if in.GroupHash != d.nextGroupHash {
+	// check that we are resharing to the new group that we expect, using the
+	// version 2 hash unless the sender doesn't advertise it
+	if net.HasFeature(in.GetMetadata(), net.FeatureChainHash) {
+		if in.GetChainHash() != d.nextChainHash {
+			return nil, errors.New("drand: can't reshare to new group: incompatible chain hashes")
+		}
+	} else if in.GroupHash != d.nextGroupHash {
 		return nil, errors.New("drand: can't reshare to new group: incompatible hashes")
 	}
 
+	if len(in.GetPartialSignature()) > 0 {
+		if err := d.addNextPartial(in.GetPartialSignature()); err != nil {
+			d.log.Debug("reshare", "partial_signature", "err", err)
+		}
+	}
+
 	if !d.nextFirstReceived && d.nextOldPresent {
 		d.nextFirstReceived = true
 		// go routine since StartDKG requires the global lock
@@ -194,6 +260,7 @@ func (d *Drand) Home(c context.Context, in *drand.HomeRequest) (*drand.HomeRespo
 	return &drand.HomeResponse{
 		Status: fmt.Sprintf("drand up and running on %s",
 			d.priv.Public.Address()),
+		Metadata: net.NewMetadata(d.beaconID),
 	}, nil
 }
 
@@ -205,10 +272,36 @@ func (d *Drand) Group(ctx context.Context, in *drand.GroupRequest) (*drand.Group
 	if d.group == nil {
 		return nil, errors.New("drand: no dkg group setup yet")
 	}
-	return groupToProto(d.group), nil
+	return groupToProto(d.group)
+}
+
+// GroupHistory returns the groups of the chain this node follows, with the
+// first round each of them produced.
+func (d *Drand) GroupHistory(ctx context.Context, in *drand.GroupHistoryRequest) (*drand.GroupHistoryResponse, error) {
+	d.state.Lock()
+	defer d.state.Unlock()
+	if d.group == nil {
+		return nil, errors.New("drand: no dkg group setup yet")
+	}
+	history, err := d.store.LoadGroupHistory()
+	if err != nil {
+		return nil, fmt.Errorf("drand: can't load group history: %v", err)
+	}
+	resp := new(drand.GroupHistoryResponse)
+	for _, h := range history {
+		group, err := groupToProto(h.Group)
+		if err != nil {
+			return nil, err
+		}
+		resp.Groups = append(resp.Groups, &drand.HistoryGroup{
+			StartRound: h.StartAt,
+			Group:      group,
+		})
+	}
+	return resp, nil
 }
 
-func (d *Drand) PrepareDKGGroup(ctx context.Context, p *drand.PrepareDKGPacket) (*drand.Empty, error) {
+func (d *Drand) PrepareDKGGroup(ctx context.Context, p *drand.PrepareDKGPacket) (*drand.PrepareDKGResponse, error) {
 	d.state.Lock()
 	defer d.state.Unlock()
 	if d.manager == nil {
@@ -223,7 +316,9 @@ func (d *Drand) PrepareDKGGroup(ctx context.Context, p *drand.PrepareDKGPacket)
 	if err != nil {
 		return nil, err
 	}
-	return new(drand.Empty), nil
+	// the participant checks the abort of the setup against our key
+	leader := identitiesToProto([]*key.Identity{d.manager.leaderKey})[0]
+	return &drand.PrepareDKGResponse{Leader: leader}, nil
 }
 
 func (d *Drand) PushDKGGroup(ctx context.Context, in *drand.PushGroupPacket) (*drand.Empty, error) {
@@ -239,3 +334,49 @@ func (d *Drand) PushDKGGroup(ctx context.Context, in *drand.PushGroupPacket) (*d
 	}
 	return new(drand.Empty), nil
 }
+
+// NewGroupSilent receives the statement of a node of the old group that the
+// new group did not start after a resharing, and replies with our own if we
+// made it. A node that went back to the old group, because its operator
+// cancelled the transition, does not run the new group either and always
+// replies.
+func (d *Drand) NewGroupSilent(c context.Context, in *drand.NewGroupSilentPacket) (*drand.NewGroupSilentPacket, error) {
+	d.state.Lock()
+	defer d.state.Unlock()
+	pt := d.nextTransition
+	if pt == nil {
+		if d.group == nil {
+			return nil, errors.New("drand: no transition in progress")
+		}
+		current, err := d.group.ChainHash()
+		if err != nil || current == in.GetChainHash() {
+			return nil, errors.New("drand: no transition in progress")
+		}
+		if _, err := verifyNewGroupSilent(d.group, in); err != nil {
+			return nil, fmt.Errorf("drand: invalid statement: %s", err)
+		}
+		return signNewGroupSilent(d.priv, in.GetChainHash(), d.beaconID)
+	}
+	if err := pt.addSilent(in); err != nil {
+		return nil, fmt.Errorf("drand: invalid statement: %s", err)
+	}
+	if pt.own == nil {
+		return nil, errors.New("drand: the new group is not silent for us")
+	}
+	return pt.own, nil
+}
+
+// ConfirmGroup receives the hash of the group another node holds during a
+// setup without leader, or its acknowledgement of the group we pushed as a
+// leader, and replies with the hash of our own group.
+func (d *Drand) ConfirmGroup(ctx context.Context, in *drand.GroupHashPacket) (*drand.GroupHashPacket, error) {
+	d.state.Lock()
+	defer d.state.Unlock()
+	if d.confirmer == nil {
+		return nil, errors.New("drand: no group setup in progress")
+	}
+	if err := d.confirmer.Received(in); err != nil {
+		return nil, fmt.Errorf("drand: invalid group hash packet: %s", err)
+	}
+	return d.confirmer.Packet(), nil
+}
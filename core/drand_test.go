@@ -2,6 +2,7 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	gnet "net"
@@ -42,6 +43,7 @@ func TestDrandDKGFresh(t *testing.T) {
 	defer dt.Cleanup()
 	finalGroup := dt.RunDKG()
 	fmt.Println(" --- DKG FINISHED ---")
+	dt.TestTranscript(finalGroup, dt.ids...)
 	// make the last node fail
 	lastID := dt.ids[n-1]
 	dt.StopDrand(lastID)
@@ -66,6 +68,91 @@ func TestDrandDKGFresh(t *testing.T) {
 	dt.MoveTime(beaconPeriod)
 	dt.TestBeaconLength(3, dt.ids...)
 	dt.TestPublicBeacon(dt.ids[0])
+	dt.TestGroupHistory([]uint64{0}, dt.ids...)
+}
+
+// dropClient fails all the DKG packets a node sends
+type dropClient struct {
+	net.ProtocolClient
+}
+
+func (c *dropClient) FreshDKG(context.Context, net.Peer, *drand.DKGPacket, ...net.CallOption) (*drand.Empty, error) {
+	return nil, errors.New("dropped")
+}
+
+func TestDrandDKGResume(t *testing.T) {
+	n, thr := 5, 3
+	dt := NewDrandTest(t, n, thr, time.Second)
+	defer dt.Cleanup()
+	secret := "thisisdkg"
+	timeout, err := time.ParseDuration(testDkgTimeout)
+	require.NoError(t, err)
+	leader := dt.drands[dt.ids[0]]
+	lastID := dt.ids[n-1]
+	last := dt.drands[lastID]
+	// the packets of one node never reach the others, so the DKG can only
+	// finish at its timeout, without the deal of that node
+	dropping := dt.drands[dt.ids[1]]
+	dropping.gateway.ProtocolClient = &dropClient{dropping.gateway.ProtocolClient}
+	dt.setClock(dt.ids...)
+	dt.setDKGCallback(dt.ids)
+
+	groupCh := make(chan *key.Group, 1)
+	go func() {
+		client, err := net.NewControlClient(leader.opts.controlPort)
+		require.NoError(t, err)
+		groupP, err := client.InitDKGLeader(n, thr, dt.period, testDkgTimeout, nil, secret, testBeaconOffset, nil)
+		require.NoError(t, err)
+		group, err := ProtoToGroup(groupP)
+		require.NoError(t, err)
+		groupCh <- group
+	}()
+	time.Sleep(1 * time.Second)
+	for _, id := range dt.ids[1:] {
+		go func(dd *Drand) {
+			client, err := net.NewControlClient(dd.opts.controlPort)
+			require.NoError(t, err)
+			go dt.acceptGroup(client)
+			// the call to the last node fails when it stops
+			client.InitDKG(leader.priv.Public, n, thr, testDkgTimeout, nil, secret)
+		}(dt.drands[id])
+	}
+
+	// stop the last node once it dealt and received the packets of the
+	// others, which it saves in the background
+	require.Eventually(t, func() bool {
+		state, err := last.store.LoadDKGState()
+		return err == nil && state.StartedAt != 0 && len(state.Deals) > 0 && len(state.Received) > 0
+	}, 10*time.Second, 50*time.Millisecond)
+	time.Sleep(500 * time.Millisecond)
+	dt.MoveTime(timeout / 2)
+	dt.StopDrand(lastID)
+	_, err = last.store.LoadDKGState()
+	require.NoError(t, err)
+
+	// it resumes the DKG from its store, and the DKG finishes at the timeout
+	// of the first start for all the nodes
+	restarted, err := LoadDrand(last.store, last.opts)
+	require.NoError(t, err)
+	defer restarted.Stop()
+	dt.drands[lastID] = restarted
+	time.Sleep(500 * time.Millisecond)
+	dt.MoveTime(timeout / 2)
+
+	var group *key.Group
+	select {
+	case group = <-groupCh:
+	case <-time.After(10 * time.Second):
+		t.Fatal("dkg not finished at its timeout")
+	}
+	var share *key.Share
+	require.Eventually(t, func() bool {
+		share, err = restarted.store.LoadShare()
+		return err == nil
+	}, 10*time.Second, 50*time.Millisecond)
+	require.True(t, share.Public().Key().Equal(group.PublicKey.Key()))
+	_, err = restarted.store.LoadDKGState()
+	require.Equal(t, key.ErrAbsent, err)
 }
 
 func TestDrandDKGReshareTimeout(t *testing.T) {
@@ -114,6 +201,9 @@ func TestDrandDKGReshareTimeout(t *testing.T) {
 		require.True(t, false)
 	}
 	fmt.Println(" RESHARED GROUP:", resharedGroup)
+	dt.TestTranscript(resharedGroup, dt.reshareIds...)
+	// the old group signed the new one
+	require.NoError(t, resharedGroup.VerifyTransition(group1))
 	dt.TestBeaconLength(3, dt.ids...)
 	fmt.Println(" --- AFTER RESHARED ROUND ---")
 	fmt.Println(" --- dt.ids ", dt.ids)
@@ -123,6 +213,225 @@ func TestDrandDKGReshareTimeout(t *testing.T) {
 	time.Sleep(getSleepDuration())
 	fmt.Println(" --- AFTER RESHARED ROUND  SLEEEPING ---")
 	dt.TestBeaconLength(4, dt.reshareIds...)
+	transitionRound := beacon.StartRound(resharedGroup)
+	dt.TestGroupHistory([]uint64{0, transitionRound}, dt.reshareIds...)
+}
+
+func TestDrandReshareCancelTransition(t *testing.T) {
+	oldN := 4
+	oldThr := 3
+	newThr := 3
+	timeoutStr := "1s"
+	timeout, _ := time.ParseDuration(timeoutStr)
+	beaconPeriod := 2 * time.Second
+
+	dt := NewDrandTest(t, oldN, oldThr, beaconPeriod)
+	defer dt.Cleanup()
+	group1 := dt.RunDKG()
+	dt.MoveToTime(group1.GenesisTime)
+	dt.TestBeaconLength(2, dt.ids...)
+	dt.MoveTime(1 * time.Second)
+
+	// one old node leaves and one new node joins
+	toKeep := oldN - 1
+	dt.SetupReshare(toKeep, 1, newThr)
+	var doneReshare = make(chan *key.Group)
+	go func() {
+		doneReshare <- dt.RunReshare(toKeep, 1, timeoutStr)
+	}()
+	time.Sleep(DefaultSyncTime)
+	time.Sleep(getSleepDuration())
+	dt.MoveTime(timeout)
+	var resharedGroup *key.Group
+	select {
+	case resharedGroup = <-doneReshare:
+	case <-time.After(1 * time.Second):
+		require.True(t, false)
+	}
+
+	// one node of the new group cancels the transition and the new node is
+	// down: the new group can't reach its threshold
+	cancelled := dt.ids[toKeep-1]
+	client, err := net.NewControlClient(dt.drands[cancelled].opts.controlPort)
+	require.NoError(t, err)
+	require.NoError(t, client.CancelTransition())
+	require.Error(t, client.CancelTransition())
+	dt.newDrands[dt.newIds[0]].Stop()
+	g, err := dt.drands[cancelled].store.LoadGroup()
+	require.NoError(t, err)
+	require.True(t, g.Equal(group1))
+
+	dt.MoveToTime(resharedGroup.TransitionTime)
+	nRound, _ := beacon.NextRound(resharedGroup.TransitionTime, beaconPeriod, group1.GenesisTime)
+	transitionRound := nRound - 1
+	// the old group takes over again after a few rounds without beacon
+	for i := 0; i <= DefaultTransitionCheckRounds+1; i++ {
+		dt.MoveTime(beaconPeriod)
+		time.Sleep(getSleepDuration())
+	}
+	for _, id := range dt.ids[:toKeep] {
+		dr := dt.drands[id]
+		dr.state.Lock()
+		require.True(t, dr.group.Equal(group1), "id %s", id)
+		last, err := dr.beacon.Store().Last()
+		dr.state.Unlock()
+		require.NoError(t, err)
+		require.True(t, last.Round >= transitionRound, "id %s at round %d", id, last.Round)
+		msg := beacon.Message(last.Round, last.PreviousSig)
+		require.NoError(t, key.Scheme.VerifyRecovered(group1.PublicKey.Key(), msg, last.Signature))
+	}
+	dt.TestGroupHistory([]uint64{0}, dt.ids[:toKeep]...)
+}
+
+// blindClient can't see the beacons of the other nodes
+type blindClient struct {
+	net.ProtocolClient
+}
+
+func (c *blindClient) SyncChain(context.Context, net.Peer, *drand.SyncRequest, ...net.CallOption) (chan *drand.BeaconPacket, error) {
+	return nil, errors.New("blind")
+}
+
+func TestDrandReshareTransitionPartialView(t *testing.T) {
+	oldN := 4
+	oldThr := 3
+	newThr := 3
+	timeoutStr := "1s"
+	timeout, _ := time.ParseDuration(timeoutStr)
+	beaconPeriod := 2 * time.Second
+
+	dt := NewDrandTest(t, oldN, oldThr, beaconPeriod)
+	defer dt.Cleanup()
+	group1 := dt.RunDKG()
+	dt.MoveToTime(group1.GenesisTime)
+	dt.TestBeaconLength(2, dt.ids...)
+	dt.MoveTime(1 * time.Second)
+
+	// one old node leaves and one new node joins
+	toKeep := oldN - 1
+	dt.SetupReshare(toKeep, 1, newThr)
+	var doneReshare = make(chan *key.Group)
+	go func() {
+		doneReshare <- dt.RunReshare(toKeep, 1, timeoutStr)
+	}()
+	time.Sleep(DefaultSyncTime)
+	time.Sleep(getSleepDuration())
+	dt.MoveTime(timeout)
+	var resharedGroup *key.Group
+	select {
+	case resharedGroup = <-doneReshare:
+	case <-time.After(1 * time.Second):
+		require.True(t, false)
+	}
+
+	dt.MoveToTime(resharedGroup.TransitionTime)
+	for i := 0; i <= DefaultTransitionCheckRounds+1; i++ {
+		dt.MoveTime(beaconPeriod)
+		time.Sleep(getSleepDuration())
+	}
+	for _, id := range dt.ids[:toKeep] {
+		dr := dt.drands[id]
+		dr.state.Lock()
+		require.True(t, dr.group.Equal(resharedGroup), "id %s", id)
+		require.Nil(t, dr.nextTransition, "id %s", id)
+		dr.state.Unlock()
+	}
+
+	// an old node that can't see the new group running does not go back to
+	// the old group alone, the others saw the new group
+	blind := dt.drands[dt.ids[0]]
+	blind.state.Lock()
+	blind.beacon.Stop()
+	blind.beacon = nil
+	blind.state.Unlock()
+	client := blind.gateway.ProtocolClient
+	blind.gateway.ProtocolClient = &blindClient{client}
+	chainHash, err := resharedGroup.ChainHash()
+	require.NoError(t, err)
+	pt := &pendingTransition{
+		oldGroup:  group1,
+		newGroup:  resharedGroup,
+		chainHash: chainHash,
+		silent:    make(map[int]bool),
+	}
+	reverted := make(chan bool, 1)
+	go func() { reverted <- blind.agreeNewGroupSilent(pt) }()
+	select {
+	case <-reverted:
+		t.Fatal("node went back to the old group alone")
+	case <-time.After(3 * DefaultConfirmGroupPeriod):
+	}
+	blind.state.Lock()
+	require.Len(t, pt.silent, 1)
+	blind.state.Unlock()
+
+	// once it sees the new group, it keeps it
+	blind.gateway.ProtocolClient = client
+	select {
+	case r := <-reverted:
+		require.False(t, r)
+	case <-time.After(10 * time.Second):
+		t.Fatal("node still waiting for the other old nodes")
+	}
+}
+
+func TestDrandDKGFromGroup(t *testing.T) {
+	n := 4
+	thr := key.DefaultThreshold(n)
+	dt := NewDrandTest(t, n, thr, 1*time.Second)
+	defer dt.Cleanup()
+	dt.setClock(dt.ids...)
+	dt.setDKGCallback(dt.ids)
+
+	group := key.NewGroup(dt.group.Nodes, thr, dt.Now().Add(1*time.Minute).Unix())
+	group.Period = dt.period
+	dir, err := ioutil.TempDir(os.TempDir(), "drand-group")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	groupPath := path.Join(dir, "agreed.toml")
+	require.NoError(t, key.Save(groupPath, group, false))
+	// one node holds a group with a different genesis time
+	wrongGroup := key.NewGroup(dt.group.Nodes, thr, group.GenesisTime+10)
+	wrongGroup.Period = dt.period
+	wrongPath := path.Join(dir, "wrong.toml")
+	require.NoError(t, key.Save(wrongPath, wrongGroup, false))
+
+	runAll := func(paths []string) ([]*key.Group, []error) {
+		groups := make([]*key.Group, n)
+		errs := make([]error, n)
+		var wg sync.WaitGroup
+		for i, id := range dt.ids {
+			wg.Add(1)
+			go func(i int, dd *Drand) {
+				defer wg.Done()
+				client, err := net.NewControlClient(dd.opts.controlPort)
+				require.NoError(t, err)
+				groupP, err := client.InitDKGFromGroup(paths[i], testDkgTimeout, nil)
+				if errs[i] = err; err == nil {
+					groups[i], errs[i] = ProtoToGroup(groupP)
+				}
+			}(i, dt.drands[id])
+			// nodes don't start at the same time
+			time.Sleep(200 * time.Millisecond)
+		}
+		wg.Wait()
+		return groups, errs
+	}
+
+	paths := []string{groupPath, groupPath, groupPath, wrongPath}
+	_, errs := runAll(paths)
+	for _, err := range errs {
+		require.Error(t, err)
+	}
+
+	paths[n-1] = groupPath
+	groups, errs := runAll(paths)
+	for i := range groups {
+		require.NoError(t, errs[i])
+		require.Equal(t, group.GenesisTime, groups[i].GenesisTime)
+		require.True(t, groups[0].PublicKey.Equal(groups[i].PublicKey))
+	}
+	dt.TestTranscript(groups[0], dt.ids...)
 }
 
 type DrandTest struct {
@@ -149,6 +458,161 @@ type DrandTest struct {
 	newCertPaths []string
 }
 
+func TestDrandCancelSetup(t *testing.T) {
+	n := 4
+	thr := key.DefaultThreshold(n)
+	dt := NewDrandTest(t, n, thr, 1*time.Second)
+	defer dt.Cleanup()
+	dt.setClock(dt.ids...)
+	secret := "thisisdkg"
+	leader := dt.drands[dt.ids[0]]
+	leaderClient, err := net.NewControlClient(leader.opts.controlPort)
+	require.NoError(t, err)
+
+	errs := make(map[string]chan error)
+	run := func(id string, fn func(client *net.ControlClient) error) {
+		client, err := net.NewControlClient(dt.drands[id].opts.controlPort)
+		require.NoError(t, err)
+		errs[id] = make(chan error, 1)
+		go func() { errs[id] <- fn(client) }()
+	}
+	joined := func(expected int) func() bool {
+		return func() bool {
+			status, err := leaderClient.SetupStatus()
+			return err == nil && len(status.GetJoined()) == expected
+		}
+	}
+	run(dt.ids[0], func(client *net.ControlClient) error {
+		_, err := client.InitDKGLeader(n, thr, dt.period, testDkgTimeout, nil, secret, testBeaconOffset, nil)
+		return err
+	})
+	time.Sleep(500 * time.Millisecond)
+	for _, id := range dt.ids[1:3] {
+		run(id, func(client *net.ControlClient) error {
+			_, err := client.InitDKG(leader.priv.Public, n, thr, testDkgTimeout, nil, secret)
+			return err
+		})
+	}
+	require.Eventually(t, joined(3), 5*time.Second, 50*time.Millisecond)
+
+	// an abort must be signed by the key the node joined with, so another
+	// node knowing the secret can't evict a participant or cancel the setup
+	forge := func(victim *key.Pair, signer *key.Pair) *drand.AbortSetupPacket {
+		buff, err := victim.Public.Key.MarshalBinary()
+		require.NoError(t, err)
+		sig, err := key.AuthScheme.Sign(signer.Key, abortSetupMsg(secret))
+		require.NoError(t, err)
+		return &drand.AbortSetupPacket{Key: buff, SecretProof: secret, Signature: sig}
+	}
+	intruder := dt.drands[dt.ids[3]].priv
+	_, err = leader.AbortSetup(context.Background(), forge(dt.drands[dt.ids[1]].priv, intruder))
+	require.Error(t, err)
+	_, err = leader.AbortSetup(context.Background(), forge(intruder, intruder))
+	require.Error(t, err)
+	_, err = dt.drands[dt.ids[1]].AbortSetup(context.Background(), forge(leader.priv, intruder))
+	require.Error(t, err)
+	require.True(t, joined(3)())
+
+	// a participant leaving is removed from the setup
+	client, err := net.NewControlClient(dt.drands[dt.ids[2]].opts.controlPort)
+	require.NoError(t, err)
+	require.NoError(t, client.CancelDKG())
+	require.Error(t, <-errs[dt.ids[2]])
+	require.Eventually(t, joined(2), 5*time.Second, 50*time.Millisecond)
+
+	// the leader cancelling aborts the setup of the participants
+	require.NoError(t, leaderClient.CancelDKG())
+	require.Error(t, <-errs[dt.ids[0]])
+	select {
+	case err := <-errs[dt.ids[1]]:
+		require.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("participant not notified of the cancelled setup")
+	}
+	require.Error(t, leaderClient.CancelDKG())
+
+	// all nodes can run a new setup
+	group := dt.RunDKG()
+	require.Equal(t, n, group.Len())
+}
+
+func TestDrandSlowAcceptance(t *testing.T) {
+	n := 3
+	thr := key.DefaultThreshold(n)
+	dt := NewDrandTest(t, n, thr, 1*time.Second)
+	defer dt.Cleanup()
+	dt.setClock(dt.ids...)
+	secret := "thisisdkg"
+	leader := dt.drands[dt.ids[0]]
+
+	errs := make(map[string]chan error)
+	clients := make(map[string]*net.ControlClient)
+	for _, id := range dt.ids {
+		client, err := net.NewControlClient(dt.drands[id].opts.controlPort)
+		require.NoError(t, err)
+		clients[id] = client
+		errs[id] = make(chan error, 1)
+	}
+	go func() {
+		_, err := clients[dt.ids[0]].InitDKGLeader(n, thr, dt.period, testDkgTimeout, nil, secret, testBeaconOffset, nil)
+		errs[dt.ids[0]] <- err
+	}()
+	time.Sleep(500 * time.Millisecond)
+	for _, id := range dt.ids[1:] {
+		go func(id string) {
+			_, err := clients[id].InitDKG(leader.priv.Public, n, thr, testDkgTimeout, nil, secret)
+			errs[id] <- err
+		}(id)
+	}
+	// the first participant accepts the group right away, the operator of
+	// the second one takes longer than the genesis offset
+	dt.acceptGroup(clients[dt.ids[1]])
+	require.Eventually(t, func() bool {
+		_, err := clients[dt.ids[2]].PendingGroup()
+		return err == nil
+	}, 5*time.Second, 50*time.Millisecond)
+	dt.MoveTime(time.Duration(testBeaconOffset)*time.Second + dt.period)
+
+	// the leader gives up and the participant that accepted the group does
+	// not wait for the DKG
+	for _, id := range dt.ids {
+		select {
+		case err := <-errs[id]:
+			require.Error(t, err, "node %s", id)
+		case <-time.After(10 * time.Second):
+			t.Fatalf("node %s still waiting for the DKG", id)
+		}
+	}
+
+	group := dt.RunDKG()
+	require.Equal(t, n, group.Len())
+}
+
+func TestDrandRemoteSignerSetup(t *testing.T) {
+	n := 3
+	thr := key.DefaultThreshold(n)
+	dt := NewDrandTest(t, n, thr, 1*time.Second)
+	defer dt.Cleanup()
+	dt.setClock(dt.ids...)
+	leader := dt.drands[dt.ids[0]]
+	socket := path.Join(dt.dir, "signer.sock")
+	client, err := net.NewControlClient(leader.opts.controlPort)
+	require.NoError(t, err)
+
+	leader.opts.signerSocket = socket
+	_, err = client.InitDKGLeader(n, thr, dt.period, testDkgTimeout, nil, "thisisdkg", testBeaconOffset, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "remote signer")
+
+	leader.opts.signerSocket = ""
+	dt.RunDKG()
+	// the daemon can't deal from the share held by the signer
+	leader.opts.signerSocket = socket
+	_, err = client.InitReshareLeader(n, thr, testDkgTimeout, "thisisreshare", "", "", testBeaconOffset, nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "remote signer")
+}
+
 func (d *DrandTest) Cleanup() {
 	os.RemoveAll(d.dir)
 	os.RemoveAll(d.newDir)
@@ -202,6 +666,73 @@ func (d *DrandTest) SetupReshare(keepOld, addNew, newThr int) []string {
 	return newAddr
 }
 
+func TestDrandReshareRotateKey(t *testing.T) {
+	oldN := 4
+	oldThr := 3
+	newThr := 3
+	timeoutStr := "1s"
+	timeout, _ := time.ParseDuration(timeoutStr)
+	beaconPeriod := 2 * time.Second
+
+	dt := NewDrandTest(t, oldN, oldThr, beaconPeriod)
+	defer dt.Cleanup()
+	group1 := dt.RunDKG()
+	dt.MoveToTime(group1.GenesisTime)
+	dt.TestBeaconLength(2, dt.ids...)
+	dt.MoveTime(1 * time.Second)
+
+	toKeep := oldN - 1
+	dt.SetupReshare(toKeep, 1, newThr)
+	// the leader and another old node rotate their key
+	rotating := dt.ids[:2]
+	nextKeys := make(map[string]*key.Pair)
+	for _, id := range rotating {
+		dr := dt.drands[id]
+		next := key.NewTLSKeyPair(dr.priv.Public.Address())
+		require.NoError(t, dr.store.SaveNextKeyPair(next))
+		nextKeys[id] = next
+	}
+	var doneReshare = make(chan *key.Group)
+	go func() {
+		doneReshare <- dt.RunReshare(toKeep, 1, timeoutStr)
+	}()
+	time.Sleep(DefaultSyncTime)
+	time.Sleep(getSleepDuration())
+	dt.MoveTime(timeout)
+	var resharedGroup *key.Group
+	select {
+	case resharedGroup = <-doneReshare:
+	case <-time.After(1 * time.Second):
+		require.True(t, false)
+	}
+	require.NoError(t, resharedGroup.VerifyTransition(group1))
+	for _, id := range rotating {
+		require.True(t, resharedGroup.Contains(nextKeys[id].Public), "id %s", id)
+		require.False(t, resharedGroup.Contains(dt.drands[id].priv.Public), "id %s", id)
+	}
+
+	dt.MoveToTime(resharedGroup.TransitionTime)
+	time.Sleep(getSleepDuration())
+	dt.TestBeaconLength(4, dt.reshareIds...)
+	// the old key is archived once the new group runs
+	for i := 0; i <= DefaultTransitionCheckRounds; i++ {
+		dt.MoveTime(beaconPeriod)
+	}
+	time.Sleep(getSleepDuration())
+	for _, id := range rotating {
+		dr := dt.drands[id]
+		dr.state.Lock()
+		priv := dr.priv
+		dr.state.Unlock()
+		require.True(t, priv.Public.Equal(nextKeys[id].Public), "id %s", id)
+		stored, err := dr.store.LoadKeyPair()
+		require.NoError(t, err)
+		require.True(t, stored.Public.Equal(nextKeys[id].Public), "id %s", id)
+		_, err = dr.store.LoadNextKeyPair()
+		require.Equal(t, key.ErrAbsent, err)
+	}
+}
+
 func (d *DrandTest) RunReshare(oldRun, newRun int, timeout string) *key.Group {
 	fmt.Printf(" -- Running RESHARE with %d/%d old, %d/%d new nodes\n", oldRun, len(d.drands), newRun, len(d.newIds))
 	var clientCounter = &sync.WaitGroup{}
@@ -237,7 +768,8 @@ func (d *DrandTest) RunReshare(oldRun, newRun int, timeout string) *key.Group {
 		// instruct to be ready for a reshare
 		client, err := net.NewControlClient(dr.opts.controlPort)
 		require.NoError(d.t, err)
-		_, err = client.InitReshare(leaderAddr, d.newGroup.Len(), d.newGroup.Threshold, timeout, secret, d.groupPath)
+		go d.acceptGroup(client)
+		_, err = client.InitReshare(leaderAddr, d.newGroup.Len(), d.newGroup.Threshold, timeout, secret, d.groupPath, "")
 		require.NoError(d.t, err)
 		fmt.Printf("\n\nDKG TEST: drand %s DONE RESHARING (leader? %v)\n", dr.priv.Public.Address(), leader)
 		clientCounter.Done()
@@ -254,7 +786,7 @@ func (d *DrandTest) RunReshare(oldRun, newRun int, timeout string) *key.Group {
 		fmt.Printf("Launching reshare on (old) root %d - %s\n", idx, oldNodes[0])
 		client, err := net.NewControlClient(leader.opts.controlPort)
 		require.NoError(d.t, err)
-		finalGroup, err := client.InitReshareLeader(d.newGroup.Len(), d.newGroup.Threshold, timeout, secret, d.groupPath, testBeaconOffset)
+		finalGroup, err := client.InitReshareLeader(d.newGroup.Len(), d.newGroup.Threshold, timeout, secret, d.groupPath, "", testBeaconOffset, nil)
 		if err != nil {
 			panic(err)
 		}
@@ -306,6 +838,25 @@ func (d *DrandTest) RunReshare(oldRun, newRun int, timeout string) *key.Group {
 	return finalGroup
 }
 
+// acceptGroup accepts the group received from the leader as soon as the node
+// got it, like an operator would do.
+func (d *DrandTest) acceptGroup(client *net.ControlClient) {
+	deadline := time.Now().Add(30 * time.Second)
+	for time.Now().Before(deadline) {
+		groupPacket, err := client.PendingGroup()
+		if err != nil {
+			time.Sleep(50 * time.Millisecond)
+			continue
+		}
+		group, err := ProtoToGroup(groupPacket)
+		require.NoError(d.t, err)
+		hash, err := group.ChainHash()
+		require.NoError(d.t, err)
+		require.NoError(d.t, client.AcceptGroup(hash, true))
+		return
+	}
+}
+
 func checkWait(counter *sync.WaitGroup) {
 	var doneCh = make(chan bool, 1)
 	go func() {
@@ -360,7 +911,7 @@ func (d *DrandTest) RunDKG() *key.Group {
 	require.NoError(d.t, err)
 	// first run the leader and then run the other nodes
 	go func() {
-		finalGroup, err := controlClient.InitDKGLeader(d.group.Len(), d.group.Threshold, d.group.Period, testDkgTimeout, nil, secret, testBeaconOffset)
+		finalGroup, err := controlClient.InitDKGLeader(d.group.Len(), d.group.Threshold, d.group.Period, testDkgTimeout, nil, secret, testBeaconOffset, nil)
 		require.NoError(d.t, err)
 		g, err := ProtoToGroup(finalGroup)
 		if err != nil {
@@ -379,6 +930,7 @@ func (d *DrandTest) RunDKG() *key.Group {
 		go func(dd *Drand) {
 			client, err := net.NewControlClient(dd.opts.controlPort)
 			require.NoError(d.t, err)
+			go d.acceptGroup(client)
 			_, err = client.InitDKG(leaderAddr, d.group.Len(), d.group.Threshold, testDkgTimeout, nil, secret)
 			require.NoError(d.t, err)
 			wg.Done()
@@ -529,6 +1081,36 @@ func (d *DrandTest) TestBeaconLength(length int, ids ...string) {
 
 }
 
+// TestTranscript checks that the transcript saved by each node after the last
+// DKG is valid for the distributed key of the given group
+func (d *DrandTest) TestTranscript(group *key.Group, ids ...string) {
+	for _, id := range ids {
+		d.tryBoth(id, func(drand *Drand) {
+			tr, err := drand.store.LoadTranscript()
+			require.NoError(d.t, err)
+			require.NotNil(d.t, tr, "id %s has no transcript", id)
+			require.NoError(d.t, tr.Verify(group.PublicKey), "id %s", id)
+		})
+	}
+}
+
+// TestGroupHistory checks that the given nodes return a group history with the
+// given starting rounds
+func (d *DrandTest) TestGroupHistory(startRounds []uint64, ids ...string) {
+	for _, id := range ids {
+		d.tryBoth(id, func(dr *Drand) {
+			client := net.NewGrpcClientFromCertManager(dr.opts.certmanager, dr.opts.grpcOpts...)
+			resp, err := client.GroupHistory(context.TODO(), test.NewTLSPeer(dr.priv.Public.Addr), &drand.GroupHistoryRequest{})
+			require.NoError(d.t, err)
+			var got []uint64
+			for _, g := range resp.GetGroups() {
+				got = append(got, g.GetStartRound())
+			}
+			require.Equal(d.t, startRounds, got, "id %s", id)
+		})
+	}
+}
+
 func (d *DrandTest) TestPublicBeacon(id string) {
 	dr := d.GetDrand(id)
 	client := net.NewGrpcClientFromCertManager(dr.opts.certmanager, dr.opts.grpcOpts...)
@@ -688,6 +1270,62 @@ func TestDrandPublicStream(t *testing.T) {
 	}
 }
 
+func TestDrandPeers(t *testing.T) {
+	n := 4
+	thr := key.DefaultThreshold(n)
+	p := 1 * time.Second
+	dt := NewDrandTest(t, n, thr, p)
+	defer dt.Cleanup()
+	group := dt.RunDKG()
+	root := dt.drands[dt.ids[0]]
+
+	dt.MoveToTime(group.GenesisTime)
+	for i := 0; i < 3; i++ {
+		dt.MoveTime(group.Period)
+	}
+
+	client, err := net.NewControlClient(root.opts.controlPort)
+	require.NoError(t, err)
+	resp, err := client.Peers()
+	require.NoError(t, err)
+	require.Len(t, resp.GetPeers(), n-1)
+	for _, peer := range resp.GetPeers() {
+		require.NotEqual(t, root.priv.Public.Address(), peer.GetAddress())
+		require.True(t, peer.GetSuccesses() > 0)
+		require.True(t, peer.GetLastRound() > 0)
+		require.Zero(t, peer.GetBackoffUntil())
+	}
+}
+
+func TestDrandSyncStatus(t *testing.T) {
+	n := 4
+	thr := key.DefaultThreshold(n)
+	p := 1 * time.Second
+	dt := NewDrandTest(t, n, thr, p)
+	defer dt.Cleanup()
+	group := dt.RunDKG()
+	root := dt.drands[dt.ids[0]]
+
+	dt.MoveToTime(group.GenesisTime)
+	for i := 0; i < 3; i++ {
+		dt.MoveTime(group.Period)
+	}
+
+	client, err := net.NewControlClient(root.opts.controlPort)
+	require.NoError(t, err)
+	status, err := client.SyncStatus()
+	require.NoError(t, err)
+	require.False(t, status.GetSyncing())
+	require.True(t, status.GetCurrent() > 0)
+	require.Empty(t, status.GetPeers())
+
+	// the node has the rounds already
+	_, err = client.StartSync(status.GetCurrent())
+	require.Error(t, err)
+	_, err = client.StartSync(1)
+	require.Error(t, err)
+}
+
 // BatchNewDrand returns n drands, using TLS or not, with the given
 // options. It returns the list of Drand structures, the group created,
 // the folder where db, certificates, etc are stored. It is the folder
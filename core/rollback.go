@@ -0,0 +1,41 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/drand/drand/beacon"
+)
+
+// RollbackChain deletes the beacons of the chain of the given ID saved after
+// the given round, and returns how many it deleted. The key and group folders
+// are kept, so the node syncs the deleted rounds again from its peers at the
+// next start. The daemon must be stopped.
+func RollbackChain(c *Config, beaconID string, round uint64) (int, error) {
+	if round == math.MaxUint64 {
+		return 0, fmt.Errorf("drand: no round can follow round %d", round)
+	}
+	backend, err := beacon.GetStoreBackend(c.dbBackend)
+	if err != nil {
+		return 0, err
+	}
+	db := backend.Path(c.BeaconDBFolder(beaconID))
+	if db == "" {
+		return 0, fmt.Errorf("drand: the %s storage backend keeps no beacon on disk", c.dbBackend)
+	}
+	if _, err := os.Stat(db); err != nil {
+		return 0, fmt.Errorf("drand: no beacon database at %s: %v", db, err)
+	}
+	store, err := c.openBeaconStore(beaconID)
+	if err != nil {
+		return 0, err
+	}
+	defer store.Close()
+
+	deleted, err := store.DelFrom(round + 1)
+	if err != nil {
+		return 0, fmt.Errorf("drand: can't delete the rounds after %d: %v", round, err)
+	}
+	return deleted, nil
+}
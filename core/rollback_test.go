@@ -0,0 +1,56 @@
+package core
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/drand/drand/beacon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollbackChain(t *testing.T) {
+	for _, backend := range []string{beacon.BoltBackend, beacon.LevelDBBackend} {
+		t.Run(backend, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "drand-rollback")
+			require.NoError(t, err)
+			defer os.RemoveAll(dir)
+			conf := NewConfig(WithConfigFolder(dir), WithDbBackend(backend))
+
+			_, err = RollbackChain(conf, DefaultBeaconID, 2)
+			require.Error(t, err)
+
+			require.NoError(t, os.MkdirAll(conf.DBFolder(), 0700))
+			store, err := conf.openBeaconStore(DefaultBeaconID)
+			require.NoError(t, err)
+			for i := uint64(0); i <= 5; i++ {
+				require.NoError(t, store.Put(&beacon.Beacon{Round: i, Signature: []byte{byte(i)}}))
+			}
+			store.Close()
+
+			// no round follows the last possible one
+			_, err = RollbackChain(conf, DefaultBeaconID, math.MaxUint64)
+			require.Error(t, err)
+
+			deleted, err := RollbackChain(conf, DefaultBeaconID, 2)
+			require.NoError(t, err)
+			require.Equal(t, 3, deleted)
+			deleted, err = RollbackChain(conf, DefaultBeaconID, 2)
+			require.NoError(t, err)
+			require.Equal(t, 0, deleted)
+
+			store, err = conf.openBeaconStore(DefaultBeaconID)
+			require.NoError(t, err)
+			defer store.Close()
+			require.Equal(t, 3, store.Len())
+			last, err := store.Last()
+			require.NoError(t, err)
+			require.Equal(t, uint64(2), last.Round)
+		})
+	}
+
+	conf := NewConfig(WithDbBackend(beacon.MemoryBackend))
+	_, err := RollbackChain(conf, DefaultBeaconID, 2)
+	require.Error(t, err)
+}
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sync"
@@ -9,9 +10,11 @@ import (
 	"github.com/drand/drand/beacon"
 	"github.com/drand/drand/key"
 	"github.com/drand/drand/log"
+	dnet "github.com/drand/drand/net"
 	"github.com/drand/drand/protobuf/drand"
 	control "github.com/drand/drand/protobuf/drand"
 	proto "github.com/drand/drand/protobuf/drand"
+	"github.com/drand/kyber"
 	clock "github.com/jonboulle/clockwork"
 )
 
@@ -46,19 +49,27 @@ type setupManager struct {
 	clock        clock.Clock
 	leaderKey    *key.Identity
 	verifySecret func(string) bool
-	verifyKeys   func([]*key.Identity) bool
 	l            log.Logger
 
+	// allowed are the only identities that can join if not empty
+	allowed []*key.Identity
+	// joined are the identities received so far, including the leader's
+	joined     []*key.Identity
+	joinedLock sync.Mutex
+
 	isResharing bool
 	oldGroup    *key.Group
 	oldHash     string
+	// version 2 hash of the old group
+	oldChainHash string
 
 	startDKG  chan *key.Group
 	pushKeyCh chan pushKey
+	leaveCh   chan kyber.Point
 	doneCh    chan bool
 }
 
-func newDKGSetup(l log.Logger, c clock.Clock, leaderKey *key.Identity, beaconPeriod uint32, in *control.SetupInfoPacket) (*setupManager, error) {
+func newDKGSetup(l log.Logger, c clock.Clock, leader *key.Pair, beaconPeriod uint32, in *control.SetupInfoPacket) (*setupManager, error) {
 	n, thr, dkgTimeout, err := validInitPacket(in)
 	if err != nil {
 		return nil, err
@@ -69,15 +80,14 @@ func newDKGSetup(l log.Logger, c clock.Clock, leaderKey *key.Identity, beaconPer
 		// elaborate things later like a separate secret to each individual etc
 		return given == secret
 	}
-	verifyKeys := func(keys []*key.Identity) bool {
-		// XXX Later we can add specific name list of DNS, or prexisting
-		// keys..
-		return true
-	}
 	offset := time.Duration(in.GetBeaconOffset()) * time.Second
 	if offset == 0 {
 		offset = DefaultGenesisOffset
 	}
+	allowed, err := allowedIdentities(leader.Public, n, in.GetAllowed())
+	if err != nil {
+		return nil, err
+	}
 
 	sm := &setupManager{
 		expected:     n,
@@ -88,19 +98,22 @@ func newDKGSetup(l log.Logger, c clock.Clock, leaderKey *key.Identity, beaconPer
 		l:            l,
 		startDKG:     make(chan *key.Group, 1),
 		pushKeyCh:    make(chan pushKey, n),
+		leaveCh:      make(chan kyber.Point, n),
 		verifySecret: verifySecret,
-		verifyKeys:   verifyKeys,
 		doneCh:       make(chan bool, 1),
 		clock:        c,
-		leaderKey:    leaderKey,
+		allowed:      allowed,
+	}
+	if err := sm.signLeaderKey(leader); err != nil {
+		return nil, err
 	}
 	return sm, nil
 }
 
-func newReshareSetup(l log.Logger, c clock.Clock, leaderKey *key.Identity, oldGroup *key.Group, in *control.InitResharePacket) (*setupManager, error) {
+func newReshareSetup(l log.Logger, c clock.Clock, leader *key.Pair, oldGroup *key.Group, in *control.InitResharePacket) (*setupManager, error) {
 	// period isn't included for resharing since we keep the same period
 	beaconPeriod := uint32(oldGroup.Period.Seconds())
-	sm, err := newDKGSetup(l, c, leaderKey, beaconPeriod, in.GetInfo())
+	sm, err := newDKGSetup(l, c, leader, beaconPeriod, in.GetInfo())
 	if err != nil {
 		return nil, err
 	}
@@ -111,7 +124,14 @@ func newReshareSetup(l log.Logger, c clock.Clock, leaderKey *key.Identity, oldGr
 		return nil, err
 	}
 	sm.oldHash = hash
+	if sm.oldChainHash, err = oldGroup.ChainHash(); err != nil {
+		return nil, err
+	}
 	sm.isResharing = true
+	// the previous group hash is part of the parameters signed by the leader
+	if err := sm.signLeaderKey(leader); err != nil {
+		return nil, err
+	}
 	offset := time.Duration(in.GetInfo().GetBeaconOffset()) * time.Second
 	if offset == 0 {
 		offset = DefaultResharingOffset
@@ -119,6 +139,77 @@ func newReshareSetup(l log.Logger, c clock.Clock, leaderKey *key.Identity, oldGr
 	return sm, nil
 }
 
+// allowedIdentities decodes the list of identities allowed to join the setup
+// and checks there are enough of them to reach the expected number of nodes.
+func allowedIdentities(leader *key.Identity, n int, list []*proto.Identity) ([]*key.Identity, error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+	allowed := make([]*key.Identity, 0, len(list))
+	for _, p := range list {
+		id, err := protoToIdentity(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed identity: %v", err)
+		}
+		if id.Equal(leader) {
+			continue
+		}
+		allowed = append(allowed, id)
+	}
+	if len(allowed)+1 < n {
+		return nil, fmt.Errorf("%d allowed participants for %d expected nodes", len(allowed)+1, n)
+	}
+	return allowed, nil
+}
+
+func (s *setupManager) isAllowed(id *key.Identity) bool {
+	if len(s.allowed) == 0 {
+		return true
+	}
+	for _, a := range s.allowed {
+		if a.Equal(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// Status returns the identities that joined the setup so far and the allowed
+// identities that did not join yet.
+func (s *setupManager) Status() (joined, missing []*key.Identity) {
+	s.joinedLock.Lock()
+	defer s.joinedLock.Unlock()
+	joined = append(joined, s.joined...)
+	for _, a := range s.allowed {
+		var found bool
+		for _, j := range s.joined {
+			if a.Equal(j) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, a)
+		}
+	}
+	return joined, missing
+}
+
+// setupParams returns the parameters of the setup each participant signs
+// along with its identity.
+func (s *setupManager) setupParams() []byte {
+	return key.SetupParams(s.expected, s.thr, s.dkgTimeout, s.oldHash)
+}
+
+func (s *setupManager) signLeaderKey(leader *key.Pair) error {
+	id, err := leader.SignedIdentity(s.setupParams())
+	if err != nil {
+		return fmt.Errorf("can't sign leader identity: %v", err)
+	}
+	s.leaderKey = id
+	return nil
+}
+
 type pushKey struct {
 	addr string
 	id   *key.Identity
@@ -132,6 +223,9 @@ type pushKey struct {
 func (s *setupManager) ReceivedKey(addr string, p *proto.PrepareDKGPacket) error {
 	s.Lock()
 	defer s.Unlock()
+	if err := dnet.CheckMetadata(p.GetMetadata()); err != nil {
+		return fmt.Errorf("incompatible node %s: %v", addr, err)
+	}
 	// verify informations are correct
 	if s.expected != int(p.GetExpected()) {
 		return fmt.Errorf("expected nodes %d vs given %d", s.expected, p.GetExpected())
@@ -153,6 +247,11 @@ func (s *setupManager) ReceivedKey(addr string, p *proto.PrepareDKGPacket) error
 		if s.oldHash != p.GetPreviousGroupHash() {
 			return errors.New("inconsistent previous group hash")
 		}
+		// older nodes don't advertise nor send the version 2 hash
+		chainHash := p.GetPreviousChainHash()
+		if dnet.HasFeature(p.GetMetadata(), dnet.FeatureChainHash) && s.oldChainHash != chainHash {
+			return errors.New("inconsistent previous group chain hash")
+		}
 	}
 
 	newID, err := protoToIdentity(p.GetNode())
@@ -160,6 +259,14 @@ func (s *setupManager) ReceivedKey(addr string, p *proto.PrepareDKGPacket) error
 		s.l.Info("setup", "error_decoding", "id", addr, err)
 		return fmt.Errorf("invalid id: %v", err)
 	}
+	if err := newID.ValidSignature(s.setupParams()); err != nil {
+		s.l.Info("setup", "invalid_signature", "id", addr, "err", err)
+		return fmt.Errorf("invalid id %s: %v", newID.Address(), err)
+	}
+	if !s.isAllowed(newID) {
+		s.l.Info("setup", "not_allowed", "id", addr, "addr", newID.String())
+		return fmt.Errorf("identity %s is not in the list of allowed participants", newID.String())
+	}
 
 	s.l.Debug("setup", "received_new_key", "id", newID.String())
 
@@ -180,6 +287,7 @@ type groupReceiver struct {
 func (s *setupManager) run() {
 	var inKeys = make([]*key.Identity, 0, s.expected)
 	inKeys = append(inKeys, s.leaderKey)
+	s.setJoined(inKeys)
 	for {
 		select {
 		case pk := <-s.pushKeyCh:
@@ -200,18 +308,27 @@ func (s *setupManager) run() {
 				break
 			}
 			inKeys = append(inKeys, pk.id)
+			s.setJoined(inKeys)
 			s.l.Debug("setup", "added", "key", pk.id.String(), "have", fmt.Sprintf("%d/%d", len(inKeys), s.expected))
 
-			// create group if we have enough keys
+			// create group if we have enough keys, the allowed list has been
+			// checked when each key was received
 			if len(inKeys) == s.expected {
-				if s.verifyKeys(inKeys) {
-					// we dont want to receive others
-					s.doneCh <- true
-					// we dont want to receive others
-					// go send the keys back to all participants
-					s.createAndSend(inKeys)
-					// job is done
-					return
+				// we dont want to receive others
+				s.doneCh <- true
+				// go send the keys back to all participants
+				s.createAndSend(inKeys)
+				// job is done
+				return
+			}
+		case pub := <-s.leaveCh:
+			// the leader never leaves, it cancels the whole setup
+			for i, id := range inKeys[1:] {
+				if id.Key.Equal(pub) {
+					inKeys = append(inKeys[:i+1], inKeys[i+2:]...)
+					s.setJoined(inKeys)
+					s.l.Debug("setup", "left", "key", id.String(), "have", fmt.Sprintf("%d/%d", len(inKeys), s.expected))
+					break
 				}
 			}
 		case <-s.doneCh:
@@ -221,6 +338,21 @@ func (s *setupManager) run() {
 	}
 }
 
+// Left removes a participant that cancelled the setup before the group is
+// created.
+func (s *setupManager) Left(pub kyber.Point) {
+	select {
+	case s.leaveCh <- pub:
+	default:
+	}
+}
+
+func (s *setupManager) setJoined(keys []*key.Identity) {
+	s.joinedLock.Lock()
+	defer s.joinedLock.Unlock()
+	s.joined = append([]*key.Identity{}, keys...)
+}
+
 func (s *setupManager) createAndSend(keys []*key.Identity) {
 	// create group
 	var group *key.Group
@@ -254,7 +386,11 @@ func (s *setupManager) WaitGroup() chan *key.Group {
 // StopPreemptively is to be called if something is wrong *before* the
 // group is created. In normal cases, setupManager will stop itself.
 func (s *setupManager) StopPreemptively() {
-	s.doneCh <- true
+	select {
+	case s.doneCh <- true:
+	default:
+		// the manager stopped already
+	}
 }
 
 func validInitPacket(in *control.SetupInfoPacket) (n int, thr int, dkg time.Duration, err error) {
@@ -272,33 +408,362 @@ func validInitPacket(in *control.SetupInfoPacket) (n int, thr int, dkg time.Dura
 	return
 }
 
+// setupRun is a setup, and the DKG that follows, started with InitDKG or
+// InitReshare. It is cancelled by the operator with CancelDKG, or by the other
+// nodes of a setup with a leader.
+type setupRun struct {
+	sync.Mutex
+	cancel context.CancelFunc
+	doneCh chan bool
+	// leader is true if this node coordinates the setup
+	leader bool
+	// secret of a setup with a leader, empty without leader
+	secret string
+	// key is the key pair this node takes part in the setup with
+	key *key.Pair
+	// peers are the nodes notified if the setup is cancelled
+	peers []dnet.Peer
+	// nodes are the nodes allowed to abort the setup, once the group is
+	// created for a leader, or the leader for a participant
+	nodes []*key.Identity
+}
+
+func (r *setupRun) setPeers(peers []dnet.Peer) {
+	r.Lock()
+	defer r.Unlock()
+	r.peers = peers
+}
+
+func (r *setupRun) getPeers() []dnet.Peer {
+	r.Lock()
+	defer r.Unlock()
+	return r.peers
+}
+
+func (r *setupRun) setNodes(nodes []*key.Identity) {
+	r.Lock()
+	defer r.Unlock()
+	r.nodes = nodes
+}
+
+// node returns the node allowed to abort the setup with the given key, nil if
+// there is none
+func (r *setupRun) node(pub kyber.Point) *key.Identity {
+	r.Lock()
+	defer r.Unlock()
+	return findIdentity(r.nodes, pub)
+}
+
+func findIdentity(ids []*key.Identity, pub kyber.Point) *key.Identity {
+	for _, id := range ids {
+		if id.Key.Equal(pub) {
+			return id
+		}
+	}
+	return nil
+}
+
+// abortSetupMsg is the message a node signs with its longterm key to abort the
+// setup with the given secret
+func abortSetupMsg(secret string) []byte {
+	return []byte("drand-abort-setup:" + secret)
+}
+
 type setupReceiver struct {
+	sync.Mutex
 	ch     chan *drand.GroupPacket
+	errCh  chan error
 	l      log.Logger
 	secret string
+	setup  []byte
+
+	// pending is the group waiting to be accepted by the operator
+	pending     *drand.GroupPacket
+	pendingHash string
+	answered    bool
+	acceptCh    chan bool
 }
 
-func newSetupReceiver(l log.Logger, in *control.SetupInfoPacket) *setupReceiver {
+func newSetupReceiver(l log.Logger, in *control.SetupInfoPacket, setup []byte) *setupReceiver {
 	return &setupReceiver{
-		ch:     make(chan *drand.GroupPacket, 1),
-		l:      l,
-		secret: in.GetSecret(),
+		ch:       make(chan *drand.GroupPacket, 1),
+		errCh:    make(chan error, 1),
+		l:        l,
+		secret:   in.GetSecret(),
+		setup:    setup,
+		acceptCh: make(chan bool, 1),
 	}
 }
 
+// ReceivedGroup verifies the group pushed by the leader before acknowledging
+// it, so the leader only starts the DKG once all participants are ready.
 func (r *setupReceiver) ReceivedGroup(pg *drand.PushGroupPacket) error {
 	if pg.GetSecretProof() != r.secret {
 		r.l.Debug("received", "invalid_secret_proof")
 		return errors.New("invalid secret")
 	}
+	if err := dnet.CheckMetadata(pg.GetMetadata()); err != nil {
+		err = fmt.Errorf("incompatible leader: %v", err)
+		r.l.Error("received", "invalid_leader", "err", err)
+		r.reportError(err)
+		return err
+	}
+	group, err := ProtoToGroup(pg.GetNewGroup())
+	if err != nil {
+		return fmt.Errorf("invalid group: %s", err)
+	}
+	if err := verifyPossession(group, r.setup); err != nil {
+		r.l.Error("received", "invalid_group", "err", err)
+		r.reportError(err)
+		return err
+	}
 	r.ch <- pg.GetNewGroup()
 	return nil
 }
 
+// reportError sends the error to the waiting setup, unless an error is pending
+// already, so an invalid push never blocks
+func (r *setupReceiver) reportError(err error) {
+	select {
+	case r.errCh <- err:
+	default:
+	}
+}
+
 func (r *setupReceiver) WaitGroup() chan *drand.GroupPacket {
 	return r.ch
 }
 
+// WaitError returns a channel over which an error is sent if the group pushed
+// by the leader is invalid
+func (r *setupReceiver) WaitError() chan error {
+	return r.errCh
+}
+
+// SetPending records the group received from the leader, which the operator
+// must accept before the node acknowledges it to the leader.
+func (r *setupReceiver) SetPending(g *drand.GroupPacket, hash string) {
+	r.Lock()
+	defer r.Unlock()
+	r.pending = g
+	r.pendingHash = hash
+}
+
+// Pending returns the group waiting to be accepted by the operator, or nil if
+// none has been received yet.
+func (r *setupReceiver) Pending() *drand.GroupPacket {
+	r.Lock()
+	defer r.Unlock()
+	return r.pending
+}
+
+// Accept records the decision of the operator about the pending group. The
+// hash must be the one of the pending group, so the operator can only accept
+// the group that has been shown.
+func (r *setupReceiver) Accept(hash string, accept bool) error {
+	r.Lock()
+	defer r.Unlock()
+	if r.pending == nil {
+		return errors.New("no group received from the leader yet")
+	}
+	if hash != r.pendingHash {
+		return fmt.Errorf("hash %s does not match the received group of hash %s", hash, r.pendingHash)
+	}
+	if r.answered {
+		return errors.New("group already accepted or rejected")
+	}
+	r.answered = true
+	r.acceptCh <- accept
+	return nil
+}
+
+// WaitAccept returns a channel over which the decision of the operator about
+// the pending group is sent
+func (r *setupReceiver) WaitAccept() chan bool {
+	return r.acceptCh
+}
+
 func (r *setupReceiver) stop() {
 	close(r.ch)
 }
+
+// groupConfirmer collects the signed hashes of the group a DKG is about to run
+// with. When there is no leader, the group has been agreed upon beforehand and
+// every node sends the hash of the group it holds to all the other members.
+// With a leader, every node that received the group acknowledges it to the
+// leader. Once every node confirmed the same hash, the DKG can start.
+type groupConfirmer struct {
+	sync.Mutex
+	l         log.Logger
+	nodes     []*key.Identity
+	hash      string
+	chainHash string
+	own       *drand.GroupHashPacket
+	confirmed map[int]bool
+	doneCh    chan bool
+	errCh     chan error
+}
+
+// newGroupConfirmer returns a confirmer waiting for the given nodes to confirm
+// the hash of the group of the given chain. Our own key must be part of the
+// nodes.
+func newGroupConfirmer(l log.Logger, priv *key.Pair, group *key.Group, nodes []*key.Identity, beaconID string) (*groupConfirmer, error) {
+	index := indexOfKey(nodes, priv.Public.Key)
+	if index < 0 {
+		return nil, errors.New("public key not found in group")
+	}
+	own, err := signGroupHash(priv, group, beaconID)
+	if err != nil {
+		return nil, err
+	}
+	return &groupConfirmer{
+		l:         l,
+		nodes:     nodes,
+		hash:      own.GetHash(),
+		chainHash: own.GetChainHash(),
+		own:       own,
+		confirmed: map[int]bool{index: true},
+		doneCh:    make(chan bool),
+		errCh:     make(chan error, 1),
+	}, nil
+}
+
+// signGroupHash returns both versions of the hash of the group of the given
+// chain, each signed with our longterm key
+func signGroupHash(priv *key.Pair, group *key.Group, beaconID string) (*drand.GroupHashPacket, error) {
+	hash, err := group.Hash()
+	if err != nil {
+		return nil, err
+	}
+	chainHash, err := group.ChainHash()
+	if err != nil {
+		return nil, err
+	}
+	sig, err := key.AuthScheme.Sign(priv.Key, groupHashMsg(hash))
+	if err != nil {
+		return nil, err
+	}
+	chainSig, err := key.AuthScheme.Sign(priv.Key, chainHashMsg(chainHash))
+	if err != nil {
+		return nil, err
+	}
+	buff, _ := priv.Public.Key.MarshalBinary()
+	return &drand.GroupHashPacket{
+		Key:            buff,
+		Hash:           hash,
+		Signature:      sig,
+		ChainHash:      chainHash,
+		ChainSignature: chainSig,
+		Metadata:       dnet.NewMetadata(beaconID),
+	}, nil
+}
+
+// indexOfKey looks up a node by its key only, since the same node may be
+// reachable under different addresses.
+func indexOfKey(nodes []*key.Identity, pub kyber.Point) int {
+	for i, id := range nodes {
+		if id.Key.Equal(pub) {
+			return i
+		}
+	}
+	return -1
+}
+
+func groupHashMsg(hash string) []byte {
+	return []byte("drand-group-hash:" + hash)
+}
+
+func chainHashMsg(hash string) []byte {
+	return []byte("drand-chain-hash:" + hash)
+}
+
+// Packet returns the signed hash of our group to send to the other members
+func (g *groupConfirmer) Packet() *drand.GroupHashPacket {
+	return g.own
+}
+
+// Received processes the group hash sent by another member of the group,
+// either in its request or in its reply to ours. It returns an error if the
+// packet is not properly signed by a member of the group. A member holding a
+// different group aborts the setup.
+func (g *groupConfirmer) Received(p *drand.GroupHashPacket) error {
+	pub := key.KeyGroup.Point()
+	if err := pub.UnmarshalBinary(p.GetKey()); err != nil {
+		return fmt.Errorf("invalid key: %v", err)
+	}
+	index := indexOfKey(g.nodes, pub)
+	if index < 0 {
+		return errors.New("key not found in group")
+	}
+	if err := dnet.CheckMetadata(p.GetMetadata()); err != nil {
+		err = fmt.Errorf("incompatible node %s: %v", g.nodes[index].Address(), err)
+		g.l.Error("setup", "incompatible_node", "err", err)
+		select {
+		case g.errCh <- err:
+		default:
+		}
+		return err
+	}
+	if err := key.AuthScheme.Verify(pub, groupHashMsg(p.GetHash()), p.GetSignature()); err != nil {
+		return fmt.Errorf("invalid signature: %v", err)
+	}
+	// older nodes only send the first version of the hash
+	chainHash := p.GetChainHash()
+	hasChainHash := dnet.HasFeature(p.GetMetadata(), dnet.FeatureChainHash)
+	if hasChainHash {
+		if err := key.AuthScheme.Verify(pub, chainHashMsg(chainHash), p.GetChainSignature()); err != nil {
+			return fmt.Errorf("invalid chain hash signature: %v", err)
+		}
+	}
+	addr := g.nodes[index].Address()
+	g.Lock()
+	defer g.Unlock()
+	var mismatch error
+	if p.GetHash() != g.hash {
+		mismatch = fmt.Errorf("node %s holds group hash %s instead of %s", addr, p.GetHash(), g.hash)
+	} else if hasChainHash && chainHash != g.chainHash {
+		mismatch = fmt.Errorf("node %s holds group chain hash %s instead of %s", addr, chainHash, g.chainHash)
+	}
+	if mismatch != nil {
+		g.l.Error("setup", "group_hash_mismatch", "from", addr, "hash", p.GetHash(), "chain_hash", chainHash)
+		select {
+		case g.errCh <- mismatch:
+		default:
+		}
+		return nil
+	}
+	if g.confirmed[index] {
+		return nil
+	}
+	g.confirmed[index] = true
+	g.l.Debug("setup", "group_confirmed", "from", addr, "have", fmt.Sprintf("%d/%d", len(g.confirmed), len(g.nodes)))
+	if len(g.confirmed) == len(g.nodes) {
+		close(g.doneCh)
+	}
+	return nil
+}
+
+// Missing returns the members that have not confirmed the group yet
+func (g *groupConfirmer) Missing() []*key.Identity {
+	g.Lock()
+	defer g.Unlock()
+	var missing []*key.Identity
+	for i, id := range g.nodes {
+		if !g.confirmed[i] {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+// WaitConfirmed returns a channel that is closed once every node confirmed
+// the group
+func (g *groupConfirmer) WaitConfirmed() chan bool {
+	return g.doneCh
+}
+
+// WaitError returns a channel over which an error is sent if a member holds a
+// different group
+func (g *groupConfirmer) WaitError() chan error {
+	return g.errCh
+}
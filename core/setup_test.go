@@ -0,0 +1,180 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/drand/drand/key"
+	"github.com/drand/drand/log"
+	dnet "github.com/drand/drand/net"
+	"github.com/drand/drand/protobuf/drand"
+	"github.com/drand/drand/test"
+	clock "github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetupAllowed(t *testing.T) {
+	n := 4
+	privs := test.GenerateIDs(n + 1)
+	leader, members, intruder := privs[0], privs[1:n], privs[n]
+	in := &drand.SetupInfoPacket{
+		Leader:    true,
+		Nodes:     uint32(n),
+		Threshold: 3,
+		Timeout:   "10s",
+		Secret:    "secret",
+		Allowed:   identitiesToProto(test.ListFromPrivates(members)),
+	}
+	l := log.NewLogger(log.LogInfo)
+	c := clock.NewFakeClock()
+
+	// not enough allowed participants
+	short := *in
+	short.Allowed = short.Allowed[1:]
+	_, err := newDKGSetup(l, c, leader, 10, &short)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "3 allowed participants for 4 expected nodes")
+	// the leader counts once, listed or not
+	short.Allowed = append(short.Allowed, identitiesToProto([]*key.Identity{leader.Public})...)
+	_, err = newDKGSetup(l, c, leader, 10, &short)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "3 allowed participants for 4 expected nodes")
+
+	sm, err := newDKGSetup(l, c, leader, 10, in)
+	require.NoError(t, err)
+	go sm.run()
+
+	sendWith := func(p *key.Pair, meta *drand.Metadata) error {
+		id, err := p.SignedIdentity(key.SetupParams(n, 3, 10, ""))
+		require.NoError(t, err)
+		return sm.ReceivedKey(p.Public.Address(), &drand.PrepareDKGPacket{
+			Node:        identitiesToProto([]*key.Identity{id})[0],
+			Expected:    uint32(n),
+			Threshold:   3,
+			DkgTimeout:  10,
+			SecretProof: "secret",
+			Metadata:    meta,
+		})
+	}
+	send := func(p *key.Pair) error {
+		return sendWith(p, dnet.NewMetadata(DefaultBeaconID))
+	}
+	require.NoError(t, send(members[0]))
+	require.NoError(t, send(members[1]))
+	require.Eventually(t, func() bool {
+		joined, _ := sm.Status()
+		return len(joined) == 3
+	}, time.Second, 10*time.Millisecond)
+	_, missing := sm.Status()
+	require.Len(t, missing, 1)
+	require.True(t, missing[0].Equal(members[2].Public))
+
+	err = send(intruder)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "not in the list of allowed participants")
+
+	// nodes speaking another version of the protocol are rejected
+	err = sendWith(members[2], nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "incompatible")
+	err = sendWith(members[2], &drand.Metadata{Major: dnet.ProtocolMajor + 1})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "incompatible")
+
+	require.NoError(t, send(members[2]))
+	select {
+	case group := <-sm.WaitGroup():
+		require.Equal(t, n, group.Len())
+		require.False(t, group.Contains(intruder.Public))
+	case <-time.After(time.Second):
+		t.Fatal("group not created")
+	}
+}
+
+func TestSetupReceiverAccept(t *testing.T) {
+	l := log.NewLogger(log.LogInfo)
+	r := newSetupReceiver(l, &drand.SetupInfoPacket{Secret: "secret"}, nil)
+	// nothing received yet
+	require.Nil(t, r.Pending())
+	require.Error(t, r.Accept("hash", true))
+
+	_, group := test.BatchIdentities(3)
+	hash, err := group.Hash()
+	require.NoError(t, err)
+	packet, err := groupToProto(group)
+	require.NoError(t, err)
+	r.SetPending(packet, hash)
+	require.NotNil(t, r.Pending())
+	// the operator must accept the group that has been shown
+	require.Error(t, r.Accept("wrong", true))
+	require.NoError(t, r.Accept(hash, true))
+	require.True(t, <-r.WaitAccept())
+	require.Error(t, r.Accept(hash, false))
+}
+
+func TestSetupReceiverInvalidPushes(t *testing.T) {
+	l := log.NewLogger(log.LogInfo)
+	r := newSetupReceiver(l, &drand.SetupInfoPacket{Secret: "secret"}, nil)
+	// the pushes of an unversioned leader must not block after the first error
+	for i := 0; i < 3; i++ {
+		require.Error(t, r.ReceivedGroup(&drand.PushGroupPacket{SecretProof: "secret"}))
+	}
+	require.Error(t, <-r.WaitError())
+}
+
+func TestGroupConfirmerChainHash(t *testing.T) {
+	n := 3
+	privs, group := test.BatchIdentities(n)
+	group.Period = 2 * time.Second
+	group.GenesisTime = 1000
+	l := log.NewLogger(log.LogInfo)
+
+	confirmer, err := newGroupConfirmer(l, privs[0], group, group.Nodes, DefaultBeaconID)
+	require.NoError(t, err)
+	require.NotEmpty(t, confirmer.Packet().GetChainHash())
+
+	// a node advertising the chain hash must send it
+	stripped, err := signGroupHash(privs[1], group, DefaultBeaconID)
+	require.NoError(t, err)
+	stripped.ChainHash = ""
+	stripped.ChainSignature = nil
+	require.Error(t, confirmer.Received(stripped))
+
+	// an older node only sends the first version of the hash
+	older, err := signGroupHash(privs[1], group, DefaultBeaconID)
+	require.NoError(t, err)
+	older.ChainHash = ""
+	older.ChainSignature = nil
+	older.Metadata.Features = nil
+	require.NoError(t, confirmer.Received(older))
+
+	// the chain hash must be signed
+	forged, err := signGroupHash(privs[2], group, DefaultBeaconID)
+	require.NoError(t, err)
+	forged.ChainSignature = older.GetSignature()
+	require.Error(t, confirmer.Received(forged))
+
+	// a node with the same first version of the hash but a different period
+	// holds a different group
+	other := *group
+	other.Period = 3 * time.Second
+	different, err := signGroupHash(privs[2], &other, DefaultBeaconID)
+	require.NoError(t, err)
+	require.Equal(t, older.GetHash(), different.GetHash())
+	require.NoError(t, confirmer.Received(different))
+	select {
+	case err := <-confirmer.WaitError():
+		require.Contains(t, err.Error(), "chain hash")
+	default:
+		t.Fatal("different group not detected")
+	}
+
+	same, err := signGroupHash(privs[2], group, DefaultBeaconID)
+	require.NoError(t, err)
+	require.NoError(t, confirmer.Received(same))
+	select {
+	case <-confirmer.WaitConfirmed():
+	default:
+		t.Fatal("group not confirmed")
+	}
+}
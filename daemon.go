@@ -4,35 +4,91 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/drand/drand/beacon"
 	"github.com/drand/drand/core"
 	"github.com/drand/drand/key"
 	"github.com/drand/drand/metrics"
+	"github.com/drand/drand/net"
 	"github.com/urfave/cli/v2"
 )
 
 func startCmd(c *cli.Context) error {
 	conf := contextToConfig(c)
-	fs := key.NewFileStore(conf.ConfigFolder())
-	var drand *core.Drand
+	ids, err := core.StoredBeaconIDs(conf)
+	if err != nil {
+		fatal("drand: can't list the beacon ids: %v", err)
+	}
+	if len(ids) == 0 {
+		// fails with the error of the missing key pair
+		ids = []string{core.DefaultBeaconID}
+	}
+	stores := make(map[string]key.Store, len(ids))
+	var addr string
+	for _, id := range ids {
+		fs := openStore(c, conf.BeaconFolder(id))
+		priv, err := fs.LoadKeyPair()
+		if err != nil {
+			fatal("drand: can't load the key pair of beacon %q: %s", id, err)
+		}
+		// all the chains are served on the address of the first one
+		if addr == "" {
+			addr = priv.Public.Address()
+		} else if priv.Public.Address() != addr {
+			fmt.Printf("drand: beacon %q has the address %s but is served on %s\n", id, priv.Public.Address(), addr)
+		}
+		stores[id] = fs
+	}
+	daemon, err := core.NewDaemon(conf, addr)
+	if err != nil {
+		fatal("drand: can't instantiate drand daemon %s", err)
+	}
+	for _, id := range ids {
+		startBeacon(c, conf, daemon, id, stores[id])
+	}
+	// Start metrics server
+	if c.IsSet(metricsFlag.Name) {
+		go metrics.Start(c.Int(metricsFlag.Name))
+	}
+	<-daemon.WaitExit()
+
+	return nil
+}
+
+// startBeacon adds the chain of the given beacon ID to the daemon, either
+// waiting for its DKG or running its beacon.
+func startBeacon(c *cli.Context, conf *core.Config, daemon *core.Daemon, id string, fs key.Store) {
+	name := "drand"
+	if id != core.DefaultBeaconID {
+		name = fmt.Sprintf("drand: beacon %q", id)
+	}
 	// determine if we already ran a DKG or not
 	_, errG := fs.LoadGroup()
 	_, errS := fs.LoadShare()
 	_, errD := fs.LoadDistPublic()
 	// XXX place that logic inside core/ directly with only one method
+	if c.IsSet(signerFlag.Name) && id == core.DefaultBeaconID {
+		// the share is held by the signer process
+		errS = nil
+	}
 	freshRun := errG != nil || errS != nil || errD != nil
-	var err error
-	if freshRun {
-		if exit := resetBeaconDB(conf); exit {
+	// a DKG interrupted by a crash is resumed by core.LoadDrand
+	_, errDKG := fs.LoadDKGState()
+	if freshRun && errDKG == nil {
+		fmt.Printf("%s: will resume the DKG in progress.\n", name)
+		if _, err := daemon.LoadBeacon(id, fs); err != nil {
+			fatal("drand: can't load drand instance %s", err)
+		}
+	} else if freshRun {
+		if exit := resetBeaconDB(conf, id); exit {
 			os.Exit(0)
 		}
-		fmt.Println("drand: will run as fresh install -> expect to run DKG.")
-		drand, err = core.NewDrand(fs, conf)
-		if err != nil {
+		fmt.Printf("%s: will run as fresh install -> expect to run DKG.\n", name)
+		if _, err := daemon.NewBeacon(id, fs); err != nil {
 			fatal("drand: can't instantiate drand instance %s", err)
 		}
 	} else {
-		fmt.Println("drand: will already start running randomness beacon")
-		drand, err = core.LoadDrand(fs, conf)
+		fmt.Printf("%s: will already start running randomness beacon\n", name)
+		drand, err := daemon.LoadBeacon(id, fs)
 		if err != nil {
 			fatal("drand: can't load drand instance %s", err)
 		}
@@ -42,12 +98,34 @@ func startCmd(c *cli.Context) error {
 		catchup := true
 		drand.StartBeacon(catchup)
 	}
-	// Start metrics server
-	if c.IsSet(metricsFlag.Name) {
-		go metrics.Start(c.Int(metricsFlag.Name))
-	}
-	<-drand.WaitExit()
+}
 
+func signerCmd(c *cli.Context) error {
+	if !c.IsSet(signerFlag.Name) {
+		fatal("drand: the signer needs the path of its socket with --%s", signerFlag.Name)
+	}
+	conf := contextToConfig(c)
+	fs := openStore(c, conf.ConfigFolder())
+	share, err := fs.LoadShare()
+	if err == key.ErrEncrypted {
+		// the signer folder may only hold the share
+		fs, err = key.NewEncryptedFileStore(conf.ConfigFolder(), promptPassphrase(false))
+		if err != nil {
+			fatal("drand: %v", err)
+		}
+		share, err = fs.LoadShare()
+	}
+	if err != nil {
+		fatal("drand: can't load the share: %v", err)
+	}
+	socket := c.String(signerFlag.Name)
+	signer := beacon.NewSignerServer(beacon.NewShareSigner(share))
+	listener, err := net.NewUnixSignerListener(signer, socket)
+	if err != nil {
+		fatal("drand: can't listen on %s: %v", socket, err)
+	}
+	fmt.Printf("drand: signer for share %d listening on %s\n", share.Share.I, socket)
+	listener.Start()
 	return nil
 }
 
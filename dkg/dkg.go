@@ -3,11 +3,14 @@ package dkg
 import (
 	"bytes"
 	"context"
+	"crypto/cipher"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -21,6 +24,7 @@ import (
 	"github.com/drand/kyber"
 	dkg "github.com/drand/kyber/share/dkg/pedersen"
 	vss "github.com/drand/kyber/share/vss/pedersen"
+	"github.com/drand/kyber/util/random"
 	clock "github.com/jonboulle/clockwork"
 	"google.golang.org/grpc/peer"
 )
@@ -31,6 +35,10 @@ type Suite = dkg.Suite
 // DefaultTimeout is the timeout used by default when unspecified in the config
 const DefaultTimeout = time.Duration(1) * time.Minute
 
+// ErrStopped is sent over the error channel of a handler stopped before the
+// end of the protocol
+var ErrStopped = errors.New("dkg: protocol stopped")
+
 // Config holds all necessary information to run a dkg protocol. This config is
 // transformed to be passed down to the kyber dkg library.
 type Config struct {
@@ -43,6 +51,38 @@ type Config struct {
 	Reader         io.Reader
 	UserReaderOnly bool
 	Clock          clock.Clock
+	// NewKey, if set, is the key of this node in the new group of a
+	// resharing, Key being its key in the old group. A node rotating its
+	// long-term key deals with the old one and receives its new share with
+	// the new one.
+	NewKey *key.Pair
+	// Seed, if set, is the secret from which the dealer polynomial of this
+	// node is derived. A node resuming a protocol with the same seed issues
+	// deals for the same polynomial. If not set, a seed is drawn from Reader
+	// with NewSeed.
+	Seed []byte
+	// Journal, if set, records the progress of the protocol.
+	Journal Journal
+}
+
+// Journal is notified of the progress of a protocol so it can be saved and
+// later resumed with Handler.Resume in case the node crashes.
+type Journal interface {
+	// Started is called when the timeout of the protocol starts ticking.
+	Started(t time.Time)
+	// SentDeal is called for each deal sent to the node at the given index in
+	// the new group.
+	SentDeal(to int, p *dkg_proto.Packet)
+	// Received is called for each packet received from the network, before it
+	// is processed.
+	Received(p *dkg_proto.Packet)
+}
+
+// Progress is the progress of a protocol as recorded by a Journal.
+type Progress struct {
+	StartedAt time.Time
+	Deals     map[int]*dkg_proto.Packet
+	Received  []*dkg_proto.Packet
 }
 
 // Share represents the private information that a node holds after a successful
@@ -60,12 +100,16 @@ type Handler struct {
 	newNode       bool                       // true if this node belongs in the new group or not
 	oldNode       bool                       // true if this node belongs to the oldNode list
 	state         *dkg.DistKeyGenerator      // dkg stateful struct
+	dealer        *dkg.DistKeyGenerator      // issues the deals, same as state unless the node rotates its key
+	commitsSig    []byte                     // our signature over the commitments of our deal
+	commitsSigs   map[uint32][]byte          // signatures of the dealers over the commitments of their deal
 	n             int                        // number of participants
 	tmpResponses  map[uint32][]*dkg.Response // temporary buffer of responses
 	sentDeals     bool                       // true if the deals have been sent already
 	dealProcessed int                        // how many deals have we processed so far
 	respProcessed int                        // how many responses have we processed so far
 	done          bool                       // is the protocol done
+	stopped       bool                       // true if the protocol has been stopped
 	shareCh       chan Share                 // share gets sent over shareCh when ready
 	errCh         chan error                 // any fatal error for the protocol gets sent over
 	exitCh        chan bool                  // any old node not in the new group will signal the end of the protocol through this channel
@@ -97,15 +141,24 @@ func NewHandler(n Network, c *Config, l log.Logger) (*Handler, error) {
 	if c.Timeout == time.Duration(0) {
 		c.Timeout = DefaultTimeout
 	}
+	suite := c.Suite.(dkg.Suite)
+	seed := c.Seed
+	if seed == nil {
+		// the polynomial is always derived from a seed so we can recompute
+		// the commitments of our deal to sign them
+		seed = NewSeed(c.Reader, c.UserReaderOnly)
+	}
+	// the stream is only used while creating the dealer, see below
+	seeded := &seededSuite{Suite: suite, stream: suite.XOF(append([]byte("poly"), seed...))}
 	cdkg := &dkg.Config{
-		Suite:          c.Suite.(dkg.Suite),
+		Suite:          seeded,
 		Longterm:       c.Key.Key,
 		NewNodes:       c.NewNodes.Points(),
 		PublicCoeffs:   dpub,
 		Share:          share,
 		Threshold:      c.NewNodes.Threshold,
-		Reader:         c.Reader,
-		UserReaderOnly: c.UserReaderOnly,
+		Reader:         suite.XOF(append([]byte("secret"), seed...)),
+		UserReaderOnly: true,
 	}
 
 	if c.OldNodes != nil {
@@ -116,11 +169,33 @@ func NewHandler(n Network, c *Config, l log.Logger) (*Handler, error) {
 	if err != nil {
 		return nil, fmt.Errorf("dkg: error using dkg library: %s", err)
 	}
+	// encryption and signatures must use fresh randomness
+	seeded.stream = nil
+	commitsSig, err := signCommitments(suite, c, cdkg, seed)
+	if err != nil {
+		return nil, fmt.Errorf("dkg: can't sign the commitments of our deal: %s", err)
+	}
+	dealer := state
+	newKey := c.Key
+	if c.NewKey != nil && !c.NewKey.Public.Equal(c.Key.Public) {
+		if c.OldNodes == nil {
+			return nil, errors.New("dkg: a new key can only be used during a resharing")
+		}
+		// the kyber library only knows one key per node: the node is seen as
+		// an old node leaving and a new node joining at the same address
+		receiver := *cdkg
+		receiver.Longterm = c.NewKey.Key
+		receiver.Share = nil
+		if state, err = dkg.NewDistKeyHandler(&receiver); err != nil {
+			return nil, fmt.Errorf("dkg: error using dkg library: %s", err)
+		}
+		newKey = c.NewKey
+	}
 
 	var newNode, oldNode bool
 	var nidx, oidx int
 	var found bool
-	nidx, found = c.NewNodes.Index(c.Key.Public)
+	nidx, found = c.NewNodes.Index(newKey.Public)
 	if found {
 		newNode = true
 	}
@@ -143,6 +218,9 @@ func NewHandler(n Network, c *Config, l log.Logger) (*Handler, error) {
 		cdkg:         cdkg,
 		private:      c.Key,
 		state:        state,
+		dealer:       dealer,
+		commitsSig:   commitsSig,
+		commitsSigs:  make(map[uint32][]byte),
 		net:          n,
 		nidx:         nidx,
 		oidx:         oidx,
@@ -156,19 +234,72 @@ func NewHandler(n Network, c *Config, l log.Logger) (*Handler, error) {
 		sendDeal:     shouldSendDeal,
 		timerCh:      make(chan bool, 1),
 	}
+	if commitsSig != nil && dealer == state && newNode {
+		// the library processes our own deal itself
+		own := uint32(nidx)
+		if oldNode {
+			own = uint32(oidx)
+		}
+		handler.commitsSigs[own] = commitsSig
+	}
 	handler.l = l.With("dkg", handler.info())
 	return handler, nil
 }
 
+// NewSeed draws a seed for the dealer polynomial of a node from the given
+// reader, mixed with crypto/rand unless userOnly is set, or from crypto/rand
+// alone if the reader is nil.
+func NewSeed(reader io.Reader, userOnly bool) []byte {
+	stream := random.New()
+	if reader != nil && userOnly {
+		stream = random.New(reader)
+	} else if reader != nil {
+		stream = random.New(reader, rand.Reader)
+	}
+	seed := make([]byte, 32)
+	random.Bytes(seed, stream)
+	return seed
+}
+
+// signCommitments derives the polynomial of our deal from the seed the same
+// way the dkg library does, and signs its commitments. It returns nil if the
+// node does not deal.
+func signCommitments(suite dkg.Suite, c *Config, cdkg *dkg.Config, seed []byte) ([]byte, error) {
+	var secret kyber.Scalar
+	if cdkg.Share != nil {
+		// resharing case
+		secret = cdkg.Share.Share.V
+	} else if _, found := c.NewNodes.Index(c.Key.Public); found && c.OldNodes == nil {
+		// fresh dkg case
+		reader := suite.XOF(append([]byte("secret"), seed...))
+		secret = suite.Scalar().Pick(random.New(reader))
+	} else {
+		return nil, nil
+	}
+	seeded := &seededSuite{Suite: suite, stream: suite.XOF(append([]byte("poly"), seed...))}
+	dealer, err := vss.NewDealer(seeded, c.Key.Key, secret, cdkg.NewNodes, cdkg.Threshold)
+	if err != nil {
+		return nil, err
+	}
+	return key.SignDealCommitments(c.Key.Key, dealer.SessionID())
+}
+
 // Process process an incoming message from the network.
 func (h *Handler) Process(c context.Context, packet *dkg_proto.Packet) {
 	h.Lock()
 	defer h.Unlock()
-	if !h.timeoutLaunched {
-		h.timeoutLaunched = true
-		go h.startTimer() // start timer at the first message received
+	if h.stopped {
+		return
+	}
+	h.launchTimer() // start timer at the first message received
+	if h.conf.Journal != nil {
+		h.conf.Journal.Received(packet)
 	}
 	peer, _ := peer.FromContext(c)
+	h.process(peer, packet)
+}
+
+func (h *Handler) process(peer *peer.Peer, packet *dkg_proto.Packet) {
 	switch {
 	case packet.Deal != nil:
 		h.processDeal(peer, packet.Deal)
@@ -182,16 +313,73 @@ func (h *Handler) Process(c context.Context, packet *dkg_proto.Packet) {
 // Start sends the first message to run the protocol
 func (h *Handler) Start() {
 	h.Lock()
-	if !h.timeoutLaunched {
-		h.timeoutLaunched = true
-		go h.startTimer() // start timer at the first message received
+	if h.stopped {
+		h.Unlock()
+		return
 	}
+	h.launchTimer()
 	h.Unlock()
 	if err := h.sendDeals(); err != nil {
 		h.errCh <- err
 	}
 }
 
+// Resume restores the handler to the given progress of a protocol that was
+// interrupted. The timeout resumes where it was left, the deals already sent
+// are sent again and the received packets are replayed, which re-broadcasts
+// the responses our peers may have missed.
+func (h *Handler) Resume(p *Progress) {
+	h.Lock()
+	if !p.StartedAt.IsZero() && !h.timeoutLaunched {
+		h.timeoutLaunched = true
+		remaining := h.conf.Timeout - h.conf.Clock.Now().Sub(p.StartedAt)
+		if remaining < 0 {
+			remaining = 0
+		}
+		h.l.Info("resume", "timeout", "remaining", remaining)
+		go h.startTimer(remaining)
+	}
+	var deals map[int]*dkg_proto.Packet
+	var err error
+	if len(p.Deals) > 0 && h.sendDeal && !h.sentDeals {
+		// we need to process our own deal before the others' responses
+		h.sentDeals = true
+		deals, err = h.dealPackets(p.Deals)
+	}
+	h.l.Info("resume", "replay", "packets", len(p.Received), "deals", len(p.Deals))
+	for _, packet := range p.Received {
+		h.process(nil, packet)
+	}
+	h.Unlock()
+	if err != nil {
+		h.errCh <- err
+		return
+	}
+	if deals != nil {
+		if err := h.sendDealPackets(deals); err != nil {
+			h.errCh <- err
+		}
+	}
+}
+
+// Stop aborts the protocol if it is not finished yet: packets received
+// afterwards are ignored and ErrStopped is sent over the error channel.
+func (h *Handler) Stop() {
+	h.Lock()
+	defer h.Unlock()
+	if h.done {
+		return
+	}
+	h.done = true
+	h.stopped = true
+	close(h.timerCh)
+	h.l.Info("dkg", "stopped")
+	select {
+	case h.errCh <- ErrStopped:
+	default:
+	}
+}
+
 // WaitShare returns a channel over which the share will be sent over when
 // ready.
 func (h *Handler) WaitShare() chan Share {
@@ -233,14 +421,76 @@ func (h *Handler) QualifiedGroup() *key.Group {
 	return key.LoadGroup(newGroup, &key.DistPublic{Coefficients: h.share.Commits}, h.conf.NewNodes.Threshold)
 }
 
-func (h *Handler) startTimer() {
-	fmt.Printf(" DKG HANDLER TIMEOUT %s -> now %d -> will trigger at %d\n", h.conf.Key.Public.Address(), h.conf.Clock.Now().Unix(), h.conf.Clock.Now().Add(h.conf.Timeout).Unix())
+// launchTimer starts the timeout of the protocol if not already started. It
+// must be called with the lock held.
+func (h *Handler) launchTimer() {
+	if h.timeoutLaunched {
+		return
+	}
+	h.timeoutLaunched = true
+	if h.conf.Journal != nil {
+		h.conf.Journal.Started(h.conf.Clock.Now())
+	}
+	go h.startTimer(h.conf.Timeout)
+}
+
+// Transcript returns the public transcript of the protocol: the commitments of
+// the qualified dealers, the responses received for their deals and the QUAL
+// set. Only nodes of the new group can produce a transcript. This method MUST
+// only be called once the dkg has finished, as QualifiedGroup.
+func (h *Handler) Transcript() *key.Transcript {
+	h.Lock()
+	defer h.Unlock()
+	t := &key.Transcript{
+		Threshold: h.conf.NewNodes.Threshold,
+		Dealers:   h.cdkg.NewNodes,
+		Verifiers: h.cdkg.NewNodes,
+		QUAL:      h.state.QUAL(),
+	}
+	if h.conf.OldNodes != nil {
+		t.Dealers = h.cdkg.OldNodes
+		t.OldThreshold = h.conf.OldNodes.Threshold
+	}
+	sort.Ints(t.QUAL)
+	verifiers := h.state.Verifiers()
+	for _, dealer := range t.QUAL {
+		v := verifiers[uint32(dealer)]
+		deal := v.Deal()
+		if deal == nil {
+			continue
+		}
+		t.Deals = append(t.Deals, &key.DealCommitment{
+			Dealer:    uint32(dealer),
+			Commits:   deal.Commitments,
+			Signature: h.commitsSigs[uint32(dealer)],
+		})
+		responses := v.Responses()
+		for i := range h.cdkg.NewNodes {
+			r, ok := responses[uint32(i)]
+			if !ok || len(r.Signature) == 0 {
+				// the approval of a dealer for its own deal is not signed
+				continue
+			}
+			t.Responses = append(t.Responses, &key.DealResponse{
+				Dealer:    uint32(dealer),
+				Verifier:  r.Index,
+				Status:    r.Status,
+				SessionID: r.SessionID,
+				Signature: r.Signature,
+			})
+		}
+	}
+	return t
+}
+
+func (h *Handler) startTimer(timeout time.Duration) {
+	now := h.conf.Clock.Now()
+	h.l.Debug("timeout", "start", "now", now.Unix(), "trigger_at", now.Add(timeout).Unix())
 	select {
-	case <-h.conf.Clock.After(h.conf.Timeout):
+	case <-h.conf.Clock.After(timeout):
 		h.Lock()
 		defer h.Unlock()
-		fmt.Printf("DKG HANDLER %s - %d - timeout triggered !\n", h.conf.Key.Public.Address(), h.nidx)
-		h.l.Info("timout", "triggered")
+		h.l.Info("timout", "triggered", "index", h.nidx)
 		h.timeouted = true
 		h.state.SetTimeout()
 		h.checkCertified()
@@ -254,16 +504,7 @@ func (h *Handler) startTimer() {
 func (h *Handler) processDeal(p *peer.Peer, pdeal *dkg_proto.Deal) {
 	localLog := h.l.With("process", "deal")
 	h.dealProcessed++
-	deal := &dkg.Deal{
-		Index:     pdeal.Index,
-		Signature: pdeal.Signature,
-		Deal: &vss.EncryptedDeal{
-			DHKey:     pdeal.Deal.Dhkey,
-			Signature: pdeal.Deal.Signature,
-			Nonce:     pdeal.Deal.Nonce,
-			Cipher:    pdeal.Deal.Cipher,
-		},
-	}
+	deal := protoToDeal(pdeal)
 	defer h.processTmpResponses(deal)
 	localLog.Debug("deal_from", h.dealerAddr(deal.Index), "processed", h.dealProcessed, "sent", h.sentDeals)
 	resp, err := h.state.ProcessDeal(deal)
@@ -271,6 +512,7 @@ func (h *Handler) processDeal(p *peer.Peer, pdeal *dkg_proto.Deal) {
 		localLog.Error("kyber", err)
 		return
 	}
+	h.recordCommitsSig(deal.Index, pdeal.GetCommitsSignature())
 
 	if !h.sentDeals && h.sendDeal {
 		localLog.Debug("action", "sending_deals")
@@ -300,6 +542,26 @@ func (h *Handler) processDeal(p *peer.Peer, pdeal *dkg_proto.Deal) {
 	}
 }
 
+// recordCommitsSig keeps the signature of a dealer over the commitments of its
+// deal, once the deal is processed, for the transcript. A deal without a valid
+// signature is still processed but its dealer can't be in the QUAL set of a
+// transcript that verifies.
+func (h *Handler) recordCommitsSig(dealer uint32, sig []byte) {
+	dealers := h.cdkg.NewNodes
+	if h.conf.OldNodes != nil {
+		dealers = h.cdkg.OldNodes
+	}
+	v, ok := h.state.Verifiers()[dealer]
+	if !ok || v.SessionID() == nil || int(dealer) >= len(dealers) {
+		return
+	}
+	if err := key.VerifyDealCommitments(dealers[dealer], v.SessionID(), sig); err != nil {
+		h.l.Error("commitments_signature", err, "from", h.dealerAddr(dealer))
+		return
+	}
+	h.commitsSigs[dealer] = sig
+}
+
 func (h *Handler) processTmpResponses(deal *dkg.Deal) {
 	defer h.checkCertified()
 	resps, ok := h.tmpResponses[deal.Index]
@@ -332,14 +594,14 @@ func (h *Handler) processResponse(p *peer.Peer, presp *dkg_proto.Response) {
 		},
 	}
 	j, err := h.state.ProcessResponse(resp)
-	localLog.Debug("from", resp.Response.Index, "for_deal", resp.Index, "addr", p.Addr.String())
+	localLog.Debug("from", resp.Response.Index, "for_deal", resp.Index, "addr", peerAddr(p))
 	if err != nil {
 		if err == vss.ErrNoDealBeforeResponse {
 			h.tmpResponses[resp.Index] = append(h.tmpResponses[resp.Index], resp)
-			localLog.Debug("response_unknown_deal", resp.Index, "addr", p.Addr.String())
+			localLog.Debug("response_unknown_deal", resp.Index, "addr", peerAddr(p))
 			return
 		}
-		localLog.Error("for_deal", resp.Index, "addr", p.Addr, "error", err)
+		localLog.Error("for_deal", resp.Index, "addr", peerAddr(p), "error", err)
 		return
 	}
 	if j != nil && h.oldNode {
@@ -435,34 +697,62 @@ func (h *Handler) sendDeals() error {
 		return nil
 	}
 	h.sentDeals = true
-	deals, err := h.state.Deals()
+	packets, err := h.dealPackets(nil)
+	h.Unlock()
 	if err != nil {
-		h.Unlock()
 		return err
 	}
-	h.Unlock()
-	h.l.Debug("send_deal", "start")
-	statusCh := make(chan bool, len(deals))
-	ids := h.conf.NewNodes.Identities()
+	return h.sendDealPackets(packets)
+}
+
+// dealPackets creates the deals of this node and returns the packets to send to
+// each node, indexed by their index in the new group. Deals present in sent are
+// used instead of the freshly created ones, so that nodes see the same deals
+// after a restart. It must be called with the lock held.
+func (h *Handler) dealPackets(sent map[int]*dkg_proto.Packet) (map[int]*dkg_proto.Packet, error) {
+	deals, err := h.dealer.Deals()
+	if err != nil {
+		return nil, err
+	}
+	packets := make(map[int]*dkg_proto.Packet, len(deals))
 	for i, deal := range deals {
-		if i == h.nidx && h.newNode {
+		// a node rotating its key sends a deal to its new key like to any
+		// other node
+		if i == h.nidx && h.newNode && h.dealer == h.state {
 			h.l.Fatal("same index deal", i, "pubkey", h.conf.Key.Public.Key.String())
 			panic("this is a bug with drand that should not happen. Please submit report if possible")
 		}
-		go func(i int, deal *dkg.Deal) {
-			id := ids[i]
-			packet := &dkg_proto.Packet{
-				Deal: &dkg_proto.Deal{
-					Index:     deal.Index,
-					Signature: deal.Signature,
-					Deal: &vss_proto.EncryptedDeal{
-						Dhkey:     deal.Deal.DHKey,
-						Signature: deal.Deal.Signature,
-						Nonce:     deal.Deal.Nonce,
-						Cipher:    deal.Deal.Cipher,
-					},
+		if p, ok := sent[i]; ok {
+			packets[i] = p
+			continue
+		}
+		packets[i] = &dkg_proto.Packet{
+			Deal: &dkg_proto.Deal{
+				Index:            deal.Index,
+				Signature:        deal.Signature,
+				CommitsSignature: h.commitsSig,
+				Deal: &vss_proto.EncryptedDeal{
+					Dhkey:     deal.Deal.DHKey,
+					Signature: deal.Deal.Signature,
+					Nonce:     deal.Deal.Nonce,
+					Cipher:    deal.Deal.Cipher,
 				},
-			}
+			},
+		}
+		if h.conf.Journal != nil {
+			h.conf.Journal.SentDeal(i, packets[i])
+		}
+	}
+	return packets, nil
+}
+
+func (h *Handler) sendDealPackets(packets map[int]*dkg_proto.Packet) error {
+	h.l.Debug("send_deal", "start")
+	statusCh := make(chan bool, len(packets))
+	ids := h.conf.NewNodes.Identities()
+	for i, packet := range packets {
+		go func(i int, packet *dkg_proto.Packet) {
+			id := ids[i]
 			h.l.Debug("send_deal_to", i, "addr", id.Address())
 			if err := h.net.Send(id, packet); err != nil {
 				h.l.Error("send_deal_fail", err, "to", id.Address())
@@ -470,7 +760,7 @@ func (h *Handler) sendDeals() error {
 			} else {
 				statusCh <- true
 			}
-		}(i, deal)
+		}(i, packet)
 	}
 
 	var good = 1
@@ -522,6 +812,10 @@ func (h *Handler) broadcast(p *dkg_proto.Packet, toOldNodes bool, msgType string
 			if present {
 				continue
 			}
+			if h.newNode && h.dealer != h.state && id.Address() == h.addr() {
+				// our old key, it does not process the responses
+				continue
+			}
 			if err := h.net.Send(id, p); err != nil {
 				h.l.Debug("broadcast_old", err, "to", id.Address(), "type", msgType)
 				continue
@@ -581,3 +875,37 @@ func (i intArray) String() string {
 	s.WriteString("]")
 	return s.String()
 }
+
+// seededSuite is a Suite whose random stream can be fixed to a deterministic
+// stream. It is used to derive the dealer polynomial from a seed.
+type seededSuite struct {
+	dkg.Suite
+	stream cipher.Stream
+}
+
+func (s *seededSuite) RandomStream() cipher.Stream {
+	if s.stream != nil {
+		return s.stream
+	}
+	return s.Suite.RandomStream()
+}
+
+func protoToDeal(pdeal *dkg_proto.Deal) *dkg.Deal {
+	return &dkg.Deal{
+		Index:     pdeal.Index,
+		Signature: pdeal.Signature,
+		Deal: &vss.EncryptedDeal{
+			DHKey:     pdeal.Deal.Dhkey,
+			Signature: pdeal.Deal.Signature,
+			Nonce:     pdeal.Deal.Nonce,
+			Cipher:    pdeal.Deal.Cipher,
+		},
+	}
+}
+
+func peerAddr(p *peer.Peer) string {
+	if p == nil || p.Addr == nil {
+		return "<replay>"
+	}
+	return p.Addr.String()
+}
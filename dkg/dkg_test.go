@@ -17,6 +17,7 @@ import (
 	"github.com/drand/drand/protobuf/crypto/dkg"
 	"github.com/drand/drand/protobuf/drand"
 	"github.com/drand/drand/test"
+	"github.com/drand/kyber/util/random"
 	clock "github.com/jonboulle/clockwork"
 	"github.com/nikkolasg/slog"
 	"github.com/stretchr/testify/require"
@@ -390,6 +391,15 @@ func (d *DKGTest) CheckIncludedQUAL(ids []string) bool {
 	return true
 }
 
+// CheckTranscripts verifies the transcript of each given new node against its
+// distributed key
+func (d *DKGTest) CheckTranscripts(t *testing.T, ids []string) {
+	for _, id := range ids {
+		tr := d.newNodes[id].handler.Transcript()
+		require.NoError(t, tr.Verify(d.getShare(id).Public()), "node %s", id)
+	}
+}
+
 func (d *DKGTest) StartDKG(id string) {
 	d.tryBoth(id, func(n *node) {
 		fmt.Printf(" -- Test - StartDKG for %s\n", n.pub.Address())
@@ -451,6 +461,28 @@ func TestDKGFresh(t *testing.T) {
 	dt.StartDKG(dt.keys[0])
 	keys, _ := dt.WaitFinish(n)
 	require.True(t, dt.CheckIncludedQUAL(keys))
+	dt.CheckTranscripts(t, keys)
+
+	// the transcript survives a round trip to its file
+	tr := dt.newNodes[keys[0]].handler.Transcript()
+	f, err := ioutil.TempFile("", "transcript")
+	require.NoError(t, err)
+	f.Close()
+	defer os.Remove(f.Name())
+	require.NoError(t, key.Save(f.Name(), tr, false))
+	loaded := new(key.Transcript)
+	require.NoError(t, key.Load(f.Name(), loaded))
+	require.NoError(t, loaded.Verify(dt.getShare(keys[0]).Public()))
+
+	// each qualified dealer signed its commitments
+	sig := tr.Deals[0].Signature
+	tr.Deals[0].Signature = tr.Deals[1].Signature
+	require.Error(t, tr.Verify(dt.getShare(keys[0]).Public()))
+	tr.Deals[0].Signature = sig
+
+	// a transcript with tampered commitments does not verify
+	tr.Deals[0].Commits[0] = key.KeyGroup.Point().Pick(random.New())
+	require.Error(t, tr.Verify(dt.getShare(keys[0]).Public()))
 }
 
 func TestDKGWithTimeout(t *testing.T) {
@@ -603,6 +635,7 @@ func TestDKGResharingPartial(t *testing.T) {
 	finished, to := dt.WaitFinish(newN)
 	require.False(t, to)
 	require.True(t, dt.CheckIncludedQUAL(finished))
+	dt.CheckTranscripts(t, finished)
 }
 
 func TestDKGEntropy(t *testing.T) {
@@ -671,3 +704,96 @@ func tmpEntropySource() *entropy.ScriptReader {
 	r := entropy.NewScriptReader(f.Name())
 	return r
 }
+
+type testJournal struct {
+	sync.Mutex
+	deals map[int]*dkg.Packet
+}
+
+func (j *testJournal) Started(time.Time) {}
+
+func (j *testJournal) SentDeal(to int, p *dkg.Packet) {
+	j.Lock()
+	defer j.Unlock()
+	j.deals[to] = p
+}
+
+func (j *testJournal) Received(*dkg.Packet) {}
+
+func TestDKGSeedDeals(t *testing.T) {
+	n := 4
+	privs := test.GenerateIDs(n)
+	group := key.NewGroup(test.ListFromPrivates(privs), key.DefaultThreshold(n), 0)
+	newHandler := func(priv *key.Pair, seed []byte, j Journal) *Handler {
+		conf := &Config{
+			Suite:    key.KeyGroup.(Suite),
+			NewNodes: group,
+			Key:      priv,
+			Clock:    clock.NewFakeClock(),
+			Seed:     seed,
+			Journal:  j,
+		}
+		h, err := NewHandler(nil, conf, log.DefaultLogger)
+		require.NoError(t, err)
+		return h
+	}
+	receiver, _ := group.Index(privs[1].Public)
+	sessionID := func(p *dkg.Packet) []byte {
+		h := newHandler(privs[1], nil, nil)
+		resp, err := h.state.ProcessDeal(protoToDeal(p.Deal))
+		require.NoError(t, err)
+		return resp.Response.SessionID
+	}
+
+	seed := []byte("same seed same deals")
+	journal := &testJournal{deals: make(map[int]*dkg.Packet)}
+	dealer := newHandler(privs[0], seed, journal)
+	_, err := dealer.dealPackets(nil)
+	require.NoError(t, err)
+	require.Len(t, journal.deals, n-1)
+
+	// a dealer restarting with the same seed deals the same polynomial
+	restarted := newHandler(privs[0], seed, nil)
+	deals, err := restarted.dealPackets(nil)
+	require.NoError(t, err)
+	require.Equal(t, sessionID(journal.deals[receiver]), sessionID(deals[receiver]))
+
+	// deals already sent are re-used as is
+	deals, err = newHandler(privs[0], seed, nil).dealPackets(journal.deals)
+	require.NoError(t, err)
+	require.Equal(t, journal.deals[receiver], deals[receiver])
+
+	other := newHandler(privs[0], []byte("another seed"), nil)
+	deals, err = other.dealPackets(nil)
+	require.NoError(t, err)
+	require.NotEqual(t, sessionID(journal.deals[receiver]), sessionID(deals[receiver]))
+}
+
+func TestDKGStop(t *testing.T) {
+	n := 4
+	privs := test.GenerateIDs(n)
+	group := key.NewGroup(test.ListFromPrivates(privs), key.DefaultThreshold(n), 0)
+	newHandler := func(priv *key.Pair) *Handler {
+		h, err := NewHandler(nil, &Config{
+			Suite:    key.KeyGroup.(Suite),
+			NewNodes: group,
+			Key:      priv,
+			Clock:    clock.NewFakeClock(),
+		}, log.DefaultLogger)
+		require.NoError(t, err)
+		return h
+	}
+	dealer := newHandler(privs[0])
+	deals, err := dealer.dealPackets(nil)
+	require.NoError(t, err)
+	receiver, _ := group.Index(privs[1].Public)
+
+	h := newHandler(privs[1])
+	h.Stop()
+	require.Equal(t, ErrStopped, <-h.WaitError())
+	// packets received after the stop are ignored
+	h.Process(context.Background(), deals[receiver])
+	require.Equal(t, 0, h.dealProcessed)
+	// stopping twice does not block
+	h.Stop()
+}
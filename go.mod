@@ -11,6 +11,7 @@ require (
 	github.com/go-kit/kit v0.9.0
 	github.com/go-logfmt/logfmt v0.5.0 // indirect
 	github.com/golang/protobuf v1.3.5
+	github.com/golang/snappy v0.0.1 // indirect
 	github.com/grpc-ecosystem/go-grpc-prometheus v1.2.0
 	github.com/grpc-ecosystem/grpc-gateway v1.14.3
 	github.com/jonboulle/clockwork v0.1.1-0.20190114141812-62fb9bc030d1
@@ -20,6 +21,7 @@ require (
 	github.com/prometheus/client_golang v1.5.1
 	github.com/soheilhy/cmux v0.1.4
 	github.com/stretchr/testify v1.4.0
+	github.com/syndtr/goleveldb v1.0.0
 	github.com/urfave/cli/v2 v2.2.0
 	go.etcd.io/bbolt v1.3.3 // indirect
 	golang.org/x/crypto v0.0.0-20200427165652-729f1e841bcc
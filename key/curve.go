@@ -2,6 +2,7 @@ package key
 
 import (
 	bls "github.com/drand/bls12-381"
+	sign "github.com/drand/kyber/sign/bls"
 	"github.com/drand/kyber/sign/tbls"
 )
 
@@ -28,3 +29,7 @@ var SigGroup = Pairing.G2()
 // Scheme is the signature scheme used, defining over which curve the signature
 // and keys respectively are.
 var Scheme = tbls.NewThresholdSchemeOnG2(Pairing)
+
+// AuthScheme is the signature scheme nodes use to sign with their longterm
+// key, for example to prove the possession of their private key.
+var AuthScheme = sign.NewSchemeOnG2(Pairing)
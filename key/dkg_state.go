@@ -0,0 +1,130 @@
+package key
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// DKGState holds all the information a node needs to resume a DKG protocol it
+// was running before a crash: the configuration of the protocol, the seed from
+// which our dealer polynomial is derived, the deals we sent out and all the
+// packets we received so far. Packets are kept as opaque marshalled protobuf
+// packets.
+// The state contains secret material and MUST be kept private.
+type DKGState struct {
+	// Leader is true if this node initiated the protocol
+	Leader bool
+	// Seed is the secret seed used to derive our dealer polynomial
+	Seed []byte
+	// Timeout is the timeout of the protocol
+	Timeout time.Duration
+	// StartedAt is the unix time at which the timeout of the protocol started
+	// ticking. It is 0 if the protocol has not started yet.
+	StartedAt int64
+	// NewGroup is the group that runs the DKG
+	NewGroup *Group
+	// OldGroup is only set in case of a resharing
+	OldGroup *Group
+	// Deals are the deals this node sent, indexed by the index of the recipient
+	// in the new group.
+	Deals map[int][]byte
+	// Received are all the packets received from the other nodes, in order.
+	Received [][]byte
+}
+
+// IsResharing returns true if the state belongs to a resharing protocol
+func (s *DKGState) IsResharing() bool {
+	return s.OldGroup != nil
+}
+
+// DKGStateTOML is the TOML-able version of a DKGState
+type DKGStateTOML struct {
+	Leader    bool
+	Seed      string
+	Timeout   string
+	StartedAt int64
+	NewGroup  *GroupTOML
+	OldGroup  *GroupTOML `toml:",omitempty"`
+	Deals     map[string]string
+	Received  []string
+}
+
+// TOML returns a TOML-compatible version of the state
+func (s *DKGState) TOML() interface{} {
+	st := &DKGStateTOML{
+		Leader:    s.Leader,
+		Seed:      hex.EncodeToString(s.Seed),
+		Timeout:   s.Timeout.String(),
+		StartedAt: s.StartedAt,
+		NewGroup:  s.NewGroup.TOML().(*GroupTOML),
+		Deals:     make(map[string]string, len(s.Deals)),
+		Received:  make([]string, 0, len(s.Received)),
+	}
+	if s.OldGroup != nil {
+		st.OldGroup = s.OldGroup.TOML().(*GroupTOML)
+	}
+	for i, d := range s.Deals {
+		st.Deals[strconv.Itoa(i)] = hex.EncodeToString(d)
+	}
+	for _, p := range s.Received {
+		st.Received = append(st.Received, hex.EncodeToString(p))
+	}
+	return st
+}
+
+// FromTOML decodes the state from its TOML representation
+func (s *DKGState) FromTOML(i interface{}) error {
+	st, ok := i.(*DKGStateTOML)
+	if !ok {
+		return errors.New("dkg state: invalid TOML value")
+	}
+	var err error
+	s.Leader = st.Leader
+	s.StartedAt = st.StartedAt
+	if s.Seed, err = hex.DecodeString(st.Seed); err != nil {
+		return fmt.Errorf("dkg state: invalid seed: %v", err)
+	}
+	if s.Timeout, err = time.ParseDuration(st.Timeout); err != nil {
+		return fmt.Errorf("dkg state: invalid timeout: %v", err)
+	}
+	if st.NewGroup == nil {
+		return errors.New("dkg state: no new group")
+	}
+	s.NewGroup = new(Group)
+	if err := s.NewGroup.FromTOML(st.NewGroup); err != nil {
+		return fmt.Errorf("dkg state: new group: %v", err)
+	}
+	if st.OldGroup != nil {
+		s.OldGroup = new(Group)
+		if err := s.OldGroup.FromTOML(st.OldGroup); err != nil {
+			return fmt.Errorf("dkg state: old group: %v", err)
+		}
+	}
+	s.Deals = make(map[int][]byte, len(st.Deals))
+	for idx, d := range st.Deals {
+		i, err := strconv.Atoi(idx)
+		if err != nil {
+			return fmt.Errorf("dkg state: invalid deal index %s: %v", idx, err)
+		}
+		if s.Deals[i], err = hex.DecodeString(d); err != nil {
+			return fmt.Errorf("dkg state: invalid deal %d: %v", i, err)
+		}
+	}
+	s.Received = make([][]byte, 0, len(st.Received))
+	for i, p := range st.Received {
+		buff, err := hex.DecodeString(p)
+		if err != nil {
+			return fmt.Errorf("dkg state: invalid packet %d: %v", i, err)
+		}
+		s.Received = append(s.Received, buff)
+	}
+	return nil
+}
+
+// TOMLValue returns an empty TOML-compatible value of the state
+func (s *DKGState) TOMLValue() interface{} {
+	return &DKGStateTOML{}
+}
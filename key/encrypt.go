@@ -0,0 +1,234 @@
+package key
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// ErrEncrypted is returned when loading an encrypted file from a store that
+// has no passphrase
+var ErrEncrypted = errors.New("store file is encrypted, a passphrase is needed")
+
+// ErrPassphrase is returned when an encrypted file can't be decrypted with the
+// passphrase of the store
+var ErrPassphrase = errors.New("store file can't be decrypted, invalid passphrase")
+
+// encryptionScheme identifies the way the secret files are encrypted: the key
+// is derived from the passphrase with argon2id and the file is sealed with
+// XChaCha20-Poly1305.
+const encryptionScheme = "argon2id-xchacha20poly1305"
+
+// Parameters of argon2id, as recommended by the RFC draft for the second
+// option. They are saved along with each file so they can be changed later.
+const (
+	argonTime    = 1
+	argonMemory  = 64 * 1024
+	argonThreads = 4
+	saltLength   = 16
+)
+
+// Bounds of the argon2id parameters read from a file. They are read before
+// the file can be authenticated, so they must not make the key derivation
+// panic or exhaust the memory of the node.
+const (
+	maxArgonTime   = 64
+	maxArgonMemory = 4 * 1024 * 1024
+)
+
+// checkArgonParams returns an error if the given argon2id parameters are out
+// of bounds
+func checkArgonParams(time, memory uint32, threads uint8) error {
+	if time < 1 || time > maxArgonTime {
+		return fmt.Errorf("invalid argon2 time %d, must be between 1 and %d", time, maxArgonTime)
+	}
+	if threads < 1 {
+		return errors.New("invalid argon2 threads, must be at least 1")
+	}
+	if memory < 8*uint32(threads) || memory > maxArgonMemory {
+		return fmt.Errorf("invalid argon2 memory %d KiB, must be between %d and %d", memory, 8*uint32(threads), maxArgonMemory)
+	}
+	return nil
+}
+
+// encryptedTOML is the content of an encrypted file
+type encryptedTOML struct {
+	Encryption string
+	Salt       string
+	Time       uint32
+	Memory     uint32
+	Threads    uint8
+	Nonce      string
+	Ciphertext string
+}
+
+// sealer encrypts and decrypts the secret files of a store with a key derived
+// from a passphrase. Deriving a key is purposely slow, so the sealer derives
+// it only once per salt.
+type sealer struct {
+	sync.Mutex
+	passphrase []byte
+	salt       []byte
+	keys       map[string][]byte
+}
+
+func newSealer(passphrase []byte) (*sealer, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("empty passphrase")
+	}
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return &sealer{
+		passphrase: passphrase,
+		salt:       salt,
+		keys:       make(map[string][]byte),
+	}, nil
+}
+
+func (s *sealer) key(salt []byte, time, memory uint32, threads uint8) []byte {
+	s.Lock()
+	defer s.Unlock()
+	id := fmt.Sprintf("%x-%d-%d-%d", salt, time, memory, threads)
+	if k, ok := s.keys[id]; ok {
+		return k
+	}
+	k := argon2.IDKey(s.passphrase, salt, time, memory, threads, chacha20poly1305.KeySize)
+	s.keys[id] = k
+	return k
+}
+
+// seal returns the encrypted TOML encoding of t
+func (s *sealer) seal(t Tomler) (*encryptedTOML, error) {
+	var plain bytes.Buffer
+	if err := toml.NewEncoder(&plain).Encode(t.TOML()); err != nil {
+		return nil, err
+	}
+	aead, err := chacha20poly1305.NewX(s.key(s.salt, argonTime, argonMemory, argonThreads))
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	cipher := aead.Seal(nil, nonce, plain.Bytes(), []byte(encryptionScheme))
+	return &encryptedTOML{
+		Encryption: encryptionScheme,
+		Salt:       hex.EncodeToString(s.salt),
+		Time:       argonTime,
+		Memory:     argonMemory,
+		Threads:    argonThreads,
+		Nonce:      hex.EncodeToString(nonce),
+		Ciphertext: hex.EncodeToString(cipher),
+	}, nil
+}
+
+// open decrypts the file and decodes its content into t
+func (s *sealer) open(e *encryptedTOML, t Tomler) error {
+	if e.Encryption != encryptionScheme {
+		return fmt.Errorf("unknown encryption scheme %q", e.Encryption)
+	}
+	salt, err := hex.DecodeString(e.Salt)
+	if err != nil {
+		return fmt.Errorf("invalid salt: %v", err)
+	}
+	nonce, err := hex.DecodeString(e.Nonce)
+	if err != nil {
+		return fmt.Errorf("invalid nonce: %v", err)
+	}
+	cipher, err := hex.DecodeString(e.Ciphertext)
+	if err != nil {
+		return fmt.Errorf("invalid ciphertext: %v", err)
+	}
+	if err := checkArgonParams(e.Time, e.Memory, e.Threads); err != nil {
+		return err
+	}
+	aead, err := chacha20poly1305.NewX(s.key(salt, e.Time, e.Memory, e.Threads))
+	if err != nil {
+		return err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return errors.New("invalid nonce length")
+	}
+	plain, err := aead.Open(nil, nonce, cipher, []byte(encryptionScheme))
+	if err != nil {
+		return ErrPassphrase
+	}
+	tomlValue := t.TOMLValue()
+	if _, err := toml.Decode(string(plain), tomlValue); err != nil {
+		return err
+	}
+	return t.FromTOML(tomlValue)
+}
+
+// saveSecret saves t in a file with tight permissions, encrypted if the
+// sealer is not nil.
+func saveSecret(path string, t Tomler, s *sealer) error {
+	if s == nil {
+		return Save(path, t, true)
+	}
+	e, err := s.seal(t)
+	if err != nil {
+		return fmt.Errorf("config: can't encrypt %s: %v", path, err)
+	}
+	return Save(path, e, true)
+}
+
+// loadSecret loads t from the given file, decrypting it if needed. It returns
+// ErrEncrypted if the file is encrypted and the sealer is nil.
+func loadSecret(path string, t Tomler, s *sealer) error {
+	buff, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	e := new(encryptedTOML)
+	if _, err := toml.Decode(string(buff), e); err != nil || e.Encryption == "" {
+		// plain file
+		return Load(path, t)
+	}
+	if s == nil {
+		return ErrEncrypted
+	}
+	return s.open(e, t)
+}
+
+// IsEncrypted returns true if the file at the given path is encrypted
+func IsEncrypted(path string) (bool, error) {
+	buff, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	e := new(encryptedTOML)
+	if _, err := toml.Decode(string(buff), e); err != nil {
+		return false, nil
+	}
+	return e.Encryption != "", nil
+}
+
+// encryptedTOML is its own TOML representation so it can be saved with Save
+func (e *encryptedTOML) TOML() interface{} {
+	return e
+}
+
+func (e *encryptedTOML) FromTOML(i interface{}) error {
+	other, ok := i.(*encryptedTOML)
+	if !ok {
+		return errors.New("invalid encrypted file")
+	}
+	*e = *other
+	return nil
+}
+
+func (e *encryptedTOML) TOMLValue() interface{} {
+	return &encryptedTOML{}
+}
@@ -0,0 +1,222 @@
+package key
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// An encrypted stream, e.g. a backup of a node, starts with a header holding
+// the parameters of argon2id and a random nonce prefix. Then come the chunks
+// of the stream, each sealed with XChaCha20-Poly1305 under the nonce prefix
+// followed by the index of the chunk, and preceded by its length. The last
+// chunk is marked in its additional data, so a truncated stream is detected.
+
+var streamMagic = []byte("drand-encrypted-stream-v1\n")
+
+const (
+	streamChunkSize   = 64 * 1024
+	streamNoncePrefix = chacha20poly1305.NonceSizeX - 8
+)
+
+// IsEncryptedStream returns true if the stream read by r starts like a stream
+// written by NewEncryptedWriter. It doesn't consume any byte of r.
+func IsEncryptedStream(r *bufio.Reader) bool {
+	header, err := r.Peek(len(streamMagic))
+	return err == nil && bytes.Equal(header, streamMagic)
+}
+
+type encryptedWriter struct {
+	w      io.Writer
+	aead   cipher.AEAD
+	prefix []byte
+	index  uint64
+	buff   []byte
+	closed bool
+}
+
+// NewEncryptedWriter returns a writer encrypting what is written to it with a
+// key derived from the passphrase, and writing the result to w. Close must be
+// called to write the end of the stream; it doesn't close w.
+func NewEncryptedWriter(w io.Writer, passphrase []byte) (io.WriteCloser, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("empty passphrase")
+	}
+	salt := make([]byte, saltLength)
+	prefix := make([]byte, streamNoncePrefix)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, err
+	}
+	aead, err := chacha20poly1305.NewX(argon2.IDKey(passphrase, salt, argonTime, argonMemory, argonThreads, chacha20poly1305.KeySize))
+	if err != nil {
+		return nil, err
+	}
+	var header bytes.Buffer
+	header.Write(streamMagic)
+	header.Write(salt)
+	binary.Write(&header, binary.BigEndian, uint32(argonTime))
+	binary.Write(&header, binary.BigEndian, uint32(argonMemory))
+	header.WriteByte(argonThreads)
+	header.Write(prefix)
+	if _, err := w.Write(header.Bytes()); err != nil {
+		return nil, err
+	}
+	return &encryptedWriter{
+		w:      w,
+		aead:   aead,
+		prefix: prefix,
+		buff:   make([]byte, 0, streamChunkSize),
+	}, nil
+}
+
+func (e *encryptedWriter) Write(p []byte) (int, error) {
+	if e.closed {
+		return 0, errors.New("write to a closed encrypted stream")
+	}
+	written := 0
+	for len(p) > 0 {
+		n := copy(e.buff[len(e.buff):cap(e.buff)], p)
+		e.buff = e.buff[:len(e.buff)+n]
+		p = p[n:]
+		written += n
+		// the last chunk is only known at Close, so a full chunk is kept
+		// until more data comes
+		if len(e.buff) == cap(e.buff) && len(p) > 0 {
+			if err := e.flush(false); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// Close writes the last chunk of the stream
+func (e *encryptedWriter) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	return e.flush(true)
+}
+
+func (e *encryptedWriter) flush(last bool) error {
+	sealed := e.aead.Seal(nil, chunkNonce(e.prefix, e.index), e.buff, chunkData(last))
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(sealed)))
+	if _, err := e.w.Write(length[:]); err != nil {
+		return err
+	}
+	if _, err := e.w.Write(sealed); err != nil {
+		return err
+	}
+	e.index++
+	e.buff = e.buff[:0]
+	return nil
+}
+
+type decryptedReader struct {
+	r      io.Reader
+	aead   cipher.AEAD
+	prefix []byte
+	index  uint64
+	plain  []byte
+	done   bool
+}
+
+// NewDecryptedReader returns a reader decrypting the stream read from r, that
+// was written by NewEncryptedWriter with the same passphrase. Reading returns
+// ErrPassphrase if the stream can't be decrypted, and io.ErrUnexpectedEOF if
+// it is truncated.
+func NewDecryptedReader(r io.Reader, passphrase []byte) (io.Reader, error) {
+	header := make([]byte, len(streamMagic)+saltLength+4+4+1+streamNoncePrefix)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(header[:len(streamMagic)], streamMagic) {
+		return nil, errors.New("not an encrypted stream")
+	}
+	header = header[len(streamMagic):]
+	salt := header[:saltLength]
+	time := binary.BigEndian.Uint32(header[saltLength:])
+	memory := binary.BigEndian.Uint32(header[saltLength+4:])
+	threads := header[saltLength+8]
+	prefix := header[saltLength+9:]
+	if err := checkArgonParams(time, memory, threads); err != nil {
+		return nil, err
+	}
+	aead, err := chacha20poly1305.NewX(argon2.IDKey(passphrase, salt, time, memory, threads, chacha20poly1305.KeySize))
+	if err != nil {
+		return nil, err
+	}
+	return &decryptedReader{r: r, aead: aead, prefix: prefix}, nil
+}
+
+func (d *decryptedReader) Read(p []byte) (int, error) {
+	for len(d.plain) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.plain)
+	d.plain = d.plain[n:]
+	return n, nil
+}
+
+func (d *decryptedReader) next() error {
+	var length [4]byte
+	if _, err := io.ReadFull(d.r, length[:]); err != nil {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	size := binary.BigEndian.Uint32(length[:])
+	if size > streamChunkSize+uint32(d.aead.Overhead()) {
+		return errors.New("invalid encrypted stream: chunk too large")
+	}
+	sealed := make([]byte, size)
+	if _, err := io.ReadFull(d.r, sealed); err != nil {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	nonce := chunkNonce(d.prefix, d.index)
+	plain, err := d.aead.Open(nil, nonce, sealed, chunkData(false))
+	if err != nil {
+		if plain, err = d.aead.Open(nil, nonce, sealed, chunkData(true)); err != nil {
+			return ErrPassphrase
+		}
+		d.done = true
+	}
+	d.index++
+	d.plain = plain
+	return nil
+}
+
+func chunkNonce(prefix []byte, index uint64) []byte {
+	nonce := make([]byte, chacha20poly1305.NonceSizeX)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint64(nonce[len(prefix):], index)
+	return nonce
+}
+
+func chunkData(last bool) []byte {
+	if last {
+		return []byte{1}
+	}
+	return []byte{0}
+}
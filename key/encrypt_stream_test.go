@@ -0,0 +1,82 @@
+package key
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptedStream(t *testing.T) {
+	passphrase := []byte("correct horse battery staple")
+	// more than two chunks
+	plain := make([]byte, 2*streamChunkSize+1000)
+	_, err := rand.Read(plain)
+	require.NoError(t, err)
+
+	var stream bytes.Buffer
+	w, err := NewEncryptedWriter(&stream, passphrase)
+	require.NoError(t, err)
+	_, err = w.Write(plain[:10])
+	require.NoError(t, err)
+	_, err = w.Write(plain[10:])
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	encrypted := stream.Bytes()
+	require.False(t, bytes.Contains(encrypted, plain[:64]))
+
+	r := bufio.NewReader(bytes.NewReader(encrypted))
+	require.True(t, IsEncryptedStream(r))
+	dr, err := NewDecryptedReader(r, passphrase)
+	require.NoError(t, err)
+	decrypted, err := ioutil.ReadAll(dr)
+	require.NoError(t, err)
+	require.Equal(t, plain, decrypted)
+
+	require.False(t, IsEncryptedStream(bufio.NewReader(bytes.NewReader(plain))))
+
+	// wrong passphrase
+	dr, err = NewDecryptedReader(bytes.NewReader(encrypted), []byte("wrong"))
+	require.NoError(t, err)
+	_, err = ioutil.ReadAll(dr)
+	require.Equal(t, ErrPassphrase, err)
+
+	// the parameters of argon2 come from the stream and are checked before
+	// deriving the key: no time, no threads, and 4 TiB of memory
+	offset := len(streamMagic) + saltLength
+	for _, field := range []struct {
+		pos   int
+		value []byte
+	}{
+		{offset, []byte{0, 0, 0, 0}},
+		{offset + 8, []byte{0}},
+		{offset + 4, []byte{0xff, 0xff, 0xff, 0xff}},
+	} {
+		tampered := append([]byte{}, encrypted...)
+		copy(tampered[field.pos:], field.value)
+		_, err = NewDecryptedReader(bytes.NewReader(tampered), passphrase)
+		require.Error(t, err)
+	}
+
+	// the stream is cut right after a chunk
+	cut := len(encrypted) - (1000 + 4 + 16)
+	dr, err = NewDecryptedReader(bytes.NewReader(encrypted[:cut]), passphrase)
+	require.NoError(t, err)
+	_, err = ioutil.ReadAll(dr)
+	require.Equal(t, io.ErrUnexpectedEOF, err)
+
+	// an empty stream still has its last chunk
+	stream.Reset()
+	w, err = NewEncryptedWriter(&stream, passphrase)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	dr, err = NewDecryptedReader(&stream, passphrase)
+	require.NoError(t, err)
+	decrypted, err = ioutil.ReadAll(dr)
+	require.NoError(t, err)
+	require.Empty(t, decrypted)
+}
@@ -39,6 +39,10 @@ type Group struct {
 	// The distributed public key of this group. It is nil if the group has not
 	// ran a DKG protocol yet.
 	PublicKey *DistPublic
+	// In case of a resharing, this is the threshold signature of the previous
+	// group over the message returned by TransitionMsg. It lets clients trust
+	// the new group from the previous one.
+	TransitionSignature []byte
 }
 
 // Identities return the underlying slice of identities
@@ -102,6 +106,104 @@ func (g *Group) hashBytes() ([]byte, error) {
 	return h.Sum(nil), nil
 }
 
+// chainHashVersion prefixes the data covered by the chain hash, so the hashes
+// of different versions never collide.
+const chainHashVersion = "drand-group-v2"
+
+// ChainHash returns the version 2 of the group hash. On top of what Hash
+// covers, it takes into account the period, the genesis seed, the transition
+// time and the distributed public key when set, so two groups producing
+// different chains never share the same hash.
+func (g *Group) ChainHash() (string, error) {
+	h := blake2b.New256()
+	h.Write([]byte(chainHashVersion))
+	buff, err := g.hashBytes()
+	if err != nil {
+		return "", err
+	}
+	h.Write(buff)
+	// the period travels in seconds
+	binary.Write(h, binary.LittleEndian, uint64(g.Period.Seconds()))
+	binary.Write(h, binary.LittleEndian, uint64(g.TransitionTime))
+	h.Write(g.GetGenesisSeed())
+	if g.PublicKey != nil {
+		for _, c := range g.PublicKey.Coefficients {
+			b, err := c.MarshalBinary()
+			if err != nil {
+				return "", err
+			}
+			h.Write(b)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// transitionMsgPrefix prefixes the message the previous group signs during a
+// resharing.
+const transitionMsgPrefix = "drand-transition:"
+
+// TransitionMsg returns the message the previous group signs during a
+// resharing to vouch for this group. It is built from the chain hash of the
+// group without its distributed key, since the old nodes sign it before the
+// resharing runs. The free coefficient of the key is the one of the previous
+// group anyway, which VerifyTransition checks.
+func (g *Group) TransitionMsg() ([]byte, error) {
+	cp := *g
+	cp.PublicKey = nil
+	hash, err := cp.ChainHash()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(transitionMsgPrefix + hash), nil
+}
+
+// VerifyTransition returns an error if this group has not been signed by the
+// previous group during a resharing, or if both groups do not share the same
+// collective key.
+func (g *Group) VerifyTransition(prev *Group) error {
+	if prev.PublicKey == nil || g.PublicKey == nil {
+		return errors.New("group: missing distributed key")
+	}
+	if !prev.PublicKey.Key().Equal(g.PublicKey.Key()) {
+		return errors.New("group: collective key differs from the previous group")
+	}
+	if len(g.TransitionSignature) == 0 {
+		return errors.New("group: no transition signature")
+	}
+	msg, err := g.TransitionMsg()
+	if err != nil {
+		return err
+	}
+	if err := Scheme.VerifyRecovered(prev.PublicKey.Key(), msg, g.TransitionSignature); err != nil {
+		return fmt.Errorf("group: invalid transition signature: %v", err)
+	}
+	return nil
+}
+
+// LatestTrustedGroup looks for the trusted group in the list of groups that
+// ran the chain, in order, and returns the last group of the list, provided
+// each group following the trusted one has been signed by its predecessor.
+func LatestTrustedGroup(trusted *Group, groups []*Group) (*Group, error) {
+	start := -1
+	for i, g := range groups {
+		if g.Equal(trusted) {
+			start = i
+			break
+		}
+	}
+	if start == -1 {
+		return nil, errors.New("group: trusted group not found")
+	}
+	latest := groups[start]
+	for _, g := range groups[start+1:] {
+		if err := g.VerifyTransition(latest); err != nil {
+			return nil, err
+		}
+		latest = g
+	}
+	return latest, nil
+}
+
 // Points returns itself under the form of a list of kyber.Point
 func (g *Group) Points() []kyber.Point {
 	pts := make([]kyber.Point, g.Len())
@@ -167,6 +269,8 @@ type GroupTOML struct {
 	TransitionTime int64  `toml:omitempty`
 	GenesisSeed    string `toml:omitempty`
 	PublicKey      *DistPublicTOML
+	// TransitionSignature is set by a resharing
+	TransitionSignature string `toml:",omitempty"`
 }
 
 // FromTOML decodes the group from the toml struct
@@ -210,6 +314,11 @@ func (g *Group) FromTOML(i interface{}) (err error) {
 			return fmt.Errorf("group: decoding genesis seed %v", err)
 		}
 	}
+	if gt.TransitionSignature != "" {
+		if g.TransitionSignature, err = hex.DecodeString(gt.TransitionSignature); err != nil {
+			return fmt.Errorf("group: decoding transition signature %v", err)
+		}
+	}
 	return nil
 }
 
@@ -232,6 +341,7 @@ func (g *Group) TOML() interface{} {
 		gtoml.TransitionTime = g.TransitionTime
 	}
 	gtoml.GenesisSeed = hex.EncodeToString(g.GetGenesisSeed())
+	gtoml.TransitionSignature = hex.EncodeToString(g.TransitionSignature)
 	return gtoml
 }
 
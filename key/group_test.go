@@ -7,6 +7,7 @@ import (
 	"time"
 
 	kyber "github.com/drand/kyber"
+	"github.com/drand/kyber/share"
 	"github.com/drand/kyber/util/random"
 	"github.com/stretchr/testify/require"
 )
@@ -59,3 +60,118 @@ func TestGroupSaveLoad(t *testing.T) {
 	require.Equal(t, genesis, loaded.GenesisTime)
 	require.Equal(t, transition, loaded.TransitionTime)
 }
+
+func TestGroupChainHash(t *testing.T) {
+	n := 3
+	ids := newIds(n)
+	newGroup := func() *Group {
+		g := NewGroup(ids, DefaultThreshold(n), 1000)
+		g.Period = 4 * time.Second
+		return g
+	}
+	group := newGroup()
+	hash, err := group.Hash()
+	require.NoError(t, err)
+	chainHash, err := group.ChainHash()
+	require.NoError(t, err)
+	require.NotEqual(t, hash, chainHash)
+
+	// the chain hash covers the fields the first version of the hash does not
+	changes := []func(g *Group){
+		func(g *Group) { g.Period = 5 * time.Second },
+		func(g *Group) { g.TransitionTime = 2000 },
+		func(g *Group) { g.GenesisSeed = []byte("another seed") },
+		func(g *Group) {
+			g.PublicKey = &DistPublic{[]kyber.Point{KeyGroup.Point().Pick(random.New())}}
+		},
+	}
+	for i, change := range changes {
+		g := newGroup()
+		change(g)
+		h, err := g.Hash()
+		require.NoError(t, err)
+		require.Equal(t, hash, h, "change %d", i)
+		ch, err := g.ChainHash()
+		require.NoError(t, err)
+		require.NotEqual(t, chainHash, ch, "change %d", i)
+	}
+
+	// it is stable across encodings
+	groupFile, err := ioutil.TempFile("", "group.toml")
+	require.NoError(t, err)
+	groupPath := groupFile.Name()
+	groupFile.Close()
+	defer os.RemoveAll(groupPath)
+	require.NoError(t, Save(groupPath, group, false))
+	loaded := &Group{}
+	require.NoError(t, Load(groupPath, loaded))
+	loadedHash, err := loaded.ChainHash()
+	require.NoError(t, err)
+	require.Equal(t, chainHash, loadedHash)
+}
+
+func TestGroupVerifyTransition(t *testing.T) {
+	n := 3
+	thr := DefaultThreshold(n)
+	priPoly := share.NewPriPoly(KeyGroup, thr, nil, random.New())
+	shares := priPoly.Shares(n)
+	pub := distPublic(priPoly)
+
+	oldGroup := NewGroup(newIds(n), thr, 1000)
+	oldGroup.Period = 4 * time.Second
+	oldGroup.PublicKey = pub
+	newGroup := NewGroup(newIds(n+1), DefaultThreshold(n+1), 1000)
+	newGroup.Period = 4 * time.Second
+	newGroup.TransitionTime = 2000
+	newGroup.GenesisSeed = oldGroup.GetGenesisSeed()
+
+	// the old nodes sign the new group before it gets its distributed key
+	msg, err := newGroup.TransitionMsg()
+	require.NoError(t, err)
+	var partials [][]byte
+	for _, s := range shares[:thr] {
+		partial, err := Scheme.Sign(s, msg)
+		require.NoError(t, err)
+		partials = append(partials, partial)
+	}
+	sig, err := Scheme.Recover(pub.PubPoly(), msg, partials, thr, n)
+	require.NoError(t, err)
+
+	newPriPoly := share.NewPriPoly(KeyGroup, newGroup.Threshold, priPoly.Secret(), random.New())
+	newGroup.PublicKey = distPublic(newPriPoly)
+	require.Error(t, newGroup.VerifyTransition(oldGroup))
+	newGroup.TransitionSignature = sig
+	require.NoError(t, newGroup.VerifyTransition(oldGroup))
+
+	// the signature is kept in the group file
+	groupFile, err := ioutil.TempFile("", "group.toml")
+	require.NoError(t, err)
+	groupPath := groupFile.Name()
+	groupFile.Close()
+	defer os.RemoveAll(groupPath)
+	require.NoError(t, Save(groupPath, newGroup, false))
+	loaded := &Group{}
+	require.NoError(t, Load(groupPath, loaded))
+	require.NoError(t, loaded.VerifyTransition(oldGroup))
+
+	latest, err := LatestTrustedGroup(oldGroup, []*Group{oldGroup, loaded})
+	require.NoError(t, err)
+	require.True(t, latest.Equal(newGroup))
+
+	// a group with another collective key or other parameters is rejected
+	other := *newGroup
+	other.PublicKey = distPublic(share.NewPriPoly(KeyGroup, newGroup.Threshold, nil, random.New()))
+	require.Error(t, other.VerifyTransition(oldGroup))
+	other = *newGroup
+	other.TransitionTime = 3000
+	require.Error(t, other.VerifyTransition(oldGroup))
+	_, err = LatestTrustedGroup(oldGroup, []*Group{oldGroup, &other})
+	require.Error(t, err)
+	_, err = LatestTrustedGroup(newGroup, []*Group{oldGroup})
+	require.Error(t, err)
+}
+
+func distPublic(p *share.PriPoly) *DistPublic {
+	_, commits := p.Commit(KeyGroup.Point().Base()).Info()
+	return &DistPublic{Coefficients: commits}
+}
@@ -3,6 +3,7 @@ package key
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -28,6 +29,12 @@ type Identity struct {
 	Key  kyber.Point
 	Addr string
 	TLS  bool
+	// Signature is a proof of possession of the private key, binding the
+	// identity to the parameters of the group setup it has been sent for. It
+	// is empty for identities that are not part of a setup.
+	Signature []byte
+	// Operator optionally describes who runs the node
+	Operator *Operator
 }
 
 // Address implements the net.Peer interface
@@ -57,6 +64,76 @@ func (i *Identity) Equal(i2 *Identity) bool {
 	return true
 }
 
+// SetupParams returns the canonical encoding of the parameters of a group
+// setup, which are signed along with the identities of the participants.
+// previousGroupHash is empty for a fresh DKG.
+func SetupParams(nodes, threshold int, dkgTimeout uint64, previousGroupHash string) []byte {
+	var b bytes.Buffer
+	binary.Write(&b, binary.LittleEndian, uint32(nodes))
+	binary.Write(&b, binary.LittleEndian, uint32(threshold))
+	binary.Write(&b, binary.LittleEndian, dkgTimeout)
+	b.WriteString(previousGroupHash)
+	return b.Bytes()
+}
+
+// possessionMsg returns the message signed by a node to prove it owns the
+// private key of its identity for the given setup.
+func (i *Identity) possessionMsg(setup []byte) ([]byte, error) {
+	key, err := i.Key.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	b.WriteString(possessionDomain)
+	binary.Write(&b, binary.LittleEndian, uint32(len(i.Addr)))
+	b.WriteString(i.Addr)
+	b.Write(key)
+	if i.TLS {
+		b.WriteByte(1)
+	} else {
+		b.WriteByte(0)
+	}
+	b.Write(setup)
+	return b.Bytes(), nil
+}
+
+const possessionDomain = "drand-proof-of-possession"
+
+// SignedIdentity returns a copy of the public identity of the pair, signed
+// over the given setup parameters.
+func (p *Pair) SignedIdentity(setup []byte) (*Identity, error) {
+	id := &Identity{
+		Key:      p.Public.Key,
+		Addr:     p.Public.Addr,
+		TLS:      p.Public.TLS,
+		Operator: p.Public.Operator,
+	}
+	msg, err := id.possessionMsg(setup)
+	if err != nil {
+		return nil, err
+	}
+	if id.Signature, err = AuthScheme.Sign(p.Key, msg); err != nil {
+		return nil, err
+	}
+	return id, nil
+}
+
+// ValidSignature returns an error if the identity does not carry a valid
+// proof of possession of its private key for the given setup parameters.
+func (i *Identity) ValidSignature(setup []byte) error {
+	if len(i.Signature) == 0 {
+		return errors.New("no proof of possession")
+	}
+	msg, err := i.possessionMsg(setup)
+	if err != nil {
+		return err
+	}
+	if err := AuthScheme.Verify(i.Key, msg, i.Signature); err != nil {
+		return fmt.Errorf("invalid proof of possession: %s", err)
+	}
+	return nil
+}
+
 // NewKeyPair returns a freshly created private / public key pair. The group is
 // decided by the group variable by default. Currently, drand only supports
 // bn256.
@@ -88,9 +165,11 @@ type PairTOML struct {
 
 // PublicTOML is the TOML-able version of a public key
 type PublicTOML struct {
-	Address string
-	Key     string
-	TLS     bool
+	Address   string
+	Key       string
+	TLS       bool
+	Signature string        `toml:",omitempty"`
+	Operator  *OperatorTOML `toml:",omitempty"`
 }
 
 // TOML returns a struct that can be marshalled using a TOML-encoding library
@@ -136,17 +215,35 @@ func (i *Identity) FromTOML(t interface{}) error {
 	i.Addr = ptoml.Address
 	i.Key = KeyGroup.Point()
 	i.TLS = ptoml.TLS
-	return i.Key.UnmarshalBinary(buff)
+	if ptoml.Signature != "" {
+		if i.Signature, err = hex.DecodeString(ptoml.Signature); err != nil {
+			return err
+		}
+	}
+	if err := i.Key.UnmarshalBinary(buff); err != nil {
+		return err
+	}
+	if ptoml.Operator != nil {
+		i.Operator = new(Operator)
+		if err := i.Operator.FromTOML(ptoml.Operator); err != nil {
+			return err
+		}
+	}
+	return i.ValidOperator()
 }
 
 // TOML returns a empty TOML-compatible version of the public key
 func (i *Identity) TOML() interface{} {
-	hex := PointToString(i.Key)
-	return &PublicTOML{
-		Address: i.Addr,
-		Key:     hex,
-		TLS:     i.TLS,
+	ptoml := &PublicTOML{
+		Address:   i.Addr,
+		Key:       PointToString(i.Key),
+		TLS:       i.TLS,
+		Signature: hex.EncodeToString(i.Signature),
+	}
+	if i.Operator != nil {
+		ptoml.Operator = i.Operator.TOML().(*OperatorTOML)
 	}
+	return ptoml
 }
 
 // TOMLValue returns a TOML-compatible interface value
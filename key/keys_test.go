@@ -36,6 +36,76 @@ func TestKeyPublic(t *testing.T) {
 	require.Equal(t, kp.Public.Key.String(), p2.Key.String())
 }
 
+func TestKeyOperator(t *testing.T) {
+	kp := NewTLSKeyPair("127.0.0.1:80")
+	op := &Operator{Name: "League", Contact: "ops@example.com", Region: "eu-west", Website: "https://example.com"}
+	require.NoError(t, kp.SignOperator(op))
+	require.NoError(t, kp.Public.ValidOperator())
+	// the metadata survives a TOML round trip and travels with the proof of
+	// possession
+	id, err := kp.SignedIdentity(SetupParams(5, 3, 60, ""))
+	require.NoError(t, err)
+	var writer bytes.Buffer
+	require.NoError(t, toml.NewEncoder(&writer).Encode(id.TOML()))
+	id2 := new(Identity)
+	id2toml := new(PublicTOML)
+	_, err = toml.DecodeReader(&writer, id2toml)
+	require.NoError(t, err)
+	require.NoError(t, id2.FromTOML(id2toml))
+	require.Equal(t, op.Name, id2.Operator.Name)
+	require.Equal(t, op.Website, id2.Operator.Website)
+	require.NoError(t, id2.ValidOperator())
+
+	// forged metadata
+	forged := *kp.Public.Operator
+	forged.Contact = "attacker@example.com"
+	id2.Operator = &forged
+	require.Error(t, id2.ValidOperator())
+	forgedTOML := id2.TOML().(*PublicTOML)
+	require.Error(t, new(Identity).FromTOML(forgedTOML))
+	// metadata of another node
+	other := NewTLSKeyPair("127.0.0.1:81")
+	other.Public.Operator = kp.Public.Operator
+	require.Error(t, other.Public.ValidOperator())
+	// identities without metadata are valid
+	other.Public.Operator = nil
+	require.NoError(t, other.Public.ValidOperator())
+}
+
+func TestKeyProofOfPossession(t *testing.T) {
+	kp := NewTLSKeyPair("127.0.0.1:80")
+	setup := SetupParams(5, 3, 60, "")
+	id, err := kp.SignedIdentity(setup)
+	require.NoError(t, err)
+	require.True(t, id.Equal(kp.Public))
+	require.Empty(t, kp.Public.Signature)
+	require.NoError(t, id.ValidSignature(setup))
+
+	// the signature survives a TOML round trip
+	var writer bytes.Buffer
+	require.NoError(t, toml.NewEncoder(&writer).Encode(id.TOML()))
+	id2 := new(Identity)
+	id2toml := new(PublicTOML)
+	_, err = toml.DecodeReader(&writer, id2toml)
+	require.NoError(t, err)
+	require.NoError(t, id2.FromTOML(id2toml))
+	require.NoError(t, id2.ValidSignature(setup))
+
+	// different setup parameters
+	require.Error(t, id.ValidSignature(SetupParams(5, 4, 60, "")))
+	require.Error(t, id.ValidSignature(SetupParams(5, 3, 60, "deadbeef")))
+	// squatting someone else's address
+	squat := *id
+	squat.Addr = "127.0.0.1:81"
+	require.Error(t, squat.ValidSignature(setup))
+	// someone else's key
+	rogue := *id
+	rogue.Key = NewKeyPair("127.0.0.1:80").Public.Key
+	require.Error(t, rogue.ValidSignature(setup))
+	// no signature at all
+	require.Error(t, kp.Public.ValidSignature(setup))
+}
+
 func TestKeyDistributedPublic(t *testing.T) {
 	n := 4
 	publics := make([]kyber.Point, n)
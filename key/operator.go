@@ -0,0 +1,132 @@
+package key
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// Operator describes who runs a node. It is signed with the private key of the
+// node, so a group file or a node serving the group can't forge it.
+type Operator struct {
+	Name    string
+	Contact string
+	Region  string
+	Website string
+	// Signature is the proof of possession of the private key of the node
+	// over the metadata, its address and its public key.
+	Signature []byte
+}
+
+// OperatorTOML is the TOML-able version of the operator metadata
+type OperatorTOML struct {
+	Name      string `toml:",omitempty"`
+	Contact   string `toml:",omitempty"`
+	Region    string `toml:",omitempty"`
+	Website   string `toml:",omitempty"`
+	Signature string
+}
+
+const operatorDomain = "drand-operator"
+
+// operatorMsg returns the message signed by the node over the given metadata
+func (i *Identity) operatorMsg(o *Operator) ([]byte, error) {
+	key, err := i.Key.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	b.WriteString(operatorDomain)
+	b.Write(key)
+	for _, field := range []string{i.Addr, o.Name, o.Contact, o.Region, o.Website} {
+		binary.Write(&b, binary.LittleEndian, uint32(len(field)))
+		b.WriteString(field)
+	}
+	return b.Bytes(), nil
+}
+
+// SignOperator sets the given operator metadata in the public identity of the
+// pair, signed with its private key.
+func (p *Pair) SignOperator(o *Operator) error {
+	signed := &Operator{
+		Name:    o.Name,
+		Contact: o.Contact,
+		Region:  o.Region,
+		Website: o.Website,
+	}
+	msg, err := p.Public.operatorMsg(signed)
+	if err != nil {
+		return err
+	}
+	if signed.Signature, err = AuthScheme.Sign(p.Key, msg); err != nil {
+		return err
+	}
+	p.Public.Operator = signed
+	return nil
+}
+
+// ValidOperator returns an error if the identity carries operator metadata that
+// was not signed by its private key. Identities without metadata are valid.
+func (i *Identity) ValidOperator() error {
+	if i.Operator == nil {
+		return nil
+	}
+	if len(i.Operator.Signature) == 0 {
+		return errors.New("unsigned operator metadata")
+	}
+	msg, err := i.operatorMsg(i.Operator)
+	if err != nil {
+		return err
+	}
+	if err := AuthScheme.Verify(i.Key, msg, i.Operator.Signature); err != nil {
+		return fmt.Errorf("invalid operator metadata signature: %s", err)
+	}
+	return nil
+}
+
+// String returns the metadata on one line, without the signature
+func (o *Operator) String() string {
+	s := o.Name
+	for _, field := range []string{o.Contact, o.Region, o.Website} {
+		if field != "" {
+			s += " - " + field
+		}
+	}
+	return s
+}
+
+// TOML returns the TOML-able version of the metadata
+func (o *Operator) TOML() interface{} {
+	return &OperatorTOML{
+		Name:      o.Name,
+		Contact:   o.Contact,
+		Region:    o.Region,
+		Website:   o.Website,
+		Signature: hex.EncodeToString(o.Signature),
+	}
+}
+
+// FromTOML decodes the metadata from its TOML version
+func (o *Operator) FromTOML(i interface{}) error {
+	otoml, ok := i.(*OperatorTOML)
+	if !ok {
+		return errors.New("operator can't decode from non OperatorTOML struct")
+	}
+	sig, err := hex.DecodeString(otoml.Signature)
+	if err != nil {
+		return err
+	}
+	o.Name = otoml.Name
+	o.Contact = otoml.Contact
+	o.Region = otoml.Region
+	o.Website = otoml.Website
+	o.Signature = sig
+	return nil
+}
+
+// TOMLValue returns an empty TOML-compatible value of the metadata
+func (o *Operator) TOMLValue() interface{} {
+	return &OperatorTOML{}
+}
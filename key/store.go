@@ -3,17 +3,20 @@ package key
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path"
 	"reflect"
+	"sort"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/drand/drand/fs"
 )
 
 // Store abstracts the loading and saving of any private/public cryptographic
-// material to be used by drand. For the moment, only a file based store is
-// implemented.
+// material to be used by drand. It is implemented by a file based store and by
+// an in-memory store.
 type Store interface {
 	// SaveKeyPair saves the private key generated by drand as well as the
 	// public identity key associated
@@ -21,15 +24,51 @@ type Store interface {
 	// LoadKeyPair loads the private/public key pair associated with the drand
 	// operator
 	LoadKeyPair() (*Pair, error)
+	// SaveNextKeyPair saves the key pair the node switches to after the next
+	// resharing, alongside the current one.
+	SaveNextKeyPair(p *Pair) error
+	// LoadNextKeyPair returns the next key pair, or ErrAbsent if there is
+	// none.
+	LoadNextKeyPair() (*Pair, error)
+	// RotateKeyPair makes the next key pair the key pair of the node and
+	// archives the current one.
+	RotateKeyPair() error
 	SaveShare(share *Share) error
 	LoadShare() (*Share, error)
 	SaveGroup(*Group) error
 	LoadGroup() (*Group, error)
 	SaveDistPublic(d *DistPublic) error
 	LoadDistPublic() (*DistPublic, error)
+	// SaveDKGState saves the progress of a running DKG protocol so it can be
+	// resumed after a crash.
+	SaveDKGState(*DKGState) error
+	// LoadDKGState returns the state of the DKG protocol in progress, or
+	// ErrAbsent if there is none.
+	LoadDKGState() (*DKGState, error)
+	// DeleteDKGState removes the state of the DKG protocol, once it is
+	// finished.
+	DeleteDKGState() error
+	// SaveTranscript saves the public transcript of the last DKG or resharing
+	SaveTranscript(*Transcript) error
+	LoadTranscript() (*Transcript, error)
+	// SaveHistoryGroup adds the given group to the history of the groups of
+	// the chain, with the first round it produces.
+	SaveHistoryGroup(g *Group, startAt uint64) error
+	// DeleteHistoryGroup removes the group starting at the given round from
+	// the history.
+	DeleteHistoryGroup(startAt uint64) error
+	// LoadGroupHistory returns the groups of the history, ordered by
+	// starting round.
+	LoadGroupHistory() ([]*HistoryGroup, error)
 	Reset(...ResetOption) error
 }
 
+// HistoryGroup is a group of the chain along with the first round it produces
+type HistoryGroup struct {
+	StartAt uint64
+	Group   *Group
+}
+
 // ErrStoreFile returns an error in case the store can not save the requested
 // file
 var ErrStoreFile = errors.New("store file issues")
@@ -47,11 +86,26 @@ const KeyFolderName = "key"
 // GroupFolderName is the name of the folder where drand keeps its group files
 const GroupFolderName = "groups"
 const keyFileName = "drand_id"
+const nextKeyFileName = "drand_id_next"
+
+// KeyArchiveFolderName is the name of the folder where drand keeps the key
+// pairs it rotated, in the key folder.
+const KeyArchiveFolderName = "archive"
 const privateExtension = ".private"
 const publicExtension = ".public"
 const groupFileName = "drand_group.toml"
 const shareFileName = "dist_key.private"
 const distKeyFileName = "dist_key.public"
+const dkgStateFileName = "dkg_state.private"
+
+// HistoryFolderName is the name of the folder where drand keeps the groups of
+// the chain, in the group folder.
+const HistoryFolderName = "history"
+const historyFilePrefix = "group_"
+
+// TranscriptFileName is the name of the file where drand keeps the transcript
+// of the last DKG or resharing, in the group folder.
+const TranscriptFileName = "dkg_transcript.toml"
 
 // Tomler represents any struct that can be (un)marshalled into/from toml format
 type Tomler interface {
@@ -62,12 +116,20 @@ type Tomler interface {
 
 // fileStore is a Store using filesystem to store informations
 type fileStore struct {
-	baseFolder     string
-	privateKeyFile string
-	publicKeyFile  string
-	shareFile      string
-	distKeyFile    string
-	groupFile      string
+	baseFolder         string
+	privateKeyFile     string
+	publicKeyFile      string
+	nextPrivateKeyFile string
+	nextPublicKeyFile  string
+	keyArchiveFolder   string
+	shareFile          string
+	distKeyFile        string
+	groupFile          string
+	dkgStateFile       string
+	transcriptFile     string
+	historyFolder      string
+	// sealer encrypts the secret files when the store has a passphrase
+	sealer *sealer
 }
 
 // NewFileStore is used to create the config folder and all the subfolders.
@@ -81,18 +143,89 @@ func NewFileStore(baseFolder string) Store {
 	store := &fileStore{baseFolder: baseFolder}
 	keyFolder := fs.CreateSecureFolder(path.Join(baseFolder, KeyFolderName))
 	groupFolder := fs.CreateSecureFolder(path.Join(baseFolder, GroupFolderName))
-	store.privateKeyFile = path.Join(keyFolder, keyFileName) + privateExtension
-	store.publicKeyFile = path.Join(keyFolder, keyFileName) + publicExtension
+	store.privateKeyFile = PrivateKeyFile(baseFolder)
+	store.publicKeyFile = PublicKeyFile(baseFolder)
+	store.nextPrivateKeyFile = path.Join(keyFolder, nextKeyFileName) + privateExtension
+	store.nextPublicKeyFile = path.Join(keyFolder, nextKeyFileName) + publicExtension
+	store.keyArchiveFolder = path.Join(keyFolder, KeyArchiveFolderName)
 	store.groupFile = path.Join(groupFolder, groupFileName)
 	store.shareFile = path.Join(groupFolder, shareFileName)
 	store.distKeyFile = path.Join(groupFolder, distKeyFileName)
+	store.dkgStateFile = path.Join(groupFolder, dkgStateFileName)
+	store.transcriptFile = path.Join(groupFolder, TranscriptFileName)
+	store.historyFolder = path.Join(groupFolder, HistoryFolderName)
 	return store
 }
 
-// SaveKeyPair first saves the private key in a file with tight permissions and then
-// saves the public part in another file.
+// PrivateKeyFile returns the path of the private key file in the given
+// configuration folder
+func PrivateKeyFile(baseFolder string) string {
+	return path.Join(baseFolder, KeyFolderName, keyFileName) + privateExtension
+}
+
+// PublicKeyFile returns the path of the public key file in the given
+// configuration folder
+func PublicKeyFile(baseFolder string) string {
+	return path.Join(baseFolder, KeyFolderName, keyFileName) + publicExtension
+}
+
+// NewEncryptedFileStore returns a file store that encrypts the private key, the
+// share and the DKG state with a key derived from the given passphrase. It can
+// still load files saved in plain text.
+func NewEncryptedFileStore(baseFolder string, passphrase []byte) (Store, error) {
+	s, err := newSealer(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	store := NewFileStore(baseFolder).(*fileStore)
+	store.sealer = s
+	return store, nil
+}
+
+// EncryptFileStore encrypts with the passphrase the secret files kept in plain
+// text in the given folder, and returns the encrypted store. Files already
+// encrypted with the same passphrase are left readable.
+func EncryptFileStore(baseFolder string, passphrase []byte) (Store, error) {
+	store, err := NewEncryptedFileStore(baseFolder, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	f := store.(*fileStore)
+	secrets := []struct {
+		path string
+		t    Tomler
+	}{
+		{f.privateKeyFile, new(Pair)},
+		{f.nextPrivateKeyFile, new(Pair)},
+		{f.shareFile, new(Share)},
+		{f.dkgStateFile, new(DKGState)},
+	}
+	for _, secret := range secrets {
+		if exists, err := fs.Exists(secret.path); err != nil {
+			return nil, err
+		} else if !exists {
+			continue
+		}
+		if err := loadSecret(secret.path, secret.t, f.sealer); err != nil {
+			return nil, fmt.Errorf("key: can't load %s: %v", secret.path, err)
+		}
+		// the plain file is only replaced once the encrypted one is written
+		tmp := secret.path + ".tmp"
+		if err := saveSecret(tmp, secret.t, f.sealer); err != nil {
+			return nil, err
+		}
+		if err := os.Rename(tmp, secret.path); err != nil {
+			return nil, err
+		}
+	}
+	return store, nil
+}
+
+// SaveKeyPair first saves the private key in a file with tight permissions,
+// encrypted if the store has a passphrase, and then saves the public part in
+// another file.
 func (f *fileStore) SaveKeyPair(p *Pair) error {
-	if err := Save(f.privateKeyFile, p, true); err != nil {
+	if err := saveSecret(f.privateKeyFile, p, f.sealer); err != nil {
 		return err
 	}
 	fmt.Printf("Saved the key : %s at %s\n", p.Public.Addr, f.publicKeyFile)
@@ -101,13 +234,94 @@ func (f *fileStore) SaveKeyPair(p *Pair) error {
 
 // LoadKeyPair decode private key first then public
 func (f *fileStore) LoadKeyPair() (*Pair, error) {
+	if err := f.finishRotation(); err != nil {
+		return nil, err
+	}
 	p := new(Pair)
-	if err := Load(f.privateKeyFile, p); err != nil {
+	if err := loadSecret(f.privateKeyFile, p, f.sealer); err != nil {
 		return nil, err
 	}
 	return p, Load(f.publicKeyFile, p.Public)
 }
 
+// SaveNextKeyPair saves the next key pair like SaveKeyPair, in files of their
+// own.
+func (f *fileStore) SaveNextKeyPair(p *Pair) error {
+	if err := saveSecret(f.nextPrivateKeyFile, p, f.sealer); err != nil {
+		return err
+	}
+	fmt.Printf("Saved the next key : %s at %s\n", p.Public.Addr, f.nextPublicKeyFile)
+	return Save(f.nextPublicKeyFile, p.Public, false)
+}
+
+func (f *fileStore) LoadNextKeyPair() (*Pair, error) {
+	if exists, err := fs.Exists(f.nextPrivateKeyFile); err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, ErrAbsent
+	}
+	p := new(Pair)
+	if err := loadSecret(f.nextPrivateKeyFile, p, f.sealer); err != nil {
+		return nil, err
+	}
+	return p, Load(f.nextPublicKeyFile, p.Public)
+}
+
+// RotateKeyPair copies the current key files to the archive folder, named
+// after the time of the rotation, and renames the next key files to take their
+// place. The private key is renamed first, so the key files are complete at
+// any time: if the node stops before the public key is renamed, the rotation
+// is finished the next time the key pair is loaded.
+func (f *fileStore) RotateKeyPair() error {
+	if exists, err := fs.Exists(f.nextPrivateKeyFile); err != nil {
+		return err
+	} else if !exists {
+		return ErrAbsent
+	}
+	if fs.CreateSecureFolder(f.keyArchiveFolder) == "" {
+		return fmt.Errorf("drand: can't create key archive folder %s", f.keyArchiveFolder)
+	}
+	archived := path.Join(f.keyArchiveFolder, fmt.Sprintf("%s_%d", keyFileName, time.Now().Unix()))
+	if err := copyFile(f.privateKeyFile, archived+privateExtension); err != nil {
+		return fmt.Errorf("drand: can't archive key pair: %v", err)
+	}
+	if err := copyFile(f.publicKeyFile, archived+publicExtension); err != nil {
+		return fmt.Errorf("drand: can't archive key pair: %v", err)
+	}
+	if err := os.Rename(f.nextPrivateKeyFile, f.privateKeyFile); err != nil {
+		return fmt.Errorf("drand: can't rotate key pair: %v", err)
+	}
+	return f.finishRotation()
+}
+
+// finishRotation renames the next public key in place if the next private key
+// has already been, which happens when the node stopped during a rotation.
+func (f *fileStore) finishRotation() error {
+	if exists, err := fs.Exists(f.nextPrivateKeyFile); err != nil || exists {
+		return err
+	}
+	if exists, err := fs.Exists(f.nextPublicKeyFile); err != nil || !exists {
+		return err
+	}
+	if err := os.Rename(f.nextPublicKeyFile, f.publicKeyFile); err != nil {
+		return fmt.Errorf("drand: can't rotate key pair: %v", err)
+	}
+	return nil
+}
+
+// copyFile copies a file with the same permissions
+func copyFile(from, to string) error {
+	info, err := os.Stat(from)
+	if err != nil {
+		return err
+	}
+	buff, err := ioutil.ReadFile(from)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(to, buff, info.Mode().Perm())
+}
+
 func (f *fileStore) LoadGroup() (*Group, error) {
 	g := new(Group)
 	return g, Load(f.groupFile, g)
@@ -119,12 +333,12 @@ func (f *fileStore) SaveGroup(g *Group) error {
 
 func (f *fileStore) SaveShare(share *Share) error {
 	fmt.Printf("crypto store: saving private share in %s\n", f.shareFile)
-	return Save(f.shareFile, share, true)
+	return saveSecret(f.shareFile, share, f.sealer)
 }
 
 func (f *fileStore) LoadShare() (*Share, error) {
 	s := new(Share)
-	return s, Load(f.shareFile, s)
+	return s, loadSecret(f.shareFile, s, f.sealer)
 }
 
 func (f *fileStore) SaveDistPublic(d *DistPublic) error {
@@ -137,7 +351,83 @@ func (f *fileStore) LoadDistPublic() (*DistPublic, error) {
 	return d, Load(f.distKeyFile, d)
 }
 
+// SaveDKGState saves the state of the running DKG in a file with tight
+// permissions, encrypted if the store has a passphrase, since it contains
+// secret material.
+func (f *fileStore) SaveDKGState(s *DKGState) error {
+	return saveSecret(f.dkgStateFile, s, f.sealer)
+}
+
+func (f *fileStore) LoadDKGState() (*DKGState, error) {
+	if exists, err := fs.Exists(f.dkgStateFile); err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, ErrAbsent
+	}
+	s := new(DKGState)
+	return s, loadSecret(f.dkgStateFile, s, f.sealer)
+}
+
+func (f *fileStore) DeleteDKGState() error {
+	return Delete(f.dkgStateFile)
+}
+
+func (f *fileStore) SaveTranscript(t *Transcript) error {
+	return Save(f.transcriptFile, t, false)
+}
+
+func (f *fileStore) LoadTranscript() (*Transcript, error) {
+	t := new(Transcript)
+	return t, Load(f.transcriptFile, t)
+}
+
+// SaveHistoryGroup saves the group in the history folder, in a file named
+// after its starting round.
+func (f *fileStore) SaveHistoryGroup(g *Group, startAt uint64) error {
+	if fs.CreateSecureFolder(f.historyFolder) == "" {
+		return fmt.Errorf("drand: can't create group history folder %s", f.historyFolder)
+	}
+	return Save(f.historyFile(startAt), g, false)
+}
+
+func (f *fileStore) DeleteHistoryGroup(startAt uint64) error {
+	return Delete(f.historyFile(startAt))
+}
+
+func (f *fileStore) LoadGroupHistory() ([]*HistoryGroup, error) {
+	files, err := ioutil.ReadDir(f.historyFolder)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var history []*HistoryGroup
+	for _, file := range files {
+		var startAt uint64
+		if _, err := fmt.Sscanf(file.Name(), historyFilePrefix+"%d.toml", &startAt); err != nil {
+			continue
+		}
+		g := new(Group)
+		if err := Load(path.Join(f.historyFolder, file.Name()), g); err != nil {
+			return nil, fmt.Errorf("drand: can't load group history %s: %v", file.Name(), err)
+		}
+		history = append(history, &HistoryGroup{StartAt: startAt, Group: g})
+	}
+	sort.Slice(history, func(i, j int) bool { return history[i].StartAt < history[j].StartAt })
+	return history, nil
+}
+
+func (f *fileStore) historyFile(startAt uint64) string {
+	return path.Join(f.historyFolder, fmt.Sprintf("%s%d.toml", historyFilePrefix, startAt))
+}
+
 func (f *fileStore) Reset(...ResetOption) error {
+	if err := Delete(f.transcriptFile); err != nil {
+		return fmt.Errorf("drand: err deleting transcript file: %v", err)
+	}
+	if err := Delete(f.dkgStateFile); err != nil {
+		return fmt.Errorf("drand: err deleting dkg state file: %v", err)
+	}
 	if err := Delete(f.distKeyFile); err != nil {
 		return fmt.Errorf("drand: err deleting dist. key file: %v", err)
 	}
@@ -148,6 +438,9 @@ func (f *fileStore) Reset(...ResetOption) error {
 	if err := Delete(f.groupFile); err != nil {
 		return fmt.Errorf("drand: err deleting group file: %v", err)
 	}
+	if err := Delete(f.historyFolder); err != nil {
+		return fmt.Errorf("drand: err deleting group history: %v", err)
+	}
 	return nil
 }
 
@@ -0,0 +1,234 @@
+package key
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+)
+
+// memStore is a Store keeping everything in memory. Like the file store, it
+// keeps the TOML encoding of the objects, so the objects it returns are
+// independent of the ones saved and of each other.
+type memStore struct {
+	sync.Mutex
+	priv     []byte
+	pub      []byte
+	nextPriv []byte
+	nextPub  []byte
+	share    []byte
+	group    []byte
+	dist     []byte
+	dkgState []byte
+	tr       []byte
+	history  map[uint64][]byte
+}
+
+// NewMemoryStore returns a Store that keeps the key material in memory. It
+// loses everything when the process exits, so it is meant for tests and for
+// nodes whose keys are provisioned at each start.
+func NewMemoryStore() Store {
+	return &memStore{history: make(map[uint64][]byte)}
+}
+
+func (m *memStore) SaveKeyPair(p *Pair) error {
+	m.Lock()
+	defer m.Unlock()
+	return m.savePair(p, &m.priv, &m.pub)
+}
+
+func (m *memStore) LoadKeyPair() (*Pair, error) {
+	m.Lock()
+	defer m.Unlock()
+	return loadPair(m.priv, m.pub)
+}
+
+func (m *memStore) SaveNextKeyPair(p *Pair) error {
+	m.Lock()
+	defer m.Unlock()
+	return m.savePair(p, &m.nextPriv, &m.nextPub)
+}
+
+func (m *memStore) LoadNextKeyPair() (*Pair, error) {
+	m.Lock()
+	defer m.Unlock()
+	return loadPair(m.nextPriv, m.nextPub)
+}
+
+// RotateKeyPair replaces the key pair by the next one. The memory store keeps
+// no archive of the former key pairs.
+func (m *memStore) RotateKeyPair() error {
+	m.Lock()
+	defer m.Unlock()
+	if m.nextPriv == nil {
+		return ErrAbsent
+	}
+	m.priv, m.pub = m.nextPriv, m.nextPub
+	m.nextPriv, m.nextPub = nil, nil
+	return nil
+}
+
+func (m *memStore) savePair(p *Pair, priv, pub *[]byte) error {
+	privBuff, err := encodeTOML(p)
+	if err != nil {
+		return err
+	}
+	pubBuff, err := encodeTOML(p.Public)
+	if err != nil {
+		return err
+	}
+	*priv, *pub = privBuff, pubBuff
+	return nil
+}
+
+func loadPair(priv, pub []byte) (*Pair, error) {
+	p := new(Pair)
+	if err := decodeTOML(priv, p); err != nil {
+		return nil, err
+	}
+	p.Public = new(Identity)
+	if err := decodeTOML(pub, p.Public); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (m *memStore) SaveShare(share *Share) error {
+	return m.save(&m.share, share)
+}
+
+func (m *memStore) LoadShare() (*Share, error) {
+	s := new(Share)
+	return s, m.load(&m.share, s)
+}
+
+func (m *memStore) SaveGroup(g *Group) error {
+	return m.save(&m.group, g)
+}
+
+func (m *memStore) LoadGroup() (*Group, error) {
+	g := new(Group)
+	return g, m.load(&m.group, g)
+}
+
+func (m *memStore) SaveDistPublic(d *DistPublic) error {
+	return m.save(&m.dist, d)
+}
+
+func (m *memStore) LoadDistPublic() (*DistPublic, error) {
+	d := new(DistPublic)
+	return d, m.load(&m.dist, d)
+}
+
+func (m *memStore) SaveDKGState(s *DKGState) error {
+	return m.save(&m.dkgState, s)
+}
+
+func (m *memStore) LoadDKGState() (*DKGState, error) {
+	s := new(DKGState)
+	if err := m.load(&m.dkgState, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (m *memStore) DeleteDKGState() error {
+	m.Lock()
+	defer m.Unlock()
+	m.dkgState = nil
+	return nil
+}
+
+func (m *memStore) SaveTranscript(t *Transcript) error {
+	return m.save(&m.tr, t)
+}
+
+func (m *memStore) LoadTranscript() (*Transcript, error) {
+	t := new(Transcript)
+	return t, m.load(&m.tr, t)
+}
+
+func (m *memStore) SaveHistoryGroup(g *Group, startAt uint64) error {
+	buff, err := encodeTOML(g)
+	if err != nil {
+		return err
+	}
+	m.Lock()
+	defer m.Unlock()
+	m.history[startAt] = buff
+	return nil
+}
+
+func (m *memStore) DeleteHistoryGroup(startAt uint64) error {
+	m.Lock()
+	defer m.Unlock()
+	delete(m.history, startAt)
+	return nil
+}
+
+func (m *memStore) LoadGroupHistory() ([]*HistoryGroup, error) {
+	m.Lock()
+	defer m.Unlock()
+	var history []*HistoryGroup
+	for startAt, buff := range m.history {
+		g := new(Group)
+		if err := decodeTOML(buff, g); err != nil {
+			return nil, err
+		}
+		history = append(history, &HistoryGroup{StartAt: startAt, Group: g})
+	}
+	sort.Slice(history, func(i, j int) bool { return history[i].StartAt < history[j].StartAt })
+	return history, nil
+}
+
+// Reset removes everything but the key pairs, like the file store does.
+func (m *memStore) Reset(...ResetOption) error {
+	m.Lock()
+	defer m.Unlock()
+	m.share = nil
+	m.group = nil
+	m.dist = nil
+	m.dkgState = nil
+	m.tr = nil
+	m.history = make(map[uint64][]byte)
+	return nil
+}
+
+func (m *memStore) save(dst *[]byte, t Tomler) error {
+	buff, err := encodeTOML(t)
+	if err != nil {
+		return err
+	}
+	m.Lock()
+	defer m.Unlock()
+	*dst = buff
+	return nil
+}
+
+func (m *memStore) load(src *[]byte, t Tomler) error {
+	m.Lock()
+	defer m.Unlock()
+	return decodeTOML(*src, t)
+}
+
+func encodeTOML(t Tomler) ([]byte, error) {
+	var buff bytes.Buffer
+	if err := toml.NewEncoder(&buff).Encode(t.TOML()); err != nil {
+		return nil, err
+	}
+	return buff.Bytes(), nil
+}
+
+// decodeTOML decodes the buffer into t, or returns ErrAbsent if the buffer is
+// empty.
+func decodeTOML(buff []byte, t Tomler) error {
+	if buff == nil {
+		return ErrAbsent
+	}
+	tomlValue := t.TOMLValue()
+	if _, err := toml.Decode(string(buff), tomlValue); err != nil {
+		return err
+	}
+	return t.FromTOML(tomlValue)
+}
@@ -0,0 +1,73 @@
+package key
+
+import (
+	"testing"
+	"time"
+
+	kyber "github.com/drand/kyber"
+	"github.com/drand/kyber/share"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ps, group := BatchIdentities(4)
+	group.Period = 10 * time.Second
+	store := NewMemoryStore()
+
+	_, err := store.LoadKeyPair()
+	require.Equal(t, ErrAbsent, err)
+	_, err = store.LoadGroup()
+	require.Equal(t, ErrAbsent, err)
+
+	require.NoError(t, store.SaveKeyPair(ps[0]))
+	loadedKey, err := store.LoadKeyPair()
+	require.NoError(t, err)
+	require.Equal(t, ps[0].Key.String(), loadedKey.Key.String())
+	require.True(t, ps[0].Public.Equal(loadedKey.Public))
+
+	// the store keeps its own copy of the saved objects
+	require.NoError(t, store.SaveGroup(group))
+	group.Threshold++
+	loadedGroup, err := store.LoadGroup()
+	require.NoError(t, err)
+	require.Equal(t, group.Threshold-1, loadedGroup.Threshold)
+	require.Equal(t, group.Period, loadedGroup.Period)
+
+	share := &Share{
+		Commits: []kyber.Point{ps[0].Public.Key, ps[1].Public.Key},
+		Share:   &share.PriShare{V: ps[0].Key, I: 0},
+	}
+	require.NoError(t, store.SaveShare(share))
+	loadedShare, err := store.LoadShare()
+	require.NoError(t, err)
+	require.Equal(t, share.Share.V.String(), loadedShare.Share.V.String())
+
+	// rotating the key pair
+	require.Equal(t, ErrAbsent, store.RotateKeyPair())
+	require.NoError(t, store.SaveNextKeyPair(ps[1]))
+	require.NoError(t, store.RotateKeyPair())
+	loadedKey, err = store.LoadKeyPair()
+	require.NoError(t, err)
+	require.True(t, ps[1].Public.Equal(loadedKey.Public))
+	_, err = store.LoadNextKeyPair()
+	require.Equal(t, ErrAbsent, err)
+
+	// the history is ordered by starting round
+	require.NoError(t, store.SaveHistoryGroup(group, 100))
+	require.NoError(t, store.SaveHistoryGroup(group, 10))
+	history, err := store.LoadGroupHistory()
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, uint64(10), history[0].StartAt)
+	require.Equal(t, uint64(100), history[1].StartAt)
+
+	// resetting keeps the key pair only
+	require.NoError(t, store.Reset())
+	_, err = store.LoadShare()
+	require.Equal(t, ErrAbsent, err)
+	history, err = store.LoadGroupHistory()
+	require.NoError(t, err)
+	require.Empty(t, history)
+	_, err = store.LoadKeyPair()
+	require.NoError(t, err)
+}
@@ -1,9 +1,11 @@
 package key
 
 import (
+	"io/ioutil"
 	"os"
 	"path"
 	"testing"
+	"time"
 
 	kyber "github.com/drand/kyber"
 	"github.com/drand/kyber/share"
@@ -74,3 +76,210 @@ func TestKeysSaveLoad(t *testing.T) {
 	require.Equal(t, dp.Key().String(), loadedDp.Key().String())
 
 }
+
+func TestDKGStateSaveLoad(t *testing.T) {
+	_, group := BatchIdentities(4)
+	group.Period = 30 * time.Second
+	tmp := path.Join(os.TempDir(), "drand-dkg-state")
+	os.RemoveAll(tmp)
+	defer os.RemoveAll(tmp)
+	store := NewFileStore(tmp)
+
+	_, err := store.LoadDKGState()
+	require.Equal(t, ErrAbsent, err)
+
+	state := &DKGState{
+		Leader:    true,
+		Seed:      []byte("seed"),
+		Timeout:   time.Minute,
+		StartedAt: 1000,
+		NewGroup:  group,
+		Deals:     map[int][]byte{1: []byte("deal1"), 3: []byte("deal3")},
+		Received:  [][]byte{[]byte("packet1"), []byte("packet2")},
+	}
+	require.NoError(t, store.SaveDKGState(state))
+	loaded, err := store.LoadDKGState()
+	require.NoError(t, err)
+	require.False(t, loaded.IsResharing())
+	require.Equal(t, state.Leader, loaded.Leader)
+	require.Equal(t, state.Seed, loaded.Seed)
+	require.Equal(t, state.Timeout, loaded.Timeout)
+	require.Equal(t, state.StartedAt, loaded.StartedAt)
+	require.True(t, state.NewGroup.Equal(loaded.NewGroup))
+	require.Equal(t, state.Deals, loaded.Deals)
+	require.Equal(t, state.Received, loaded.Received)
+
+	require.NoError(t, store.DeleteDKGState())
+	_, err = store.LoadDKGState()
+	require.Equal(t, ErrAbsent, err)
+}
+
+func TestGroupHistorySaveLoad(t *testing.T) {
+	_, group := BatchIdentities(4)
+	group.Period = 10 * time.Second
+	group.GenesisTime = time.Now().Unix()
+	next := NewGroup(group.Nodes[:3], 2, group.GenesisTime)
+	next.Period = group.Period
+	next.TransitionTime = group.GenesisTime + 100
+
+	tmp := path.Join(os.TempDir(), "drand-history")
+	os.RemoveAll(tmp)
+	defer os.RemoveAll(tmp)
+	store := NewFileStore(tmp)
+
+	history, err := store.LoadGroupHistory()
+	require.NoError(t, err)
+	require.Len(t, history, 0)
+
+	require.NoError(t, store.SaveHistoryGroup(next, 11))
+	require.NoError(t, store.SaveHistoryGroup(group, 0))
+	history, err = store.LoadGroupHistory()
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, uint64(0), history[0].StartAt)
+	require.True(t, group.Equal(history[0].Group))
+	require.Equal(t, uint64(11), history[1].StartAt)
+	require.True(t, next.Equal(history[1].Group))
+
+	require.NoError(t, store.DeleteHistoryGroup(11))
+	history, err = store.LoadGroupHistory()
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+
+	require.NoError(t, store.Reset())
+	history, err = store.LoadGroupHistory()
+	require.NoError(t, err)
+	require.Len(t, history, 0)
+	require.NoError(t, store.SaveHistoryGroup(group, 0))
+}
+
+func TestKeyRotation(t *testing.T) {
+	tmp := path.Join(os.TempDir(), "drand-rotation")
+	os.RemoveAll(tmp)
+	defer os.RemoveAll(tmp)
+	store := NewFileStore(tmp).(*fileStore)
+
+	current := NewKeyPair("127.0.0.1:8080")
+	require.NoError(t, store.SaveKeyPair(current))
+	_, err := store.LoadNextKeyPair()
+	require.Equal(t, ErrAbsent, err)
+	require.Equal(t, ErrAbsent, store.RotateKeyPair())
+
+	next := NewKeyPair("127.0.0.1:8080")
+	require.NoError(t, store.SaveNextKeyPair(next))
+	loaded, err := store.LoadNextKeyPair()
+	require.NoError(t, err)
+	require.True(t, loaded.Public.Equal(next.Public))
+
+	require.NoError(t, store.RotateKeyPair())
+	loaded, err = store.LoadKeyPair()
+	require.NoError(t, err)
+	require.True(t, loaded.Public.Equal(next.Public))
+	require.Equal(t, next.Key.String(), loaded.Key.String())
+	_, err = store.LoadNextKeyPair()
+	require.Equal(t, ErrAbsent, err)
+
+	archived, err := ioutil.ReadDir(store.keyArchiveFolder)
+	require.NoError(t, err)
+	require.Len(t, archived, 2)
+	old := new(Pair)
+	require.NoError(t, Load(path.Join(store.keyArchiveFolder, archived[0].Name()), old))
+	require.Equal(t, current.Key.String(), old.Key.String())
+
+	// a node stopped after the private key was renamed finishes the rotation
+	// when it loads its key pair
+	last := NewKeyPair("127.0.0.1:8080")
+	require.NoError(t, store.SaveNextKeyPair(last))
+	require.NoError(t, os.Rename(store.nextPrivateKeyFile, store.privateKeyFile))
+	loaded, err = store.LoadKeyPair()
+	require.NoError(t, err)
+	require.True(t, loaded.Public.Equal(last.Public))
+	require.Equal(t, last.Key.String(), loaded.Key.String())
+}
+
+func TestEncryptedStore(t *testing.T) {
+	ps, group := BatchIdentities(2)
+	group.Period = 30 * time.Second
+	tmp := path.Join(os.TempDir(), "drand-encrypted")
+	os.RemoveAll(tmp)
+	defer os.RemoveAll(tmp)
+	passphrase := []byte("passphrase")
+
+	// existing plain files get encrypted
+	plain := NewFileStore(tmp).(*fileStore)
+	require.NoError(t, plain.SaveKeyPair(ps[0]))
+	sh := &Share{
+		Commits: []kyber.Point{ps[0].Public.Key, ps[1].Public.Key},
+		Share:   &share.PriShare{V: ps[0].Key, I: 0},
+	}
+	require.NoError(t, plain.SaveShare(sh))
+	encrypted, err := EncryptFileStore(tmp, passphrase)
+	require.NoError(t, err)
+	for _, f := range []string{plain.privateKeyFile, plain.shareFile} {
+		isEncrypted, err := IsEncrypted(f)
+		require.NoError(t, err)
+		require.True(t, isEncrypted, f)
+	}
+
+	// they can't be loaded without the passphrase
+	_, err = plain.LoadKeyPair()
+	require.Equal(t, ErrEncrypted, err)
+	_, err = plain.LoadShare()
+	require.Equal(t, ErrEncrypted, err)
+	wrong, err := NewEncryptedFileStore(tmp, []byte("wrong"))
+	require.NoError(t, err)
+	_, err = wrong.LoadKeyPair()
+	require.Equal(t, ErrPassphrase, err)
+
+	// a new store with the same passphrase loads them
+	reopened, err := NewEncryptedFileStore(tmp, passphrase)
+	require.NoError(t, err)
+	loadedKey, err := reopened.LoadKeyPair()
+	require.NoError(t, err)
+	require.Equal(t, ps[0].Key.String(), loadedKey.Key.String())
+	require.Equal(t, ps[0].Public.Address(), loadedKey.Public.Address())
+	loadedShare, err := reopened.LoadShare()
+	require.NoError(t, err)
+	require.Equal(t, sh.Share.V.String(), loadedShare.Share.V.String())
+
+	// the DKG state is encrypted as well
+	state := &DKGState{
+		Seed:     []byte("seed"),
+		Timeout:  time.Minute,
+		NewGroup: group,
+	}
+	require.NoError(t, encrypted.SaveDKGState(state))
+	_, err = plain.LoadDKGState()
+	require.Equal(t, ErrEncrypted, err)
+	loadedState, err := reopened.LoadDKGState()
+	require.NoError(t, err)
+	require.Equal(t, state.Seed, loadedState.Seed)
+
+	// encrypting again with the same passphrase keeps the files readable
+	_, err = EncryptFileStore(tmp, passphrase)
+	require.NoError(t, err)
+	_, err = reopened.LoadKeyPair()
+	require.NoError(t, err)
+	_, err = EncryptFileStore(tmp, []byte("wrong"))
+	require.Error(t, err)
+
+	// the parameters of argon2 are checked before deriving the key
+	e := new(encryptedTOML)
+	require.NoError(t, Load(plain.privateKeyFile, e))
+	for _, params := range []struct {
+		time, memory uint32
+		threads      uint8
+	}{
+		{0, argonMemory, argonThreads},
+		{argonTime, argonMemory, 0},
+		{argonTime, 0, argonThreads},
+		{argonTime, 1 << 31, argonThreads},
+	} {
+		tampered := *e
+		tampered.Time, tampered.Memory, tampered.Threads = params.time, params.memory, params.threads
+		require.NoError(t, Save(plain.privateKeyFile, &tampered, true))
+		_, err = reopened.LoadKeyPair()
+		require.Error(t, err)
+		require.NotEqual(t, ErrPassphrase, err)
+	}
+}
@@ -0,0 +1,339 @@
+package key
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	kyber "github.com/drand/kyber"
+	"github.com/drand/kyber/share"
+	dkg "github.com/drand/kyber/share/dkg/pedersen"
+	vss "github.com/drand/kyber/share/vss/pedersen"
+	"github.com/drand/kyber/sign/schnorr"
+)
+
+// Transcript is the public record of a DKG or resharing protocol. It holds the
+// commitments of each dealer, the responses signed by the verifiers and the
+// final set of qualified dealers. With it, anybody can check that the
+// distributed public key of a group comes out of the protocol.
+type Transcript struct {
+	// Threshold of the new group
+	Threshold int
+	// OldThreshold is the threshold of the old group in case of a resharing,
+	// 0 otherwise.
+	OldThreshold int
+	// Dealers are the public keys of the dealers, i.e. the old nodes in case
+	// of a resharing or the new nodes otherwise.
+	Dealers []kyber.Point
+	// Verifiers are the public keys of the new nodes
+	Verifiers []kyber.Point
+	// Deals are the commitments of each dealer whose deal was received, signed
+	// by the dealer
+	Deals []*DealCommitment
+	// Responses are the responses of the verifiers to each deal
+	Responses []*DealResponse
+	// QUAL is the list of qualified dealers
+	QUAL []int
+}
+
+// DealCommitment holds the commitments to the polynomial of a dealer and the
+// signature of the dealer over them, see SignDealCommitments.
+type DealCommitment struct {
+	Dealer    uint32
+	Commits   []kyber.Point
+	Signature []byte
+}
+
+// DealResponse is the response of a verifier to a deal, signed with its
+// longterm key.
+type DealResponse struct {
+	Dealer    uint32
+	Verifier  uint32
+	Status    bool
+	SessionID []byte
+	Signature []byte
+}
+
+// IsResharing returns true if the transcript is the one of a resharing
+func (t *Transcript) IsResharing() bool {
+	return t.OldThreshold != 0
+}
+
+// Verify checks that the commitments of all qualified dealers are signed by
+// them, that their deals have been approved by at least a threshold of
+// verifiers, with valid signatures, and that the given
+// distributed public key is the combination of the commitments of the
+// qualified dealers: their sum in the case of a fresh DKG, their interpolation
+// in the case of a resharing.
+func (t *Transcript) Verify(dist *DistPublic) error {
+	if len(t.QUAL) == 0 {
+		return errors.New("transcript: empty QUAL set")
+	}
+	commits := make(map[uint32][]kyber.Point, len(t.Deals))
+	signatures := make(map[uint32][]byte, len(t.Deals))
+	for _, d := range t.Deals {
+		commits[d.Dealer] = d.Commits
+		signatures[d.Dealer] = d.Signature
+	}
+	for _, dealer := range t.QUAL {
+		cs, ok := commits[uint32(dealer)]
+		if !ok {
+			return fmt.Errorf("transcript: no commitments for qualified dealer %d", dealer)
+		}
+		if len(cs) != t.Threshold {
+			return fmt.Errorf("transcript: dealer %d has %d commitments, expected %d", dealer, len(cs), t.Threshold)
+		}
+		if err := t.verifyDeal(uint32(dealer), cs, signatures[uint32(dealer)]); err != nil {
+			return err
+		}
+	}
+
+	var expected []kyber.Point
+	var err error
+	if t.IsResharing() {
+		expected, err = t.recoverCommits(commits)
+	} else {
+		expected, err = t.sumCommits(commits)
+	}
+	if err != nil {
+		return err
+	}
+	if !(&DistPublic{expected}).Equal(dist) {
+		return errors.New("transcript: distributed public key does not match the qualified dealers' commitments")
+	}
+	return nil
+}
+
+// verifyDeal checks that the dealer signed the given commitments, that a
+// threshold of verifiers approved its deal, and that no valid complaint has
+// been issued against it.
+func (t *Transcript) verifyDeal(dealer uint32, commits []kyber.Point, signature []byte) error {
+	if int(dealer) >= len(t.Dealers) {
+		return fmt.Errorf("transcript: unknown dealer %d", dealer)
+	}
+	sid := transcriptSessionID(t.Dealers[dealer], t.Verifiers, commits, t.Threshold)
+	if err := VerifyDealCommitments(t.Dealers[dealer], sid, signature); err != nil {
+		return fmt.Errorf("transcript: invalid signature of dealer %d over its commitments: %s", dealer, err)
+	}
+	approvals := make(map[uint32]bool)
+	if !t.IsResharing() {
+		// in a fresh DKG, the dealer implicitly approves its own deal
+		approvals[dealer] = true
+	}
+	for _, r := range t.Responses {
+		if r.Dealer != dealer || !bytes.Equal(r.SessionID, sid) {
+			continue
+		}
+		if int(r.Verifier) >= len(t.Verifiers) {
+			return fmt.Errorf("transcript: unknown verifier %d", r.Verifier)
+		}
+		resp := &vss.Response{
+			SessionID: r.SessionID,
+			Index:     r.Verifier,
+			Status:    r.Status,
+		}
+		msg := resp.Hash(KeyGroup.(dkg.Suite))
+		if err := schnorr.Verify(KeyGroup, t.Verifiers[r.Verifier], msg, r.Signature); err != nil {
+			return fmt.Errorf("transcript: invalid signature from verifier %d on deal %d: %s", r.Verifier, dealer, err)
+		}
+		if r.Status != vss.StatusApproval {
+			return fmt.Errorf("transcript: qualified dealer %d has a complaint from verifier %d", dealer, r.Verifier)
+		}
+		approvals[r.Verifier] = true
+	}
+	if len(approvals) < t.Threshold {
+		return fmt.Errorf("transcript: dealer %d has %d approvals, expected at least %d", dealer, len(approvals), t.Threshold)
+	}
+	return nil
+}
+
+func (t *Transcript) sumCommits(commits map[uint32][]kyber.Point) ([]kyber.Point, error) {
+	var pub *share.PubPoly
+	for _, dealer := range t.QUAL {
+		poly := share.NewPubPoly(KeyGroup, KeyGroup.Point().Base(), commits[uint32(dealer)])
+		if pub == nil {
+			pub = poly
+			continue
+		}
+		var err error
+		if pub, err = pub.Add(poly); err != nil {
+			return nil, fmt.Errorf("transcript: can't add commitments: %s", err)
+		}
+	}
+	_, coeffs := pub.Info()
+	return coeffs, nil
+}
+
+func (t *Transcript) recoverCommits(commits map[uint32][]kyber.Point) ([]kyber.Point, error) {
+	coeffs := make([]kyber.Point, t.Threshold)
+	for i := range coeffs {
+		shares := make([]*share.PubShare, len(t.Dealers))
+		for _, dealer := range t.QUAL {
+			shares[dealer] = &share.PubShare{I: dealer, V: commits[uint32(dealer)][i]}
+		}
+		coeff, err := share.RecoverCommit(KeyGroup, shares, t.OldThreshold, len(t.Dealers))
+		if err != nil {
+			return nil, fmt.Errorf("transcript: can't recover commitment %d: %s", i, err)
+		}
+		coeffs[i] = coeff
+	}
+	return coeffs, nil
+}
+
+// transcriptSessionID computes the session ID of a deal the same way the vss
+// library does, binding the commitments to the responses of the verifiers.
+func transcriptSessionID(dealer kyber.Point, verifiers, commits []kyber.Point, t int) []byte {
+	h := KeyGroup.(dkg.Suite).Hash()
+	_, _ = dealer.MarshalTo(h)
+	for _, v := range verifiers {
+		_, _ = v.MarshalTo(h)
+	}
+	for _, c := range commits {
+		_, _ = c.MarshalTo(h)
+	}
+	_ = binary.Write(h, binary.LittleEndian, uint32(t))
+	return h.Sum(nil)
+}
+
+// SignDealCommitments returns the signature of a dealer over the commitments
+// of its deal, given through the session ID of the deal which hashes them
+// along with the dealer and verifiers keys.
+func SignDealCommitments(private kyber.Scalar, sid []byte) ([]byte, error) {
+	return schnorr.Sign(KeyGroup.(dkg.Suite), private, dealCommitmentsMsg(sid))
+}
+
+// VerifyDealCommitments checks the signature of a dealer over the commitments
+// of its deal, see SignDealCommitments.
+func VerifyDealCommitments(dealer kyber.Point, sid, signature []byte) error {
+	return schnorr.Verify(KeyGroup, dealer, dealCommitmentsMsg(sid), signature)
+}
+
+// dealCommitmentsMsg separates the signature over the commitments from the
+// other signatures issued with the longterm key of the dealer.
+func dealCommitmentsMsg(sid []byte) []byte {
+	return append([]byte("drand-deal-commitments"), sid...)
+}
+
+// TranscriptTOML is the TOML-able version of a Transcript
+type TranscriptTOML struct {
+	Threshold    int
+	OldThreshold int
+	Dealers      []string
+	Verifiers    []string
+	QUAL         []int
+	Deals        []*DealCommitmentTOML
+	Responses    []*DealResponseTOML
+}
+
+// DealCommitmentTOML is the TOML-able version of a DealCommitment
+type DealCommitmentTOML struct {
+	Dealer    uint32
+	Commits   []string
+	Signature string
+}
+
+// DealResponseTOML is the TOML-able version of a DealResponse
+type DealResponseTOML struct {
+	Dealer    uint32
+	Verifier  uint32
+	Status    bool
+	SessionID string
+	Signature string
+}
+
+// TOML returns a TOML-compatible version of the transcript
+func (t *Transcript) TOML() interface{} {
+	tt := &TranscriptTOML{
+		Threshold:    t.Threshold,
+		OldThreshold: t.OldThreshold,
+		Dealers:      pointsToStrings(t.Dealers),
+		Verifiers:    pointsToStrings(t.Verifiers),
+		QUAL:         t.QUAL,
+	}
+	for _, d := range t.Deals {
+		tt.Deals = append(tt.Deals, &DealCommitmentTOML{
+			Dealer:    d.Dealer,
+			Commits:   pointsToStrings(d.Commits),
+			Signature: hex.EncodeToString(d.Signature),
+		})
+	}
+	for _, r := range t.Responses {
+		tt.Responses = append(tt.Responses, &DealResponseTOML{
+			Dealer:    r.Dealer,
+			Verifier:  r.Verifier,
+			Status:    r.Status,
+			SessionID: hex.EncodeToString(r.SessionID),
+			Signature: hex.EncodeToString(r.Signature),
+		})
+	}
+	return tt
+}
+
+// FromTOML decodes the transcript from its TOML representation
+func (t *Transcript) FromTOML(i interface{}) error {
+	tt, ok := i.(*TranscriptTOML)
+	if !ok {
+		return errors.New("transcript: invalid TOML value")
+	}
+	var err error
+	t.Threshold = tt.Threshold
+	t.OldThreshold = tt.OldThreshold
+	t.QUAL = tt.QUAL
+	if t.Dealers, err = stringsToPoints(tt.Dealers); err != nil {
+		return fmt.Errorf("transcript: invalid dealer: %s", err)
+	}
+	if t.Verifiers, err = stringsToPoints(tt.Verifiers); err != nil {
+		return fmt.Errorf("transcript: invalid verifier: %s", err)
+	}
+	t.Deals = make([]*DealCommitment, 0, len(tt.Deals))
+	for _, d := range tt.Deals {
+		commits, err := stringsToPoints(d.Commits)
+		if err != nil {
+			return fmt.Errorf("transcript: invalid commitment of dealer %d: %s", d.Dealer, err)
+		}
+		signature, err := hex.DecodeString(d.Signature)
+		if err != nil {
+			return fmt.Errorf("transcript: invalid signature of dealer %d: %s", d.Dealer, err)
+		}
+		t.Deals = append(t.Deals, &DealCommitment{Dealer: d.Dealer, Commits: commits, Signature: signature})
+	}
+	t.Responses = make([]*DealResponse, 0, len(tt.Responses))
+	for _, r := range tt.Responses {
+		resp := &DealResponse{Dealer: r.Dealer, Verifier: r.Verifier, Status: r.Status}
+		if resp.SessionID, err = hex.DecodeString(r.SessionID); err != nil {
+			return fmt.Errorf("transcript: invalid session id: %s", err)
+		}
+		if resp.Signature, err = hex.DecodeString(r.Signature); err != nil {
+			return fmt.Errorf("transcript: invalid signature: %s", err)
+		}
+		t.Responses = append(t.Responses, resp)
+	}
+	return nil
+}
+
+// TOMLValue returns an empty TOML-compatible value of the transcript
+func (t *Transcript) TOMLValue() interface{} {
+	return &TranscriptTOML{}
+}
+
+func pointsToStrings(ps []kyber.Point) []string {
+	s := make([]string, len(ps))
+	for i, p := range ps {
+		s[i] = PointToString(p)
+	}
+	return s
+}
+
+func stringsToPoints(s []string) ([]kyber.Point, error) {
+	ps := make([]kyber.Point, len(s))
+	for i, str := range s {
+		p, err := StringToPoint(KeyGroup, str)
+		if err != nil {
+			return nil, err
+		}
+		ps[i] = p
+	}
+	return ps, nil
+}
@@ -14,10 +14,12 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
 	gonet "net"
 
 	"github.com/BurntSushi/toml"
+	"github.com/drand/drand/beacon"
 	"github.com/drand/drand/core"
 	"github.com/drand/drand/fs"
 	"github.com/drand/drand/key"
@@ -122,15 +124,18 @@ var periodFlag = &cli.StringFlag{
 }
 
 var thresholdFlag = &cli.IntFlag{
-	Name:     "threshold",
-	Required: true,
-	Usage:    "threshold to use for the DKG",
+	Name:  "threshold",
+	Usage: "threshold to use for the DKG",
 }
 
 var shareNodeFlag = &cli.IntFlag{
-	Name:     "nodes",
-	Required: true,
-	Usage:    "number of nodes expected",
+	Name:  "nodes",
+	Usage: "number of nodes expected",
+}
+
+var genesisFlag = &cli.Int64Flag{
+	Name:  "genesis",
+	Usage: "Genesis time of the beacon chain, as a unix timestamp",
 }
 
 var transitionFlag = &cli.BoolFlag{
@@ -141,12 +146,11 @@ var transitionFlag = &cli.BoolFlag{
 // secret flag is the "manual" security when the "leader"/coordinator creates the
 // group: every participant must know this secret. It is not a consensus, not
 // perfect, but since all members are known after the protocol, and members can
-// decide to redo the setup, it works in practice well enough.
-// XXX Add a manual check when the group is created so the user manually ACK.
+// decide to redo the setup, it works in practice well enough. Participants
+// also manually accept the group created by the leader before the DKG starts.
 var secretFlag = &cli.StringFlag{
-	Name:     "secret",
-	Required: true,
-	Usage:    "Specify the secret to use when doing the share so the leader knows you are an eligible potential participant",
+	Name:  "secret",
+	Usage: "Specify the secret to use when doing the share so the leader knows you are an eligible potential participant",
 }
 
 var connectFlag = &cli.StringFlag{
@@ -159,6 +163,29 @@ var leaderFlag = &cli.BoolFlag{
 	Usage: "Specify if this node should act as the leader for setting up the group",
 }
 
+var allowFlag = &cli.StringSliceFlag{
+	Name: "allow",
+	Usage: "Leader only: file listing the participants allowed to join the setup, " +
+		"with the same [[Nodes]] format as a group file, or public key file of " +
+		"an allowed participant. Can be repeated.",
+}
+
+var cancelFlag = &cli.BoolFlag{
+	Name:  "cancel",
+	Usage: "Cancel the setup or the DKG the daemon runs, so it can run a new one",
+}
+
+var cancelTransitionFlag = &cli.BoolFlag{
+	Name:  "cancel-transition",
+	Usage: "Cancel the transition to the new group scheduled after a resharing, to keep the old group",
+}
+
+var autoAcceptHashFlag = &cli.StringFlag{
+	Name: "auto-accept-hash",
+	Usage: "Participant only: accept the group created by the leader without " +
+		"asking if its chain hash is the given one, reject it otherwise",
+}
+
 var beaconOffset = &cli.IntFlag{
 	Name:  "beacon-delay",
 	Usage: "Leader uses this flag to specify the genesis time or transition time as a delay from when group is ready to run the share protocol",
@@ -168,9 +195,15 @@ var beaconOffset = &cli.IntFlag{
 
 var oldGroupFlag = &cli.StringFlag{
 	Name: "from",
-	Usage: "Old group.toml path to specify when a new node wishes to participate " +
-		"in a resharing protocol. This flag is optional in case a node is already" +
-		"included in the current DKG.",
+	Usage: "Old group.toml path or http(s) url to specify when a new node wishes " +
+		"to participate in a resharing protocol. This flag is optional in case a " +
+		"node is already included in the current DKG.",
+}
+
+var groupHashFlag = &cli.StringFlag{
+	Name: "group-hash",
+	Usage: "Expected chain hash of the old group used for a resharing, as " +
+		"printed by drand show group. Required when the old group is fetched from an url.",
 }
 
 var timeoutFlag = &cli.StringFlag{
@@ -203,6 +236,97 @@ var groupFlag = &cli.StringFlag{
 	Usage: "Test connections to nodes listed in the group",
 }
 
+var trustedFlag = &cli.StringFlag{
+	Name:  "trusted",
+	Usage: "Path of a group file to trust. Only the latest group signed from it, through the successive resharings, is accepted.",
+}
+
+var passphraseEnvFlag = &cli.StringFlag{
+	Name:  "passphrase-env",
+	Usage: "Name of the environment variable holding the passphrase of the encrypted private key and share. Without it nor --passphrase-fd, the passphrase is asked on the terminal.",
+}
+
+var passphraseFdFlag = &cli.IntFlag{
+	Name:  "passphrase-fd",
+	Usage: "File descriptor to read the passphrase of the encrypted private key and share from, up to the first newline.",
+}
+
+var signerFlag = &cli.StringFlag{
+	Name:  "signer",
+	Usage: "Path of the Unix socket of the signer process holding the share, started with `drand signer`. The share is then not needed in the configuration folder of the daemon.",
+}
+
+var transcriptFlag = &cli.StringFlag{
+	Name:  "transcript",
+	Usage: "Path of the DKG transcript, saved by each node in its group folder as " + key.TranscriptFileName,
+}
+
+var idFlag = &cli.StringFlag{
+	Name:  "id",
+	Usage: "Beacon ID of the chain the command is about, among the chains run by the daemon. Without it, the command is about the default chain.",
+}
+
+var operatorNameFlag = &cli.StringFlag{
+	Name:  "operator-name",
+	Usage: "Name of the operator of the node, signed with its private key and shown in the group file.",
+}
+
+var operatorContactFlag = &cli.StringFlag{
+	Name:  "operator-contact",
+	Usage: "Contact of the operator of the node, e.g. an email address.",
+}
+
+var operatorRegionFlag = &cli.StringFlag{
+	Name:  "operator-region",
+	Usage: "Region where the node runs.",
+}
+
+var operatorWebsiteFlag = &cli.StringFlag{
+	Name:  "operator-website",
+	Usage: "Website of the operator of the node.",
+}
+
+var backupOutFlag = &cli.StringFlag{
+	Name:  "out",
+	Usage: "File where the backup is saved",
+}
+
+var encryptFlag = &cli.BoolFlag{
+	Name:  "encrypt",
+	Usage: "Encrypt the backup with a passphrase, read from the passphrase flags or asked on the terminal.",
+}
+
+var rollbackToFlag = &cli.Uint64Flag{
+	Name:  "to",
+	Usage: "Last round kept in the beacon database.",
+}
+
+var upToFlag = &cli.Uint64Flag{
+	Name:  "up-to",
+	Usage: "Last round to sync. By default, the node syncs up to the current round.",
+}
+
+var syncPeersFlag = &cli.IntFlag{
+	Name:  "sync-peers",
+	Usage: "Number of peers the node syncs the chain from at once when it is far behind. By default, it syncs from one peer at a time.",
+}
+
+var syncMaxCountFlag = &cli.Uint64Flag{
+	Name:  "sync-max-count",
+	Usage: "Maximum number of beacons sent to another node for a single sync request.",
+}
+
+var syncMaxPerPeerFlag = &cli.IntFlag{
+	Name:  "sync-max-per-peer",
+	Usage: "Maximum number of sync requests served at once to a single node.",
+}
+
+var dbBackendFlag = &cli.StringFlag{
+	Name:  "db-backend",
+	Value: beacon.DefaultBackend,
+	Usage: "Storage backend of the random beacons, among: " + strings.Join(beacon.StoreBackends(), ", ") + ". The beacons of the memory backend are synced again from the other nodes at each start.",
+}
+
 func main() {
 	app := cli.NewApp()
 
@@ -219,12 +343,26 @@ func main() {
 			Usage: "Start the drand daemon.",
 			Flags: toArray(folderFlag, tlsCertFlag, tlsKeyFlag,
 				insecureFlag, controlFlag, listenFlag, metricsFlag,
-				certsDirFlag, pushFlag, verboseFlag),
+				certsDirFlag, pushFlag, verboseFlag, passphraseEnvFlag,
+				passphraseFdFlag, signerFlag, dbBackendFlag, syncPeersFlag,
+				syncMaxCountFlag, syncMaxPerPeerFlag),
 			Action: func(c *cli.Context) error {
 				banner()
 				return startCmd(c)
 			},
 		},
+		&cli.Command{
+			Name: "signer",
+			Usage: "Start a signer process holding the share of the node " +
+				"and signing the partial beacons of the daemon started " +
+				"with the same --signer socket.\n",
+			Flags: toArray(folderFlag, signerFlag, passphraseEnvFlag,
+				passphraseFdFlag),
+			Action: func(c *cli.Context) error {
+				banner()
+				return signerCmd(c)
+			},
+		},
 		&cli.Command{
 			Name:  "stop",
 			Usage: "Stop the drand daemon.\n",
@@ -235,23 +373,50 @@ func main() {
 			},
 		},
 		&cli.Command{
-			Name:  "share",
-			Usage: "Launch a sharing protocol.",
-			Flags: toArray(insecureFlag, controlFlag, oldGroupFlag,
+			Name: "sync",
+			Usage: "Make the daemon sync its chain from its peers, to recover a " +
+				"node that fell behind. The sync runs in the background: " +
+				"follow it with the show sync command.\n",
+			Flags: toArray(controlFlag, idFlag, upToFlag),
+			Action: func(c *cli.Context) error {
+				return syncCmd(c)
+			},
+		},
+		&cli.Command{
+			Name: "share",
+			Usage: "Launch a sharing protocol. If a group file is given, " +
+				"the DKG runs with this group, agreed upon beforehand by all " +
+				"participants, without leader.",
+			ArgsUsage: "[group.toml] group file built with the group command",
+			Flags: toArray(insecureFlag, controlFlag, idFlag, oldGroupFlag,
 				timeoutFlag, sourceFlag, userEntropyOnlyFlag, secretFlag,
 				periodFlag, shareNodeFlag, thresholdFlag, connectFlag, outFlag,
-				leaderFlag, beaconOffset, transitionFlag),
+				leaderFlag, beaconOffset, transitionFlag, allowFlag,
+				autoAcceptHashFlag, groupHashFlag, cancelFlag, cancelTransitionFlag),
 			Action: func(c *cli.Context) error {
 				banner()
 				return shareCmd(c)
 			},
 		},
+		&cli.Command{
+			Name: "group",
+			Usage: "Build the group file from the public key files of all the " +
+				"participants. Every participant can build it offline: the " +
+				"nodes are sorted so the same keys and parameters always give " +
+				"the same group.\n",
+			ArgsUsage: "<key1 key2 key3...> must be the public key files of the participants",
+			Flags:     toArray(thresholdFlag, periodFlag, genesisFlag, outFlag),
+			Action: func(c *cli.Context) error {
+				return groupCmd(c)
+			},
+		},
 		&cli.Command{
 			Name: "generate-keypair",
 			Usage: "Generate the longterm keypair (drand.private, drand.public)" +
 				"for this node.\n",
 			ArgsUsage: "<address> is the public address for other nodes to contact",
-			Flags:     toArray(folderFlag, insecureFlag),
+			Flags: toArray(folderFlag, idFlag, insecureFlag, operatorNameFlag,
+				operatorContactFlag, operatorRegionFlag, operatorWebsiteFlag),
 			Action: func(c *cli.Context) error {
 				banner()
 				return keygenCmd(c)
@@ -262,7 +427,8 @@ func main() {
 			Name: "check",
 			Usage: "Check node at the given `ADDRESS` (you can put multiple ones)" +
 				" in the group for accessibility over the gRPC communication. If the node " +
-				" is not running behind TLS, you need to pass the tls-disable flag. You can " +
+				" is not running behind TLS, you need to pass the tls-disable flag. It also " +
+				"reports the protocol version of each node. You can " +
 				"also check a whole group's connectivity with the group flag.",
 			Flags: toArray(groupFlag, certsDirFlag, insecureFlag),
 			Action: func(c *cli.Context) error {
@@ -315,6 +481,17 @@ func main() {
 						return getCokeyCmd(c)
 					},
 				},
+				{
+					Name: "groups",
+					Usage: "Get the groups that produced the chain, with the " +
+						"first round each of them produced. With --trusted, only " +
+						"output the latest group signed from the trusted one.",
+					ArgsUsage: "`ADDRESS` provides the address of the node",
+					Flags:     toArray(tlsCertFlag, insecureFlag, trustedFlag, outFlag),
+					Action: func(c *cli.Context) error {
+						return getGroupHistoryCmd(c)
+					},
+				},
 			},
 		},
 		{
@@ -328,11 +505,102 @@ func main() {
 		{
 			Name:  "reset",
 			Usage: "Resets the local distributed information (share, group file and random beacons). It KEEPS the private/public key pair.",
-			Flags: toArray(folderFlag, controlFlag),
+			Flags: toArray(folderFlag, idFlag, controlFlag),
 			Action: func(c *cli.Context) error {
 				return resetCmd(c)
 			},
 		},
+		{
+			Name:  "util",
+			Usage: "Multiple commands of utility functions, such as verifying a DKG transcript.",
+			Subcommands: []*cli.Command{
+				{
+					Name: "verify-dkg",
+					Usage: "Verify that the distributed public key of the group " +
+						"is the combination of the commitments of the qualified " +
+						"dealers recorded in the transcript of the DKG.\n",
+					ArgsUsage: "<group.toml> is the group file holding the distributed key",
+					Flags:     toArray(transcriptFlag),
+					Action: func(c *cli.Context) error {
+						return verifyDKGCmd(c)
+					},
+				},
+				{
+					Name: "encrypt-keys",
+					Usage: "Encrypt the private key, the share and the DKG " +
+						"state of the node with a passphrase. The daemon " +
+						"must be stopped, and then started with the same " +
+						"passphrase.\n",
+					Flags: toArray(folderFlag, idFlag, passphraseEnvFlag, passphraseFdFlag),
+					Action: func(c *cli.Context) error {
+						return encryptKeysCmd(c)
+					},
+				},
+				{
+					Name: "rotate-key",
+					Usage: "Generate a new key pair replacing the current " +
+						"one in the group of the next resharing. The node " +
+						"takes part in the resharing with both keys, signs " +
+						"as the new one from the transition time and " +
+						"archives the old one once the new group runs.\n",
+					Flags: toArray(folderFlag, idFlag, passphraseEnvFlag, passphraseFdFlag),
+					Action: func(c *cli.Context) error {
+						return rotateKeyCmd(c)
+					},
+				},
+				{
+					Name: "set-operator",
+					Usage: "Set the operator metadata of the node, signed " +
+						"with its private key. The daemon must be stopped, " +
+						"and the metadata reaches the group file at the next " +
+						"setup or resharing.\n",
+					Flags: toArray(folderFlag, idFlag, passphraseEnvFlag, passphraseFdFlag,
+						operatorNameFlag, operatorContactFlag, operatorRegionFlag,
+						operatorWebsiteFlag),
+					Action: func(c *cli.Context) error {
+						return setOperatorCmd(c)
+					},
+				},
+				{
+					Name: "backup",
+					Usage: "Save a backup of the key material and of the " +
+						"beacon database of the running daemon. The " +
+						"database is copied consistently while the daemon " +
+						"runs, with the bolt storage backend only.\n",
+					Flags: toArray(controlFlag, idFlag, backupOutFlag, encryptFlag,
+						passphraseEnvFlag, passphraseFdFlag),
+					Action: func(c *cli.Context) error {
+						return backupCmd(c)
+					},
+				},
+				{
+					Name: "restore",
+					Usage: "Restore the backup given as argument. The backup " +
+						"must hold the key pair of the node, if it has one " +
+						"already. The beacons are restored in the given " +
+						"storage backend. The daemon must be stopped.\n",
+					ArgsUsage: "<backup file>",
+					Flags: toArray(folderFlag, controlFlag, idFlag,
+						passphraseEnvFlag, passphraseFdFlag, dbBackendFlag),
+					Action: func(c *cli.Context) error {
+						return restoreCmd(c)
+					},
+				},
+				{
+					Name: "rollback",
+					Usage: "Delete the beacons of the local chain after the " +
+						"given round, keeping the key pair, the share and " +
+						"the group. The daemon syncs the deleted rounds " +
+						"again from its peers at the next start. The " +
+						"daemon must be stopped.\n",
+					Flags: toArray(folderFlag, controlFlag, idFlag, rollbackToFlag,
+						dbBackendFlag),
+					Action: func(c *cli.Context) error {
+						return rollbackCmd(c)
+					},
+				},
+			},
+		},
 		{
 			Name: "show",
 			Usage: "local information retrieval about the node's cryptographic " +
@@ -346,7 +614,7 @@ func main() {
 				{
 					Name:  "share",
 					Usage: "shows the private share\n",
-					Flags: toArray(controlFlag),
+					Flags: toArray(controlFlag, idFlag),
 					Action: func(c *cli.Context) error {
 						return showShareCmd(c)
 					},
@@ -356,7 +624,7 @@ func main() {
 					Usage: "shows the current group.toml used. The group.toml " +
 						"may contain the distributed public key if the DKG has been " +
 						"ran already.\n",
-					Flags: toArray(outFlag, controlFlag),
+					Flags: toArray(outFlag, controlFlag, idFlag),
 					Action: func(c *cli.Context) error {
 						return showGroupCmd(c)
 					},
@@ -364,7 +632,7 @@ func main() {
 				{
 					Name:  "cokey",
 					Usage: "shows the collective key generated during DKG.\n",
-					Flags: toArray(controlFlag),
+					Flags: toArray(controlFlag, idFlag),
 					Action: func(c *cli.Context) error {
 						return showCokeyCmd(c)
 					},
@@ -372,7 +640,7 @@ func main() {
 				{
 					Name:  "private",
 					Usage: "shows the long-term private key of a node.\n",
-					Flags: toArray(controlFlag),
+					Flags: toArray(controlFlag, idFlag),
 					Action: func(c *cli.Context) error {
 						return showPrivateCmd(c)
 					},
@@ -380,11 +648,31 @@ func main() {
 				{
 					Name:  "public",
 					Usage: "shows the long-term public key of a node.\n",
-					Flags: toArray(controlFlag),
+					Flags: toArray(controlFlag, idFlag),
 					Action: func(c *cli.Context) error {
 						return showPublicCmd(c)
 					},
 				},
+				{
+					Name: "peers",
+					Usage: "shows the latency, error rate and last round the " +
+						"node keeps for each of its peers, and whether it " +
+						"backs off from them.\n",
+					Flags: toArray(controlFlag, idFlag),
+					Action: func(c *cli.Context) error {
+						return showPeersCmd(c)
+					},
+				},
+				{
+					Name: "sync",
+					Usage: "shows the progress of the sync of the chain the " +
+						"node runs: target and current rounds, peers in use, " +
+						"rate and estimated time left.\n",
+					Flags: toArray(controlFlag, idFlag),
+					Action: func(c *cli.Context) error {
+						return showSyncCmd(c)
+					},
+				},
 			},
 		},
 	}
@@ -411,25 +699,49 @@ func resetCmd(c *cli.Context) error {
 		slog.Print("drand: not reseting the state.")
 		return nil
 	}
-	store := key.NewFileStore(conf.ConfigFolder())
+	id := beaconID(c)
+	store := key.NewFileStore(conf.BeaconFolder(id))
 	if err := store.Reset(); err != nil {
 		fmt.Printf("drand: err reseting key store: %v\n", err)
 		os.Exit(1)
 	}
-	if err := os.RemoveAll(conf.DBFolder()); err != nil {
-		fmt.Printf("drand: err reseting beacons database: %v\n", err)
-		os.Exit(1)
+	// the database folder of the default chain holds the ones of the others
+	for _, db := range beaconDBPaths(conf, id) {
+		if err := os.RemoveAll(db); err != nil {
+			fmt.Printf("drand: err reseting beacons database: %v\n", err)
+			os.Exit(1)
+		}
 	}
 	fmt.Println("drand: database reset")
 	return nil
 }
 
-func resetBeaconDB(config *core.Config) bool {
-	if _, err := os.Stat(config.DBFolder()); err == nil {
+// beaconDBPaths returns the files and folders of the beacon databases of the
+// chain of the given ID, whatever the storage backend that created them.
+func beaconDBPaths(config *core.Config, beaconID string) []string {
+	var paths []string
+	for _, name := range beacon.StoreBackends() {
+		backend, err := beacon.GetStoreBackend(name)
+		if err != nil {
+			continue
+		}
+		db := backend.Path(config.BeaconDBFolder(beaconID))
+		if db == "" {
+			continue
+		}
+		if _, err := os.Stat(db); err == nil {
+			paths = append(paths, db)
+		}
+	}
+	return paths
+}
+
+func resetBeaconDB(config *core.Config, beaconID string) bool {
+	for _, dbFile := range beaconDBPaths(config, beaconID) {
 		fmt.Printf("INCONSISTENT STATE: A beacon database exists already.\n"+
 			"drand support only one identity at the time and thus needs to delete "+
-			"the existing beacon database.\nCurrent folder is %s.\nAccept to delete "+
-			"database ? [Y/n]: ", config.DBFolder())
+			"the existing beacon database.\nCurrent file is %s.\nAccept to delete "+
+			"database ? [Y/n]: ", dbFile)
 		reader := bufio.NewReader(os.Stdin)
 		answer, err := reader.ReadString('\n')
 		if err != nil {
@@ -441,7 +753,7 @@ func resetBeaconDB(config *core.Config) bool {
 			return true
 		}
 
-		if err := os.RemoveAll(config.DBFolder()); err != nil {
+		if err := os.RemoveAll(dbFile); err != nil {
 			slog.Fatal(err)
 		}
 		slog.Print("drand: removed existing beacon database.")
@@ -498,18 +810,24 @@ func keygenCmd(c *cli.Context) error {
 		fmt.Println("Generating private / public key pair with TLS indication")
 		priv = key.NewTLSKeyPair(addr)
 	}
+	if op := operatorFromFlags(c); op != nil {
+		if err := priv.SignOperator(op); err != nil {
+			fatal("drand: can't sign the operator metadata: %v", err)
+		}
+	}
 
 	config := contextToConfig(c)
-	fs := key.NewFileStore(config.ConfigFolder())
+	folder := config.BeaconFolder(beaconID(c))
+	fs := key.NewFileStore(folder)
 
-	if _, err := fs.LoadKeyPair(); err == nil {
-		fmt.Printf("Keypair already present in `%s`.\nRemove them before generating new one\n", config.ConfigFolder())
+	if _, err := fs.LoadKeyPair(); err == nil || err == key.ErrEncrypted {
+		fmt.Printf("Keypair already present in `%s`.\nRemove them before generating new one\n", folder)
 		return nil
 	}
 	if err := fs.SaveKeyPair(priv); err != nil {
 		fatal("could not save key: ", err)
 	}
-	fullpath := path.Join(config.ConfigFolder(), key.KeyFolderName)
+	fullpath := path.Join(folder, key.KeyFolderName)
 	absPath, err := filepath.Abs(fullpath)
 	if err != nil {
 		fatal("err getting full path: ", err)
@@ -527,6 +845,37 @@ func keygenCmd(c *cli.Context) error {
 	return nil
 }
 
+func groupCmd(c *cli.Context) error {
+	if !c.Args().Present() {
+		fatal("drand: group command needs the public key files of the participants")
+	}
+	if !c.IsSet(periodFlag.Name) {
+		fatal("drand: group command needs the period flag")
+	}
+	period, err := time.ParseDuration(c.String(periodFlag.Name))
+	if err != nil {
+		fatal("drand: invalid period: %v", err)
+	}
+	if !c.IsSet(genesisFlag.Name) {
+		fatal("drand: group command needs the genesis flag")
+	}
+	publics := getPublicKeys(c)
+	group := key.NewGroup(publics, getThreshold(c), c.Int64(genesisFlag.Name))
+	group.Period = period
+	hash, err := group.Hash()
+	if err != nil {
+		fatal("drand: can't compute group hash: %v", err)
+	}
+	chainHash, err := group.ChainHash()
+	if err != nil {
+		fatal("drand: can't compute group chain hash: %v", err)
+	}
+	groupOut(c, group)
+	fmt.Printf("Group hash: %s\n", hash)
+	fmt.Printf("Chain hash: %s\n", chainHash)
+	return nil
+}
+
 func groupOut(c *cli.Context, group *key.Group) {
 	if c.IsSet("out") {
 		groupPath := c.String("out")
@@ -595,10 +944,11 @@ func checkConnection(c *cli.Context) error {
 	var isVerbose = c.IsSet(verboseFlag.Name)
 	var allGood = true
 	var invalidIds []string
+	var incompatibleIds []string
 	for _, address := range names {
 		peer := net.CreatePeer(address, !c.Bool(insecureFlag.Name))
 		client := net.NewGrpcClientFromCertManager(conf.Certs())
-		_, err := client.Home(context.Background(), peer, &drand.HomeRequest{})
+		resp, err := client.Home(context.Background(), peer, &drand.HomeRequest{})
 		if err != nil {
 			if isVerbose {
 				fmt.Printf("drand: error checking id %s: %s\n", peer.Address(), err)
@@ -609,11 +959,20 @@ func checkConnection(c *cli.Context) error {
 			invalidIds = append(invalidIds, peer.Address())
 			continue
 		}
-		fmt.Printf("drand: id %s answers correctly\n", peer.Address())
+		version := net.VersionString(resp.GetMetadata())
+		if err := net.CheckMetadata(resp.GetMetadata()); err != nil {
+			fmt.Printf("drand: id %s runs an incompatible protocol %s: %s\n", peer.Address(), version, err)
+			incompatibleIds = append(incompatibleIds, peer.Address())
+			continue
+		}
+		fmt.Printf("drand: id %s answers correctly, protocol %s\n", peer.Address(), version)
 	}
 	if !allGood {
 		return fmt.Errorf("Following nodes don't answer: %s", strings.Join(invalidIds, ","))
 	}
+	if len(incompatibleIds) > 0 {
+		return fmt.Errorf("Following nodes run an incompatible protocol: %s", strings.Join(incompatibleIds, ","))
+	}
 	return nil
 }
 
@@ -679,6 +1038,18 @@ func contextToConfig(c *cli.Context) *core.Config {
 		}
 		opts = append(opts, core.WithTrustedCerts(paths...))
 	}
+	if c.IsSet(signerFlag.Name) {
+		opts = append(opts, core.WithRemoteSigner(c.String(signerFlag.Name)))
+	}
+	if c.IsSet(dbBackendFlag.Name) {
+		opts = append(opts, core.WithDbBackend(c.String(dbBackendFlag.Name)))
+	}
+	if c.IsSet(syncPeersFlag.Name) {
+		opts = append(opts, core.WithParallelSync(c.Int(syncPeersFlag.Name)))
+	}
+	if c.IsSet(syncMaxCountFlag.Name) || c.IsSet(syncMaxPerPeerFlag.Name) {
+		opts = append(opts, core.WithSyncLimits(c.Uint64(syncMaxCountFlag.Name), c.Int(syncMaxPerPeerFlag.Name)))
+	}
 	conf := core.NewConfig(opts...)
 	return conf
 }
@@ -47,6 +47,17 @@ func TestKeyGen(t *testing.T) {
 	priv, err := fs.LoadKeyPair()
 	require.NoError(t, err)
 	require.NotNil(t, priv.Public)
+	require.Nil(t, priv.Public.Operator)
+
+	cmd = exec.Command("drand", "util", "set-operator", "--folder", tmp,
+		"--operator-name", "League", "--operator-region", "eu-west")
+	out, err = cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	priv, err = fs.LoadKeyPair()
+	require.NoError(t, err)
+	require.Equal(t, "League", priv.Public.Operator.Name)
+	require.Equal(t, "eu-west", priv.Public.Operator.Region)
+	require.NoError(t, priv.Public.ValidOperator())
 
 	tmp2 := path.Join(os.TempDir(), "drand2")
 	defer os.RemoveAll(tmp2)
@@ -61,6 +72,46 @@ func TestKeyGen(t *testing.T) {
 	require.Nil(t, priv)
 }
 
+func TestGroup(t *testing.T) {
+	tmp := path.Join(os.TempDir(), "drand-group")
+	require.NoError(t, os.MkdirAll(tmp, 0740))
+	defer os.RemoveAll(tmp)
+	n := 5
+	privs := test.GenerateIDs(n)
+	keyPaths := make([]string, n)
+	for i, p := range privs {
+		keyPaths[i] = path.Join(tmp, fmt.Sprintf("key%d.toml", i))
+		require.NoError(t, key.Save(keyPaths[i], p.Public, false))
+	}
+	genesis := "1600000000"
+	// missing genesis
+	cmd := exec.Command("drand", append([]string{"group", "--period", "30s"}, keyPaths...)...)
+	_, err := cmd.Output()
+	require.Error(t, err)
+
+	// operators building the group with the keys in a different order end up
+	// with the same group
+	var hashes []string
+	for i, paths := range [][]string{keyPaths, {keyPaths[3], keyPaths[1], keyPaths[4], keyPaths[0], keyPaths[2]}} {
+		out := path.Join(tmp, fmt.Sprintf("group%d.toml", i))
+		args := append([]string{"group", "--period", "30s", "--genesis", genesis, "--threshold", "4", "--out", out}, paths...)
+		cmd := exec.Command("drand", args...)
+		buff, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(buff))
+		group := new(key.Group)
+		require.NoError(t, key.Load(out, group))
+		require.Equal(t, 4, group.Threshold)
+		require.Equal(t, int64(1600000000), group.GenesisTime)
+		require.Equal(t, 30*time.Second, group.Period)
+		require.Equal(t, n, group.Len())
+		hash, err := group.Hash()
+		require.NoError(t, err)
+		require.Contains(t, string(buff), hash)
+		hashes = append(hashes, hash)
+	}
+	require.Equal(t, hashes[0], hashes[1])
+}
+
 //tests valid commands and then invalid commands
 func TestStartAndStop(t *testing.T) {
 	tmpPath := path.Join(os.TempDir(), "drand")
@@ -225,6 +276,28 @@ func TestStartWithoutGroup(t *testing.T) {
 	require.True(t, strings.Contains(string(out), expectedOutput))
 	require.NoError(t, err)
 
+	// encrypted backup of the running node
+	backupPath := path.Join(tmpPath, "backup.tar")
+	require.NoError(t, os.Setenv("DRAND_TEST_BACKUP", "backup passphrase"))
+	defer os.Unsetenv("DRAND_TEST_BACKUP")
+	backupCmd := exec.Command("drand", "util", "backup", "--control", ctrlPort2, "--out", backupPath,
+		"--encrypt", "--passphrase-env", "DRAND_TEST_BACKUP")
+	out, err = backupCmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	// it is not restored while the daemon runs
+	restoreCmd := exec.Command("drand", "util", "restore", "--control", ctrlPort2, "--folder", tmpPath,
+		"--passphrase-env", "DRAND_TEST_BACKUP", backupPath)
+	out, err = restoreCmd.CombinedOutput()
+	require.Error(t, err, string(out))
+	restoredPath := path.Join(tmpPath, "restored")
+	restoreCmd = exec.Command("drand", "util", "restore", "--control", test.FreePort(), "--folder", restoredPath,
+		"--passphrase-env", "DRAND_TEST_BACKUP", backupPath)
+	out, err = restoreCmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+	restoredShare, err := key.NewFileStore(restoredPath).LoadShare()
+	require.NoError(t, err)
+	require.True(t, scalarOne.Equal(restoredShare.Share.V))
+
 	// reset state
 	resetCmd := exec.Command("drand", "reset", "--folder", tmpPath)
 	var in bytes.Buffer
@@ -372,4 +445,14 @@ func TestClientTLS(t *testing.T) {
 	expectedOutput = keyStr
 	require.True(t, strings.Contains(string(out), expectedOutput))
 	require.NoError(t, err)
+
+	cmd = exec.Command("drand", "show", "peers", "--control", ctrlPort)
+	out, err = cmd.CombinedOutput()
+	fmt.Println(string(out))
+	require.NoError(t, err)
+
+	cmd = exec.Command("drand", "show", "sync", "--control", ctrlPort)
+	out, err = cmd.CombinedOutput()
+	fmt.Println(string(out))
+	require.NoError(t, err)
 }
@@ -22,8 +22,11 @@ type ProtocolClient interface {
 	PartialBeacon(ctx context.Context, p Peer, in *drand.PartialBeaconPacket, opts ...CallOption) error
 	FreshDKG(ctx context.Context, p Peer, in *drand.DKGPacket, opts ...CallOption) (*drand.Empty, error)
 	ReshareDKG(ctx context.Context, p Peer, in *drand.ResharePacket, opts ...CallOption) (*drand.Empty, error)
-	PrepareDKGGroup(ctx context.Context, p Peer, in *drand.PrepareDKGPacket, opts ...CallOption) error
+	PrepareDKGGroup(ctx context.Context, p Peer, in *drand.PrepareDKGPacket, opts ...CallOption) (*drand.PrepareDKGResponse, error)
 	PushDKGGroup(ctx context.Context, p Peer, in *drand.PushGroupPacket, opts ...grpc.CallOption) error
+	ConfirmGroup(ctx context.Context, p Peer, in *drand.GroupHashPacket, opts ...CallOption) (*drand.GroupHashPacket, error)
+	AbortSetup(ctx context.Context, p Peer, in *drand.AbortSetupPacket, opts ...CallOption) error
+	NewGroupSilent(ctx context.Context, p Peer, in *drand.NewGroupSilentPacket, opts ...CallOption) (*drand.NewGroupSilentPacket, error)
 	SetTimeout(time.Duration)
 }
 
@@ -35,5 +38,6 @@ type PublicClient interface {
 	PrivateRand(ctx context.Context, p Peer, in *drand.PrivateRandRequest) (*drand.PrivateRandResponse, error)
 	DistKey(ctx context.Context, p Peer, in *drand.DistKeyRequest) (*drand.DistKeyResponse, error)
 	Group(ctx context.Context, p Peer, in *drand.GroupRequest) (*drand.GroupPacket, error)
+	GroupHistory(ctx context.Context, p Peer, in *drand.GroupHistoryRequest) (*drand.GroupHistoryResponse, error)
 	Home(ctx context.Context, p Peer, in *drand.HomeRequest) (*drand.HomeResponse, error)
 }
@@ -148,6 +148,17 @@ func (g *grpcClient) Group(ctx context.Context, p Peer, in *drand.GroupRequest)
 	resp, err = client.Group(ctx, in)
 	return resp, err
 }
+func (g *grpcClient) GroupHistory(ctx context.Context, p Peer, in *drand.GroupHistoryRequest) (*drand.GroupHistoryResponse, error) {
+	c, err := g.conn(p)
+	if err != nil {
+		return nil, err
+	}
+	client := drand.NewPublicClient(c)
+	ctx, cancel := g.getTimeoutContext(ctx)
+	defer cancel()
+	return client.GroupHistory(ctx, in)
+}
+
 func (g *grpcClient) DistKey(ctx context.Context, p Peer, in *drand.DistKeyRequest) (*drand.DistKeyResponse, error) {
 	var resp *drand.DistKeyResponse
 	c, err := g.conn(p)
@@ -171,16 +182,43 @@ func (g *grpcClient) PushDKGGroup(ctx context.Context, p Peer, in *drand.PushGro
 	return err
 
 }
-func (g *grpcClient) PrepareDKGGroup(ctx context.Context, p Peer, in *drand.PrepareDKGPacket, opts ...CallOption) error {
+func (g *grpcClient) ConfirmGroup(ctx context.Context, p Peer, in *drand.GroupHashPacket, opts ...CallOption) (*drand.GroupHashPacket, error) {
+	c, err := g.conn(p)
+	if err != nil {
+		return nil, err
+	}
+	client := drand.NewProtocolClient(c)
+	return client.ConfirmGroup(ctx, in, opts...)
+}
+
+func (g *grpcClient) NewGroupSilent(ctx context.Context, p Peer, in *drand.NewGroupSilentPacket, opts ...CallOption) (*drand.NewGroupSilentPacket, error) {
+	c, err := g.conn(p)
+	if err != nil {
+		return nil, err
+	}
+	client := drand.NewProtocolClient(c)
+	return client.NewGroupSilent(ctx, in, opts...)
+}
+
+func (g *grpcClient) AbortSetup(ctx context.Context, p Peer, in *drand.AbortSetupPacket, opts ...CallOption) error {
 	c, err := g.conn(p)
 	if err != nil {
 		return err
 	}
 	client := drand.NewProtocolClient(c)
+	_, err = client.AbortSetup(ctx, in, opts...)
+	return err
+}
+
+func (g *grpcClient) PrepareDKGGroup(ctx context.Context, p Peer, in *drand.PrepareDKGPacket, opts ...CallOption) (*drand.PrepareDKGResponse, error) {
+	c, err := g.conn(p)
+	if err != nil {
+		return nil, err
+	}
+	client := drand.NewProtocolClient(c)
 	//ctx, cancel := g.getTimeoutContext(ctx)
 	//defer cancel()
-	_, err = client.PrepareDKGGroup(ctx, in, opts...)
-	return err
+	return client.PrepareDKGGroup(ctx, in, opts...)
 }
 
 func (g *grpcClient) FreshDKG(ctx context.Context, p Peer, in *drand.DKGPacket, opts ...CallOption) (*drand.Empty, error) {
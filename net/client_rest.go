@@ -128,6 +128,25 @@ func (r *restClient) Group(ctx context.Context, p Peer, in *drand.GroupRequest)
 	return drandResponse, r.marshaller.Unmarshal(respBody, drandResponse)
 }
 
+func (r *restClient) GroupHistory(ctx context.Context, p Peer, in *drand.GroupHistoryRequest) (*drand.GroupHistoryResponse, error) {
+	base := restAddr(p)
+	buff, err := r.marshaller.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+	url := base + "/api/info/groups"
+	req, err := http.NewRequest("GET", url, bytes.NewBuffer(buff))
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := r.doRequest(p, req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	drandResponse := new(drand.GroupHistoryResponse)
+	return drandResponse, r.marshaller.Unmarshal(respBody, drandResponse)
+}
+
 func (r *restClient) Home(ctx context.Context, p Peer, in *drand.HomeRequest) (*drand.HomeResponse, error) {
 	base := restAddr(p)
 	buff, err := r.marshaller.Marshal(in)
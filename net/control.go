@@ -3,7 +3,9 @@ package net
 import (
 	"context"
 	"fmt"
+	"io"
 	"net"
+	"strings"
 	"time"
 
 	control "github.com/drand/drand/protobuf/drand"
@@ -47,6 +49,8 @@ func (g *ControlListener) Stop() {
 type ControlClient struct {
 	conn   *grpc.ClientConn
 	client control.ControlClient
+	// beaconID identifies the chain the commands are about
+	beaconID string
 }
 
 // NewControlClient creates a client capable of issuing control commands to a
@@ -62,6 +66,12 @@ func NewControlClient(port string) (*ControlClient, error) {
 	return &ControlClient{conn: conn, client: c}, nil
 }
 
+// ForBeacon returns a client issuing the commands about the chain of the given
+// beacon ID, empty for the default chain.
+func (c *ControlClient) ForBeacon(beaconID string) *ControlClient {
+	return &ControlClient{conn: c.conn, client: c.client, beaconID: beaconID}
+}
+
 // Ping the drand daemon to check if it's up and running
 func (c *ControlClient) Ping() error {
 	_, err := c.client.PingPong(context.Background(), &control.Ping{})
@@ -69,13 +79,13 @@ func (c *ControlClient) Ping() error {
 }
 
 // InitReshare sets up the node to be ready for a resharing protocol.
-// NOTE: only group referral via filesystem path is supported at the moment.
+// The old group is either a path on the local filesystem or an http(s) url,
+// in which case the hash the old group must have is required.
 // XXX Might be best to move to core/
-func (c *ControlClient) InitReshareLeader(nodes, threshold int, timeout string, secret string, oldPath string, offset int) (*control.GroupPacket, error) {
+func (c *ControlClient) InitReshareLeader(nodes, threshold int, timeout string, secret string, oldGroup, oldHash string, offset int, allowed []*control.Identity) (*control.GroupPacket, error) {
 	request := &control.InitResharePacket{
-		Old: &control.GroupInfo{
-			Location: &control.GroupInfo_Path{Path: oldPath},
-		},
+		BeaconID: c.beaconID,
+		Old:      groupInfo(oldGroup, oldHash),
 		Info: &control.SetupInfoPacket{
 			Nodes:        uint32(nodes),
 			Threshold:    uint32(threshold),
@@ -83,16 +93,16 @@ func (c *ControlClient) InitReshareLeader(nodes, threshold int, timeout string,
 			Timeout:      timeout,
 			Secret:       secret,
 			BeaconOffset: uint32(offset),
+			Allowed:      allowed,
 		},
 	}
 	return c.client.InitReshare(context.Background(), request)
 }
 
-func (c *ControlClient) InitReshare(leader Peer, nodes, threshold int, timeout string, secret string, oldPath string) (*control.GroupPacket, error) {
+func (c *ControlClient) InitReshare(leader Peer, nodes, threshold int, timeout string, secret string, oldGroup, oldHash string) (*control.GroupPacket, error) {
 	request := &control.InitResharePacket{
-		Old: &control.GroupInfo{
-			Location: &control.GroupInfo_Path{Path: oldPath},
-		},
+		BeaconID: c.beaconID,
+		Old:      groupInfo(oldGroup, oldHash),
 		Info: &control.SetupInfoPacket{
 			Nodes:         uint32(nodes),
 			Threshold:     uint32(threshold),
@@ -106,12 +116,25 @@ func (c *ControlClient) InitReshare(leader Peer, nodes, threshold int, timeout s
 	return c.client.InitReshare(context.Background(), request)
 }
 
+// groupInfo returns the location of a group, a path on the local filesystem
+// or an http(s) url, along with the hash the group must have if any.
+func groupInfo(location, hash string) *control.GroupInfo {
+	info := &control.GroupInfo{Hash: hash}
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		info.Location = &control.GroupInfo_Url{Url: location}
+	} else {
+		info.Location = &control.GroupInfo_Path{Path: location}
+	}
+	return info
+}
+
 // InitDKG sets up the node to be ready for a first DKG protocol.
 // groupPart
 // NOTE: only group referral via filesystem path is supported at the moment.
 // XXX Might be best to move to core/
-func (c *ControlClient) InitDKGLeader(nodes, threshold int, beaconPeriod time.Duration, timeout string, entropy *control.EntropyInfo, secret string, offset int) (*control.GroupPacket, error) {
+func (c *ControlClient) InitDKGLeader(nodes, threshold int, beaconPeriod time.Duration, timeout string, entropy *control.EntropyInfo, secret string, offset int, allowed []*control.Identity) (*control.GroupPacket, error) {
 	request := &control.InitDKGPacket{
+		BeaconID: c.beaconID,
 		Info: &control.SetupInfoPacket{
 			Nodes:        uint32(nodes),
 			Threshold:    uint32(threshold),
@@ -119,6 +142,7 @@ func (c *ControlClient) InitDKGLeader(nodes, threshold int, beaconPeriod time.Du
 			Timeout:      timeout,
 			Secret:       secret,
 			BeaconOffset: uint32(offset),
+			Allowed:      allowed,
 		},
 		Entropy:      entropy,
 		BeaconPeriod: uint32(beaconPeriod.Seconds()),
@@ -128,6 +152,7 @@ func (c *ControlClient) InitDKGLeader(nodes, threshold int, beaconPeriod time.Du
 
 func (c *ControlClient) InitDKG(leader Peer, nodes, threshold int, timeout string, entropy *control.EntropyInfo, secret string) (*control.GroupPacket, error) {
 	request := &control.InitDKGPacket{
+		BeaconID: c.beaconID,
 		Info: &control.SetupInfoPacket{
 			Nodes:         uint32(nodes),
 			Threshold:     uint32(threshold),
@@ -142,30 +167,118 @@ func (c *ControlClient) InitDKG(leader Peer, nodes, threshold int, timeout strin
 	return c.client.InitDKG(context.Background(), request)
 }
 
+// InitDKGFromGroup sets up the node to run the DKG with the group file at the
+// given path, agreed upon beforehand by all participants. No leader is
+// involved: the nodes start the DKG once they all confirmed the same group.
+func (c *ControlClient) InitDKGFromGroup(groupPath string, timeout string, entropy *control.EntropyInfo) (*control.GroupPacket, error) {
+	request := &control.InitDKGPacket{
+		BeaconID: c.beaconID,
+		Info: &control.SetupInfoPacket{
+			Timeout: timeout,
+		},
+		Entropy: entropy,
+		Group: &control.GroupInfo{
+			Location: &control.GroupInfo_Path{Path: groupPath},
+		},
+	}
+	return c.client.InitDKG(context.Background(), request)
+}
+
 // Share returns the share of the remote node
 func (c ControlClient) Share() (*control.ShareResponse, error) {
-	return c.client.Share(context.Background(), &control.ShareRequest{})
+	return c.client.Share(context.Background(), &control.ShareRequest{BeaconID: c.beaconID})
 }
 
 // PublicKey returns the public key of the remote node
 func (c ControlClient) PublicKey() (*control.PublicKeyResponse, error) {
-	return c.client.PublicKey(context.Background(), &control.PublicKeyRequest{})
+	return c.client.PublicKey(context.Background(), &control.PublicKeyRequest{BeaconID: c.beaconID})
 }
 
 // PrivateKey returns the private key of the remote node
 func (c ControlClient) PrivateKey() (*control.PrivateKeyResponse, error) {
-	return c.client.PrivateKey(context.Background(), &control.PrivateKeyRequest{})
+	return c.client.PrivateKey(context.Background(), &control.PrivateKeyRequest{BeaconID: c.beaconID})
 }
 
 // CollectiveKey returns the collective key of the remote node
 func (c ControlClient) CollectiveKey() (*control.CokeyResponse, error) {
-	return c.client.CollectiveKey(context.Background(), &control.CokeyRequest{})
+	return c.client.CollectiveKey(context.Background(), &control.CokeyRequest{BeaconID: c.beaconID})
 }
 
 // GroupFile returns the group file that the drand instance uses at the current
 // time
 func (c ControlClient) GroupFile() (*control.GroupPacket, error) {
-	return c.client.GroupFile(context.Background(), &control.GroupRequest{})
+	return c.client.GroupFile(context.Background(), &control.GroupRequest{BeaconID: c.beaconID})
+}
+
+// SetupStatus returns the participants that joined the setup the daemon runs
+// as a leader
+func (c ControlClient) SetupStatus() (*control.SetupStatusResponse, error) {
+	return c.client.SetupStatus(context.Background(), &control.SetupStatusRequest{BeaconID: c.beaconID})
+}
+
+// PendingGroup returns the group received from the leader that waits to be
+// accepted by the operator
+func (c ControlClient) PendingGroup() (*control.GroupPacket, error) {
+	return c.client.PendingGroup(context.Background(), &control.PendingGroupRequest{BeaconID: c.beaconID})
+}
+
+// AcceptGroup accepts or rejects the group of the given hash received from the
+// leader
+func (c ControlClient) AcceptGroup(hash string, accept bool) error {
+	_, err := c.client.AcceptGroup(context.Background(), &control.AcceptGroupRequest{
+		Hash:     hash,
+		Accept:   accept,
+		BeaconID: c.beaconID,
+	})
+	return err
+}
+
+// CancelDKG aborts the setup or the DKG the daemon runs
+func (c ControlClient) CancelDKG() error {
+	_, err := c.client.CancelDKG(context.Background(), &control.CancelDKGRequest{BeaconID: c.beaconID})
+	return err
+}
+
+// CancelTransition cancels the transition to the new group the daemon
+// scheduled after a resharing
+func (c ControlClient) CancelTransition() error {
+	_, err := c.client.CancelTransition(context.Background(), &control.CancelTransitionRequest{BeaconID: c.beaconID})
+	return err
+}
+
+// Backup writes to w the backup of the chain streamed by the daemon
+func (c ControlClient) Backup(w io.Writer) error {
+	stream, err := c.client.Backup(context.Background(), &control.BackupRequest{BeaconID: c.beaconID})
+	if err != nil {
+		return err
+	}
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if _, err := w.Write(chunk.GetData()); err != nil {
+			return err
+		}
+	}
+}
+
+// Peers returns the scores the daemon keeps of its peers
+func (c ControlClient) Peers() (*control.PeersResponse, error) {
+	return c.client.Peers(context.Background(), &control.PeersRequest{BeaconID: c.beaconID})
+}
+
+// SyncStatus returns the progress of the sync of the chain the daemon runs
+func (c ControlClient) SyncStatus() (*control.SyncStatusResponse, error) {
+	return c.client.SyncStatus(context.Background(), &control.SyncStatusRequest{BeaconID: c.beaconID})
+}
+
+// StartSync makes the daemon sync its chain up to the given round, or up to
+// the current round if upTo is zero
+func (c ControlClient) StartSync(upTo uint64) (*control.SyncStatusResponse, error) {
+	return c.client.StartSync(context.Background(), &control.StartSyncRequest{BeaconID: c.beaconID, UpTo: upTo})
 }
 
 // Shutdown stops the daemon
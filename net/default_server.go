@@ -30,6 +30,11 @@ func (s *EmptyServer) Group(context.Context, *drand.GroupRequest) (*drand.GroupP
 	return nil, nil
 }
 
+// GroupHistory ...
+func (s *EmptyServer) GroupHistory(context.Context, *drand.GroupHistoryRequest) (*drand.GroupHistoryResponse, error) {
+	return nil, nil
+}
+
 // DistKey ...
 func (s *EmptyServer) DistKey(context.Context, *drand.DistKeyRequest) (*drand.DistKeyResponse, error) {
 	return nil, nil
@@ -41,7 +46,7 @@ func (s *EmptyServer) Home(context.Context, *drand.HomeRequest) (*drand.HomeResp
 }
 
 // FreshDKG ...
-func (s *EmptyServer) PrepareDKGGroup(context.Context, *drand.PrepareDKGPacket) (*drand.Empty, error) {
+func (s *EmptyServer) PrepareDKGGroup(context.Context, *drand.PrepareDKGPacket) (*drand.PrepareDKGResponse, error) {
 	return nil, nil
 }
 
@@ -49,6 +54,21 @@ func (s *EmptyServer) PushDKGGroup(context.Context, *drand.PushGroupPacket) (*dr
 	return nil, nil
 }
 
+// ConfirmGroup ...
+func (s *EmptyServer) ConfirmGroup(context.Context, *drand.GroupHashPacket) (*drand.GroupHashPacket, error) {
+	return nil, nil
+}
+
+// AbortSetup ...
+func (s *EmptyServer) AbortSetup(context.Context, *drand.AbortSetupPacket) (*drand.Empty, error) {
+	return nil, nil
+}
+
+// NewGroupSilent ...
+func (s *EmptyServer) NewGroupSilent(context.Context, *drand.NewGroupSilentPacket) (*drand.NewGroupSilentPacket, error) {
+	return nil, nil
+}
+
 // Setup ...
 func (s *EmptyServer) FreshDKG(context.Context, *drand.DKGPacket) (*drand.Empty, error) {
 	return nil, nil
@@ -112,3 +132,48 @@ func (s *EmptyServer) GroupFile(context.Context, *drand.GroupRequest) (*drand.Gr
 func (s *EmptyServer) Shutdown(context.Context, *drand.ShutdownRequest) (*drand.ShutdownResponse, error) {
 	return nil, nil
 }
+
+// SetupStatus ...
+func (s *EmptyServer) SetupStatus(context.Context, *drand.SetupStatusRequest) (*drand.SetupStatusResponse, error) {
+	return nil, nil
+}
+
+// PendingGroup ...
+func (s *EmptyServer) PendingGroup(context.Context, *drand.PendingGroupRequest) (*drand.GroupPacket, error) {
+	return nil, nil
+}
+
+// AcceptGroup ...
+func (s *EmptyServer) AcceptGroup(context.Context, *drand.AcceptGroupRequest) (*drand.AcceptGroupResponse, error) {
+	return nil, nil
+}
+
+// CancelDKG ...
+func (s *EmptyServer) CancelDKG(context.Context, *drand.CancelDKGRequest) (*drand.CancelDKGResponse, error) {
+	return nil, nil
+}
+
+// CancelTransition ...
+func (s *EmptyServer) CancelTransition(context.Context, *drand.CancelTransitionRequest) (*drand.CancelTransitionResponse, error) {
+	return nil, nil
+}
+
+// Backup ...
+func (s *EmptyServer) Backup(*drand.BackupRequest, drand.Control_BackupServer) error {
+	return nil
+}
+
+// Peers ...
+func (s *EmptyServer) Peers(context.Context, *drand.PeersRequest) (*drand.PeersResponse, error) {
+	return nil, nil
+}
+
+// SyncStatus ...
+func (s *EmptyServer) SyncStatus(context.Context, *drand.SyncStatusRequest) (*drand.SyncStatusResponse, error) {
+	return nil, nil
+}
+
+// StartSync ...
+func (s *EmptyServer) StartSync(context.Context, *drand.StartSyncRequest) (*drand.SyncStatusResponse, error) {
+	return nil, nil
+}
@@ -232,6 +232,9 @@ func (d *drandProxy) Home(c context.Context, r *drand.HomeRequest, opts ...grpc.
 func (d *drandProxy) Group(c context.Context, r *drand.GroupRequest, opts ...grpc.CallOption) (*drand.GroupPacket, error) {
 	return d.r.Group(c, r)
 }
+func (d *drandProxy) GroupHistory(c context.Context, r *drand.GroupHistoryRequest, opts ...grpc.CallOption) (*drand.GroupHistoryResponse, error) {
+	return d.r.GroupHistory(c, r)
+}
 
 // grpcHandlerFunc returns an http.Handler that delegates to grpcServer on
 // incoming gRPC connections or otherHandler otherwise. Copied from cockroachdb.
@@ -245,21 +248,21 @@ func grpcHandlerFunc(grpcServer *grpc.Server, otherHandler http.Handler) http.Ha
 		if r.ProtoMajor == 2 && strings.Contains(r.Header.Get("Content-Type"), "application/grpc") {
 			grpcServer.ServeHTTP(w, r)
 		} else {
-			// record metrics for HTTP API endpoints
-			switch r.URL.Path {
-			case "/api":
+			// record metrics for HTTP API endpoints, the paths of the
+			// chains other than the default one start with their beacon id
+			path := r.URL.Path
+			switch {
+			case path == "/api":
 				metrics.APICallCounter.WithLabelValues("home").Inc()
-			case "/api/private":
+			case strings.HasSuffix(path, "/private"):
 				metrics.APICallCounter.WithLabelValues("private").Inc()
-			case "/api/info/distkey":
+			case strings.HasSuffix(path, "/info/distkey"):
 				metrics.APICallCounter.WithLabelValues("distkey").Inc()
-			case "/api/info/group":
+			case strings.HasSuffix(path, "/info/group"):
 				metrics.APICallCounter.WithLabelValues("group").Inc()
-			default:
-				// api/public can have additional path ServerParameters
-				if strings.Contains(r.URL.Path, "/api/public") {
-					metrics.APICallCounter.WithLabelValues("public").Inc()
-				}
+			case strings.Contains(path, "/public"):
+				// public can have additional path ServerParameters
+				metrics.APICallCounter.WithLabelValues("public").Inc()
 			}
 			otherHandler.ServeHTTP(w, r)
 		}
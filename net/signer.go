@@ -0,0 +1,85 @@
+package net
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+
+	control "github.com/drand/drand/protobuf/drand"
+	"github.com/nikkolasg/slog"
+	"google.golang.org/grpc"
+)
+
+// SignerListener serves the partial signatures of a signer process to its
+// node over a Unix socket
+type SignerListener struct {
+	conns *grpc.Server
+	lis   net.Listener
+}
+
+// NewUnixSignerListener listens on the Unix socket at the given path, only
+// reachable by the user running the signer.
+func NewUnixSignerListener(s control.SignerServer, path string) (*SignerListener, error) {
+	// a socket left by a previous signer is replaced, but nothing else is
+	if info, err := os.Lstat(path); err == nil {
+		if info.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("signer: %s exists and is not a socket", path)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	// the socket is created in a private folder and moved into place once
+	// its permissions are set, so no one else can connect in between
+	dir, err := ioutil.TempDir(filepath.Dir(path), ".drand-signer")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	tmp := filepath.Join(dir, "signer.sock")
+	lis, err := net.Listen("unix", tmp)
+	if err != nil {
+		return nil, err
+	}
+	// the socket is moved, the listener can't remove it when closed
+	lis.(*net.UnixListener).SetUnlinkOnClose(false)
+	if err := os.Chmod(tmp, 0600); err != nil {
+		lis.Close()
+		return nil, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		lis.Close()
+		return nil, err
+	}
+	grpcServer := grpc.NewServer()
+	control.RegisterSignerServer(grpcServer, s)
+	return &SignerListener{conns: grpcServer, lis: lis}, nil
+}
+
+// Start serves the signing requests
+func (s *SignerListener) Start() {
+	if err := s.conns.Serve(s.lis); err != nil {
+		slog.Fatalf("signer: failed to serve: %s", err)
+	}
+}
+
+// Stop the listener and connections
+func (s *SignerListener) Stop() {
+	s.conns.Stop()
+}
+
+// NewUnixSignerClient returns a client to the signer process listening on the
+// Unix socket at the given path.
+func NewUnixSignerClient(path string) (control.SignerClient, error) {
+	dialer := func(ctx context.Context, addr string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", addr)
+	}
+	conn, err := grpc.Dial(path, grpc.WithInsecure(), grpc.WithContextDialer(dialer))
+	if err != nil {
+		return nil, err
+	}
+	return control.NewSignerClient(conn), nil
+}
@@ -0,0 +1,81 @@
+package net
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/drand/drand/protobuf/drand"
+)
+
+// ProtocolMajor is the major version of the protocol drand nodes speak to each
+// other. Nodes with different major versions can't run a setup or a beacon
+// together. ProtocolMinor is bumped when optional features are added.
+const (
+	ProtocolMajor = 1
+	ProtocolMinor = 0
+)
+
+// Optional features of the protocol a node advertises in its metadata
+const (
+	// FeatureChainHash means the node checks groups with their version 2 hash
+	FeatureChainHash = "chain-hash"
+	// FeatureGroupHistory means the node serves the groups of its chain
+	FeatureGroupHistory = "group-history"
+	// FeatureTransitionSignature means the node signs the new group during a
+	// resharing
+	FeatureTransitionSignature = "transition-signature"
+)
+
+// Features lists the optional features this node supports
+var Features = []string{
+	FeatureChainHash,
+	FeatureGroupHistory,
+	FeatureTransitionSignature,
+}
+
+// NewMetadata returns the metadata describing the protocol this node speaks,
+// attached to the messages it sends to other nodes about the chain of the
+// given beacon ID.
+func NewMetadata(beaconID string) *drand.Metadata {
+	return &drand.Metadata{
+		Major:    ProtocolMajor,
+		Minor:    ProtocolMinor,
+		Features: Features,
+		BeaconID: beaconID,
+	}
+}
+
+// CheckMetadata returns an error if the node that sent the given metadata can't
+// run the protocol with this node. Nodes older than the versioning of the
+// protocol don't send any metadata.
+func CheckMetadata(m *drand.Metadata) error {
+	if m == nil {
+		return fmt.Errorf("peer runs an unversioned protocol, incompatible with v%d.%d", ProtocolMajor, ProtocolMinor)
+	}
+	if m.GetMajor() != ProtocolMajor {
+		return fmt.Errorf("peer runs protocol v%d.%d, incompatible with v%d.%d", m.GetMajor(), m.GetMinor(), ProtocolMajor, ProtocolMinor)
+	}
+	return nil
+}
+
+// HasFeature returns true if the metadata advertises the given feature
+func HasFeature(m *drand.Metadata, feature string) bool {
+	for _, f := range m.GetFeatures() {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
+// VersionString returns a human readable version of the metadata
+func VersionString(m *drand.Metadata) string {
+	if m == nil {
+		return "unversioned"
+	}
+	s := fmt.Sprintf("v%d.%d", m.GetMajor(), m.GetMinor())
+	if len(m.GetFeatures()) > 0 {
+		s += " (" + strings.Join(m.GetFeatures(), ", ") + ")"
+	}
+	return s
+}
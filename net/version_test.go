@@ -0,0 +1,37 @@
+package net
+
+import (
+	"testing"
+
+	"github.com/drand/drand/protobuf/drand"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionMetadata(t *testing.T) {
+	m := NewMetadata("beacon")
+	require.NoError(t, CheckMetadata(m))
+	require.Equal(t, "beacon", m.GetBeaconID())
+	for _, f := range Features {
+		require.True(t, HasFeature(m, f))
+	}
+	require.False(t, HasFeature(m, "unknown"))
+
+	// nodes older than the versioning send no metadata
+	require.Error(t, CheckMetadata(nil))
+	require.False(t, HasFeature(nil, FeatureChainHash))
+	require.Equal(t, "unversioned", VersionString(nil))
+
+	// a minor version advertises optional features only
+	minor := &drand.Metadata{Major: ProtocolMajor, Minor: ProtocolMinor + 1}
+	require.NoError(t, CheckMetadata(minor))
+	require.False(t, HasFeature(minor, FeatureChainHash))
+	require.Equal(t, "v1.1", VersionString(minor))
+
+	major := &drand.Metadata{Major: ProtocolMajor + 1}
+	require.Error(t, CheckMetadata(major))
+
+	require.Equal(t, "v1.0 (chain-hash)", VersionString(&drand.Metadata{
+		Major:    1,
+		Features: []string{FeatureChainHash},
+	}))
+}
@@ -89,7 +89,10 @@ type Deal struct {
 	// issue this deal, so another one is required. Best would be to merge vss
 	// and dkg so we could use only one field of signature. For future work...
 	// :)
-	Signature            []byte   `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	Signature []byte `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	// signature of the dealer over the session ID of its deal, which commits
+	// to its polynomial. It is kept in the transcript of the protocol.
+	CommitsSignature     []byte   `protobuf:"bytes,4,opt,name=commits_signature,json=commitsSignature,proto3" json:"commits_signature,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -141,6 +144,13 @@ func (m *Deal) GetSignature() []byte {
 	return nil
 }
 
+func (m *Deal) GetCommitsSignature() []byte {
+	if m != nil {
+		return m.CommitsSignature
+	}
+	return nil
+}
+
 // Response holds the response that a participant broadcast after having
 // received a deal.
 type Response struct {
@@ -255,7 +265,7 @@ func init() {
 }
 
 var fileDescriptor_2cd2862d3a18e91b = []byte{
-	// 250 bytes of a gzipped FileDescriptorProto
+	// 271 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x49, 0x2e, 0xaa, 0x2c,
 	0x28, 0xc9, 0xd7, 0x4f, 0xc9, 0x4e, 0x07, 0x61, 0xbd, 0x82, 0xa2, 0xfc, 0x92, 0x7c, 0x21, 0xe6,
 	0x94, 0xec, 0x74, 0x29, 0x98, 0x54, 0x59, 0x71, 0x31, 0x08, 0x43, 0xa4, 0x94, 0x7a, 0x18, 0xb9,
@@ -264,12 +274,13 @@ var fileDescriptor_2cd2862d3a18e91b = []byte{
 	0xc2, 0x42, 0x9a, 0x5c, 0x1c, 0x45, 0xa9, 0xc5, 0x05, 0xf9, 0x79, 0xc5, 0xa9, 0x12, 0x4c, 0x60,
 	0x25, 0xbc, 0x60, 0x25, 0x41, 0x50, 0xc1, 0x20, 0xb8, 0xb4, 0x90, 0x05, 0x17, 0x6f, 0x56, 0x69,
 	0x71, 0x49, 0x66, 0x5a, 0x66, 0x72, 0x62, 0x49, 0x66, 0x7e, 0x9e, 0x04, 0x33, 0x58, 0xbd, 0x10,
-	0x58, 0xbd, 0x17, 0xb2, 0x4c, 0x10, 0xaa, 0x42, 0xa5, 0x24, 0x2e, 0x16, 0x90, 0x95, 0x42, 0x22,
-	0x5c, 0xac, 0x99, 0x79, 0x29, 0xa9, 0x15, 0x60, 0xc7, 0xf0, 0x06, 0x41, 0x38, 0x42, 0x6a, 0x50,
-	0x17, 0x32, 0x41, 0x8d, 0x03, 0x79, 0xc3, 0x35, 0x0f, 0xec, 0xaf, 0xd4, 0x14, 0x24, 0xa7, 0xca,
-	0x70, 0x71, 0x16, 0x67, 0xa6, 0xe7, 0x25, 0x96, 0x94, 0x16, 0xa5, 0x82, 0xed, 0xe6, 0x09, 0x42,
-	0x08, 0x28, 0x79, 0x73, 0x71, 0xc0, 0xdc, 0x8c, 0xc3, 0x1e, 0x6c, 0x5e, 0x05, 0xd9, 0x85, 0xe9,
-	0x55, 0xa5, 0x78, 0x2e, 0x5e, 0x14, 0x0f, 0xe1, 0x30, 0x11, 0x23, 0x44, 0x90, 0xbd, 0x80, 0x2f,
-	0x44, 0x9c, 0x58, 0xa3, 0x40, 0xb1, 0x97, 0xc4, 0x06, 0x8e, 0x2e, 0x63, 0x40, 0x00, 0x00, 0x00,
-	0xff, 0xff, 0x01, 0x82, 0x40, 0x8e, 0xe1, 0x01, 0x00, 0x00,
+	0x58, 0xbd, 0x17, 0xb2, 0x4c, 0x10, 0xaa, 0x42, 0xa5, 0x7e, 0x46, 0x2e, 0x16, 0x90, 0x9d, 0x42,
+	0x22, 0x5c, 0xac, 0x99, 0x79, 0x29, 0xa9, 0x15, 0x60, 0xd7, 0xf0, 0x06, 0x41, 0x38, 0x42, 0x6a,
+	0x50, 0x27, 0x32, 0x41, 0xcd, 0x03, 0xf9, 0xc3, 0x35, 0x0f, 0xec, 0xb1, 0xd4, 0x14, 0x24, 0xb7,
+	0xca, 0x70, 0x71, 0x16, 0x67, 0xa6, 0xe7, 0x25, 0x96, 0x94, 0x16, 0xa5, 0x82, 0x2d, 0xe7, 0x09,
+	0x42, 0x08, 0x08, 0x69, 0x73, 0x09, 0x26, 0xe7, 0xe7, 0xe6, 0x66, 0x96, 0x14, 0xc7, 0x23, 0x54,
+	0xb1, 0x80, 0x55, 0x09, 0x40, 0x25, 0x82, 0x61, 0xe2, 0x4a, 0xde, 0x5c, 0x1c, 0x30, 0x1f, 0xe2,
+	0x70, 0x14, 0xb6, 0x80, 0x01, 0x39, 0x0c, 0x33, 0x60, 0x94, 0xe2, 0xb9, 0x78, 0x51, 0xbc, 0x8f,
+	0xc3, 0x44, 0x8c, 0xf0, 0x43, 0xf6, 0x2f, 0xbe, 0xf0, 0x73, 0x62, 0x8d, 0x02, 0xc5, 0x75, 0x12,
+	0x1b, 0x38, 0x72, 0x8d, 0x01, 0x03, 0x00, 0x59, 0xb3, 0x28, 0x3c, 0x0f, 0x02, 0x00, 0x00,
 }
@@ -30,7 +30,9 @@ const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 type PublicRandRequest struct {
 	// round uniquely identifies a beacon. If round == 0 (or unspecified), then
 	// the response will contain the last.
-	Round                uint64   `protobuf:"varint,1,opt,name=round,proto3" json:"round,omitempty"`
+	Round uint64 `protobuf:"varint,1,opt,name=round,proto3" json:"round,omitempty"`
+	// beaconID identifies the chain, empty for the default one
+	BeaconID             string   `protobuf:"bytes,2,opt,name=beaconID,proto3" json:"beaconID,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -68,6 +70,13 @@ func (m *PublicRandRequest) GetRound() uint64 {
 	return 0
 }
 
+func (m *PublicRandRequest) GetBeaconID() string {
+	if m != nil {
+		return m.BeaconID
+	}
+	return ""
+}
+
 // PublicRandResponse holds a signature which is the random value. It can be
 // verified thanks to the distributed public key of the nodes that have ran the
 // DKG protocol and is unbiasable. The randomness can be verified using the BLS
@@ -142,7 +151,9 @@ func (m *PublicRandResponse) GetRandomness() []byte {
 type PrivateRandRequest struct {
 	// Request must contains a public key towards which to encrypt the private
 	// randomness.
-	Request              *ECIES   `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"`
+	Request *ECIES `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"`
+	// beaconID identifies the chain, empty for the default one
+	BeaconID             string   `protobuf:"bytes,3,opt,name=beaconID,proto3" json:"beaconID,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -180,6 +191,13 @@ func (m *PrivateRandRequest) GetRequest() *ECIES {
 	return nil
 }
 
+func (m *PrivateRandRequest) GetBeaconID() string {
+	if m != nil {
+		return m.BeaconID
+	}
+	return ""
+}
+
 type PrivateRandResponse struct {
 	// Response contains the private randomness encrypted towards the client's
 	// request key.
@@ -278,6 +296,8 @@ func (m *ECIES) GetNonce() []byte {
 
 // DistKeyRequest requests the distributed public key used during the randomness generation process
 type DistKeyRequest struct {
+	// beaconID identifies the chain, empty for the default one
+	BeaconID             string   `protobuf:"bytes,1,opt,name=beaconID,proto3" json:"beaconID,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -308,6 +328,13 @@ func (m *DistKeyRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_DistKeyRequest proto.InternalMessageInfo
 
+func (m *DistKeyRequest) GetBeaconID() string {
+	if m != nil {
+		return m.BeaconID
+	}
+	return ""
+}
+
 type DistKeyResponse struct {
 	Key                  []byte   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -347,7 +374,138 @@ func (m *DistKeyResponse) GetKey() []byte {
 	return nil
 }
 
+type GroupHistoryRequest struct {
+	// beaconID identifies the chain, empty for the default one
+	BeaconID             string   `protobuf:"bytes,1,opt,name=beaconID,proto3" json:"beaconID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GroupHistoryRequest) Reset()         { *m = GroupHistoryRequest{} }
+func (m *GroupHistoryRequest) String() string { return proto.CompactTextString(m) }
+func (*GroupHistoryRequest) ProtoMessage()    {}
+func (*GroupHistoryRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c0cff3fc81cf7d79, []int{7}
+}
+
+func (m *GroupHistoryRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GroupHistoryRequest.Unmarshal(m, b)
+}
+func (m *GroupHistoryRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GroupHistoryRequest.Marshal(b, m, deterministic)
+}
+func (m *GroupHistoryRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GroupHistoryRequest.Merge(m, src)
+}
+func (m *GroupHistoryRequest) XXX_Size() int {
+	return xxx_messageInfo_GroupHistoryRequest.Size(m)
+}
+func (m *GroupHistoryRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GroupHistoryRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GroupHistoryRequest proto.InternalMessageInfo
+
+func (m *GroupHistoryRequest) GetBeaconID() string {
+	if m != nil {
+		return m.BeaconID
+	}
+	return ""
+}
+
+// GroupHistoryResponse holds the groups of the chain, ordered by starting
+// round. Clients use it to know which group produced an older round.
+type GroupHistoryResponse struct {
+	Groups               []*HistoryGroup `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *GroupHistoryResponse) Reset()         { *m = GroupHistoryResponse{} }
+func (m *GroupHistoryResponse) String() string { return proto.CompactTextString(m) }
+func (*GroupHistoryResponse) ProtoMessage()    {}
+func (*GroupHistoryResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c0cff3fc81cf7d79, []int{8}
+}
+
+func (m *GroupHistoryResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GroupHistoryResponse.Unmarshal(m, b)
+}
+func (m *GroupHistoryResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GroupHistoryResponse.Marshal(b, m, deterministic)
+}
+func (m *GroupHistoryResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GroupHistoryResponse.Merge(m, src)
+}
+func (m *GroupHistoryResponse) XXX_Size() int {
+	return xxx_messageInfo_GroupHistoryResponse.Size(m)
+}
+func (m *GroupHistoryResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GroupHistoryResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GroupHistoryResponse proto.InternalMessageInfo
+
+func (m *GroupHistoryResponse) GetGroups() []*HistoryGroup {
+	if m != nil {
+		return m.Groups
+	}
+	return nil
+}
+
+type HistoryGroup struct {
+	// start_round is the first round the group produced
+	StartRound           uint64       `protobuf:"varint,1,opt,name=start_round,json=startRound,proto3" json:"start_round,omitempty"`
+	Group                *GroupPacket `protobuf:"bytes,2,opt,name=group,proto3" json:"group,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *HistoryGroup) Reset()         { *m = HistoryGroup{} }
+func (m *HistoryGroup) String() string { return proto.CompactTextString(m) }
+func (*HistoryGroup) ProtoMessage()    {}
+func (*HistoryGroup) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c0cff3fc81cf7d79, []int{9}
+}
+
+func (m *HistoryGroup) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HistoryGroup.Unmarshal(m, b)
+}
+func (m *HistoryGroup) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HistoryGroup.Marshal(b, m, deterministic)
+}
+func (m *HistoryGroup) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HistoryGroup.Merge(m, src)
+}
+func (m *HistoryGroup) XXX_Size() int {
+	return xxx_messageInfo_HistoryGroup.Size(m)
+}
+func (m *HistoryGroup) XXX_DiscardUnknown() {
+	xxx_messageInfo_HistoryGroup.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HistoryGroup proto.InternalMessageInfo
+
+func (m *HistoryGroup) GetStartRound() uint64 {
+	if m != nil {
+		return m.StartRound
+	}
+	return 0
+}
+
+func (m *HistoryGroup) GetGroup() *GroupPacket {
+	if m != nil {
+		return m.Group
+	}
+	return nil
+}
+
 type HomeRequest struct {
+	// beaconID identifies the chain, empty for the default one
+	BeaconID             string   `protobuf:"bytes,1,opt,name=beaconID,proto3" json:"beaconID,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -357,7 +515,7 @@ func (m *HomeRequest) Reset()         { *m = HomeRequest{} }
 func (m *HomeRequest) String() string { return proto.CompactTextString(m) }
 func (*HomeRequest) ProtoMessage()    {}
 func (*HomeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c0cff3fc81cf7d79, []int{7}
+	return fileDescriptor_c0cff3fc81cf7d79, []int{10}
 }
 
 func (m *HomeRequest) XXX_Unmarshal(b []byte) error {
@@ -378,18 +536,27 @@ func (m *HomeRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_HomeRequest proto.InternalMessageInfo
 
+func (m *HomeRequest) GetBeaconID() string {
+	if m != nil {
+		return m.BeaconID
+	}
+	return ""
+}
+
 type HomeResponse struct {
-	Status               string   `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Status string `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	// metadata describes the protocol version of the node
+	Metadata             *Metadata `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
 }
 
 func (m *HomeResponse) Reset()         { *m = HomeResponse{} }
 func (m *HomeResponse) String() string { return proto.CompactTextString(m) }
 func (*HomeResponse) ProtoMessage()    {}
 func (*HomeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c0cff3fc81cf7d79, []int{8}
+	return fileDescriptor_c0cff3fc81cf7d79, []int{11}
 }
 
 func (m *HomeResponse) XXX_Unmarshal(b []byte) error {
@@ -417,6 +584,13 @@ func (m *HomeResponse) GetStatus() string {
 	return ""
 }
 
+func (m *HomeResponse) GetMetadata() *Metadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
 // Node represents the information about a drand's node
 type Node struct {
 	Address              string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
@@ -431,7 +605,7 @@ func (m *Node) Reset()         { *m = Node{} }
 func (m *Node) String() string { return proto.CompactTextString(m) }
 func (*Node) ProtoMessage()    {}
 func (*Node) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c0cff3fc81cf7d79, []int{9}
+	return fileDescriptor_c0cff3fc81cf7d79, []int{12}
 }
 
 func (m *Node) XXX_Unmarshal(b []byte) error {
@@ -481,6 +655,9 @@ func init() {
 	proto.RegisterType((*ECIES)(nil), "drand.ECIES")
 	proto.RegisterType((*DistKeyRequest)(nil), "drand.DistKeyRequest")
 	proto.RegisterType((*DistKeyResponse)(nil), "drand.DistKeyResponse")
+	proto.RegisterType((*GroupHistoryRequest)(nil), "drand.GroupHistoryRequest")
+	proto.RegisterType((*GroupHistoryResponse)(nil), "drand.GroupHistoryResponse")
+	proto.RegisterType((*HistoryGroup)(nil), "drand.HistoryGroup")
 	proto.RegisterType((*HomeRequest)(nil), "drand.HomeRequest")
 	proto.RegisterType((*HomeResponse)(nil), "drand.HomeResponse")
 	proto.RegisterType((*Node)(nil), "drand.Node")
@@ -491,44 +668,58 @@ func init() {
 }
 
 var fileDescriptor_c0cff3fc81cf7d79 = []byte{
-	// 592 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x54, 0xdf, 0x6a, 0x13, 0x4f,
-	0x14, 0x66, 0xdb, 0x4d, 0xd2, 0x9e, 0xec, 0xef, 0xd7, 0xe6, 0xc4, 0xd6, 0x74, 0x29, 0x52, 0x56,
-	0x29, 0xb5, 0x60, 0x23, 0xf5, 0x46, 0x44, 0x11, 0xb4, 0x45, 0x8b, 0x22, 0x65, 0xe3, 0x55, 0x44,
-	0x64, 0x9a, 0x1d, 0xd3, 0xa5, 0xd9, 0x99, 0x75, 0x66, 0xb6, 0x58, 0xc4, 0x1b, 0x7d, 0x04, 0x2f,
-	0x7c, 0x18, 0x1f, 0xc3, 0x57, 0xf0, 0x41, 0x64, 0xfe, 0x6c, 0x76, 0xd3, 0xe4, 0xca, 0xbb, 0x39,
-	0xdf, 0x39, 0xe7, 0x3b, 0xdf, 0x39, 0x7c, 0x0c, 0xac, 0x25, 0x82, 0xb0, 0xa4, 0x4f, 0xf2, 0xf4,
-	0x20, 0x17, 0x5c, 0x71, 0x6c, 0x18, 0x20, 0xdc, 0x1e, 0x73, 0x3e, 0x9e, 0x50, 0x9d, 0xe8, 0x13,
-	0xc6, 0xb8, 0x22, 0x2a, 0xe5, 0x4c, 0xda, 0xa2, 0x10, 0x6d, 0xd7, 0x88, 0x67, 0x19, 0x67, 0x16,
-	0x8b, 0xee, 0x42, 0xe7, 0xb4, 0x38, 0x9b, 0xa4, 0xa3, 0x98, 0xb0, 0x24, 0xa6, 0x9f, 0x0a, 0x2a,
-	0x15, 0xde, 0x80, 0x86, 0xe0, 0x05, 0x4b, 0x7a, 0xde, 0x8e, 0xb7, 0xe7, 0xc7, 0x36, 0x88, 0x7e,
-	0x7a, 0x80, 0xf5, 0x5a, 0x99, 0x73, 0x26, 0xe9, 0xe2, 0x62, 0xdc, 0x86, 0x55, 0x99, 0x8e, 0x19,
-	0x51, 0x85, 0xa0, 0xbd, 0xa5, 0x1d, 0x6f, 0x2f, 0x88, 0x2b, 0x00, 0xef, 0x01, 0xe6, 0x82, 0x5e,
-	0xa6, 0xbc, 0x90, 0x1f, 0xaa, 0xb2, 0x65, 0x53, 0xd6, 0x29, 0x33, 0x83, 0x69, 0xf9, 0x2d, 0x00,
-	0xad, 0x9c, 0x67, 0x8c, 0x4a, 0xd9, 0xf3, 0x4d, 0x59, 0x0d, 0x89, 0x1e, 0x03, 0x9e, 0x8a, 0xf4,
-	0x92, 0x28, 0x5a, 0xdf, 0x62, 0x17, 0x5a, 0xc2, 0x3e, 0x8d, 0x80, 0xf6, 0x61, 0x70, 0x60, 0x0e,
-	0x70, 0x70, 0xfc, 0xfc, 0xe4, 0x78, 0x10, 0x97, 0xc9, 0xe8, 0x29, 0x74, 0x67, 0xba, 0xdd, 0x5e,
-	0x7b, 0xb0, 0x22, 0xdc, 0xdb, 0xac, 0x76, 0xbd, 0x7f, 0x9a, 0x8d, 0xde, 0x41, 0xc3, 0x40, 0x7a,
-	0x69, 0x9a, 0x9f, 0xd3, 0x8c, 0x0a, 0x32, 0x31, 0x3d, 0x41, 0x5c, 0x01, 0x7a, 0x8b, 0x51, 0x9a,
-	0x9f, 0x53, 0xa1, 0xe8, 0x67, 0xe5, 0x6e, 0x52, 0x43, 0xf4, 0x21, 0x19, 0x67, 0xa3, 0xf2, 0x0e,
-	0x36, 0x88, 0xd6, 0xe1, 0xff, 0xa3, 0x54, 0xaa, 0x57, 0xf4, 0xca, 0xed, 0x15, 0xdd, 0x86, 0xb5,
-	0x29, 0xe2, 0xb4, 0xae, 0xc3, 0xf2, 0x05, 0xbd, 0x72, 0x9c, 0xfa, 0x19, 0xfd, 0x07, 0xed, 0x97,
-	0x3c, 0xa3, 0x65, 0xcf, 0x2e, 0x04, 0x36, 0x74, 0x0d, 0x9b, 0xd0, 0x94, 0x8a, 0xa8, 0x42, 0x1a,
-	0x99, 0xab, 0xb1, 0x8b, 0xa2, 0x23, 0xf0, 0xdf, 0xf0, 0x84, 0x62, 0x0f, 0x5a, 0x24, 0x49, 0x84,
-	0x3e, 0xb7, 0x2d, 0x28, 0xc3, 0xfa, 0xa8, 0x55, 0x33, 0x4a, 0x23, 0x6f, 0x5f, 0x0f, 0x8c, 0xea,
-	0x95, 0x58, 0x3f, 0x0f, 0x7f, 0xf9, 0xd0, 0xb4, 0x4e, 0xc1, 0x0c, 0xa0, 0xf2, 0x0c, 0xf6, 0xdc,
-	0x05, 0xe7, 0x2c, 0x17, 0x6e, 0x2d, 0xc8, 0xb8, 0xf3, 0xee, 0x7f, 0xfb, 0xfd, 0xe7, 0xc7, 0xd2,
-	0x1d, 0x6c, 0x1b, 0x5b, 0xe7, 0xa6, 0x60, 0xb8, 0x81, 0xdd, 0x5a, 0xd8, 0xff, 0x62, 0x5c, 0xf7,
-	0x15, 0xbf, 0x7b, 0xb0, 0x5e, 0x51, 0x0c, 0x94, 0xa0, 0x24, 0xfb, 0xb7, 0xa9, 0x0f, 0xcd, 0xd4,
-	0x43, 0xc4, 0xfa, 0x18, 0x69, 0x08, 0x87, 0xdb, 0x18, 0xce, 0xa3, 0xa5, 0x86, 0xfb, 0x1e, 0xbe,
-	0x87, 0x76, 0xcd, 0x51, 0x38, 0x9d, 0x32, 0xe7, 0xd1, 0x30, 0x5c, 0x94, 0x72, 0x0a, 0x6e, 0x1a,
-	0x05, 0x9d, 0x28, 0xb0, 0xb3, 0x6c, 0xc5, 0x23, 0x6f, 0x1f, 0x4f, 0xa0, 0xf1, 0x42, 0xf0, 0x22,
-	0xc7, 0xae, 0xeb, 0x36, 0x51, 0x49, 0x89, 0x75, 0xf0, 0x94, 0x8c, 0x2e, 0xa8, 0x2a, 0xa9, 0x70,
-	0xcd, 0x50, 0xa5, 0xec, 0x23, 0xef, 0x8f, 0x0d, 0xc3, 0x00, 0x5a, 0xce, 0x4b, 0xb8, 0xe1, 0xfa,
-	0x66, 0xdd, 0x16, 0x6e, 0x5e, 0x87, 0x9d, 0xba, 0x2d, 0x43, 0xd9, 0xc5, 0x4e, 0x45, 0x99, 0xa4,
-	0x52, 0x69, 0x43, 0x3c, 0x01, 0x5f, 0x9b, 0x0d, 0x4b, 0x25, 0x35, 0x23, 0x86, 0xdd, 0x19, 0xcc,
-	0x71, 0x05, 0x86, 0xab, 0x89, 0xbe, 0xe6, 0x7a, 0xd6, 0x1a, 0xda, 0xdf, 0xec, 0xac, 0x69, 0xbe,
-	0xa8, 0x07, 0x7f, 0x03, 0x00, 0x00, 0xff, 0xff, 0x03, 0xca, 0x67, 0x53, 0xee, 0x04, 0x00, 0x00,
+	// 806 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0xdd, 0x4e, 0xdb, 0x48,
+	0x14, 0x96, 0xc9, 0x2f, 0x27, 0xd1, 0x86, 0x9c, 0x00, 0x6b, 0x4c, 0x80, 0xc8, 0xab, 0xdd, 0xcd,
+	0x2e, 0xbb, 0x64, 0x01, 0xad, 0x56, 0xbb, 0x37, 0x2b, 0x2d, 0xa0, 0x05, 0xf5, 0x47, 0x91, 0xd3,
+	0x8b, 0xd6, 0xbd, 0xa0, 0x43, 0x3c, 0x04, 0x0b, 0xe2, 0x71, 0x3d, 0x13, 0x54, 0x44, 0x91, 0x2a,
+	0x5e, 0xa1, 0x17, 0x7d, 0x86, 0x3e, 0x40, 0x5f, 0xa3, 0x37, 0x7d, 0x85, 0x3e, 0x48, 0xe5, 0xf1,
+	0xd8, 0x71, 0x12, 0x53, 0xa1, 0xde, 0xcd, 0x7c, 0xe7, 0x3b, 0xe7, 0x3b, 0xe7, 0x8c, 0xcf, 0x31,
+	0xd4, 0x9c, 0x80, 0x78, 0x4e, 0x87, 0xf8, 0xee, 0x96, 0x1f, 0x30, 0xc1, 0xb0, 0x20, 0x01, 0xa3,
+	0x39, 0x60, 0x6c, 0x70, 0x41, 0x43, 0x43, 0x87, 0x78, 0x1e, 0x13, 0x44, 0xb8, 0xcc, 0xe3, 0x11,
+	0xc9, 0xc0, 0xc8, 0xab, 0xcf, 0x86, 0x43, 0xe6, 0x45, 0x98, 0x79, 0x00, 0xf5, 0xee, 0xe8, 0xe4,
+	0xc2, 0xed, 0x5b, 0xc4, 0x73, 0x2c, 0xfa, 0x72, 0x44, 0xb9, 0xc0, 0x45, 0x28, 0x04, 0x6c, 0xe4,
+	0x39, 0xba, 0xd6, 0xd2, 0xda, 0x79, 0x2b, 0xba, 0xa0, 0x01, 0xe5, 0x13, 0x4a, 0xfa, 0xcc, 0x3b,
+	0xda, 0xd7, 0xe7, 0x5a, 0x5a, 0x7b, 0xde, 0x4a, 0xee, 0xe6, 0x3b, 0x0d, 0x30, 0x1d, 0x87, 0xfb,
+	0xcc, 0xe3, 0xf4, 0x8e, 0x40, 0x4d, 0x98, 0xe7, 0xee, 0xc0, 0x23, 0x62, 0x14, 0x50, 0x19, 0xa9,
+	0x6a, 0x8d, 0x01, 0xfc, 0x1d, 0xd0, 0x0f, 0xe8, 0xa5, 0xcb, 0x46, 0xfc, 0x78, 0x4c, 0xcb, 0x49,
+	0x5a, 0x3d, 0xb6, 0xf4, 0x12, 0xfa, 0x3a, 0x40, 0x58, 0x15, 0x1b, 0x7a, 0x94, 0x73, 0x3d, 0x2f,
+	0x69, 0x29, 0xc4, 0x7c, 0x0a, 0xd8, 0x0d, 0xdc, 0x4b, 0x22, 0x68, 0xba, 0xc2, 0x9f, 0xa0, 0x14,
+	0x44, 0x47, 0x99, 0x40, 0x65, 0xa7, 0xba, 0x25, 0x9b, 0xb3, 0x75, 0xb0, 0x77, 0x74, 0xd0, 0xb3,
+	0x62, 0xe3, 0x44, 0xcd, 0xb9, 0xa9, 0x9a, 0xff, 0x85, 0xc6, 0x44, 0x64, 0x55, 0x73, 0x1b, 0xca,
+	0x81, 0x3a, 0xeb, 0x5a, 0x46, 0xec, 0xc4, 0x6a, 0x3e, 0x87, 0x82, 0x84, 0xc2, 0x86, 0x50, 0xff,
+	0x8c, 0x0e, 0x69, 0x40, 0x2e, 0xa4, 0x4f, 0xd5, 0x1a, 0x03, 0x61, 0x85, 0x7d, 0xd7, 0x3f, 0xa3,
+	0x81, 0xa0, 0xaf, 0x84, 0xea, 0x57, 0x0a, 0x09, 0x9b, 0xec, 0x31, 0xaf, 0x1f, 0xf7, 0x28, 0xba,
+	0x98, 0xbf, 0xc1, 0x77, 0xfb, 0x2e, 0x17, 0x0f, 0xe8, 0x95, 0x95, 0x51, 0x8b, 0x36, 0x55, 0xcb,
+	0x0f, 0x50, 0x4b, 0xd8, 0xaa, 0x8e, 0x05, 0xc8, 0x9d, 0xd3, 0x2b, 0xa5, 0x17, 0x1e, 0xcd, 0x6d,
+	0x68, 0xfc, 0x1f, 0xb0, 0x91, 0x7f, 0xe8, 0x72, 0xc1, 0x82, 0x7b, 0xc5, 0xdd, 0x83, 0xc5, 0x49,
+	0x17, 0x15, 0x7c, 0x13, 0x8a, 0x83, 0x10, 0xe7, 0xba, 0xd6, 0xca, 0xb5, 0x2b, 0x3b, 0x0d, 0xd5,
+	0x22, 0xc5, 0x93, 0x3e, 0x96, 0xa2, 0x98, 0xcf, 0xa0, 0x9a, 0xc6, 0x71, 0x03, 0x2a, 0x5c, 0x90,
+	0x40, 0x1c, 0xa7, 0xbf, 0x2d, 0x90, 0x90, 0x15, 0x22, 0xd8, 0x86, 0x82, 0x74, 0x55, 0x6f, 0x8b,
+	0x2a, 0xb8, 0xf4, 0xee, 0x92, 0xfe, 0x39, 0x15, 0x56, 0x44, 0x30, 0x7f, 0x81, 0xca, 0x21, 0x1b,
+	0xd2, 0xfb, 0x94, 0xd2, 0x83, 0x6a, 0x44, 0x55, 0x25, 0x2c, 0x43, 0x91, 0x0b, 0x22, 0x46, 0x5c,
+	0x31, 0xd5, 0x0d, 0x37, 0xa1, 0x3c, 0xa4, 0x82, 0x38, 0x44, 0x10, 0xa5, 0x5f, 0x53, 0xfa, 0x8f,
+	0x14, 0x6c, 0x25, 0x04, 0x73, 0x1f, 0xf2, 0x8f, 0x99, 0x43, 0x51, 0x87, 0x12, 0x71, 0x9c, 0x80,
+	0xf2, 0x38, 0x5a, 0x7c, 0x4d, 0x3f, 0xc3, 0xbc, 0x7c, 0x86, 0x10, 0x79, 0xf2, 0xb0, 0x27, 0x5f,
+	0xbb, 0x6c, 0x85, 0xc7, 0x9d, 0x0f, 0x25, 0x28, 0x46, 0xd3, 0x87, 0xef, 0x35, 0x80, 0xf1, 0x20,
+	0xa2, 0xae, 0xa4, 0x67, 0x66, 0xdc, 0x58, 0xc9, 0xb0, 0xa8, 0xef, 0xf2, 0xf4, 0xf6, 0xd3, 0xe7,
+	0xb7, 0x73, 0x2f, 0xb0, 0x22, 0xf7, 0x88, 0x2f, 0x09, 0xf6, 0x12, 0x36, 0x52, 0xd7, 0xce, 0xb5,
+	0xec, 0xfd, 0x8d, 0xad, 0xe3, 0xb2, 0x84, 0xaf, 0xe3, 0x3e, 0xdd, 0xc4, 0x0e, 0x2d, 0x5c, 0xcf,
+	0xb6, 0xc4, 0xbe, 0xf8, 0x51, 0x83, 0x85, 0xb1, 0x7c, 0x4f, 0x04, 0x94, 0x0c, 0xbf, 0x2d, 0xe3,
+	0x5b, 0x4d, 0xa6, 0xfc, 0xda, 0x6e, 0xa2, 0x91, 0xce, 0x92, 0xcb, 0x98, 0x49, 0xb2, 0x1b, 0xb8,
+	0x76, 0x47, 0x4a, 0x11, 0xd1, 0xfe, 0x19, 0x7f, 0xfc, 0x2a, 0x21, 0x49, 0x1d, 0x67, 0x55, 0xfe,
+	0xd0, 0xf0, 0x1a, 0x2a, 0xa9, 0x8d, 0x80, 0x49, 0xc2, 0x33, 0xfb, 0xc7, 0x30, 0xb2, 0x4c, 0xaa,
+	0x98, 0x3f, 0x65, 0x2d, 0x1d, 0xbb, 0x69, 0x7e, 0x3f, 0x93, 0x4c, 0x44, 0xff, 0x47, 0xfb, 0xd5,
+	0xac, 0x46, 0x19, 0x24, 0x00, 0x0e, 0xa0, 0x10, 0x8d, 0x47, 0x23, 0xfd, 0xb9, 0xc7, 0x82, 0x19,
+	0x33, 0x10, 0x0b, 0x61, 0x4d, 0x86, 0x72, 0xbd, 0x53, 0xd6, 0x91, 0x53, 0x91, 0x74, 0x31, 0xa5,
+	0x3c, 0xb6, 0xe2, 0x1b, 0x0d, 0xaa, 0xe9, 0xa1, 0x46, 0x23, 0x1d, 0x7b, 0x72, 0x39, 0x18, 0xab,
+	0x99, 0x36, 0x55, 0xe9, 0x5f, 0x32, 0x81, 0x6d, 0x5c, 0x98, 0x4a, 0x80, 0xdb, 0x6b, 0xb8, 0x7a,
+	0x77, 0x06, 0x1c, 0x39, 0x94, 0xd4, 0xba, 0xc2, 0x25, 0x25, 0x30, 0xb9, 0xec, 0x8c, 0xe5, 0x69,
+	0x58, 0x49, 0xfe, 0x2d, 0x25, 0x77, 0xb1, 0x3e, 0x96, 0x74, 0x5c, 0x2e, 0xce, 0xe9, 0x95, 0xbd,
+	0x8e, 0xcd, 0x4c, 0x4d, 0x65, 0xc7, 0x2e, 0xe4, 0xc3, 0x05, 0x80, 0x71, 0x2b, 0x53, 0x8b, 0xc3,
+	0x68, 0x4c, 0x60, 0x4a, 0x6b, 0x43, 0x6a, 0xad, 0x60, 0x3e, 0x0c, 0x6b, 0xd7, 0xb1, 0x36, 0x15,
+	0xfe, 0xbf, 0x92, 0x1d, 0xfd, 0xb7, 0x4f, 0x8a, 0xf2, 0x67, 0xbc, 0xfb, 0x65, 0x00, 0xb5, 0xc2,
+	0x19, 0x53, 0xd8, 0x07, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -553,6 +744,9 @@ type PublicClient interface {
 	// Group is the method that returns the group descrition that the drand
 	// endpoint belongs to
 	Group(ctx context.Context, in *GroupRequest, opts ...grpc.CallOption) (*GroupPacket, error)
+	// GroupHistory returns the groups that produced the chain of the drand
+	// endpoint, along with the first round each of them produced
+	GroupHistory(ctx context.Context, in *GroupHistoryRequest, opts ...grpc.CallOption) (*GroupHistoryResponse, error)
 	// DistKey returns the distributed key from which drand node endpoint get a share
 	DistKey(ctx context.Context, in *DistKeyRequest, opts ...grpc.CallOption) (*DistKeyResponse, error)
 	// Home is a simple endpoint
@@ -626,6 +820,15 @@ func (c *publicClient) Group(ctx context.Context, in *GroupRequest, opts ...grpc
 	return out, nil
 }
 
+func (c *publicClient) GroupHistory(ctx context.Context, in *GroupHistoryRequest, opts ...grpc.CallOption) (*GroupHistoryResponse, error) {
+	out := new(GroupHistoryResponse)
+	err := c.cc.Invoke(ctx, "/drand.Public/GroupHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *publicClient) DistKey(ctx context.Context, in *DistKeyRequest, opts ...grpc.CallOption) (*DistKeyResponse, error) {
 	out := new(DistKeyResponse)
 	err := c.cc.Invoke(ctx, "/drand.Public/DistKey", in, out, opts...)
@@ -656,6 +859,9 @@ type PublicServer interface {
 	// Group is the method that returns the group descrition that the drand
 	// endpoint belongs to
 	Group(context.Context, *GroupRequest) (*GroupPacket, error)
+	// GroupHistory returns the groups that produced the chain of the drand
+	// endpoint, along with the first round each of them produced
+	GroupHistory(context.Context, *GroupHistoryRequest) (*GroupHistoryResponse, error)
 	// DistKey returns the distributed key from which drand node endpoint get a share
 	DistKey(context.Context, *DistKeyRequest) (*DistKeyResponse, error)
 	// Home is a simple endpoint
@@ -678,6 +884,9 @@ func (*UnimplementedPublicServer) PrivateRand(ctx context.Context, req *PrivateR
 func (*UnimplementedPublicServer) Group(ctx context.Context, req *GroupRequest) (*GroupPacket, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Group not implemented")
 }
+func (*UnimplementedPublicServer) GroupHistory(ctx context.Context, req *GroupHistoryRequest) (*GroupHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GroupHistory not implemented")
+}
 func (*UnimplementedPublicServer) DistKey(ctx context.Context, req *DistKeyRequest) (*DistKeyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DistKey not implemented")
 }
@@ -764,6 +973,24 @@ func _Public_Group_Handler(srv interface{}, ctx context.Context, dec func(interf
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Public_GroupHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PublicServer).GroupHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/drand.Public/GroupHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PublicServer).GroupHistory(ctx, req.(*GroupHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Public_DistKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DistKeyRequest)
 	if err := dec(in); err != nil {
@@ -816,6 +1043,10 @@ var _Public_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Group",
 			Handler:    _Public_Group_Handler,
 		},
+		{
+			MethodName: "GroupHistory",
+			Handler:    _Public_GroupHistory_Handler,
+		},
 		{
 			MethodName: "DistKey",
 			Handler:    _Public_DistKey_Handler,
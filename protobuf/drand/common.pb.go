@@ -53,12 +53,17 @@ var xxx_messageInfo_Empty proto.InternalMessageInfo
 
 // Identity holds the necessary information to contact a drand node
 type Identity struct {
-	Address              string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
-	Key                  []byte   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
-	Tls                  bool     `protobuf:"varint,3,opt,name=tls,proto3" json:"tls,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Key     []byte `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Tls     bool   `protobuf:"varint,3,opt,name=tls,proto3" json:"tls,omitempty"`
+	// signature over the identity and the parameters of the group setup it
+	// is sent for, proving the possession of the private key
+	Signature []byte `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	// optional description of who runs the node
+	Operator             *Operator `protobuf:"bytes,5,opt,name=operator,proto3" json:"operator,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
 }
 
 func (m *Identity) Reset()         { *m = Identity{} }
@@ -107,16 +112,109 @@ func (m *Identity) GetTls() bool {
 	return false
 }
 
+func (m *Identity) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *Identity) GetOperator() *Operator {
+	if m != nil {
+		return m.Operator
+	}
+	return nil
+}
+
+// Operator describes who runs a node, signed with the key of the node
+type Operator struct {
+	Name    string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Contact string `protobuf:"bytes,2,opt,name=contact,proto3" json:"contact,omitempty"`
+	Region  string `protobuf:"bytes,3,opt,name=region,proto3" json:"region,omitempty"`
+	Website string `protobuf:"bytes,4,opt,name=website,proto3" json:"website,omitempty"`
+	// signature over the metadata, the address and the key of the node
+	Signature            []byte   `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Operator) Reset()         { *m = Operator{} }
+func (m *Operator) String() string { return proto.CompactTextString(m) }
+func (*Operator) ProtoMessage()    {}
+func (*Operator) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e3db314147ee7469, []int{2}
+}
+
+func (m *Operator) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Operator.Unmarshal(m, b)
+}
+func (m *Operator) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Operator.Marshal(b, m, deterministic)
+}
+func (m *Operator) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Operator.Merge(m, src)
+}
+func (m *Operator) XXX_Size() int {
+	return xxx_messageInfo_Operator.Size(m)
+}
+func (m *Operator) XXX_DiscardUnknown() {
+	xxx_messageInfo_Operator.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Operator proto.InternalMessageInfo
+
+func (m *Operator) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Operator) GetContact() string {
+	if m != nil {
+		return m.Contact
+	}
+	return ""
+}
+
+func (m *Operator) GetRegion() string {
+	if m != nil {
+		return m.Region
+	}
+	return ""
+}
+
+func (m *Operator) GetWebsite() string {
+	if m != nil {
+		return m.Website
+	}
+	return ""
+}
+
+func (m *Operator) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
 // GroupPacket represents a group
 type GroupPacket struct {
 	Nodes     []*Identity `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
 	Threshold uint32      `protobuf:"varint,2,opt,name=threshold,proto3" json:"threshold,omitempty"`
 	// period in seconds
-	Period               uint32   `protobuf:"varint,3,opt,name=period,proto3" json:"period,omitempty"`
-	GenesisTime          uint64   `protobuf:"varint,4,opt,name=genesis_time,json=genesisTime,proto3" json:"genesis_time,omitempty"`
-	TransitionTime       uint64   `protobuf:"varint,5,opt,name=transition_time,json=transitionTime,proto3" json:"transition_time,omitempty"`
-	GenesisSeed          []byte   `protobuf:"bytes,6,opt,name=genesis_seed,json=genesisSeed,proto3" json:"genesis_seed,omitempty"`
-	DistKey              [][]byte `protobuf:"bytes,7,rep,name=dist_key,json=distKey,proto3" json:"dist_key,omitempty"`
+	Period         uint32   `protobuf:"varint,3,opt,name=period,proto3" json:"period,omitempty"`
+	GenesisTime    uint64   `protobuf:"varint,4,opt,name=genesis_time,json=genesisTime,proto3" json:"genesis_time,omitempty"`
+	TransitionTime uint64   `protobuf:"varint,5,opt,name=transition_time,json=transitionTime,proto3" json:"transition_time,omitempty"`
+	GenesisSeed    []byte   `protobuf:"bytes,6,opt,name=genesis_seed,json=genesisSeed,proto3" json:"genesis_seed,omitempty"`
+	DistKey        [][]byte `protobuf:"bytes,7,rep,name=dist_key,json=distKey,proto3" json:"dist_key,omitempty"`
+	// chain_hash is the version 2 hash of the group, covering all the fields
+	// above
+	ChainHash string `protobuf:"bytes,8,opt,name=chain_hash,json=chainHash,proto3" json:"chain_hash,omitempty"`
+	// transition_signature is the signature of the previous group over this
+	// group, set by a resharing
+	TransitionSignature  []byte   `protobuf:"bytes,9,opt,name=transition_signature,json=transitionSignature,proto3" json:"transition_signature,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -126,7 +224,7 @@ func (m *GroupPacket) Reset()         { *m = GroupPacket{} }
 func (m *GroupPacket) String() string { return proto.CompactTextString(m) }
 func (*GroupPacket) ProtoMessage()    {}
 func (*GroupPacket) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e3db314147ee7469, []int{2}
+	return fileDescriptor_e3db314147ee7469, []int{3}
 }
 
 func (m *GroupPacket) XXX_Unmarshal(b []byte) error {
@@ -196,7 +294,92 @@ func (m *GroupPacket) GetDistKey() [][]byte {
 	return nil
 }
 
+func (m *GroupPacket) GetChainHash() string {
+	if m != nil {
+		return m.ChainHash
+	}
+	return ""
+}
+
+func (m *GroupPacket) GetTransitionSignature() []byte {
+	if m != nil {
+		return m.TransitionSignature
+	}
+	return nil
+}
+
+// Metadata describes the version of the protocol a node speaks. Nodes with
+// different major versions can't run together. The minor version is bumped
+// when optional features are added.
+type Metadata struct {
+	Major uint32 `protobuf:"varint,1,opt,name=major,proto3" json:"major,omitempty"`
+	Minor uint32 `protobuf:"varint,2,opt,name=minor,proto3" json:"minor,omitempty"`
+	// features lists the optional features the node supports
+	Features []string `protobuf:"bytes,3,rep,name=features,proto3" json:"features,omitempty"`
+	// beaconID identifies the chain the message is about, among the chains
+	// run by the node. It is empty for the default chain.
+	BeaconID             string   `protobuf:"bytes,4,opt,name=beaconID,proto3" json:"beaconID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Metadata) Reset()         { *m = Metadata{} }
+func (m *Metadata) String() string { return proto.CompactTextString(m) }
+func (*Metadata) ProtoMessage()    {}
+func (*Metadata) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e3db314147ee7469, []int{4}
+}
+
+func (m *Metadata) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Metadata.Unmarshal(m, b)
+}
+func (m *Metadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Metadata.Marshal(b, m, deterministic)
+}
+func (m *Metadata) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Metadata.Merge(m, src)
+}
+func (m *Metadata) XXX_Size() int {
+	return xxx_messageInfo_Metadata.Size(m)
+}
+func (m *Metadata) XXX_DiscardUnknown() {
+	xxx_messageInfo_Metadata.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Metadata proto.InternalMessageInfo
+
+func (m *Metadata) GetMajor() uint32 {
+	if m != nil {
+		return m.Major
+	}
+	return 0
+}
+
+func (m *Metadata) GetMinor() uint32 {
+	if m != nil {
+		return m.Minor
+	}
+	return 0
+}
+
+func (m *Metadata) GetFeatures() []string {
+	if m != nil {
+		return m.Features
+	}
+	return nil
+}
+
+func (m *Metadata) GetBeaconID() string {
+	if m != nil {
+		return m.BeaconID
+	}
+	return ""
+}
+
 type GroupRequest struct {
+	// beaconID identifies the chain, empty for the default one
+	BeaconID             string   `protobuf:"bytes,1,opt,name=beaconID,proto3" json:"beaconID,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -206,7 +389,7 @@ func (m *GroupRequest) Reset()         { *m = GroupRequest{} }
 func (m *GroupRequest) String() string { return proto.CompactTextString(m) }
 func (*GroupRequest) ProtoMessage()    {}
 func (*GroupRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e3db314147ee7469, []int{3}
+	return fileDescriptor_e3db314147ee7469, []int{5}
 }
 
 func (m *GroupRequest) XXX_Unmarshal(b []byte) error {
@@ -227,10 +410,19 @@ func (m *GroupRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_GroupRequest proto.InternalMessageInfo
 
+func (m *GroupRequest) GetBeaconID() string {
+	if m != nil {
+		return m.BeaconID
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Empty)(nil), "drand.Empty")
 	proto.RegisterType((*Identity)(nil), "drand.Identity")
+	proto.RegisterType((*Operator)(nil), "drand.Operator")
 	proto.RegisterType((*GroupPacket)(nil), "drand.GroupPacket")
+	proto.RegisterType((*Metadata)(nil), "drand.Metadata")
 	proto.RegisterType((*GroupRequest)(nil), "drand.GroupRequest")
 }
 
@@ -239,23 +431,35 @@ func init() {
 }
 
 var fileDescriptor_e3db314147ee7469 = []byte{
-	// 288 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0x90, 0xc1, 0x4a, 0x33, 0x31,
-	0x14, 0x85, 0xc9, 0x3f, 0x9d, 0x4e, 0x7b, 0x3b, 0x6d, 0x7f, 0xb2, 0x90, 0x08, 0x2e, 0xc6, 0x01,
-	0x31, 0xab, 0x0a, 0xfa, 0x06, 0x82, 0xa8, 0xb8, 0x91, 0xe8, 0xca, 0x4d, 0x19, 0x9b, 0x8b, 0x0d,
-	0xed, 0x24, 0x63, 0x72, 0xbb, 0x98, 0xf7, 0xf6, 0x01, 0x24, 0x69, 0x4b, 0xdd, 0xe5, 0x7c, 0x39,
-	0x9c, 0xc3, 0x3d, 0xc0, 0xb5, 0x6f, 0xac, 0xbe, 0x59, 0xb9, 0xb6, 0x75, 0x76, 0xd1, 0x79, 0x47,
-	0x8e, 0xe7, 0x89, 0xd5, 0x05, 0xe4, 0x0f, 0x6d, 0x47, 0x7d, 0xfd, 0x04, 0xa3, 0x67, 0x8d, 0x96,
-	0x0c, 0xf5, 0x5c, 0x40, 0xd1, 0x68, 0xed, 0x31, 0x04, 0xc1, 0x2a, 0x26, 0xc7, 0xea, 0x28, 0xf9,
-	0x7f, 0xc8, 0x36, 0xd8, 0x8b, 0x7f, 0x15, 0x93, 0xa5, 0x8a, 0xcf, 0x48, 0x68, 0x1b, 0x44, 0x56,
-	0x31, 0x39, 0x52, 0xf1, 0x59, 0xff, 0x30, 0x98, 0x3c, 0x7a, 0xb7, 0xeb, 0x5e, 0x9b, 0xd5, 0x06,
-	0x89, 0x5f, 0x41, 0x6e, 0x9d, 0xc6, 0x98, 0x95, 0xc9, 0xc9, 0xed, 0x7c, 0x91, 0x9a, 0x17, 0xc7,
-	0x36, 0xb5, 0xff, 0xe5, 0x17, 0x30, 0xa6, 0xb5, 0xc7, 0xb0, 0x76, 0x5b, 0x9d, 0x0a, 0xa6, 0xea,
-	0x04, 0xf8, 0x19, 0x0c, 0x3b, 0xf4, 0xc6, 0xe9, 0xd4, 0x34, 0x55, 0x07, 0xc5, 0x2f, 0xa1, 0xfc,
-	0x42, 0x8b, 0xc1, 0x84, 0x25, 0x99, 0x16, 0xc5, 0xa0, 0x62, 0x72, 0xa0, 0x26, 0x07, 0xf6, 0x6e,
-	0x5a, 0xe4, 0xd7, 0x30, 0x27, 0xdf, 0xd8, 0x60, 0xc8, 0x38, 0xbb, 0x77, 0xe5, 0xc9, 0x35, 0x3b,
-	0xe1, 0x64, 0xfc, 0x93, 0x15, 0x10, 0xb5, 0x18, 0xa6, 0x2b, 0x8f, 0x59, 0x6f, 0x88, 0x9a, 0x9f,
-	0xc3, 0x48, 0x9b, 0x40, 0xcb, 0x38, 0x42, 0x51, 0x65, 0xb2, 0x54, 0x45, 0xd4, 0x2f, 0xd8, 0xd7,
-	0x33, 0x28, 0xd3, 0xd5, 0x0a, 0xbf, 0x77, 0x18, 0xe8, 0xbe, 0xf8, 0xd8, 0x4f, 0xfc, 0x39, 0x4c,
-	0x83, 0xdf, 0xfd, 0x06, 0x00, 0x00, 0xff, 0xff, 0xbe, 0xc5, 0xfe, 0x0a, 0x86, 0x01, 0x00, 0x00,
+	// 469 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x5c, 0x93, 0x51, 0x8e, 0xd3, 0x30,
+	0x10, 0x86, 0x95, 0x6d, 0xd3, 0x26, 0xd3, 0x96, 0x45, 0x66, 0x85, 0x02, 0x02, 0xa9, 0x44, 0x42,
+	0x44, 0x20, 0x15, 0x01, 0x37, 0x40, 0x20, 0x58, 0x21, 0x04, 0xf2, 0xf2, 0xc4, 0x4b, 0xe5, 0xc6,
+	0x43, 0x63, 0x76, 0x63, 0x07, 0xdb, 0x15, 0xea, 0x0d, 0x78, 0xe7, 0x4e, 0x9c, 0x0b, 0x79, 0x92,
+	0x34, 0xed, 0xbe, 0xf9, 0xff, 0xe7, 0xef, 0xf4, 0xf3, 0x4c, 0x0c, 0x4c, 0x5a, 0xa1, 0xe5, 0xcb,
+	0xd2, 0xd4, 0xb5, 0xd1, 0xab, 0xc6, 0x1a, 0x6f, 0x58, 0x4c, 0x5e, 0x3e, 0x85, 0xf8, 0x7d, 0xdd,
+	0xf8, 0x7d, 0xfe, 0x37, 0x82, 0xe4, 0x52, 0xa2, 0xf6, 0xca, 0xef, 0x59, 0x06, 0x53, 0x21, 0xa5,
+	0x45, 0xe7, 0xb2, 0x68, 0x19, 0x15, 0x29, 0xef, 0x25, 0xbb, 0x0b, 0xa3, 0x6b, 0xdc, 0x67, 0x67,
+	0xcb, 0xa8, 0x98, 0xf3, 0x70, 0x0c, 0x8e, 0xbf, 0x71, 0xd9, 0x68, 0x19, 0x15, 0x09, 0x0f, 0x47,
+	0xf6, 0x08, 0x52, 0xa7, 0xb6, 0x5a, 0xf8, 0x9d, 0xc5, 0x6c, 0x4c, 0xc9, 0xc1, 0x60, 0x2f, 0x20,
+	0x31, 0x0d, 0x5a, 0xe1, 0x8d, 0xcd, 0xe2, 0x65, 0x54, 0xcc, 0x5e, 0x9f, 0xaf, 0x88, 0x65, 0xf5,
+	0xa5, 0xb3, 0xf9, 0x21, 0x90, 0xff, 0x89, 0x20, 0xe9, 0x6d, 0xc6, 0x60, 0xac, 0x45, 0x8d, 0x1d,
+	0x12, 0x9d, 0x03, 0x69, 0x69, 0xb4, 0x17, 0xa5, 0x27, 0xa6, 0x94, 0xf7, 0x92, 0xdd, 0x87, 0x89,
+	0xc5, 0xad, 0x32, 0x9a, 0xd0, 0x52, 0xde, 0xa9, 0xf0, 0x8b, 0xdf, 0xb8, 0x71, 0xca, 0xb7, 0x6c,
+	0x29, 0xef, 0xe5, 0x29, 0x77, 0x7c, 0x8b, 0x3b, 0xff, 0x77, 0x06, 0xb3, 0x0f, 0xd6, 0xec, 0x9a,
+	0xaf, 0xa2, 0xbc, 0x46, 0xcf, 0x9e, 0x42, 0xac, 0x8d, 0xc4, 0x30, 0xa1, 0xd1, 0xd1, 0x25, 0xfa,
+	0x19, 0xf2, 0xb6, 0x1a, 0x9a, 0xfa, 0xca, 0xa2, 0xab, 0xcc, 0x8d, 0x24, 0xc4, 0x05, 0x1f, 0x8c,
+	0x00, 0xd9, 0xa0, 0x55, 0x46, 0x12, 0xe4, 0x82, 0x77, 0x8a, 0x3d, 0x81, 0xf9, 0x16, 0x35, 0x3a,
+	0xe5, 0xd6, 0x5e, 0xd5, 0x2d, 0xe9, 0x98, 0xcf, 0x3a, 0xef, 0x9b, 0xaa, 0x91, 0x3d, 0x83, 0x73,
+	0x6f, 0x85, 0x76, 0xca, 0x2b, 0xa3, 0xdb, 0x54, 0x4c, 0xa9, 0x3b, 0x83, 0x4d, 0xc1, 0xa3, 0x5e,
+	0x0e, 0x51, 0x66, 0x13, 0xba, 0x59, 0xdf, 0xeb, 0x0a, 0x51, 0xb2, 0x07, 0x90, 0x48, 0xe5, 0xfc,
+	0x3a, 0xac, 0x76, 0xba, 0x1c, 0x15, 0x73, 0x3e, 0x0d, 0xfa, 0x13, 0xee, 0xd9, 0x63, 0x80, 0xb2,
+	0x12, 0x4a, 0xaf, 0x2b, 0xe1, 0xaa, 0x2c, 0xa1, 0x89, 0xa5, 0xe4, 0x7c, 0x14, 0xae, 0x62, 0xaf,
+	0xe0, 0xe2, 0x88, 0x62, 0x18, 0x5f, 0x4a, 0x7f, 0x72, 0x6f, 0xa8, 0x5d, 0x1d, 0x06, 0xa9, 0x21,
+	0xf9, 0x8c, 0x5e, 0x48, 0xe1, 0x05, 0xbb, 0x80, 0xb8, 0x16, 0x3f, 0x8d, 0xa5, 0x9d, 0x2e, 0x78,
+	0x2b, 0xc8, 0x55, 0xda, 0xd8, 0x6e, 0x5e, 0xad, 0x60, 0x0f, 0x21, 0xf9, 0x81, 0xd4, 0x22, 0x7c,
+	0x6d, 0xa3, 0x22, 0xe5, 0x07, 0x1d, 0x6a, 0x1b, 0x14, 0xa5, 0xd1, 0x97, 0xef, 0xba, 0xad, 0x1e,
+	0x74, 0xfe, 0x1c, 0xe6, 0xb4, 0x37, 0x8e, 0xbf, 0x76, 0xe8, 0xfc, 0x49, 0x36, 0x3a, 0xcd, 0xbe,
+	0x9d, 0x7e, 0x6f, 0xdf, 0xc5, 0x66, 0x42, 0xaf, 0xe4, 0xcd, 0xff, 0x01, 0x00, 0x89, 0x28, 0x14,
+	0x0d, 0x3b, 0x03, 0x00, 0x00,
 }
@@ -24,6 +24,84 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+type SignPartialRequest struct {
+	Msg                  []byte   `protobuf:"bytes,1,opt,name=msg,proto3" json:"msg,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SignPartialRequest) Reset()         { *m = SignPartialRequest{} }
+func (m *SignPartialRequest) String() string { return proto.CompactTextString(m) }
+func (*SignPartialRequest) ProtoMessage()    {}
+func (*SignPartialRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dd5961950a69ad7, []int{0}
+}
+
+func (m *SignPartialRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SignPartialRequest.Unmarshal(m, b)
+}
+func (m *SignPartialRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SignPartialRequest.Marshal(b, m, deterministic)
+}
+func (m *SignPartialRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SignPartialRequest.Merge(m, src)
+}
+func (m *SignPartialRequest) XXX_Size() int {
+	return xxx_messageInfo_SignPartialRequest.Size(m)
+}
+func (m *SignPartialRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SignPartialRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SignPartialRequest proto.InternalMessageInfo
+
+func (m *SignPartialRequest) GetMsg() []byte {
+	if m != nil {
+		return m.Msg
+	}
+	return nil
+}
+
+type SignPartialResponse struct {
+	PartialSig           []byte   `protobuf:"bytes,1,opt,name=partial_sig,json=partialSig,proto3" json:"partial_sig,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SignPartialResponse) Reset()         { *m = SignPartialResponse{} }
+func (m *SignPartialResponse) String() string { return proto.CompactTextString(m) }
+func (*SignPartialResponse) ProtoMessage()    {}
+func (*SignPartialResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dd5961950a69ad7, []int{1}
+}
+
+func (m *SignPartialResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SignPartialResponse.Unmarshal(m, b)
+}
+func (m *SignPartialResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SignPartialResponse.Marshal(b, m, deterministic)
+}
+func (m *SignPartialResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SignPartialResponse.Merge(m, src)
+}
+func (m *SignPartialResponse) XXX_Size() int {
+	return xxx_messageInfo_SignPartialResponse.Size(m)
+}
+func (m *SignPartialResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SignPartialResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SignPartialResponse proto.InternalMessageInfo
+
+func (m *SignPartialResponse) GetPartialSig() []byte {
+	if m != nil {
+		return m.PartialSig
+	}
+	return nil
+}
+
 // SetupInfoPacket contains all information necessary to run an "automatic"
 // setup phase where the designated leader acts as a coordinator as to what is
 // the group file and when does the chain starts.
@@ -37,19 +115,22 @@ type SetupInfoPacket struct {
 	Threshold uint32 `protobuf:"varint,5,opt,name=threshold,proto3" json:"threshold,omitempty"`
 	// timeout of the dkg
 	// timeout as parsed by Golang's time.ParseDuration method.
-	Timeout              string   `protobuf:"bytes,6,opt,name=timeout,proto3" json:"timeout,omitempty"`
-	Secret               string   `protobuf:"bytes,7,opt,name=secret,proto3" json:"secret,omitempty"`
-	BeaconOffset         uint32   `protobuf:"varint,8,opt,name=beaconOffset,proto3" json:"beaconOffset,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Timeout      string `protobuf:"bytes,6,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	Secret       string `protobuf:"bytes,7,opt,name=secret,proto3" json:"secret,omitempty"`
+	BeaconOffset uint32 `protobuf:"varint,8,opt,name=beaconOffset,proto3" json:"beaconOffset,omitempty"`
+	// allowed is only used by the leader: when not empty, only these
+	// identities can join the setup
+	Allowed              []*Identity `protobuf:"bytes,9,rep,name=allowed,proto3" json:"allowed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
 }
 
 func (m *SetupInfoPacket) Reset()         { *m = SetupInfoPacket{} }
 func (m *SetupInfoPacket) String() string { return proto.CompactTextString(m) }
 func (*SetupInfoPacket) ProtoMessage()    {}
 func (*SetupInfoPacket) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{0}
+	return fileDescriptor_2dd5961950a69ad7, []int{2}
 }
 
 func (m *SetupInfoPacket) XXX_Unmarshal(b []byte) error {
@@ -126,12 +207,26 @@ func (m *SetupInfoPacket) GetBeaconOffset() uint32 {
 	return 0
 }
 
+func (m *SetupInfoPacket) GetAllowed() []*Identity {
+	if m != nil {
+		return m.Allowed
+	}
+	return nil
+}
+
 type InitDKGPacket struct {
 	Info    *SetupInfoPacket `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
 	Entropy *EntropyInfo     `protobuf:"bytes,2,opt,name=entropy,proto3" json:"entropy,omitempty"`
 	// the period time of the beacon;
 	// used only in a fresh dkg
-	BeaconPeriod         uint32   `protobuf:"varint,3,opt,name=beacon_period,json=beaconPeriod,proto3" json:"beacon_period,omitempty"`
+	BeaconPeriod uint32 `protobuf:"varint,3,opt,name=beacon_period,json=beaconPeriod,proto3" json:"beacon_period,omitempty"`
+	// group is the group file agreed upon by all participants beforehand. When
+	// set, no leader is involved: the nodes check they all hold the same group
+	// hash and run the DKG directly. The setup info is then only used for the
+	// dkg timeout.
+	Group *GroupInfo `protobuf:"bytes,4,opt,name=group,proto3" json:"group,omitempty"`
+	// beaconID identifies the chain, empty for the default one
+	BeaconID             string   `protobuf:"bytes,5,opt,name=beaconID,proto3" json:"beaconID,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -141,7 +236,7 @@ func (m *InitDKGPacket) Reset()         { *m = InitDKGPacket{} }
 func (m *InitDKGPacket) String() string { return proto.CompactTextString(m) }
 func (*InitDKGPacket) ProtoMessage()    {}
 func (*InitDKGPacket) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{1}
+	return fileDescriptor_2dd5961950a69ad7, []int{3}
 }
 
 func (m *InitDKGPacket) XXX_Unmarshal(b []byte) error {
@@ -183,6 +278,20 @@ func (m *InitDKGPacket) GetBeaconPeriod() uint32 {
 	return 0
 }
 
+func (m *InitDKGPacket) GetGroup() *GroupInfo {
+	if m != nil {
+		return m.Group
+	}
+	return nil
+}
+
+func (m *InitDKGPacket) GetBeaconID() string {
+	if m != nil {
+		return m.BeaconID
+	}
+	return ""
+}
+
 // EntropyInfo contains information about external entropy sources
 // can be optional
 type EntropyInfo struct {
@@ -198,7 +307,7 @@ func (m *EntropyInfo) Reset()         { *m = EntropyInfo{} }
 func (m *EntropyInfo) String() string { return proto.CompactTextString(m) }
 func (*EntropyInfo) ProtoMessage()    {}
 func (*EntropyInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{2}
+	return fileDescriptor_2dd5961950a69ad7, []int{4}
 }
 
 func (m *EntropyInfo) XXX_Unmarshal(b []byte) error {
@@ -240,18 +349,20 @@ type InitResharePacket struct {
 	// NOTE: It can be empty / nil. In that case, the drand node will try to
 	// load the group he belongs to at the moment, if any, and use it as the old
 	// group.
-	Old                  *GroupInfo       `protobuf:"bytes,1,opt,name=old,proto3" json:"old,omitempty"`
-	Info                 *SetupInfoPacket `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	Old  *GroupInfo       `protobuf:"bytes,1,opt,name=old,proto3" json:"old,omitempty"`
+	Info *SetupInfoPacket `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"`
+	// beaconID identifies the chain, empty for the default one
+	BeaconID             string   `protobuf:"bytes,3,opt,name=beaconID,proto3" json:"beaconID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *InitResharePacket) Reset()         { *m = InitResharePacket{} }
 func (m *InitResharePacket) String() string { return proto.CompactTextString(m) }
 func (*InitResharePacket) ProtoMessage()    {}
 func (*InitResharePacket) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{3}
+	return fileDescriptor_2dd5961950a69ad7, []int{5}
 }
 
 func (m *InitResharePacket) XXX_Unmarshal(b []byte) error {
@@ -286,21 +397,31 @@ func (m *InitResharePacket) GetInfo() *SetupInfoPacket {
 	return nil
 }
 
+func (m *InitResharePacket) GetBeaconID() string {
+	if m != nil {
+		return m.BeaconID
+	}
+	return ""
+}
+
 type GroupInfo struct {
 	// Types that are valid to be assigned to Location:
 	//	*GroupInfo_Path
 	//	*GroupInfo_Url
-	Location             isGroupInfo_Location `protobuf_oneof:"location"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+	Location isGroupInfo_Location `protobuf_oneof:"location"`
+	// hash the group must have, in hexadecimal. It is required when fetching
+	// the group from an url.
+	Hash                 string   `protobuf:"bytes,3,opt,name=hash,proto3" json:"hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *GroupInfo) Reset()         { *m = GroupInfo{} }
 func (m *GroupInfo) String() string { return proto.CompactTextString(m) }
 func (*GroupInfo) ProtoMessage()    {}
 func (*GroupInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{4}
+	return fileDescriptor_2dd5961950a69ad7, []int{6}
 }
 
 func (m *GroupInfo) XXX_Unmarshal(b []byte) error {
@@ -358,6 +479,13 @@ func (m *GroupInfo) GetUrl() string {
 	return ""
 }
 
+func (m *GroupInfo) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*GroupInfo) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -368,6 +496,8 @@ func (*GroupInfo) XXX_OneofWrappers() []interface{} {
 
 // ShareRequest requests the private share of a drand node
 type ShareRequest struct {
+	// beaconID identifies the chain, empty for the default one
+	BeaconID             string   `protobuf:"bytes,1,opt,name=beaconID,proto3" json:"beaconID,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -377,7 +507,7 @@ func (m *ShareRequest) Reset()         { *m = ShareRequest{} }
 func (m *ShareRequest) String() string { return proto.CompactTextString(m) }
 func (*ShareRequest) ProtoMessage()    {}
 func (*ShareRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{5}
+	return fileDescriptor_2dd5961950a69ad7, []int{7}
 }
 
 func (m *ShareRequest) XXX_Unmarshal(b []byte) error {
@@ -398,6 +528,13 @@ func (m *ShareRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ShareRequest proto.InternalMessageInfo
 
+func (m *ShareRequest) GetBeaconID() string {
+	if m != nil {
+		return m.BeaconID
+	}
+	return ""
+}
+
 // ShareResponse holds the private share of a drand node
 type ShareResponse struct {
 	Index                uint32   `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
@@ -411,7 +548,7 @@ func (m *ShareResponse) Reset()         { *m = ShareResponse{} }
 func (m *ShareResponse) String() string { return proto.CompactTextString(m) }
 func (*ShareResponse) ProtoMessage()    {}
 func (*ShareResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{6}
+	return fileDescriptor_2dd5961950a69ad7, []int{8}
 }
 
 func (m *ShareResponse) XXX_Unmarshal(b []byte) error {
@@ -456,7 +593,7 @@ func (m *Ping) Reset()         { *m = Ping{} }
 func (m *Ping) String() string { return proto.CompactTextString(m) }
 func (*Ping) ProtoMessage()    {}
 func (*Ping) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{7}
+	return fileDescriptor_2dd5961950a69ad7, []int{9}
 }
 
 func (m *Ping) XXX_Unmarshal(b []byte) error {
@@ -487,7 +624,7 @@ func (m *Pong) Reset()         { *m = Pong{} }
 func (m *Pong) String() string { return proto.CompactTextString(m) }
 func (*Pong) ProtoMessage()    {}
 func (*Pong) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{8}
+	return fileDescriptor_2dd5961950a69ad7, []int{10}
 }
 
 func (m *Pong) XXX_Unmarshal(b []byte) error {
@@ -510,6 +647,8 @@ var xxx_messageInfo_Pong proto.InternalMessageInfo
 
 // PublicKeyRequest requests the public key of a drand node
 type PublicKeyRequest struct {
+	// beaconID identifies the chain, empty for the default one
+	BeaconID             string   `protobuf:"bytes,1,opt,name=beaconID,proto3" json:"beaconID,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -519,7 +658,7 @@ func (m *PublicKeyRequest) Reset()         { *m = PublicKeyRequest{} }
 func (m *PublicKeyRequest) String() string { return proto.CompactTextString(m) }
 func (*PublicKeyRequest) ProtoMessage()    {}
 func (*PublicKeyRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{9}
+	return fileDescriptor_2dd5961950a69ad7, []int{11}
 }
 
 func (m *PublicKeyRequest) XXX_Unmarshal(b []byte) error {
@@ -540,6 +679,13 @@ func (m *PublicKeyRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_PublicKeyRequest proto.InternalMessageInfo
 
+func (m *PublicKeyRequest) GetBeaconID() string {
+	if m != nil {
+		return m.BeaconID
+	}
+	return ""
+}
+
 // PublicKeyResponse holds the public key of a drand node
 type PublicKeyResponse struct {
 	PubKey               []byte   `protobuf:"bytes,2,opt,name=pubKey,proto3" json:"pubKey,omitempty"`
@@ -552,7 +698,7 @@ func (m *PublicKeyResponse) Reset()         { *m = PublicKeyResponse{} }
 func (m *PublicKeyResponse) String() string { return proto.CompactTextString(m) }
 func (*PublicKeyResponse) ProtoMessage()    {}
 func (*PublicKeyResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{10}
+	return fileDescriptor_2dd5961950a69ad7, []int{12}
 }
 
 func (m *PublicKeyResponse) XXX_Unmarshal(b []byte) error {
@@ -582,6 +728,8 @@ func (m *PublicKeyResponse) GetPubKey() []byte {
 
 // PrivateKeyRequest requests the private key of a drand node
 type PrivateKeyRequest struct {
+	// beaconID identifies the chain, empty for the default one
+	BeaconID             string   `protobuf:"bytes,1,opt,name=beaconID,proto3" json:"beaconID,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -591,7 +739,7 @@ func (m *PrivateKeyRequest) Reset()         { *m = PrivateKeyRequest{} }
 func (m *PrivateKeyRequest) String() string { return proto.CompactTextString(m) }
 func (*PrivateKeyRequest) ProtoMessage()    {}
 func (*PrivateKeyRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{11}
+	return fileDescriptor_2dd5961950a69ad7, []int{13}
 }
 
 func (m *PrivateKeyRequest) XXX_Unmarshal(b []byte) error {
@@ -612,6 +760,13 @@ func (m *PrivateKeyRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_PrivateKeyRequest proto.InternalMessageInfo
 
+func (m *PrivateKeyRequest) GetBeaconID() string {
+	if m != nil {
+		return m.BeaconID
+	}
+	return ""
+}
+
 // PrivateKeyResponse holds the private key of a drand node
 type PrivateKeyResponse struct {
 	PriKey               []byte   `protobuf:"bytes,2,opt,name=priKey,proto3" json:"priKey,omitempty"`
@@ -624,7 +779,7 @@ func (m *PrivateKeyResponse) Reset()         { *m = PrivateKeyResponse{} }
 func (m *PrivateKeyResponse) String() string { return proto.CompactTextString(m) }
 func (*PrivateKeyResponse) ProtoMessage()    {}
 func (*PrivateKeyResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{12}
+	return fileDescriptor_2dd5961950a69ad7, []int{14}
 }
 
 func (m *PrivateKeyResponse) XXX_Unmarshal(b []byte) error {
@@ -654,6 +809,8 @@ func (m *PrivateKeyResponse) GetPriKey() []byte {
 
 // CokeyRequest requests the collective key of a drand node
 type CokeyRequest struct {
+	// beaconID identifies the chain, empty for the default one
+	BeaconID             string   `protobuf:"bytes,1,opt,name=beaconID,proto3" json:"beaconID,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -663,7 +820,7 @@ func (m *CokeyRequest) Reset()         { *m = CokeyRequest{} }
 func (m *CokeyRequest) String() string { return proto.CompactTextString(m) }
 func (*CokeyRequest) ProtoMessage()    {}
 func (*CokeyRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{13}
+	return fileDescriptor_2dd5961950a69ad7, []int{15}
 }
 
 func (m *CokeyRequest) XXX_Unmarshal(b []byte) error {
@@ -684,6 +841,13 @@ func (m *CokeyRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_CokeyRequest proto.InternalMessageInfo
 
+func (m *CokeyRequest) GetBeaconID() string {
+	if m != nil {
+		return m.BeaconID
+	}
+	return ""
+}
+
 // CokeyResponse holds the collective key of a drand node
 type CokeyResponse struct {
 	CoKey                []byte   `protobuf:"bytes,2,opt,name=coKey,proto3" json:"coKey,omitempty"`
@@ -696,7 +860,7 @@ func (m *CokeyResponse) Reset()         { *m = CokeyResponse{} }
 func (m *CokeyResponse) String() string { return proto.CompactTextString(m) }
 func (*CokeyResponse) ProtoMessage()    {}
 func (*CokeyResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{14}
+	return fileDescriptor_2dd5961950a69ad7, []int{16}
 }
 
 func (m *CokeyResponse) XXX_Unmarshal(b []byte) error {
@@ -736,7 +900,7 @@ func (m *GroupTOMLResponse) Reset()         { *m = GroupTOMLResponse{} }
 func (m *GroupTOMLResponse) String() string { return proto.CompactTextString(m) }
 func (*GroupTOMLResponse) ProtoMessage()    {}
 func (*GroupTOMLResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{15}
+	return fileDescriptor_2dd5961950a69ad7, []int{17}
 }
 
 func (m *GroupTOMLResponse) XXX_Unmarshal(b []byte) error {
@@ -774,7 +938,7 @@ func (m *ShutdownRequest) Reset()         { *m = ShutdownRequest{} }
 func (m *ShutdownRequest) String() string { return proto.CompactTextString(m) }
 func (*ShutdownRequest) ProtoMessage()    {}
 func (*ShutdownRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{16}
+	return fileDescriptor_2dd5961950a69ad7, []int{18}
 }
 
 func (m *ShutdownRequest) XXX_Unmarshal(b []byte) error {
@@ -805,7 +969,7 @@ func (m *ShutdownResponse) Reset()         { *m = ShutdownResponse{} }
 func (m *ShutdownResponse) String() string { return proto.CompactTextString(m) }
 func (*ShutdownResponse) ProtoMessage()    {}
 func (*ShutdownResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2dd5961950a69ad7, []int{17}
+	return fileDescriptor_2dd5961950a69ad7, []int{19}
 }
 
 func (m *ShutdownResponse) XXX_Unmarshal(b []byte) error {
@@ -826,175 +990,1058 @@ func (m *ShutdownResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ShutdownResponse proto.InternalMessageInfo
 
-func init() {
-	proto.RegisterType((*SetupInfoPacket)(nil), "drand.SetupInfoPacket")
-	proto.RegisterType((*InitDKGPacket)(nil), "drand.InitDKGPacket")
-	proto.RegisterType((*EntropyInfo)(nil), "drand.EntropyInfo")
-	proto.RegisterType((*InitResharePacket)(nil), "drand.InitResharePacket")
-	proto.RegisterType((*GroupInfo)(nil), "drand.GroupInfo")
-	proto.RegisterType((*ShareRequest)(nil), "drand.ShareRequest")
-	proto.RegisterType((*ShareResponse)(nil), "drand.ShareResponse")
-	proto.RegisterType((*Ping)(nil), "drand.Ping")
-	proto.RegisterType((*Pong)(nil), "drand.Pong")
-	proto.RegisterType((*PublicKeyRequest)(nil), "drand.PublicKeyRequest")
-	proto.RegisterType((*PublicKeyResponse)(nil), "drand.PublicKeyResponse")
-	proto.RegisterType((*PrivateKeyRequest)(nil), "drand.PrivateKeyRequest")
-	proto.RegisterType((*PrivateKeyResponse)(nil), "drand.PrivateKeyResponse")
-	proto.RegisterType((*CokeyRequest)(nil), "drand.CokeyRequest")
-	proto.RegisterType((*CokeyResponse)(nil), "drand.CokeyResponse")
-	proto.RegisterType((*GroupTOMLResponse)(nil), "drand.GroupTOMLResponse")
-	proto.RegisterType((*ShutdownRequest)(nil), "drand.ShutdownRequest")
-	proto.RegisterType((*ShutdownResponse)(nil), "drand.ShutdownResponse")
+type SetupStatusRequest struct {
+	// beaconID identifies the chain, empty for the default one
+	BeaconID             string   `protobuf:"bytes,1,opt,name=beaconID,proto3" json:"beaconID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func init() {
-	proto.RegisterFile("drand/control.proto", fileDescriptor_2dd5961950a69ad7)
+func (m *SetupStatusRequest) Reset()         { *m = SetupStatusRequest{} }
+func (m *SetupStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*SetupStatusRequest) ProtoMessage()    {}
+func (*SetupStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dd5961950a69ad7, []int{20}
 }
 
-var fileDescriptor_2dd5961950a69ad7 = []byte{
-	// 735 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x55, 0x4d, 0x4f, 0xdb, 0x4a,
-	0x14, 0x4d, 0x20, 0x9f, 0x37, 0x09, 0x90, 0x4b, 0x04, 0x7e, 0xd6, 0x43, 0x42, 0xf3, 0xc4, 0x13,
-	0x6a, 0x11, 0x95, 0xd2, 0x8f, 0x4d, 0x5b, 0xa9, 0x40, 0x5b, 0x40, 0xb4, 0x22, 0x32, 0xac, 0xba,
-	0x41, 0x8e, 0x3d, 0x21, 0x16, 0xce, 0x8c, 0x3b, 0x1e, 0xd3, 0xf2, 0x27, 0xba, 0xef, 0x4f, 0xed,
-	0xae, 0x9a, 0x0f, 0x3b, 0x0e, 0x14, 0x75, 0x05, 0xe7, 0xdc, 0xb9, 0x77, 0xe6, 0x9c, 0x39, 0x13,
-	0xc3, 0x7a, 0x28, 0x7c, 0x16, 0x3e, 0x0b, 0x38, 0x93, 0x82, 0xc7, 0xfb, 0x89, 0xe0, 0x92, 0x63,
-	0x5d, 0x93, 0x2e, 0xe6, 0xb5, 0xd9, 0x8c, 0x33, 0x53, 0x22, 0xbf, 0xaa, 0xb0, 0x7a, 0x41, 0x65,
-	0x96, 0x9c, 0xb2, 0x09, 0x1f, 0xf9, 0xc1, 0x0d, 0x95, 0xb8, 0x01, 0x8d, 0x98, 0xfa, 0x21, 0x15,
-	0x4e, 0x75, 0xbb, 0xba, 0xdb, 0xf2, 0x2c, 0xc2, 0x1d, 0x58, 0x31, 0xff, 0x5d, 0xf9, 0x61, 0x28,
-	0x68, 0x9a, 0x3a, 0x4b, 0xdb, 0xd5, 0xdd, 0xb6, 0xd7, 0x33, 0xec, 0x81, 0x21, 0x71, 0x0b, 0xc0,
-	0x2e, 0x93, 0x71, 0xea, 0x2c, 0xeb, 0x11, 0x6d, 0xc3, 0x5c, 0xc6, 0x29, 0x0e, 0xa0, 0xce, 0x78,
-	0x48, 0x53, 0xa7, 0xb6, 0x5d, 0xdd, 0xed, 0x79, 0x06, 0xe0, 0xbf, 0xd0, 0x96, 0x53, 0x41, 0xd3,
-	0x29, 0x8f, 0x43, 0xa7, 0xae, 0x2b, 0x73, 0x02, 0x1d, 0x68, 0xca, 0x68, 0x46, 0x79, 0x26, 0x9d,
-	0x86, 0xde, 0x32, 0x87, 0xea, 0xac, 0x29, 0x0d, 0x04, 0x95, 0x4e, 0x53, 0x17, 0x2c, 0x42, 0x02,
-	0xdd, 0x31, 0xf5, 0x03, 0xce, 0xce, 0x27, 0x93, 0x94, 0x4a, 0xa7, 0xa5, 0x47, 0x2e, 0x70, 0xe4,
-	0x47, 0x15, 0x7a, 0xa7, 0x2c, 0x92, 0xef, 0xcf, 0x8e, 0xad, 0xf2, 0x27, 0x50, 0x8b, 0xd8, 0x84,
-	0x6b, 0xdd, 0x9d, 0xe1, 0xc6, 0xbe, 0x36, 0x6c, 0xff, 0x9e, 0x3f, 0x9e, 0x5e, 0x83, 0x7b, 0xd0,
-	0xa4, 0xca, 0xe4, 0xe4, 0x4e, 0xdb, 0xd0, 0x19, 0xa2, 0x5d, 0xfe, 0xc1, 0xb0, 0xaa, 0xc1, 0xcb,
-	0x97, 0xe0, 0x7f, 0xd0, 0x33, 0x7b, 0x5f, 0x25, 0x54, 0x44, 0x3c, 0xd4, 0xbe, 0x14, 0x07, 0x1a,
-	0x69, 0x8e, 0x1c, 0x40, 0xa7, 0xd4, 0xac, 0xb5, 0x05, 0x22, 0x4a, 0xa4, 0x3e, 0x8f, 0xd2, 0xa6,
-	0x11, 0xba, 0xd0, 0xca, 0x52, 0x2a, 0xce, 0x59, 0x7c, 0xe7, 0x80, 0xb6, 0xb7, 0xc0, 0x24, 0x80,
-	0xbe, 0x92, 0xe4, 0xd1, 0x74, 0xea, 0x0b, 0x6a, 0x65, 0x11, 0x58, 0x56, 0xb6, 0x1a, 0x55, 0x6b,
-	0xf6, 0x98, 0xc7, 0x82, 0x1b, 0x55, 0x9e, 0x2a, 0x16, 0xd2, 0x97, 0xfe, 0x2e, 0x9d, 0x1c, 0x40,
-	0xbb, 0xe8, 0xc6, 0x01, 0xd4, 0x12, 0x5f, 0x4e, 0xcd, 0x19, 0x4f, 0x2a, 0x9e, 0x46, 0x88, 0xb0,
-	0x9c, 0x89, 0xd8, 0x04, 0xe4, 0xa4, 0xe2, 0x29, 0x70, 0x08, 0xd0, 0x8a, 0x79, 0xe0, 0xcb, 0x88,
-	0x33, 0xb2, 0x02, 0xdd, 0x0b, 0x75, 0x42, 0x8f, 0x7e, 0xcd, 0x68, 0x2a, 0xc9, 0x6b, 0xe8, 0x59,
-	0x9c, 0x26, 0x9c, 0xa5, 0x54, 0xc5, 0x24, 0x62, 0x21, 0xfd, 0xae, 0x47, 0xf4, 0x3c, 0x03, 0x14,
-	0xab, 0x85, 0x69, 0xfb, 0xba, 0x9e, 0x01, 0xa4, 0x01, 0xb5, 0x51, 0xc4, 0xae, 0xf5, 0x5f, 0xce,
-	0xae, 0x09, 0xc2, 0xda, 0x28, 0x1b, 0xc7, 0x51, 0x70, 0x46, 0xef, 0xf2, 0x0d, 0x9e, 0x42, 0xbf,
-	0xc4, 0xd9, 0x4d, 0x36, 0xa0, 0x91, 0x64, 0xe3, 0x33, 0x6a, 0xae, 0xb0, 0xeb, 0x59, 0x44, 0xd6,
-	0xa1, 0x3f, 0x12, 0xd1, 0xad, 0x2f, 0x69, 0x69, 0xc2, 0x1e, 0x60, 0x99, 0x2c, 0x8d, 0x10, 0x51,
-	0x79, 0x84, 0x46, 0x4a, 0xe0, 0x11, 0xbf, 0x99, 0x77, 0xef, 0x40, 0xcf, 0xe2, 0xb9, 0xc0, 0x80,
-	0xcf, 0xfb, 0x0c, 0x20, 0x43, 0xe8, 0x6b, 0x6b, 0x2f, 0xcf, 0x3f, 0x7f, 0x2a, 0x96, 0x6e, 0x01,
-	0x5c, 0x2b, 0xf2, 0x4a, 0xf2, 0x59, 0x6c, 0xc3, 0xd0, 0xd6, 0xcc, 0x25, 0x9f, 0xc5, 0xa4, 0x0f,
-	0xab, 0x17, 0xd3, 0x4c, 0x86, 0xfc, 0x1b, 0xcb, 0x77, 0x43, 0x58, 0x9b, 0x53, 0x66, 0xca, 0xf0,
-	0x67, 0x0d, 0x9a, 0x47, 0xe6, 0x77, 0x01, 0xff, 0x87, 0x96, 0x72, 0x4c, 0xb9, 0x85, 0x1d, 0x7b,
-	0xd7, 0x8a, 0x70, 0x0b, 0xa0, 0x7c, 0xac, 0xe0, 0x4b, 0x68, 0xda, 0x17, 0x82, 0x03, 0x5b, 0x59,
-	0x78, 0x31, 0x2e, 0x96, 0xd3, 0x64, 0x38, 0x52, 0xc1, 0xb7, 0xd0, 0x29, 0xa5, 0x10, 0x9d, 0x52,
-	0xeb, 0x42, 0x32, 0x1f, 0x69, 0x7f, 0x01, 0x75, 0x1d, 0x06, 0x5c, 0xcf, 0x63, 0x58, 0x8a, 0x8a,
-	0x3b, 0x58, 0x24, 0x8d, 0x3a, 0x52, 0xc1, 0x77, 0xd0, 0x2e, 0x6e, 0x18, 0x37, 0x73, 0x1d, 0xf7,
-	0x72, 0xe0, 0x3a, 0x0f, 0x0b, 0xc5, 0x84, 0x23, 0x80, 0xf9, 0x0d, 0x17, 0xa7, 0x7e, 0x90, 0x04,
-	0xf7, 0x9f, 0x3f, 0x54, 0x8a, 0x21, 0x6f, 0xd4, 0x45, 0xc7, 0x31, 0x0d, 0x64, 0x74, 0xab, 0xe7,
-	0xe4, 0x22, 0xca, 0x71, 0x28, 0x44, 0x2c, 0x64, 0x82, 0x54, 0xf0, 0x95, 0x7d, 0x5a, 0x1f, 0xa3,
-	0x78, 0x2e, 0x5f, 0x33, 0x79, 0xe7, 0x63, 0x8e, 0xb7, 0xf2, 0x0b, 0xc7, 0xe2, 0xf1, 0x2e, 0x86,
-	0xc2, 0xdd, 0x7c, 0xc0, 0xe7, 0xdb, 0x1e, 0x36, 0xbf, 0x98, 0x6f, 0xc4, 0xb8, 0xa1, 0x3f, 0x0b,
-	0xcf, 0x7f, 0x07, 0x00, 0x00, 0xff, 0xff, 0xac, 0xb3, 0x42, 0x79, 0x48, 0x06, 0x00, 0x00,
+func (m *SetupStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetupStatusRequest.Unmarshal(m, b)
+}
+func (m *SetupStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetupStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *SetupStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetupStatusRequest.Merge(m, src)
+}
+func (m *SetupStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_SetupStatusRequest.Size(m)
+}
+func (m *SetupStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetupStatusRequest.DiscardUnknown(m)
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConnInterface
+var xxx_messageInfo_SetupStatusRequest proto.InternalMessageInfo
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion6
+func (m *SetupStatusRequest) GetBeaconID() string {
+	if m != nil {
+		return m.BeaconID
+	}
+	return ""
+}
 
-// ControlClient is the client API for Control service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type ControlClient interface {
-	// PingPong returns an empty message. Purpose is to test the control port.
-	PingPong(ctx context.Context, in *Ping, opts ...grpc.CallOption) (*Pong, error)
-	// InitDKG sends information to daemon to start a fresh DKG protocol
-	InitDKG(ctx context.Context, in *InitDKGPacket, opts ...grpc.CallOption) (*GroupPacket, error)
-	// InitReshares sends all informations so that the drand node knows how to
-	// proceeed during the next resharing protocol.
-	InitReshare(ctx context.Context, in *InitResharePacket, opts ...grpc.CallOption) (*GroupPacket, error)
-	// Share returns the current private share used by the node
-	Share(ctx context.Context, in *ShareRequest, opts ...grpc.CallOption) (*ShareResponse, error)
-	// PublicKey returns the longterm public key of the drand node
-	PublicKey(ctx context.Context, in *PublicKeyRequest, opts ...grpc.CallOption) (*PublicKeyResponse, error)
-	// PrivateKey returns the longterm private key of the drand node
-	PrivateKey(ctx context.Context, in *PrivateKeyRequest, opts ...grpc.CallOption) (*PrivateKeyResponse, error)
-	// CollectiveKey returns the distributed public key used by the node
-	CollectiveKey(ctx context.Context, in *CokeyRequest, opts ...grpc.CallOption) (*CokeyResponse, error)
-	// GroupFile returns the TOML-encoded group file
-	// similar to public.Group method but needed for ease of use of the
-	// control functionalities
-	GroupFile(ctx context.Context, in *GroupRequest, opts ...grpc.CallOption) (*GroupPacket, error)
-	Shutdown(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownResponse, error)
+type SetupStatusResponse struct {
+	Expected uint32 `protobuf:"varint,1,opt,name=expected,proto3" json:"expected,omitempty"`
+	// participants that sent their key to the leader, including the leader
+	Joined []*Identity `protobuf:"bytes,2,rep,name=joined,proto3" json:"joined,omitempty"`
+	// allowed participants that did not join yet, only set if the leader runs
+	// with a list of allowed participants
+	Missing              []*Identity `protobuf:"bytes,3,rep,name=missing,proto3" json:"missing,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
 }
 
-type controlClient struct {
-	cc grpc.ClientConnInterface
+func (m *SetupStatusResponse) Reset()         { *m = SetupStatusResponse{} }
+func (m *SetupStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*SetupStatusResponse) ProtoMessage()    {}
+func (*SetupStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dd5961950a69ad7, []int{21}
 }
 
-func NewControlClient(cc grpc.ClientConnInterface) ControlClient {
-	return &controlClient{cc}
+func (m *SetupStatusResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetupStatusResponse.Unmarshal(m, b)
+}
+func (m *SetupStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetupStatusResponse.Marshal(b, m, deterministic)
+}
+func (m *SetupStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetupStatusResponse.Merge(m, src)
+}
+func (m *SetupStatusResponse) XXX_Size() int {
+	return xxx_messageInfo_SetupStatusResponse.Size(m)
+}
+func (m *SetupStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetupStatusResponse.DiscardUnknown(m)
 }
 
-func (c *controlClient) PingPong(ctx context.Context, in *Ping, opts ...grpc.CallOption) (*Pong, error) {
-	out := new(Pong)
-	err := c.cc.Invoke(ctx, "/drand.Control/PingPong", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_SetupStatusResponse proto.InternalMessageInfo
+
+func (m *SetupStatusResponse) GetExpected() uint32 {
+	if m != nil {
+		return m.Expected
 	}
-	return out, nil
+	return 0
 }
 
-func (c *controlClient) InitDKG(ctx context.Context, in *InitDKGPacket, opts ...grpc.CallOption) (*GroupPacket, error) {
-	out := new(GroupPacket)
-	err := c.cc.Invoke(ctx, "/drand.Control/InitDKG", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *SetupStatusResponse) GetJoined() []*Identity {
+	if m != nil {
+		return m.Joined
 	}
-	return out, nil
+	return nil
 }
 
-func (c *controlClient) InitReshare(ctx context.Context, in *InitResharePacket, opts ...grpc.CallOption) (*GroupPacket, error) {
-	out := new(GroupPacket)
-	err := c.cc.Invoke(ctx, "/drand.Control/InitReshare", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *SetupStatusResponse) GetMissing() []*Identity {
+	if m != nil {
+		return m.Missing
 	}
-	return out, nil
+	return nil
 }
 
-func (c *controlClient) Share(ctx context.Context, in *ShareRequest, opts ...grpc.CallOption) (*ShareResponse, error) {
-	out := new(ShareResponse)
-	err := c.cc.Invoke(ctx, "/drand.Control/Share", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type PendingGroupRequest struct {
+	// beaconID identifies the chain, empty for the default one
+	BeaconID             string   `protobuf:"bytes,1,opt,name=beaconID,proto3" json:"beaconID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (c *controlClient) PublicKey(ctx context.Context, in *PublicKeyRequest, opts ...grpc.CallOption) (*PublicKeyResponse, error) {
-	out := new(PublicKeyResponse)
-	err := c.cc.Invoke(ctx, "/drand.Control/PublicKey", in, out, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (m *PendingGroupRequest) Reset()         { *m = PendingGroupRequest{} }
+func (m *PendingGroupRequest) String() string { return proto.CompactTextString(m) }
+func (*PendingGroupRequest) ProtoMessage()    {}
+func (*PendingGroupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dd5961950a69ad7, []int{22}
 }
 
-func (c *controlClient) PrivateKey(ctx context.Context, in *PrivateKeyRequest, opts ...grpc.CallOption) (*PrivateKeyResponse, error) {
-	out := new(PrivateKeyResponse)
-	err := c.cc.Invoke(ctx, "/drand.Control/PrivateKey", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *PendingGroupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PendingGroupRequest.Unmarshal(m, b)
+}
+func (m *PendingGroupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PendingGroupRequest.Marshal(b, m, deterministic)
+}
+func (m *PendingGroupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PendingGroupRequest.Merge(m, src)
+}
+func (m *PendingGroupRequest) XXX_Size() int {
+	return xxx_messageInfo_PendingGroupRequest.Size(m)
+}
+func (m *PendingGroupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PendingGroupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PendingGroupRequest proto.InternalMessageInfo
+
+func (m *PendingGroupRequest) GetBeaconID() string {
+	if m != nil {
+		return m.BeaconID
 	}
-	return out, nil
+	return ""
+}
+
+type AcceptGroupRequest struct {
+	// hash of the group the operator has been shown
+	Hash   string `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Accept bool   `protobuf:"varint,2,opt,name=accept,proto3" json:"accept,omitempty"`
+	// beaconID identifies the chain, empty for the default one
+	BeaconID             string   `protobuf:"bytes,3,opt,name=beaconID,proto3" json:"beaconID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AcceptGroupRequest) Reset()         { *m = AcceptGroupRequest{} }
+func (m *AcceptGroupRequest) String() string { return proto.CompactTextString(m) }
+func (*AcceptGroupRequest) ProtoMessage()    {}
+func (*AcceptGroupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dd5961950a69ad7, []int{23}
+}
+
+func (m *AcceptGroupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AcceptGroupRequest.Unmarshal(m, b)
+}
+func (m *AcceptGroupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AcceptGroupRequest.Marshal(b, m, deterministic)
+}
+func (m *AcceptGroupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AcceptGroupRequest.Merge(m, src)
+}
+func (m *AcceptGroupRequest) XXX_Size() int {
+	return xxx_messageInfo_AcceptGroupRequest.Size(m)
+}
+func (m *AcceptGroupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AcceptGroupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AcceptGroupRequest proto.InternalMessageInfo
+
+func (m *AcceptGroupRequest) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *AcceptGroupRequest) GetAccept() bool {
+	if m != nil {
+		return m.Accept
+	}
+	return false
+}
+
+func (m *AcceptGroupRequest) GetBeaconID() string {
+	if m != nil {
+		return m.BeaconID
+	}
+	return ""
+}
+
+type AcceptGroupResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AcceptGroupResponse) Reset()         { *m = AcceptGroupResponse{} }
+func (m *AcceptGroupResponse) String() string { return proto.CompactTextString(m) }
+func (*AcceptGroupResponse) ProtoMessage()    {}
+func (*AcceptGroupResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dd5961950a69ad7, []int{24}
+}
+
+func (m *AcceptGroupResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AcceptGroupResponse.Unmarshal(m, b)
+}
+func (m *AcceptGroupResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AcceptGroupResponse.Marshal(b, m, deterministic)
+}
+func (m *AcceptGroupResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AcceptGroupResponse.Merge(m, src)
+}
+func (m *AcceptGroupResponse) XXX_Size() int {
+	return xxx_messageInfo_AcceptGroupResponse.Size(m)
+}
+func (m *AcceptGroupResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AcceptGroupResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AcceptGroupResponse proto.InternalMessageInfo
+
+type CancelDKGRequest struct {
+	// beaconID identifies the chain, empty for the default one
+	BeaconID             string   `protobuf:"bytes,1,opt,name=beaconID,proto3" json:"beaconID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CancelDKGRequest) Reset()         { *m = CancelDKGRequest{} }
+func (m *CancelDKGRequest) String() string { return proto.CompactTextString(m) }
+func (*CancelDKGRequest) ProtoMessage()    {}
+func (*CancelDKGRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dd5961950a69ad7, []int{25}
+}
+
+func (m *CancelDKGRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelDKGRequest.Unmarshal(m, b)
+}
+func (m *CancelDKGRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelDKGRequest.Marshal(b, m, deterministic)
+}
+func (m *CancelDKGRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelDKGRequest.Merge(m, src)
+}
+func (m *CancelDKGRequest) XXX_Size() int {
+	return xxx_messageInfo_CancelDKGRequest.Size(m)
+}
+func (m *CancelDKGRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelDKGRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelDKGRequest proto.InternalMessageInfo
+
+func (m *CancelDKGRequest) GetBeaconID() string {
+	if m != nil {
+		return m.BeaconID
+	}
+	return ""
+}
+
+type CancelDKGResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CancelDKGResponse) Reset()         { *m = CancelDKGResponse{} }
+func (m *CancelDKGResponse) String() string { return proto.CompactTextString(m) }
+func (*CancelDKGResponse) ProtoMessage()    {}
+func (*CancelDKGResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dd5961950a69ad7, []int{26}
+}
+
+func (m *CancelDKGResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelDKGResponse.Unmarshal(m, b)
+}
+func (m *CancelDKGResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelDKGResponse.Marshal(b, m, deterministic)
+}
+func (m *CancelDKGResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelDKGResponse.Merge(m, src)
+}
+func (m *CancelDKGResponse) XXX_Size() int {
+	return xxx_messageInfo_CancelDKGResponse.Size(m)
+}
+func (m *CancelDKGResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelDKGResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelDKGResponse proto.InternalMessageInfo
+
+type CancelTransitionRequest struct {
+	// beaconID identifies the chain, empty for the default one
+	BeaconID             string   `protobuf:"bytes,1,opt,name=beaconID,proto3" json:"beaconID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CancelTransitionRequest) Reset()         { *m = CancelTransitionRequest{} }
+func (m *CancelTransitionRequest) String() string { return proto.CompactTextString(m) }
+func (*CancelTransitionRequest) ProtoMessage()    {}
+func (*CancelTransitionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dd5961950a69ad7, []int{27}
+}
+
+func (m *CancelTransitionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelTransitionRequest.Unmarshal(m, b)
+}
+func (m *CancelTransitionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelTransitionRequest.Marshal(b, m, deterministic)
+}
+func (m *CancelTransitionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelTransitionRequest.Merge(m, src)
+}
+func (m *CancelTransitionRequest) XXX_Size() int {
+	return xxx_messageInfo_CancelTransitionRequest.Size(m)
+}
+func (m *CancelTransitionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelTransitionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelTransitionRequest proto.InternalMessageInfo
+
+func (m *CancelTransitionRequest) GetBeaconID() string {
+	if m != nil {
+		return m.BeaconID
+	}
+	return ""
+}
+
+type CancelTransitionResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CancelTransitionResponse) Reset()         { *m = CancelTransitionResponse{} }
+func (m *CancelTransitionResponse) String() string { return proto.CompactTextString(m) }
+func (*CancelTransitionResponse) ProtoMessage()    {}
+func (*CancelTransitionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dd5961950a69ad7, []int{28}
+}
+
+func (m *CancelTransitionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CancelTransitionResponse.Unmarshal(m, b)
+}
+func (m *CancelTransitionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CancelTransitionResponse.Marshal(b, m, deterministic)
+}
+func (m *CancelTransitionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CancelTransitionResponse.Merge(m, src)
+}
+func (m *CancelTransitionResponse) XXX_Size() int {
+	return xxx_messageInfo_CancelTransitionResponse.Size(m)
+}
+func (m *CancelTransitionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CancelTransitionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CancelTransitionResponse proto.InternalMessageInfo
+
+type BackupRequest struct {
+	// beaconID identifies the chain, empty for the default one
+	BeaconID             string   `protobuf:"bytes,1,opt,name=beaconID,proto3" json:"beaconID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BackupRequest) Reset()         { *m = BackupRequest{} }
+func (m *BackupRequest) String() string { return proto.CompactTextString(m) }
+func (*BackupRequest) ProtoMessage()    {}
+func (*BackupRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dd5961950a69ad7, []int{29}
+}
+
+func (m *BackupRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BackupRequest.Unmarshal(m, b)
+}
+func (m *BackupRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BackupRequest.Marshal(b, m, deterministic)
+}
+func (m *BackupRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BackupRequest.Merge(m, src)
+}
+func (m *BackupRequest) XXX_Size() int {
+	return xxx_messageInfo_BackupRequest.Size(m)
+}
+func (m *BackupRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BackupRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BackupRequest proto.InternalMessageInfo
+
+func (m *BackupRequest) GetBeaconID() string {
+	if m != nil {
+		return m.BeaconID
+	}
+	return ""
+}
+
+type BackupChunk struct {
+	// next bytes of the archive
+	Data                 []byte   `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BackupChunk) Reset()         { *m = BackupChunk{} }
+func (m *BackupChunk) String() string { return proto.CompactTextString(m) }
+func (*BackupChunk) ProtoMessage()    {}
+func (*BackupChunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dd5961950a69ad7, []int{30}
+}
+
+func (m *BackupChunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BackupChunk.Unmarshal(m, b)
+}
+func (m *BackupChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BackupChunk.Marshal(b, m, deterministic)
+}
+func (m *BackupChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BackupChunk.Merge(m, src)
+}
+func (m *BackupChunk) XXX_Size() int {
+	return xxx_messageInfo_BackupChunk.Size(m)
+}
+func (m *BackupChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_BackupChunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BackupChunk proto.InternalMessageInfo
+
+func (m *BackupChunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type PeersRequest struct {
+	// beaconID identifies the chain, empty for the default one
+	BeaconID             string   `protobuf:"bytes,1,opt,name=beaconID,proto3" json:"beaconID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PeersRequest) Reset()         { *m = PeersRequest{} }
+func (m *PeersRequest) String() string { return proto.CompactTextString(m) }
+func (*PeersRequest) ProtoMessage()    {}
+func (*PeersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dd5961950a69ad7, []int{31}
+}
+
+func (m *PeersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PeersRequest.Unmarshal(m, b)
+}
+func (m *PeersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PeersRequest.Marshal(b, m, deterministic)
+}
+func (m *PeersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeersRequest.Merge(m, src)
+}
+func (m *PeersRequest) XXX_Size() int {
+	return xxx_messageInfo_PeersRequest.Size(m)
+}
+func (m *PeersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeersRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeersRequest proto.InternalMessageInfo
+
+func (m *PeersRequest) GetBeaconID() string {
+	if m != nil {
+		return m.BeaconID
+	}
+	return ""
+}
+
+// PeerScore describes the behavior of a peer seen by the node
+type PeerScore struct {
+	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	// latency is the moving average of the duration of the successful
+	// requests to the peer, in milliseconds
+	Latency   uint64 `protobuf:"varint,2,opt,name=latency,proto3" json:"latency,omitempty"`
+	Successes uint64 `protobuf:"varint,3,opt,name=successes,proto3" json:"successes,omitempty"`
+	Failures  uint64 `protobuf:"varint,4,opt,name=failures,proto3" json:"failures,omitempty"`
+	// error_rate is the fraction of the requests to the peer that failed
+	ErrorRate float64 `protobuf:"fixed64,5,opt,name=error_rate,json=errorRate,proto3" json:"error_rate,omitempty"`
+	// last_round is the last round the peer is known to have
+	LastRound uint64 `protobuf:"varint,6,opt,name=last_round,json=lastRound,proto3" json:"last_round,omitempty"`
+	// backoff_until is the UNIX time until which the node tries the peer
+	// last when syncing, 0 if it doesn't back off
+	BackoffUntil         int64    `protobuf:"varint,7,opt,name=backoff_until,json=backoffUntil,proto3" json:"backoff_until,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PeerScore) Reset()         { *m = PeerScore{} }
+func (m *PeerScore) String() string { return proto.CompactTextString(m) }
+func (*PeerScore) ProtoMessage()    {}
+func (*PeerScore) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dd5961950a69ad7, []int{32}
+}
+
+func (m *PeerScore) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PeerScore.Unmarshal(m, b)
+}
+func (m *PeerScore) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PeerScore.Marshal(b, m, deterministic)
+}
+func (m *PeerScore) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeerScore.Merge(m, src)
+}
+func (m *PeerScore) XXX_Size() int {
+	return xxx_messageInfo_PeerScore.Size(m)
+}
+func (m *PeerScore) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeerScore.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeerScore proto.InternalMessageInfo
+
+func (m *PeerScore) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *PeerScore) GetLatency() uint64 {
+	if m != nil {
+		return m.Latency
+	}
+	return 0
+}
+
+func (m *PeerScore) GetSuccesses() uint64 {
+	if m != nil {
+		return m.Successes
+	}
+	return 0
+}
+
+func (m *PeerScore) GetFailures() uint64 {
+	if m != nil {
+		return m.Failures
+	}
+	return 0
+}
+
+func (m *PeerScore) GetErrorRate() float64 {
+	if m != nil {
+		return m.ErrorRate
+	}
+	return 0
+}
+
+func (m *PeerScore) GetLastRound() uint64 {
+	if m != nil {
+		return m.LastRound
+	}
+	return 0
+}
+
+func (m *PeerScore) GetBackoffUntil() int64 {
+	if m != nil {
+		return m.BackoffUntil
+	}
+	return 0
+}
+
+type PeersResponse struct {
+	Peers                []*PeerScore `protobuf:"bytes,1,rep,name=peers,proto3" json:"peers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *PeersResponse) Reset()         { *m = PeersResponse{} }
+func (m *PeersResponse) String() string { return proto.CompactTextString(m) }
+func (*PeersResponse) ProtoMessage()    {}
+func (*PeersResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dd5961950a69ad7, []int{33}
+}
+
+func (m *PeersResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PeersResponse.Unmarshal(m, b)
+}
+func (m *PeersResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PeersResponse.Marshal(b, m, deterministic)
+}
+func (m *PeersResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeersResponse.Merge(m, src)
+}
+func (m *PeersResponse) XXX_Size() int {
+	return xxx_messageInfo_PeersResponse.Size(m)
+}
+func (m *PeersResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeersResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeersResponse proto.InternalMessageInfo
+
+func (m *PeersResponse) GetPeers() []*PeerScore {
+	if m != nil {
+		return m.Peers
+	}
+	return nil
+}
+
+type SyncStatusRequest struct {
+	// beaconID identifies the chain, empty for the default one
+	BeaconID             string   `protobuf:"bytes,1,opt,name=beaconID,proto3" json:"beaconID,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SyncStatusRequest) Reset()         { *m = SyncStatusRequest{} }
+func (m *SyncStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*SyncStatusRequest) ProtoMessage()    {}
+func (*SyncStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dd5961950a69ad7, []int{34}
+}
+
+func (m *SyncStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SyncStatusRequest.Unmarshal(m, b)
+}
+func (m *SyncStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SyncStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *SyncStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SyncStatusRequest.Merge(m, src)
+}
+func (m *SyncStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_SyncStatusRequest.Size(m)
+}
+func (m *SyncStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SyncStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SyncStatusRequest proto.InternalMessageInfo
+
+func (m *SyncStatusRequest) GetBeaconID() string {
+	if m != nil {
+		return m.BeaconID
+	}
+	return ""
+}
+
+type StartSyncRequest struct {
+	// beaconID identifies the chain, empty for the default one
+	BeaconID string `protobuf:"bytes,1,opt,name=beaconID,proto3" json:"beaconID,omitempty"`
+	// up_to is the last round to sync, 0 for the current round
+	UpTo                 uint64   `protobuf:"varint,2,opt,name=up_to,json=upTo,proto3" json:"up_to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StartSyncRequest) Reset()         { *m = StartSyncRequest{} }
+func (m *StartSyncRequest) String() string { return proto.CompactTextString(m) }
+func (*StartSyncRequest) ProtoMessage()    {}
+func (*StartSyncRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dd5961950a69ad7, []int{35}
+}
+
+func (m *StartSyncRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StartSyncRequest.Unmarshal(m, b)
+}
+func (m *StartSyncRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StartSyncRequest.Marshal(b, m, deterministic)
+}
+func (m *StartSyncRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StartSyncRequest.Merge(m, src)
+}
+func (m *StartSyncRequest) XXX_Size() int {
+	return xxx_messageInfo_StartSyncRequest.Size(m)
+}
+func (m *StartSyncRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StartSyncRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StartSyncRequest proto.InternalMessageInfo
+
+func (m *StartSyncRequest) GetBeaconID() string {
+	if m != nil {
+		return m.BeaconID
+	}
+	return ""
+}
+
+func (m *StartSyncRequest) GetUpTo() uint64 {
+	if m != nil {
+		return m.UpTo
+	}
+	return 0
+}
+
+type SyncStatusResponse struct {
+	// syncing is true while the node syncs its chain
+	Syncing bool `protobuf:"varint,1,opt,name=syncing,proto3" json:"syncing,omitempty"`
+	// target is the last round the sync fetches
+	Target uint64 `protobuf:"varint,2,opt,name=target,proto3" json:"target,omitempty"`
+	// current is the last round stored by the node
+	Current uint64 `protobuf:"varint,3,opt,name=current,proto3" json:"current,omitempty"`
+	// peers are the addresses of the peers the node currently syncs from
+	Peers []string `protobuf:"bytes,4,rep,name=peers,proto3" json:"peers,omitempty"`
+	// rate is the number of rounds stored per second since the sync started
+	Rate float64 `protobuf:"fixed64,5,opt,name=rate,proto3" json:"rate,omitempty"`
+	// eta is the estimated number of seconds left until the node reaches the
+	// target, 0 if unknown
+	Eta                  uint64   `protobuf:"varint,6,opt,name=eta,proto3" json:"eta,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SyncStatusResponse) Reset()         { *m = SyncStatusResponse{} }
+func (m *SyncStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*SyncStatusResponse) ProtoMessage()    {}
+func (*SyncStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2dd5961950a69ad7, []int{36}
+}
+
+func (m *SyncStatusResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SyncStatusResponse.Unmarshal(m, b)
+}
+func (m *SyncStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SyncStatusResponse.Marshal(b, m, deterministic)
+}
+func (m *SyncStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SyncStatusResponse.Merge(m, src)
+}
+func (m *SyncStatusResponse) XXX_Size() int {
+	return xxx_messageInfo_SyncStatusResponse.Size(m)
+}
+func (m *SyncStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SyncStatusResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SyncStatusResponse proto.InternalMessageInfo
+
+func (m *SyncStatusResponse) GetSyncing() bool {
+	if m != nil {
+		return m.Syncing
+	}
+	return false
+}
+
+func (m *SyncStatusResponse) GetTarget() uint64 {
+	if m != nil {
+		return m.Target
+	}
+	return 0
+}
+
+func (m *SyncStatusResponse) GetCurrent() uint64 {
+	if m != nil {
+		return m.Current
+	}
+	return 0
+}
+
+func (m *SyncStatusResponse) GetPeers() []string {
+	if m != nil {
+		return m.Peers
+	}
+	return nil
+}
+
+func (m *SyncStatusResponse) GetRate() float64 {
+	if m != nil {
+		return m.Rate
+	}
+	return 0
+}
+
+func (m *SyncStatusResponse) GetEta() uint64 {
+	if m != nil {
+		return m.Eta
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*SignPartialRequest)(nil), "drand.SignPartialRequest")
+	proto.RegisterType((*SignPartialResponse)(nil), "drand.SignPartialResponse")
+	proto.RegisterType((*SetupInfoPacket)(nil), "drand.SetupInfoPacket")
+	proto.RegisterType((*InitDKGPacket)(nil), "drand.InitDKGPacket")
+	proto.RegisterType((*EntropyInfo)(nil), "drand.EntropyInfo")
+	proto.RegisterType((*InitResharePacket)(nil), "drand.InitResharePacket")
+	proto.RegisterType((*GroupInfo)(nil), "drand.GroupInfo")
+	proto.RegisterType((*ShareRequest)(nil), "drand.ShareRequest")
+	proto.RegisterType((*ShareResponse)(nil), "drand.ShareResponse")
+	proto.RegisterType((*Ping)(nil), "drand.Ping")
+	proto.RegisterType((*Pong)(nil), "drand.Pong")
+	proto.RegisterType((*PublicKeyRequest)(nil), "drand.PublicKeyRequest")
+	proto.RegisterType((*PublicKeyResponse)(nil), "drand.PublicKeyResponse")
+	proto.RegisterType((*PrivateKeyRequest)(nil), "drand.PrivateKeyRequest")
+	proto.RegisterType((*PrivateKeyResponse)(nil), "drand.PrivateKeyResponse")
+	proto.RegisterType((*CokeyRequest)(nil), "drand.CokeyRequest")
+	proto.RegisterType((*CokeyResponse)(nil), "drand.CokeyResponse")
+	proto.RegisterType((*GroupTOMLResponse)(nil), "drand.GroupTOMLResponse")
+	proto.RegisterType((*ShutdownRequest)(nil), "drand.ShutdownRequest")
+	proto.RegisterType((*ShutdownResponse)(nil), "drand.ShutdownResponse")
+	proto.RegisterType((*SetupStatusRequest)(nil), "drand.SetupStatusRequest")
+	proto.RegisterType((*SetupStatusResponse)(nil), "drand.SetupStatusResponse")
+	proto.RegisterType((*PendingGroupRequest)(nil), "drand.PendingGroupRequest")
+	proto.RegisterType((*AcceptGroupRequest)(nil), "drand.AcceptGroupRequest")
+	proto.RegisterType((*AcceptGroupResponse)(nil), "drand.AcceptGroupResponse")
+	proto.RegisterType((*CancelDKGRequest)(nil), "drand.CancelDKGRequest")
+	proto.RegisterType((*CancelDKGResponse)(nil), "drand.CancelDKGResponse")
+	proto.RegisterType((*CancelTransitionRequest)(nil), "drand.CancelTransitionRequest")
+	proto.RegisterType((*CancelTransitionResponse)(nil), "drand.CancelTransitionResponse")
+	proto.RegisterType((*BackupRequest)(nil), "drand.BackupRequest")
+	proto.RegisterType((*BackupChunk)(nil), "drand.BackupChunk")
+	proto.RegisterType((*PeersRequest)(nil), "drand.PeersRequest")
+	proto.RegisterType((*PeerScore)(nil), "drand.PeerScore")
+	proto.RegisterType((*PeersResponse)(nil), "drand.PeersResponse")
+	proto.RegisterType((*SyncStatusRequest)(nil), "drand.SyncStatusRequest")
+	proto.RegisterType((*StartSyncRequest)(nil), "drand.StartSyncRequest")
+	proto.RegisterType((*SyncStatusResponse)(nil), "drand.SyncStatusResponse")
+}
+
+func init() {
+	proto.RegisterFile("drand/control.proto", fileDescriptor_2dd5961950a69ad7)
+}
+
+var fileDescriptor_2dd5961950a69ad7 = []byte{
+	// 1424 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x57, 0x4d, 0x73, 0x1b, 0x45,
+	0x13, 0xf6, 0x5a, 0xdf, 0x2d, 0xeb, 0x8d, 0x3c, 0xd2, 0x9b, 0x6c, 0xb6, 0x08, 0x31, 0x4b, 0xc5,
+	0x98, 0x24, 0xe5, 0x04, 0x41, 0xc2, 0x01, 0xa8, 0xc2, 0x71, 0x48, 0xe2, 0x32, 0x54, 0x54, 0x2b,
+	0xe7, 0x42, 0x51, 0xa5, 0x1a, 0xef, 0x8e, 0xa4, 0xc5, 0xab, 0x19, 0x31, 0x3b, 0x9b, 0xc4, 0x27,
+	0x4e, 0xfc, 0x0b, 0x8e, 0xfc, 0x22, 0xce, 0xfc, 0x12, 0x4e, 0xd4, 0x7c, 0xec, 0x6a, 0x56, 0xb2,
+	0x53, 0x3a, 0x69, 0xfb, 0xe9, 0x9e, 0x9e, 0x9e, 0x9e, 0xee, 0x7e, 0x46, 0xd0, 0x8b, 0x38, 0xa6,
+	0xd1, 0xa3, 0x90, 0x51, 0xc1, 0x59, 0x72, 0xb8, 0xe0, 0x4c, 0x30, 0x54, 0x53, 0xa0, 0x87, 0x72,
+	0xdd, 0x7c, 0xce, 0xa8, 0x56, 0xf9, 0xfb, 0x80, 0x46, 0xf1, 0x94, 0x0e, 0x31, 0x17, 0x31, 0x4e,
+	0x02, 0xf2, 0x5b, 0x46, 0x52, 0x81, 0xba, 0x50, 0x99, 0xa7, 0x53, 0xd7, 0xd9, 0x73, 0x0e, 0x76,
+	0x02, 0xf9, 0xe9, 0x3f, 0x85, 0x5e, 0xc9, 0x2e, 0x5d, 0x30, 0x9a, 0x12, 0x74, 0x17, 0xda, 0x0b,
+	0x0d, 0x8d, 0xd3, 0x38, 0x5f, 0x00, 0x06, 0x1a, 0xc5, 0x53, 0xff, 0xaf, 0x6d, 0xb8, 0x31, 0x22,
+	0x22, 0x5b, 0x9c, 0xd0, 0x09, 0x1b, 0xe2, 0xf0, 0x82, 0x08, 0x74, 0x13, 0xea, 0x09, 0xc1, 0x11,
+	0xe1, 0xca, 0xbe, 0x19, 0x18, 0x09, 0xdd, 0x83, 0xff, 0xe9, 0xaf, 0x31, 0x8e, 0x22, 0x4e, 0xd2,
+	0xd4, 0xdd, 0xde, 0x73, 0x0e, 0x5a, 0x41, 0x47, 0xa3, 0x47, 0x1a, 0x44, 0x77, 0x00, 0x8c, 0x99,
+	0x48, 0x52, 0xb7, 0xa2, 0x5c, 0xb4, 0x34, 0x72, 0x96, 0xa4, 0xa8, 0x0f, 0x35, 0xca, 0x22, 0x92,
+	0xba, 0xd5, 0x3d, 0xe7, 0xa0, 0x13, 0x68, 0x01, 0x7d, 0x04, 0x2d, 0x31, 0xe3, 0x24, 0x9d, 0xb1,
+	0x24, 0x72, 0x6b, 0x4a, 0xb3, 0x04, 0x90, 0x0b, 0x0d, 0x11, 0xcf, 0x09, 0xcb, 0x84, 0x5b, 0x57,
+	0x5b, 0xe6, 0xa2, 0x8c, 0x35, 0x25, 0x21, 0x27, 0xc2, 0x6d, 0x28, 0x85, 0x91, 0x90, 0x0f, 0x3b,
+	0xe7, 0x04, 0x87, 0x8c, 0xbe, 0x9e, 0x4c, 0x52, 0x22, 0xdc, 0xa6, 0x72, 0x59, 0xc2, 0xd0, 0xe7,
+	0xd0, 0xc0, 0x49, 0xc2, 0xde, 0x91, 0xc8, 0x6d, 0xed, 0x55, 0x0e, 0xda, 0x83, 0x1b, 0x87, 0xea,
+	0x06, 0x0e, 0x4f, 0x22, 0x42, 0x45, 0x2c, 0x2e, 0x83, 0x5c, 0xef, 0xff, 0xed, 0x40, 0xe7, 0x84,
+	0xc6, 0xe2, 0xf9, 0xe9, 0x4b, 0x93, 0xa4, 0xfb, 0x50, 0x8d, 0xe9, 0x84, 0xa9, 0x14, 0xb5, 0x07,
+	0x37, 0xcd, 0xca, 0x95, 0x54, 0x06, 0xca, 0x06, 0x3d, 0x84, 0x06, 0x91, 0xf7, 0xbd, 0xb8, 0x54,
+	0x19, 0x6b, 0x0f, 0x90, 0x31, 0xff, 0x41, 0xa3, 0x72, 0x41, 0x90, 0x9b, 0xa0, 0x4f, 0xa1, 0xa3,
+	0xc3, 0x1c, 0x2f, 0x08, 0x8f, 0x59, 0xe4, 0x56, 0xec, 0xd8, 0x87, 0x0a, 0x43, 0xfb, 0x50, 0x9b,
+	0x72, 0x96, 0x2d, 0x54, 0x16, 0xdb, 0x83, 0xae, 0x71, 0xf8, 0x52, 0x62, 0xca, 0x9d, 0x56, 0x23,
+	0x0f, 0x9a, 0x7a, 0xdd, 0xc9, 0x73, 0x95, 0xd6, 0x56, 0x50, 0xc8, 0xfe, 0x11, 0xb4, 0xad, 0x00,
+	0x54, 0x2a, 0x43, 0x1e, 0x2f, 0x84, 0xeb, 0x98, 0x54, 0x2a, 0x49, 0xba, 0xc8, 0x52, 0xc2, 0x5f,
+	0xd3, 0xe4, 0xd2, 0x05, 0x75, 0x9b, 0x85, 0xec, 0xff, 0x0e, 0xbb, 0x32, 0x2d, 0x01, 0x49, 0x67,
+	0x98, 0x13, 0x93, 0x1a, 0x1f, 0x2a, 0xf2, 0x16, 0x9d, 0x6b, 0x22, 0x93, 0xca, 0x22, 0x7d, 0xdb,
+	0x1b, 0xa4, 0xcf, 0x3e, 0x43, 0x65, 0xe5, 0x0c, 0x6f, 0xa0, 0x55, 0x78, 0x46, 0x7d, 0xa8, 0x2e,
+	0xb0, 0x98, 0xe9, 0xf8, 0x5f, 0x6d, 0x05, 0x4a, 0x42, 0x08, 0x2a, 0x19, 0x4f, 0x74, 0xad, 0xbe,
+	0xda, 0x0a, 0xa4, 0x80, 0x10, 0x54, 0x67, 0x38, 0x9d, 0x19, 0x77, 0xea, 0xfb, 0x19, 0x40, 0x33,
+	0x61, 0x21, 0x16, 0x31, 0xa3, 0xfe, 0x7d, 0xd8, 0x19, 0xc9, 0x13, 0xe5, 0x0d, 0x67, 0x87, 0xe0,
+	0xac, 0x84, 0xf0, 0x0d, 0x74, 0x8c, 0xad, 0x69, 0xba, 0x3e, 0xd4, 0x62, 0x1a, 0x91, 0xf7, 0x6a,
+	0xcb, 0x4e, 0xa0, 0x05, 0x89, 0xaa, 0x24, 0xa9, 0x3d, 0x77, 0x02, 0x2d, 0xf8, 0x75, 0xa8, 0x0e,
+	0x63, 0x3a, 0x55, 0xbf, 0x8c, 0x4e, 0xfd, 0x43, 0xe8, 0x0e, 0xb3, 0xf3, 0x24, 0x0e, 0x4f, 0xc9,
+	0xe5, 0x26, 0x9b, 0x3f, 0x80, 0x5d, 0xcb, 0xde, 0x04, 0x70, 0x13, 0xea, 0x8b, 0xec, 0xfc, 0x94,
+	0xe8, 0x72, 0xdb, 0x09, 0x8c, 0xe4, 0x3f, 0x82, 0xdd, 0x21, 0x8f, 0xdf, 0x62, 0x41, 0x36, 0xf4,
+	0xfe, 0x10, 0x90, 0xbd, 0xc0, 0x72, 0xcf, 0x63, 0xdb, 0xbd, 0x92, 0x64, 0xd2, 0x8e, 0xd9, 0xc5,
+	0x66, 0x9e, 0xef, 0x41, 0xc7, 0xd8, 0x2e, 0x93, 0x16, 0xb2, 0xa5, 0x4f, 0x2d, 0xf8, 0x03, 0xd8,
+	0x55, 0xd7, 0x7b, 0xf6, 0xfa, 0xa7, 0x1f, 0x0b, 0xd3, 0x3b, 0x00, 0xaa, 0xb8, 0xc7, 0x82, 0xcd,
+	0x13, 0xe3, 0xb9, 0xa5, 0x90, 0x33, 0x36, 0x4f, 0xfc, 0x5d, 0xb8, 0x31, 0x9a, 0x65, 0x22, 0x62,
+	0xef, 0xa8, 0x89, 0xc4, 0x47, 0xd0, 0x5d, 0x42, 0xda, 0x8b, 0xff, 0x18, 0x90, 0x2a, 0xb7, 0x91,
+	0xc0, 0x22, 0x4b, 0x37, 0x89, 0xf9, 0x0f, 0x07, 0x7a, 0xa5, 0x25, 0x26, 0x1e, 0x0f, 0x9a, 0xe4,
+	0xfd, 0x82, 0x84, 0x82, 0xe8, 0xa2, 0xef, 0x04, 0x85, 0x8c, 0x3e, 0x83, 0xfa, 0xaf, 0x2c, 0xa6,
+	0x24, 0x72, 0xb7, 0xaf, 0x1e, 0x31, 0x46, 0x2d, 0x87, 0xd1, 0x3c, 0x4e, 0xd3, 0x98, 0x4e, 0xdd,
+	0xca, 0x35, 0xc3, 0xc8, 0xe8, 0xfd, 0x2f, 0xa0, 0x37, 0x24, 0x34, 0x8a, 0xe9, 0x54, 0xe5, 0x66,
+	0x93, 0xd0, 0x7f, 0x01, 0x74, 0x14, 0x86, 0x64, 0x21, 0x4a, 0x2b, 0xf2, 0x2e, 0x70, 0x96, 0x5d,
+	0x20, 0x2f, 0x17, 0x2b, 0x4b, 0x75, 0x11, 0xcd, 0xc0, 0x48, 0x1f, 0x6c, 0xc2, 0xff, 0x43, 0xaf,
+	0xe4, 0xdd, 0x64, 0xf8, 0x10, 0xba, 0xc7, 0x98, 0x86, 0x24, 0x79, 0x7e, 0xfa, 0x72, 0x93, 0x20,
+	0x7b, 0xb0, 0x6b, 0xd9, 0x1b, 0x27, 0x4f, 0xe0, 0x96, 0x06, 0xcf, 0x38, 0xa6, 0x69, 0x2c, 0xbb,
+	0x73, 0x13, 0x5f, 0x1e, 0xb8, 0xeb, 0xcb, 0x8c, 0xcb, 0x07, 0xd0, 0x79, 0x86, 0xc3, 0x8b, 0xcd,
+	0x32, 0xf7, 0x09, 0xb4, 0xb5, 0xf1, 0xf1, 0x2c, 0xa3, 0x17, 0x32, 0x65, 0x11, 0x16, 0xd8, 0x30,
+	0xa9, 0xfa, 0x96, 0x75, 0x3f, 0x24, 0x84, 0x6f, 0x54, 0x43, 0xff, 0x38, 0xd0, 0x92, 0xc6, 0xa3,
+	0x90, 0x71, 0x22, 0x79, 0x2d, 0xa7, 0x52, 0x6d, 0x98, 0x8b, 0x52, 0x93, 0x60, 0x41, 0x68, 0xa8,
+	0x1b, 0xa2, 0x1a, 0xe4, 0xa2, 0x64, 0xca, 0x34, 0x0b, 0x43, 0x92, 0xa6, 0x44, 0xb3, 0x6b, 0x35,
+	0x58, 0x02, 0x72, 0xef, 0x09, 0x8e, 0x93, 0x8c, 0x1b, 0x82, 0xad, 0x06, 0x85, 0x2c, 0xfb, 0x86,
+	0x70, 0xce, 0xf8, 0x98, 0x63, 0x41, 0x14, 0x1b, 0x38, 0x41, 0x4b, 0x21, 0x01, 0x16, 0xaa, 0xad,
+	0x12, 0x9c, 0x8a, 0x31, 0x67, 0x19, 0x8d, 0x14, 0xcf, 0x56, 0x83, 0x96, 0x44, 0x02, 0x09, 0x28,
+	0x5a, 0xc2, 0xe1, 0x05, 0x9b, 0x4c, 0xc6, 0x19, 0x15, 0x71, 0xa2, 0x08, 0xb7, 0x12, 0xec, 0x18,
+	0xf0, 0x8d, 0xc4, 0xfc, 0xaf, 0xa1, 0x63, 0x52, 0x61, 0x7a, 0x63, 0x1f, 0x6a, 0x0b, 0x09, 0xb8,
+	0xce, 0x5e, 0xc5, 0x62, 0x83, 0x22, 0x05, 0x81, 0x56, 0xcb, 0xd1, 0x34, 0xba, 0xa4, 0xe1, 0xe6,
+	0xcd, 0x78, 0x0c, 0xdd, 0x91, 0xc0, 0x5c, 0xc8, 0x55, 0x1b, 0xd8, 0xa3, 0x1e, 0xd4, 0xd4, 0xc4,
+	0x30, 0xe9, 0xac, 0xca, 0x61, 0xe1, 0xff, 0xe9, 0x00, 0xb2, 0xb7, 0x35, 0x41, 0xbb, 0xd0, 0x48,
+	0x2f, 0x69, 0x28, 0x7b, 0x51, 0xbf, 0x80, 0x72, 0x51, 0x76, 0x87, 0xc0, 0x7c, 0x4a, 0x84, 0x71,
+	0x63, 0x24, 0xb9, 0x22, 0xcc, 0x38, 0x27, 0x54, 0x98, 0x2b, 0xc9, 0x45, 0x39, 0xd7, 0x74, 0x02,
+	0xaa, 0x7b, 0x95, 0x83, 0x96, 0x39, 0xae, 0x2c, 0x23, 0xeb, 0x12, 0xd4, 0xb7, 0x7c, 0xd4, 0x11,
+	0x81, 0x4d, 0xe2, 0xe5, 0xe7, 0xe0, 0xdf, 0x26, 0x34, 0x8e, 0xf5, 0x4b, 0x11, 0xed, 0x43, 0x53,
+	0x12, 0x85, 0x24, 0x09, 0xd4, 0xce, 0xb3, 0x18, 0xd3, 0xa9, 0x57, 0x08, 0x92, 0x3e, 0xb6, 0xd0,
+	0x13, 0x68, 0x98, 0x87, 0x0a, 0xea, 0x1b, 0x4d, 0xe9, 0xe1, 0xe2, 0x21, 0x9b, 0x90, 0x35, 0xe6,
+	0x6f, 0xa1, 0xef, 0xa0, 0x6d, 0x11, 0x39, 0x72, 0xad, 0xa5, 0x25, 0x72, 0xbf, 0x66, 0xf9, 0x57,
+	0x50, 0x53, 0x1c, 0x88, 0x7a, 0x46, 0x6d, 0xb3, 0xa7, 0xd7, 0x2f, 0x83, 0xa6, 0x0d, 0xb7, 0xd0,
+	0xf7, 0xd0, 0x2a, 0xc8, 0x0b, 0xdd, 0xca, 0xcf, 0xb1, 0x42, 0x7f, 0x9e, 0xbb, 0xae, 0x28, 0x3c,
+	0x1c, 0x03, 0x2c, 0x09, 0xaa, 0x88, 0x7a, 0x8d, 0xe4, 0xbc, 0xdb, 0x57, 0x68, 0x0a, 0x27, 0xdf,
+	0x4a, 0x2e, 0x4a, 0x12, 0x12, 0x8a, 0xf8, 0xad, 0xf2, 0x93, 0x1f, 0xc2, 0x66, 0x33, 0xaf, 0x5f,
+	0x06, 0x8b, 0xd5, 0x4f, 0xcd, 0x0b, 0xe4, 0x45, 0x9c, 0x2c, 0x8f, 0x6f, 0x8f, 0xd9, 0x6b, 0x33,
+	0xde, 0xcc, 0x39, 0x09, 0x15, 0xef, 0x9f, 0x32, 0x6f, 0x79, 0xb7, 0xd6, 0xf0, 0x62, 0xdb, 0x17,
+	0xd0, 0xb6, 0xb8, 0x08, 0xdd, 0xb6, 0x5f, 0x50, 0xa5, 0x2e, 0xf2, 0xbc, 0xab, 0x54, 0xd6, 0x1d,
+	0xec, 0xd8, 0x64, 0x82, 0xbc, 0xa2, 0x43, 0xd7, 0x18, 0xe6, 0x9a, 0x83, 0xbc, 0x80, 0xb6, 0x35,
+	0xfd, 0x8b, 0x48, 0xd6, 0xf9, 0xc6, 0xf3, 0xae, 0x52, 0xd9, 0xd5, 0x50, 0x8c, 0xff, 0xa2, 0x1a,
+	0x56, 0x09, 0xc4, 0x73, 0xd7, 0x15, 0x85, 0x87, 0x37, 0xd0, 0x5d, 0x1d, 0xfa, 0xe8, 0xe3, 0x92,
+	0xfd, 0x1a, 0x89, 0x78, 0x77, 0xaf, 0xd5, 0x5b, 0x37, 0x5c, 0xd7, 0x14, 0x50, 0x74, 0x54, 0x89,
+	0x3e, 0x3c, 0x54, 0x42, 0x15, 0x4f, 0xf8, 0x5b, 0x8f, 0x1d, 0xd9, 0x14, 0x6a, 0x18, 0x16, 0x55,
+	0x61, 0xb3, 0x84, 0xd7, 0x2f, 0x83, 0x76, 0x49, 0x2f, 0x47, 0x52, 0x51, 0xd2, 0x6b, 0xc3, 0xd1,
+	0xbb, 0x7d, 0x85, 0xa6, 0x70, 0x72, 0x04, 0xad, 0x62, 0x3a, 0x16, 0xb9, 0x5c, 0x9d, 0x97, 0x1f,
+	0x74, 0x31, 0x18, 0x42, 0x5d, 0xfe, 0xa3, 0x24, 0x5c, 0x95, 0xda, 0xf2, 0xbf, 0xe5, 0xb2, 0xd4,
+	0xd6, 0xfe, 0x97, 0x7a, 0xde, 0x55, 0xaa, 0xdc, 0xe3, 0xb3, 0xc6, 0xcf, 0xfa, 0x8f, 0xee, 0x79,
+	0x5d, 0xfd, 0xb7, 0xfd, 0xf2, 0xbf, 0x01, 0x00, 0x7f, 0xc2, 0x75, 0x0e, 0x0d, 0x0f, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// ControlClient is the client API for Control service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ControlClient interface {
+	// PingPong returns an empty message. Purpose is to test the control port.
+	PingPong(ctx context.Context, in *Ping, opts ...grpc.CallOption) (*Pong, error)
+	// InitDKG sends information to daemon to start a fresh DKG protocol
+	InitDKG(ctx context.Context, in *InitDKGPacket, opts ...grpc.CallOption) (*GroupPacket, error)
+	// InitReshares sends all informations so that the drand node knows how to
+	// proceeed during the next resharing protocol.
+	InitReshare(ctx context.Context, in *InitResharePacket, opts ...grpc.CallOption) (*GroupPacket, error)
+	// Share returns the current private share used by the node
+	Share(ctx context.Context, in *ShareRequest, opts ...grpc.CallOption) (*ShareResponse, error)
+	// PublicKey returns the longterm public key of the drand node
+	PublicKey(ctx context.Context, in *PublicKeyRequest, opts ...grpc.CallOption) (*PublicKeyResponse, error)
+	// PrivateKey returns the longterm private key of the drand node
+	PrivateKey(ctx context.Context, in *PrivateKeyRequest, opts ...grpc.CallOption) (*PrivateKeyResponse, error)
+	// CollectiveKey returns the distributed public key used by the node
+	CollectiveKey(ctx context.Context, in *CokeyRequest, opts ...grpc.CallOption) (*CokeyResponse, error)
+	// GroupFile returns the TOML-encoded group file
+	// similar to public.Group method but needed for ease of use of the
+	// control functionalities
+	GroupFile(ctx context.Context, in *GroupRequest, opts ...grpc.CallOption) (*GroupPacket, error)
+	Shutdown(ctx context.Context, in *ShutdownRequest, opts ...grpc.CallOption) (*ShutdownResponse, error)
+	// SetupStatus returns the participants that joined the setup run by this
+	// node as a leader and the allowed ones that did not join yet
+	SetupStatus(ctx context.Context, in *SetupStatusRequest, opts ...grpc.CallOption) (*SetupStatusResponse, error)
+	// PendingGroup returns the group received from the leader during the
+	// setup, that the operator must accept before the DKG starts
+	PendingGroup(ctx context.Context, in *PendingGroupRequest, opts ...grpc.CallOption) (*GroupPacket, error)
+	// AcceptGroup accepts or rejects the group received from the leader
+	AcceptGroup(ctx context.Context, in *AcceptGroupRequest, opts ...grpc.CallOption) (*AcceptGroupResponse, error)
+	// CancelDKG aborts the setup or the DKG in progress
+	CancelDKG(ctx context.Context, in *CancelDKGRequest, opts ...grpc.CallOption) (*CancelDKGResponse, error)
+	// CancelTransition cancels the transition to the new group scheduled after
+	// a resharing, so the node keeps using the old group
+	CancelTransition(ctx context.Context, in *CancelTransitionRequest, opts ...grpc.CallOption) (*CancelTransitionResponse, error)
+	// Backup streams an archive of the key material and of a consistent
+	// snapshot of the beacon database of the node
+	Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (Control_BackupClient, error)
+	// Peers returns the scores the node keeps of its peers, from the partial
+	// beacons it sends to them and the syncs it runs with them
+	Peers(ctx context.Context, in *PeersRequest, opts ...grpc.CallOption) (*PeersResponse, error)
+	// SyncStatus returns the progress of the sync of the chain the node runs,
+	// if any
+	SyncStatus(ctx context.Context, in *SyncStatusRequest, opts ...grpc.CallOption) (*SyncStatusResponse, error)
+	// StartSync makes the node sync its chain from its peers up to the given
+	// round
+	StartSync(ctx context.Context, in *StartSyncRequest, opts ...grpc.CallOption) (*SyncStatusResponse, error)
+}
+
+type controlClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewControlClient(cc grpc.ClientConnInterface) ControlClient {
+	return &controlClient{cc}
+}
+
+func (c *controlClient) PingPong(ctx context.Context, in *Ping, opts ...grpc.CallOption) (*Pong, error) {
+	out := new(Pong)
+	err := c.cc.Invoke(ctx, "/drand.Control/PingPong", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) InitDKG(ctx context.Context, in *InitDKGPacket, opts ...grpc.CallOption) (*GroupPacket, error) {
+	out := new(GroupPacket)
+	err := c.cc.Invoke(ctx, "/drand.Control/InitDKG", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) InitReshare(ctx context.Context, in *InitResharePacket, opts ...grpc.CallOption) (*GroupPacket, error) {
+	out := new(GroupPacket)
+	err := c.cc.Invoke(ctx, "/drand.Control/InitReshare", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) Share(ctx context.Context, in *ShareRequest, opts ...grpc.CallOption) (*ShareResponse, error) {
+	out := new(ShareResponse)
+	err := c.cc.Invoke(ctx, "/drand.Control/Share", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) PublicKey(ctx context.Context, in *PublicKeyRequest, opts ...grpc.CallOption) (*PublicKeyResponse, error) {
+	out := new(PublicKeyResponse)
+	err := c.cc.Invoke(ctx, "/drand.Control/PublicKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) PrivateKey(ctx context.Context, in *PrivateKeyRequest, opts ...grpc.CallOption) (*PrivateKeyResponse, error) {
+	out := new(PrivateKeyResponse)
+	err := c.cc.Invoke(ctx, "/drand.Control/PrivateKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
 func (c *controlClient) CollectiveKey(ctx context.Context, in *CokeyRequest, opts ...grpc.CallOption) (*CokeyResponse, error) {
@@ -1024,6 +2071,110 @@ func (c *controlClient) Shutdown(ctx context.Context, in *ShutdownRequest, opts
 	return out, nil
 }
 
+func (c *controlClient) SetupStatus(ctx context.Context, in *SetupStatusRequest, opts ...grpc.CallOption) (*SetupStatusResponse, error) {
+	out := new(SetupStatusResponse)
+	err := c.cc.Invoke(ctx, "/drand.Control/SetupStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) PendingGroup(ctx context.Context, in *PendingGroupRequest, opts ...grpc.CallOption) (*GroupPacket, error) {
+	out := new(GroupPacket)
+	err := c.cc.Invoke(ctx, "/drand.Control/PendingGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) AcceptGroup(ctx context.Context, in *AcceptGroupRequest, opts ...grpc.CallOption) (*AcceptGroupResponse, error) {
+	out := new(AcceptGroupResponse)
+	err := c.cc.Invoke(ctx, "/drand.Control/AcceptGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) CancelDKG(ctx context.Context, in *CancelDKGRequest, opts ...grpc.CallOption) (*CancelDKGResponse, error) {
+	out := new(CancelDKGResponse)
+	err := c.cc.Invoke(ctx, "/drand.Control/CancelDKG", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) CancelTransition(ctx context.Context, in *CancelTransitionRequest, opts ...grpc.CallOption) (*CancelTransitionResponse, error) {
+	out := new(CancelTransitionResponse)
+	err := c.cc.Invoke(ctx, "/drand.Control/CancelTransition", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) Backup(ctx context.Context, in *BackupRequest, opts ...grpc.CallOption) (Control_BackupClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Control_serviceDesc.Streams[0], "/drand.Control/Backup", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &controlBackupClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Control_BackupClient interface {
+	Recv() (*BackupChunk, error)
+	grpc.ClientStream
+}
+
+type controlBackupClient struct {
+	grpc.ClientStream
+}
+
+func (x *controlBackupClient) Recv() (*BackupChunk, error) {
+	m := new(BackupChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *controlClient) Peers(ctx context.Context, in *PeersRequest, opts ...grpc.CallOption) (*PeersResponse, error) {
+	out := new(PeersResponse)
+	err := c.cc.Invoke(ctx, "/drand.Control/Peers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) SyncStatus(ctx context.Context, in *SyncStatusRequest, opts ...grpc.CallOption) (*SyncStatusResponse, error) {
+	out := new(SyncStatusResponse)
+	err := c.cc.Invoke(ctx, "/drand.Control/SyncStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *controlClient) StartSync(ctx context.Context, in *StartSyncRequest, opts ...grpc.CallOption) (*SyncStatusResponse, error) {
+	out := new(SyncStatusResponse)
+	err := c.cc.Invoke(ctx, "/drand.Control/StartSync", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ControlServer is the server API for Control service.
 type ControlServer interface {
 	// PingPong returns an empty message. Purpose is to test the control port.
@@ -1046,6 +2197,31 @@ type ControlServer interface {
 	// control functionalities
 	GroupFile(context.Context, *GroupRequest) (*GroupPacket, error)
 	Shutdown(context.Context, *ShutdownRequest) (*ShutdownResponse, error)
+	// SetupStatus returns the participants that joined the setup run by this
+	// node as a leader and the allowed ones that did not join yet
+	SetupStatus(context.Context, *SetupStatusRequest) (*SetupStatusResponse, error)
+	// PendingGroup returns the group received from the leader during the
+	// setup, that the operator must accept before the DKG starts
+	PendingGroup(context.Context, *PendingGroupRequest) (*GroupPacket, error)
+	// AcceptGroup accepts or rejects the group received from the leader
+	AcceptGroup(context.Context, *AcceptGroupRequest) (*AcceptGroupResponse, error)
+	// CancelDKG aborts the setup or the DKG in progress
+	CancelDKG(context.Context, *CancelDKGRequest) (*CancelDKGResponse, error)
+	// CancelTransition cancels the transition to the new group scheduled after
+	// a resharing, so the node keeps using the old group
+	CancelTransition(context.Context, *CancelTransitionRequest) (*CancelTransitionResponse, error)
+	// Backup streams an archive of the key material and of a consistent
+	// snapshot of the beacon database of the node
+	Backup(*BackupRequest, Control_BackupServer) error
+	// Peers returns the scores the node keeps of its peers, from the partial
+	// beacons it sends to them and the syncs it runs with them
+	Peers(context.Context, *PeersRequest) (*PeersResponse, error)
+	// SyncStatus returns the progress of the sync of the chain the node runs,
+	// if any
+	SyncStatus(context.Context, *SyncStatusRequest) (*SyncStatusResponse, error)
+	// StartSync makes the node sync its chain from its peers up to the given
+	// round
+	StartSync(context.Context, *StartSyncRequest) (*SyncStatusResponse, error)
 }
 
 // UnimplementedControlServer can be embedded to have forward compatible implementations.
@@ -1079,6 +2255,33 @@ func (*UnimplementedControlServer) GroupFile(ctx context.Context, req *GroupRequ
 func (*UnimplementedControlServer) Shutdown(ctx context.Context, req *ShutdownRequest) (*ShutdownResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Shutdown not implemented")
 }
+func (*UnimplementedControlServer) SetupStatus(ctx context.Context, req *SetupStatusRequest) (*SetupStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetupStatus not implemented")
+}
+func (*UnimplementedControlServer) PendingGroup(ctx context.Context, req *PendingGroupRequest) (*GroupPacket, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PendingGroup not implemented")
+}
+func (*UnimplementedControlServer) AcceptGroup(ctx context.Context, req *AcceptGroupRequest) (*AcceptGroupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcceptGroup not implemented")
+}
+func (*UnimplementedControlServer) CancelDKG(ctx context.Context, req *CancelDKGRequest) (*CancelDKGResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelDKG not implemented")
+}
+func (*UnimplementedControlServer) CancelTransition(ctx context.Context, req *CancelTransitionRequest) (*CancelTransitionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelTransition not implemented")
+}
+func (*UnimplementedControlServer) Backup(req *BackupRequest, srv Control_BackupServer) error {
+	return status.Errorf(codes.Unimplemented, "method Backup not implemented")
+}
+func (*UnimplementedControlServer) Peers(ctx context.Context, req *PeersRequest) (*PeersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Peers not implemented")
+}
+func (*UnimplementedControlServer) SyncStatus(ctx context.Context, req *SyncStatusRequest) (*SyncStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SyncStatus not implemented")
+}
+func (*UnimplementedControlServer) StartSync(ctx context.Context, req *StartSyncRequest) (*SyncStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartSync not implemented")
+}
 
 func RegisterControlServer(s *grpc.Server, srv ControlServer) {
 	s.RegisterService(&_Control_serviceDesc, srv)
@@ -1246,6 +2449,171 @@ func _Control_Shutdown_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Control_SetupStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetupStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).SetupStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/drand.Control/SetupStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).SetupStatus(ctx, req.(*SetupStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_PendingGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PendingGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).PendingGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/drand.Control/PendingGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).PendingGroup(ctx, req.(*PendingGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_AcceptGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcceptGroupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).AcceptGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/drand.Control/AcceptGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).AcceptGroup(ctx, req.(*AcceptGroupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_CancelDKG_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelDKGRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).CancelDKG(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/drand.Control/CancelDKG",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).CancelDKG(ctx, req.(*CancelDKGRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_CancelTransition_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelTransitionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).CancelTransition(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/drand.Control/CancelTransition",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).CancelTransition(ctx, req.(*CancelTransitionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_Backup_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BackupRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ControlServer).Backup(m, &controlBackupServer{stream})
+}
+
+type Control_BackupServer interface {
+	Send(*BackupChunk) error
+	grpc.ServerStream
+}
+
+type controlBackupServer struct {
+	grpc.ServerStream
+}
+
+func (x *controlBackupServer) Send(m *BackupChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Control_Peers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PeersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).Peers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/drand.Control/Peers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).Peers(ctx, req.(*PeersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_SyncStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).SyncStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/drand.Control/SyncStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).SyncStatus(ctx, req.(*SyncStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Control_StartSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartSyncRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ControlServer).StartSync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/drand.Control/StartSync",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ControlServer).StartSync(ctx, req.(*StartSyncRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Control_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "drand.Control",
 	HandlerType: (*ControlServer)(nil),
@@ -1286,6 +2654,118 @@ var _Control_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Shutdown",
 			Handler:    _Control_Shutdown_Handler,
 		},
+		{
+			MethodName: "SetupStatus",
+			Handler:    _Control_SetupStatus_Handler,
+		},
+		{
+			MethodName: "PendingGroup",
+			Handler:    _Control_PendingGroup_Handler,
+		},
+		{
+			MethodName: "AcceptGroup",
+			Handler:    _Control_AcceptGroup_Handler,
+		},
+		{
+			MethodName: "CancelDKG",
+			Handler:    _Control_CancelDKG_Handler,
+		},
+		{
+			MethodName: "CancelTransition",
+			Handler:    _Control_CancelTransition_Handler,
+		},
+		{
+			MethodName: "Peers",
+			Handler:    _Control_Peers_Handler,
+		},
+		{
+			MethodName: "SyncStatus",
+			Handler:    _Control_SyncStatus_Handler,
+		},
+		{
+			MethodName: "StartSync",
+			Handler:    _Control_StartSync_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Backup",
+			Handler:       _Control_Backup_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "drand/control.proto",
+}
+
+// SignerClient is the client API for Signer service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type SignerClient interface {
+	// SignPartial returns the partial signature over the given message
+	SignPartial(ctx context.Context, in *SignPartialRequest, opts ...grpc.CallOption) (*SignPartialResponse, error)
+}
+
+type signerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSignerClient(cc grpc.ClientConnInterface) SignerClient {
+	return &signerClient{cc}
+}
+
+func (c *signerClient) SignPartial(ctx context.Context, in *SignPartialRequest, opts ...grpc.CallOption) (*SignPartialResponse, error) {
+	out := new(SignPartialResponse)
+	err := c.cc.Invoke(ctx, "/drand.Signer/SignPartial", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SignerServer is the server API for Signer service.
+type SignerServer interface {
+	// SignPartial returns the partial signature over the given message
+	SignPartial(context.Context, *SignPartialRequest) (*SignPartialResponse, error)
+}
+
+// UnimplementedSignerServer can be embedded to have forward compatible implementations.
+type UnimplementedSignerServer struct {
+}
+
+func (*UnimplementedSignerServer) SignPartial(ctx context.Context, req *SignPartialRequest) (*SignPartialResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignPartial not implemented")
+}
+
+func RegisterSignerServer(s *grpc.Server, srv SignerServer) {
+	s.RegisterService(&_Signer_serviceDesc, srv)
+}
+
+func _Signer_SignPartial_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignPartialRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SignerServer).SignPartial(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/drand.Signer/SignPartial",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SignerServer).SignPartial(ctx, req.(*SignPartialRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Signer_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "drand.Signer",
+	HandlerType: (*SignerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SignPartial",
+			Handler:    _Signer_SignPartial_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "drand/control.proto",
@@ -38,10 +38,15 @@ type PrepareDKGPacket struct {
 	SecretProof string `protobuf:"bytes,5,opt,name=secret_proof,json=secretProof,proto3" json:"secret_proof,omitempty"`
 	// In resharing cases, previous_group_hash is the hash of the previous group.
 	// It is to make sure the nodes build on top of the correct previous group.
-	PreviousGroupHash    string   `protobuf:"bytes,6,opt,name=previous_group_hash,json=previousGroupHash,proto3" json:"previous_group_hash,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	PreviousGroupHash string `protobuf:"bytes,6,opt,name=previous_group_hash,json=previousGroupHash,proto3" json:"previous_group_hash,omitempty"`
+	// previous_chain_hash is the version 2 hash of the previous group. Older
+	// nodes only set previous_group_hash.
+	PreviousChainHash string `protobuf:"bytes,7,opt,name=previous_chain_hash,json=previousChainHash,proto3" json:"previous_chain_hash,omitempty"`
+	// metadata describes the protocol version of the sender
+	Metadata             *Metadata `protobuf:"bytes,8,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
 }
 
 func (m *PrepareDKGPacket) Reset()         { *m = PrepareDKGPacket{} }
@@ -111,19 +116,78 @@ func (m *PrepareDKGPacket) GetPreviousGroupHash() string {
 	return ""
 }
 
+func (m *PrepareDKGPacket) GetPreviousChainHash() string {
+	if m != nil {
+		return m.PreviousChainHash
+	}
+	return ""
+}
+
+func (m *PrepareDKGPacket) GetMetadata() *Metadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// PrepareDKGResponse is the reply of the leader to a participant joining its
+// setup. Older leaders send an empty reply.
+type PrepareDKGResponse struct {
+	// leader is the identity of the leader, signed over the setup parameters,
+	// against which the participant verifies the abort of the setup
+	Leader               *Identity `protobuf:"bytes,1,opt,name=leader,proto3" json:"leader,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *PrepareDKGResponse) Reset()         { *m = PrepareDKGResponse{} }
+func (m *PrepareDKGResponse) String() string { return proto.CompactTextString(m) }
+func (*PrepareDKGResponse) ProtoMessage()    {}
+func (*PrepareDKGResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e344a98fea1e2f3a, []int{1}
+}
+
+func (m *PrepareDKGResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrepareDKGResponse.Unmarshal(m, b)
+}
+func (m *PrepareDKGResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrepareDKGResponse.Marshal(b, m, deterministic)
+}
+func (m *PrepareDKGResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrepareDKGResponse.Merge(m, src)
+}
+func (m *PrepareDKGResponse) XXX_Size() int {
+	return xxx_messageInfo_PrepareDKGResponse.Size(m)
+}
+func (m *PrepareDKGResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrepareDKGResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrepareDKGResponse proto.InternalMessageInfo
+
+func (m *PrepareDKGResponse) GetLeader() *Identity {
+	if m != nil {
+		return m.Leader
+	}
+	return nil
+}
+
 type PushGroupPacket struct {
-	NewGroup             *GroupPacket `protobuf:"bytes,1,opt,name=new_group,json=newGroup,proto3" json:"new_group,omitempty"`
-	SecretProof          string       `protobuf:"bytes,2,opt,name=secret_proof,json=secretProof,proto3" json:"secret_proof,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+	NewGroup    *GroupPacket `protobuf:"bytes,1,opt,name=new_group,json=newGroup,proto3" json:"new_group,omitempty"`
+	SecretProof string       `protobuf:"bytes,2,opt,name=secret_proof,json=secretProof,proto3" json:"secret_proof,omitempty"`
+	// metadata describes the protocol version of the sender
+	Metadata             *Metadata `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
 }
 
 func (m *PushGroupPacket) Reset()         { *m = PushGroupPacket{} }
 func (m *PushGroupPacket) String() string { return proto.CompactTextString(m) }
 func (*PushGroupPacket) ProtoMessage()    {}
 func (*PushGroupPacket) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e344a98fea1e2f3a, []int{1}
+	return fileDescriptor_e344a98fea1e2f3a, []int{2}
 }
 
 func (m *PushGroupPacket) XXX_Unmarshal(b []byte) error {
@@ -158,25 +222,257 @@ func (m *PushGroupPacket) GetSecretProof() string {
 	return ""
 }
 
+func (m *PushGroupPacket) GetMetadata() *Metadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// GroupHashPacket contains the hash of the group a node is about to run the
+// DKG with, signed by the node's longterm key.
+type GroupHashPacket struct {
+	// public key of the node
+	Key       []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Hash      string `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`
+	Signature []byte `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	// chain_hash is the version 2 hash of the group and chain_signature its
+	// signature. Older nodes only set hash.
+	ChainHash      string `protobuf:"bytes,4,opt,name=chain_hash,json=chainHash,proto3" json:"chain_hash,omitempty"`
+	ChainSignature []byte `protobuf:"bytes,5,opt,name=chain_signature,json=chainSignature,proto3" json:"chain_signature,omitempty"`
+	// metadata describes the protocol version of the sender
+	Metadata             *Metadata `protobuf:"bytes,6,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *GroupHashPacket) Reset()         { *m = GroupHashPacket{} }
+func (m *GroupHashPacket) String() string { return proto.CompactTextString(m) }
+func (*GroupHashPacket) ProtoMessage()    {}
+func (*GroupHashPacket) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e344a98fea1e2f3a, []int{3}
+}
+
+func (m *GroupHashPacket) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GroupHashPacket.Unmarshal(m, b)
+}
+func (m *GroupHashPacket) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GroupHashPacket.Marshal(b, m, deterministic)
+}
+func (m *GroupHashPacket) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GroupHashPacket.Merge(m, src)
+}
+func (m *GroupHashPacket) XXX_Size() int {
+	return xxx_messageInfo_GroupHashPacket.Size(m)
+}
+func (m *GroupHashPacket) XXX_DiscardUnknown() {
+	xxx_messageInfo_GroupHashPacket.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GroupHashPacket proto.InternalMessageInfo
+
+func (m *GroupHashPacket) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *GroupHashPacket) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *GroupHashPacket) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *GroupHashPacket) GetChainHash() string {
+	if m != nil {
+		return m.ChainHash
+	}
+	return ""
+}
+
+func (m *GroupHashPacket) GetChainSignature() []byte {
+	if m != nil {
+		return m.ChainSignature
+	}
+	return nil
+}
+
+func (m *GroupHashPacket) GetMetadata() *Metadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// AbortSetupPacket is sent by a node that cancelled the setup it runs with
+// the other nodes
+type AbortSetupPacket struct {
+	// public key of the node
+	Key         []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	SecretProof string `protobuf:"bytes,2,opt,name=secret_proof,json=secretProof,proto3" json:"secret_proof,omitempty"`
+	// metadata describes the protocol version of the sender
+	Metadata *Metadata `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// signature of the abort by the longterm key of the node, checked against
+	// the key the node joined the setup with
+	Signature            []byte   `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AbortSetupPacket) Reset()         { *m = AbortSetupPacket{} }
+func (m *AbortSetupPacket) String() string { return proto.CompactTextString(m) }
+func (*AbortSetupPacket) ProtoMessage()    {}
+func (*AbortSetupPacket) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e344a98fea1e2f3a, []int{4}
+}
+
+func (m *AbortSetupPacket) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AbortSetupPacket.Unmarshal(m, b)
+}
+func (m *AbortSetupPacket) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AbortSetupPacket.Marshal(b, m, deterministic)
+}
+func (m *AbortSetupPacket) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AbortSetupPacket.Merge(m, src)
+}
+func (m *AbortSetupPacket) XXX_Size() int {
+	return xxx_messageInfo_AbortSetupPacket.Size(m)
+}
+func (m *AbortSetupPacket) XXX_DiscardUnknown() {
+	xxx_messageInfo_AbortSetupPacket.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AbortSetupPacket proto.InternalMessageInfo
+
+func (m *AbortSetupPacket) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *AbortSetupPacket) GetSecretProof() string {
+	if m != nil {
+		return m.SecretProof
+	}
+	return ""
+}
+
+func (m *AbortSetupPacket) GetMetadata() *Metadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *AbortSetupPacket) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+// NewGroupSilentPacket is the statement of a node of the old group that the
+// new group did not produce its first beacon after a resharing, signed by the
+// longterm key of the node.
+type NewGroupSilentPacket struct {
+	// public key of the node in the old group
+	Key []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// chain hash of the new group
+	ChainHash string `protobuf:"bytes,2,opt,name=chain_hash,json=chainHash,proto3" json:"chain_hash,omitempty"`
+	Signature []byte `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	// metadata describes the protocol version of the sender
+	Metadata             *Metadata `protobuf:"bytes,4,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *NewGroupSilentPacket) Reset()         { *m = NewGroupSilentPacket{} }
+func (m *NewGroupSilentPacket) String() string { return proto.CompactTextString(m) }
+func (*NewGroupSilentPacket) ProtoMessage()    {}
+func (*NewGroupSilentPacket) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e344a98fea1e2f3a, []int{5}
+}
+
+func (m *NewGroupSilentPacket) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NewGroupSilentPacket.Unmarshal(m, b)
+}
+func (m *NewGroupSilentPacket) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NewGroupSilentPacket.Marshal(b, m, deterministic)
+}
+func (m *NewGroupSilentPacket) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NewGroupSilentPacket.Merge(m, src)
+}
+func (m *NewGroupSilentPacket) XXX_Size() int {
+	return xxx_messageInfo_NewGroupSilentPacket.Size(m)
+}
+func (m *NewGroupSilentPacket) XXX_DiscardUnknown() {
+	xxx_messageInfo_NewGroupSilentPacket.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NewGroupSilentPacket proto.InternalMessageInfo
+
+func (m *NewGroupSilentPacket) GetKey() []byte {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *NewGroupSilentPacket) GetChainHash() string {
+	if m != nil {
+		return m.ChainHash
+	}
+	return ""
+}
+
+func (m *NewGroupSilentPacket) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *NewGroupSilentPacket) GetMetadata() *Metadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
 type PartialBeaconPacket struct {
 	// Round is the round for which the beacon will be created from the partial
 	// signatures
 	Round uint64 `protobuf:"varint,1,opt,name=round,proto3" json:"round,omitempty"`
 	// PreviousRound is the round for which the beacon is building on top of
 	// from.
-	PreviousRound        uint64   `protobuf:"varint,2,opt,name=previous_round,json=previousRound,proto3" json:"previous_round,omitempty"`
-	PartialSig           []byte   `protobuf:"bytes,3,opt,name=partial_sig,json=partialSig,proto3" json:"partial_sig,omitempty"`
-	PreviousSig          []byte   `protobuf:"bytes,4,opt,name=previous_sig,json=previousSig,proto3" json:"previous_sig,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	PreviousRound uint64 `protobuf:"varint,2,opt,name=previous_round,json=previousRound,proto3" json:"previous_round,omitempty"`
+	PartialSig    []byte `protobuf:"bytes,3,opt,name=partial_sig,json=partialSig,proto3" json:"partial_sig,omitempty"`
+	PreviousSig   []byte `protobuf:"bytes,4,opt,name=previous_sig,json=previousSig,proto3" json:"previous_sig,omitempty"`
+	// metadata describes the protocol version of the sender
+	Metadata             *Metadata `protobuf:"bytes,5,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
 }
 
 func (m *PartialBeaconPacket) Reset()         { *m = PartialBeaconPacket{} }
 func (m *PartialBeaconPacket) String() string { return proto.CompactTextString(m) }
 func (*PartialBeaconPacket) ProtoMessage()    {}
 func (*PartialBeaconPacket) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e344a98fea1e2f3a, []int{2}
+	return fileDescriptor_e344a98fea1e2f3a, []int{6}
 }
 
 func (m *PartialBeaconPacket) XXX_Unmarshal(b []byte) error {
@@ -225,18 +521,27 @@ func (m *PartialBeaconPacket) GetPreviousSig() []byte {
 	return nil
 }
 
+func (m *PartialBeaconPacket) GetMetadata() *Metadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
 type DKGPacket struct {
-	Dkg                  *dkg.Packet `protobuf:"bytes,1,opt,name=dkg,proto3" json:"dkg,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
-	XXX_unrecognized     []byte      `json:"-"`
-	XXX_sizecache        int32       `json:"-"`
+	Dkg *dkg.Packet `protobuf:"bytes,1,opt,name=dkg,proto3" json:"dkg,omitempty"`
+	// metadata describes the protocol version of the sender
+	Metadata             *Metadata `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
 }
 
 func (m *DKGPacket) Reset()         { *m = DKGPacket{} }
 func (m *DKGPacket) String() string { return proto.CompactTextString(m) }
 func (*DKGPacket) ProtoMessage()    {}
 func (*DKGPacket) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e344a98fea1e2f3a, []int{3}
+	return fileDescriptor_e344a98fea1e2f3a, []int{7}
 }
 
 func (m *DKGPacket) XXX_Unmarshal(b []byte) error {
@@ -264,23 +569,40 @@ func (m *DKGPacket) GetDkg() *dkg.Packet {
 	return nil
 }
 
+func (m *DKGPacket) GetMetadata() *Metadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
 // Reshare is a wrapper around a Setup packet for resharing operation that
 // serves two purposes: - indicate to non-leader old nodes that they should
 // generate and send their deals - indicate to which new group are we resharing.
 // drand should keep a list of new ready-to-operate groups allowed.
 type ResharePacket struct {
-	Dkg                  *dkg.Packet `protobuf:"bytes,1,opt,name=dkg,proto3" json:"dkg,omitempty"`
-	GroupHash            string      `protobuf:"bytes,2,opt,name=group_hash,json=groupHash,proto3" json:"group_hash,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
-	XXX_unrecognized     []byte      `json:"-"`
-	XXX_sizecache        int32       `json:"-"`
+	Dkg       *dkg.Packet `protobuf:"bytes,1,opt,name=dkg,proto3" json:"dkg,omitempty"`
+	GroupHash string      `protobuf:"bytes,2,opt,name=group_hash,json=groupHash,proto3" json:"group_hash,omitempty"`
+	// chain_hash is the version 2 hash of the new group. Older nodes only set
+	// group_hash.
+	ChainHash string `protobuf:"bytes,3,opt,name=chain_hash,json=chainHash,proto3" json:"chain_hash,omitempty"`
+	// partial_signature is the partial signature of an old node over the new
+	// group, with its share of the old group. Once enough are collected, the
+	// new nodes recover the signature of the old group vouching for the new
+	// one.
+	PartialSignature []byte `protobuf:"bytes,4,opt,name=partial_signature,json=partialSignature,proto3" json:"partial_signature,omitempty"`
+	// metadata describes the protocol version of the sender
+	Metadata             *Metadata `protobuf:"bytes,5,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
 }
 
 func (m *ResharePacket) Reset()         { *m = ResharePacket{} }
 func (m *ResharePacket) String() string { return proto.CompactTextString(m) }
 func (*ResharePacket) ProtoMessage()    {}
 func (*ResharePacket) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e344a98fea1e2f3a, []int{4}
+	return fileDescriptor_e344a98fea1e2f3a, []int{8}
 }
 
 func (m *ResharePacket) XXX_Unmarshal(b []byte) error {
@@ -315,10 +637,39 @@ func (m *ResharePacket) GetGroupHash() string {
 	return ""
 }
 
+func (m *ResharePacket) GetChainHash() string {
+	if m != nil {
+		return m.ChainHash
+	}
+	return ""
+}
+
+func (m *ResharePacket) GetPartialSignature() []byte {
+	if m != nil {
+		return m.PartialSignature
+	}
+	return nil
+}
+
+func (m *ResharePacket) GetMetadata() *Metadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
 // SyncRequest is from a node that needs to sync up with the current head of the
 // chain
 type SyncRequest struct {
-	FromRound            uint64   `protobuf:"varint,1,opt,name=from_round,json=fromRound,proto3" json:"from_round,omitempty"`
+	FromRound uint64 `protobuf:"varint,1,opt,name=from_round,json=fromRound,proto3" json:"from_round,omitempty"`
+	// metadata describes the protocol version of the sender
+	Metadata *Metadata `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// to_round is the last round the sender wants, 0 to follow the head of
+	// the chain
+	ToRound uint64 `protobuf:"varint,3,opt,name=to_round,json=toRound,proto3" json:"to_round,omitempty"`
+	// max_count is the maximum number of beacons the sender wants, 0 for as
+	// many as the receiver sends for a single request
+	MaxCount             uint64   `protobuf:"varint,4,opt,name=max_count,json=maxCount,proto3" json:"max_count,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -328,7 +679,7 @@ func (m *SyncRequest) Reset()         { *m = SyncRequest{} }
 func (m *SyncRequest) String() string { return proto.CompactTextString(m) }
 func (*SyncRequest) ProtoMessage()    {}
 func (*SyncRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e344a98fea1e2f3a, []int{5}
+	return fileDescriptor_e344a98fea1e2f3a, []int{9}
 }
 
 func (m *SyncRequest) XXX_Unmarshal(b []byte) error {
@@ -356,6 +707,27 @@ func (m *SyncRequest) GetFromRound() uint64 {
 	return 0
 }
 
+func (m *SyncRequest) GetMetadata() *Metadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *SyncRequest) GetToRound() uint64 {
+	if m != nil {
+		return m.ToRound
+	}
+	return 0
+}
+
+func (m *SyncRequest) GetMaxCount() uint64 {
+	if m != nil {
+		return m.MaxCount
+	}
+	return 0
+}
+
 type BeaconPacket struct {
 	PreviousSig          []byte   `protobuf:"bytes,1,opt,name=previous_sig,json=previousSig,proto3" json:"previous_sig,omitempty"`
 	Round                uint64   `protobuf:"varint,2,opt,name=round,proto3" json:"round,omitempty"`
@@ -369,7 +741,7 @@ func (m *BeaconPacket) Reset()         { *m = BeaconPacket{} }
 func (m *BeaconPacket) String() string { return proto.CompactTextString(m) }
 func (*BeaconPacket) ProtoMessage()    {}
 func (*BeaconPacket) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e344a98fea1e2f3a, []int{6}
+	return fileDescriptor_e344a98fea1e2f3a, []int{10}
 }
 
 func (m *BeaconPacket) XXX_Unmarshal(b []byte) error {
@@ -413,7 +785,11 @@ func (m *BeaconPacket) GetSignature() []byte {
 
 func init() {
 	proto.RegisterType((*PrepareDKGPacket)(nil), "drand.PrepareDKGPacket")
+	proto.RegisterType((*PrepareDKGResponse)(nil), "drand.PrepareDKGResponse")
 	proto.RegisterType((*PushGroupPacket)(nil), "drand.PushGroupPacket")
+	proto.RegisterType((*GroupHashPacket)(nil), "drand.GroupHashPacket")
+	proto.RegisterType((*AbortSetupPacket)(nil), "drand.AbortSetupPacket")
+	proto.RegisterType((*NewGroupSilentPacket)(nil), "drand.NewGroupSilentPacket")
 	proto.RegisterType((*PartialBeaconPacket)(nil), "drand.PartialBeaconPacket")
 	proto.RegisterType((*DKGPacket)(nil), "drand.DKGPacket")
 	proto.RegisterType((*ResharePacket)(nil), "drand.ResharePacket")
@@ -426,43 +802,61 @@ func init() {
 }
 
 var fileDescriptor_e344a98fea1e2f3a = []byte{
-	// 575 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0xdb, 0x6e, 0xd3, 0x40,
-	0x10, 0x95, 0xd3, 0xb4, 0xc4, 0xe3, 0x84, 0x96, 0x4d, 0x04, 0x96, 0xd5, 0xaa, 0xc1, 0x08, 0x29,
-	0xaa, 0x50, 0x52, 0x15, 0x84, 0xe0, 0xb5, 0x14, 0x02, 0xaa, 0x90, 0x2c, 0x87, 0x27, 0x5e, 0x2c,
-	0x63, 0x4f, 0x6d, 0x2b, 0x89, 0xd7, 0xac, 0xd7, 0x94, 0xfc, 0x08, 0x3f, 0xc5, 0x77, 0xf0, 0x1f,
-	0x68, 0x2f, 0x4e, 0x9c, 0x44, 0x48, 0x3c, 0x44, 0xca, 0x9e, 0x33, 0xb3, 0x3b, 0xe7, 0xcc, 0x8c,
-	0x61, 0x10, 0xb3, 0x30, 0x8f, 0x27, 0x05, 0xa3, 0x9c, 0x46, 0x74, 0x31, 0x96, 0x7f, 0xc8, 0xa1,
-	0x44, 0x9d, 0x41, 0xc4, 0x56, 0x05, 0xa7, 0x93, 0x78, 0x9e, 0x88, 0x9f, 0x22, 0x1d, 0xa2, 0x52,
-	0x22, 0xba, 0x5c, 0xd2, 0x5c, 0x61, 0xee, 0x1f, 0x03, 0x4e, 0x3c, 0x86, 0x45, 0xc8, 0xf0, 0xe6,
-	0x76, 0xea, 0x85, 0xd1, 0x1c, 0x39, 0x79, 0x06, 0xed, 0x9c, 0xc6, 0x68, 0x1b, 0x43, 0x63, 0x64,
-	0x5d, 0x1d, 0x8f, 0x65, 0xde, 0xf8, 0x53, 0x8c, 0x39, 0xcf, 0xf8, 0xca, 0x97, 0x24, 0x71, 0xa0,
-	0x83, 0x3f, 0x0b, 0x8c, 0x38, 0xc6, 0x76, 0x6b, 0x68, 0x8c, 0x7a, 0xfe, 0xfa, 0x4c, 0x4e, 0xc1,
-	0xe4, 0x29, 0xc3, 0x32, 0xa5, 0x8b, 0xd8, 0x3e, 0x90, 0xe4, 0x06, 0x20, 0xe7, 0x60, 0xc5, 0xf3,
-	0x24, 0xe0, 0xd9, 0x12, 0x69, 0xc5, 0xed, 0xf6, 0xd0, 0x18, 0xb5, 0x7d, 0x88, 0xe7, 0xc9, 0x17,
-	0x85, 0x90, 0xa7, 0xd0, 0x2d, 0x31, 0x62, 0xc8, 0x83, 0x82, 0x51, 0x7a, 0x67, 0x1f, 0x0e, 0x8d,
-	0x91, 0xe9, 0x5b, 0x0a, 0xf3, 0x04, 0x44, 0xc6, 0xd0, 0x2f, 0x18, 0xfe, 0xc8, 0x68, 0x55, 0x06,
-	0x09, 0xa3, 0x55, 0x11, 0xa4, 0x61, 0x99, 0xda, 0x47, 0x32, 0xf2, 0x51, 0x4d, 0x4d, 0x05, 0xf3,
-	0x31, 0x2c, 0x53, 0x17, 0xe1, 0xd8, 0xab, 0xca, 0x54, 0x02, 0x5a, 0xe5, 0x04, 0xcc, 0x1c, 0xef,
-	0x55, 0xb6, 0x96, 0x4a, 0xb4, 0xd4, 0x46, 0x98, 0xdf, 0xc9, 0xf1, 0x5e, 0x9e, 0xf7, 0xca, 0x6a,
-	0xed, 0x95, 0xe5, 0xfe, 0x32, 0xa0, 0xef, 0x85, 0x8c, 0x67, 0xe1, 0xe2, 0x1a, 0xc3, 0x88, 0xe6,
-	0xfa, 0xad, 0x01, 0x1c, 0x32, 0x5a, 0xe5, 0xb1, 0x7c, 0xa7, 0xed, 0xab, 0x03, 0x79, 0x0e, 0x0f,
-	0xd7, 0x22, 0x14, 0xdd, 0x92, 0x74, 0xaf, 0x46, 0x7d, 0x19, 0x76, 0x0e, 0x56, 0xa1, 0xee, 0x0c,
-	0xca, 0x2c, 0x91, 0x7e, 0x76, 0x7d, 0xd0, 0xd0, 0x2c, 0x4b, 0x44, 0x61, 0xeb, 0x7b, 0x44, 0x44,
-	0x5b, 0x46, 0x58, 0x35, 0x36, 0xcb, 0x12, 0xf7, 0x02, 0xcc, 0x4d, 0x7f, 0xcf, 0xe0, 0x20, 0x9e,
-	0x27, 0x5a, 0xb3, 0x35, 0x16, 0x13, 0xa2, 0xc5, 0x0a, 0xdc, 0xfd, 0x0c, 0x3d, 0x1f, 0xcb, 0x34,
-	0x64, 0xf8, 0x5f, 0xf1, 0xe4, 0x0c, 0xa0, 0xd1, 0x02, 0xe5, 0x8a, 0x99, 0xac, 0xad, 0x7f, 0x01,
-	0xd6, 0x6c, 0x95, 0x47, 0x3e, 0x7e, 0xaf, 0xb0, 0x14, 0x97, 0xc1, 0x1d, 0xa3, 0xcb, 0xa0, 0xe9,
-	0x87, 0x29, 0x10, 0x29, 0xd6, 0x45, 0xe8, 0x6e, 0x39, 0xb7, 0xab, 0xcd, 0xd8, 0xd3, 0xb6, 0x31,
-	0xb7, 0xd5, 0x34, 0xf7, 0x14, 0xcc, 0x32, 0x4b, 0xf2, 0x90, 0x57, 0x0c, 0xb5, 0x67, 0x1b, 0xe0,
-	0xea, 0x77, 0x0b, 0x3a, 0x9e, 0xde, 0x1d, 0xf2, 0x06, 0x8e, 0x37, 0x3b, 0xa0, 0x7a, 0xfd, 0x44,
-	0x4f, 0xc2, 0xee, 0x6e, 0x38, 0x5d, 0x4d, 0xbc, 0x5f, 0x16, 0x7c, 0x45, 0x5e, 0x41, 0x57, 0x8c,
-	0xd5, 0x3a, 0xed, 0x71, 0x9d, 0xb6, 0x3d, 0x6b, 0x3b, 0x59, 0x17, 0xd0, 0xf9, 0x20, 0x96, 0xe1,
-	0xe6, 0x76, 0x4a, 0x4e, 0x34, 0xf3, 0xaf, 0x17, 0x2e, 0x01, 0x74, 0x33, 0x44, 0xf4, 0x40, 0x73,
-	0x5b, 0xfd, 0xd9, 0xc9, 0x78, 0x0b, 0xbd, 0xad, 0x11, 0x24, 0x4e, 0x5d, 0xd4, 0xfe, 0x60, 0xee,
-	0xa4, 0xbe, 0x06, 0x53, 0xb4, 0xea, 0x5d, 0x1a, 0x66, 0x39, 0xa9, 0x97, 0xa1, 0xd1, 0x3c, 0xa7,
-	0xaf, 0xb1, 0xe6, 0x1d, 0x97, 0xc6, 0xf5, 0x83, 0xaf, 0xea, 0xc3, 0xf3, 0xed, 0x48, 0x7e, 0x55,
-	0x5e, 0xfe, 0x0d, 0x00, 0x00, 0xff, 0xff, 0x77, 0xb7, 0x86, 0x56, 0x9e, 0x04, 0x00, 0x00,
+	// 864 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0xdd, 0x8e, 0xdb, 0x44,
+	0x14, 0x96, 0x13, 0x67, 0xd7, 0x3e, 0x49, 0x36, 0xe9, 0x6c, 0x04, 0xa9, 0xcb, 0xaa, 0x21, 0x08,
+	0x35, 0xa2, 0x52, 0xb6, 0x6a, 0x11, 0x12, 0x17, 0x20, 0xd1, 0x6d, 0x59, 0xa0, 0x02, 0x45, 0x0e,
+	0x12, 0x12, 0x37, 0xd1, 0xd4, 0x9e, 0xb5, 0xad, 0xc4, 0x1e, 0x33, 0x1e, 0xd3, 0xcd, 0x63, 0xd0,
+	0x3b, 0x5e, 0x07, 0x24, 0x2e, 0x78, 0x2a, 0x34, 0x3f, 0xf1, 0x5f, 0x9a, 0x0d, 0x48, 0x5c, 0x44,
+	0xb2, 0xbf, 0xf3, 0xe3, 0x73, 0xbe, 0x73, 0xe6, 0x9b, 0xc0, 0xc8, 0x67, 0x38, 0xf1, 0x2f, 0x53,
+	0x46, 0x39, 0xf5, 0xe8, 0x66, 0x2e, 0x1f, 0x50, 0x47, 0xa2, 0xce, 0xc8, 0x63, 0xdb, 0x94, 0xd3,
+	0x4b, 0x7f, 0x1d, 0x88, 0x9f, 0x32, 0x3a, 0x48, 0x85, 0x78, 0x34, 0x8e, 0x69, 0xa2, 0xb0, 0xe9,
+	0x1f, 0x2d, 0x18, 0x2e, 0x18, 0x49, 0x31, 0x23, 0x2f, 0x5e, 0x5d, 0x2f, 0xb0, 0xb7, 0x26, 0x1c,
+	0x7d, 0x04, 0x66, 0x42, 0x7d, 0x32, 0x36, 0x26, 0xc6, 0xac, 0xfb, 0x74, 0x30, 0x97, 0x71, 0xf3,
+	0x6f, 0x7d, 0x92, 0xf0, 0x88, 0x6f, 0x5d, 0x69, 0x44, 0x0e, 0x58, 0xe4, 0x36, 0x25, 0x1e, 0x27,
+	0xfe, 0xb8, 0x35, 0x31, 0x66, 0x7d, 0xb7, 0x78, 0x47, 0x1f, 0x80, 0xcd, 0x43, 0x46, 0xb2, 0x90,
+	0x6e, 0xfc, 0x71, 0x5b, 0x1a, 0x4b, 0x00, 0x3d, 0x84, 0xae, 0xbf, 0x0e, 0x56, 0x3c, 0x8a, 0x09,
+	0xcd, 0xf9, 0xd8, 0x9c, 0x18, 0x33, 0xd3, 0x05, 0x7f, 0x1d, 0xfc, 0xa8, 0x10, 0xf4, 0x21, 0xf4,
+	0x32, 0xe2, 0x31, 0xc2, 0x57, 0x29, 0xa3, 0xf4, 0x66, 0xdc, 0x99, 0x18, 0x33, 0xdb, 0xed, 0x2a,
+	0x6c, 0x21, 0x20, 0x34, 0x87, 0xf3, 0x94, 0x91, 0x5f, 0x23, 0x9a, 0x67, 0xab, 0x80, 0xd1, 0x3c,
+	0x5d, 0x85, 0x38, 0x0b, 0xc7, 0x27, 0xd2, 0xf3, 0xde, 0xce, 0x74, 0x2d, 0x2c, 0xdf, 0xe0, 0x2c,
+	0xac, 0xf9, 0x7b, 0x21, 0x8e, 0x12, 0xe5, 0x7f, 0x5a, 0xf7, 0xbf, 0x12, 0x16, 0xe9, 0xff, 0x18,
+	0xac, 0x98, 0x70, 0xec, 0x63, 0x8e, 0xc7, 0x56, 0x8d, 0x86, 0xef, 0x35, 0xec, 0x16, 0x0e, 0xd3,
+	0x2f, 0x00, 0x95, 0x1c, 0xba, 0x24, 0x4b, 0x69, 0x92, 0x11, 0xf4, 0x08, 0x4e, 0x36, 0x04, 0xfb,
+	0x84, 0x1d, 0xe2, 0x51, 0x9b, 0xa7, 0x6f, 0x0d, 0x18, 0x2c, 0xf2, 0x2c, 0x94, 0xd5, 0xea, 0x11,
+	0x5c, 0x82, 0x9d, 0x90, 0x37, 0xaa, 0x35, 0x1d, 0x8f, 0x74, 0x7c, 0xc5, 0xcd, 0xb5, 0x12, 0xf2,
+	0x46, 0xbe, 0xef, 0x71, 0xd6, 0xda, 0xe7, 0xac, 0xda, 0x53, 0xfb, 0x58, 0x4f, 0x7f, 0x1b, 0x30,
+	0x28, 0xe8, 0xd3, 0x45, 0x0d, 0xa1, 0xbd, 0x26, 0x5b, 0x59, 0x4e, 0xcf, 0x15, 0x8f, 0x08, 0x81,
+	0x29, 0x79, 0x54, 0x5f, 0x93, 0xcf, 0x62, 0xf8, 0x59, 0x14, 0x24, 0x98, 0xe7, 0x8c, 0xc8, 0xef,
+	0xf4, 0xdc, 0x12, 0x40, 0x17, 0x00, 0x15, 0xfe, 0x4d, 0x19, 0x67, 0x7b, 0x05, 0xef, 0x8f, 0x60,
+	0xa0, 0xcc, 0x65, 0x8a, 0x8e, 0x4c, 0x71, 0x26, 0xe1, 0x65, 0x91, 0xa7, 0xda, 0xcc, 0xc9, 0xb1,
+	0x66, 0xde, 0x1a, 0x30, 0xfc, 0xea, 0x35, 0x65, 0x7c, 0x49, 0x78, 0x9e, 0x1e, 0xec, 0xe6, 0x7f,
+	0xe6, 0xb0, 0xce, 0x84, 0xd9, 0x60, 0x42, 0x14, 0x35, 0xfa, 0x41, 0x8f, 0x6f, 0x19, 0x6d, 0x48,
+	0xc2, 0x0f, 0x16, 0x56, 0x27, 0xad, 0xd5, 0x24, 0xed, 0x6e, 0xc6, 0xab, 0x25, 0x9b, 0xc7, 0x98,
+	0xfa, 0xd3, 0x80, 0xf3, 0x05, 0x66, 0x3c, 0xc2, 0x9b, 0xe7, 0x04, 0x7b, 0x34, 0xd1, 0x35, 0x8d,
+	0xa0, 0xc3, 0x68, 0x9e, 0xf8, 0xb2, 0x2a, 0xd3, 0x55, 0x2f, 0xe8, 0x63, 0x38, 0x2b, 0x4e, 0x95,
+	0x32, 0xb7, 0xa4, 0xb9, 0xbf, 0x43, 0x5d, 0xe9, 0xf6, 0x10, 0xba, 0xa9, 0xca, 0x29, 0xc6, 0xaa,
+	0x2b, 0x04, 0x0d, 0x2d, 0xa3, 0x40, 0x10, 0x5f, 0xe4, 0x11, 0x1e, 0x8a, 0xab, 0xee, 0x0e, 0x13,
+	0x2e, 0xd5, 0x2e, 0x3a, 0xc7, 0xba, 0xf8, 0x09, 0xec, 0x52, 0xcd, 0x2e, 0xa0, 0xed, 0xaf, 0x03,
+	0x7d, 0x88, 0xba, 0x73, 0xa1, 0x87, 0xca, 0xe2, 0x0a, 0xbc, 0x96, 0xb8, 0x75, 0x2c, 0xf1, 0x5f,
+	0x06, 0xf4, 0x5d, 0x92, 0x85, 0x98, 0x91, 0x7f, 0x97, 0xfd, 0x02, 0xa0, 0x22, 0x4f, 0x7a, 0x72,
+	0x41, 0x21, 0x4b, 0xf5, 0xc1, 0xb6, 0x9b, 0x83, 0x7d, 0x0c, 0xf7, 0x2a, 0xc4, 0xd5, 0x16, 0x69,
+	0x58, 0xd2, 0xf7, 0x8e, 0x39, 0x1f, 0x65, 0xe8, 0x37, 0x03, 0xba, 0xcb, 0x6d, 0xe2, 0xb9, 0xe4,
+	0x97, 0x9c, 0x64, 0xa2, 0x0d, 0xb8, 0x61, 0x34, 0x5e, 0x55, 0x87, 0x6c, 0x0b, 0x44, 0x4d, 0xf0,
+	0xbf, 0x90, 0x84, 0xee, 0x83, 0xc5, 0xa9, 0xce, 0xd4, 0x96, 0x99, 0x4e, 0x39, 0x55, 0x79, 0x1e,
+	0x80, 0x1d, 0xe3, 0xdb, 0x95, 0x47, 0xf3, 0x64, 0x27, 0xfc, 0x56, 0x8c, 0x6f, 0xaf, 0xc4, 0xfb,
+	0x94, 0x40, 0xaf, 0xb6, 0x73, 0xcd, 0xad, 0x30, 0xf6, 0xb7, 0xa2, 0x58, 0xcb, 0x56, 0x75, 0x2d,
+	0xef, 0x3c, 0x0f, 0x4f, 0x7f, 0x37, 0xc1, 0x5a, 0xe8, 0x6b, 0x13, 0xbd, 0x84, 0x41, 0x29, 0xdd,
+	0x4a, 0x49, 0xdf, 0xd7, 0x9d, 0x35, 0xaf, 0x45, 0xe7, 0xfe, 0x9e, 0xa1, 0xd0, 0xfa, 0x4f, 0xa1,
+	0x27, 0x14, 0xbc, 0xc8, 0xf1, 0xde, 0xce, 0xb5, 0x2e, 0xeb, 0x4e, 0x4f, 0xe3, 0x2f, 0xe3, 0x94,
+	0x6f, 0xd1, 0x97, 0xd0, 0xbb, 0xa2, 0xc9, 0x4d, 0xc4, 0xe2, 0x7a, 0x54, 0x43, 0x77, 0x9d, 0x03,
+	0x38, 0x7a, 0x06, 0x50, 0xaa, 0x5a, 0x51, 0x77, 0x53, 0xe8, 0x1a, 0x1f, 0xfd, 0x0e, 0xce, 0xea,
+	0xaa, 0x83, 0x1e, 0x68, 0xfb, 0xbb, 0xc4, 0xc8, 0xb9, 0xcb, 0x88, 0x3e, 0x01, 0xeb, 0x6b, 0x71,
+	0xab, 0xbf, 0x78, 0x75, 0x8d, 0x86, 0xda, 0xb1, 0xe4, 0xab, 0xfe, 0xdd, 0x27, 0x00, 0xfa, 0xe4,
+	0x08, 0xef, 0x91, 0xb6, 0xd5, 0x0e, 0x53, 0x23, 0xe2, 0x73, 0xe8, 0xd7, 0xa4, 0x08, 0x39, 0x3b,
+	0x56, 0xf7, 0x05, 0xaa, 0x11, 0xfa, 0x19, 0xd8, 0x62, 0xbb, 0xe5, 0x7d, 0x8e, 0x76, 0x17, 0x67,
+	0x65, 0xdf, 0x9d, 0x73, 0x8d, 0x55, 0x73, 0x3c, 0x31, 0x9e, 0x9f, 0xfe, 0xac, 0xfe, 0x41, 0xbd,
+	0x3e, 0x91, 0x7f, 0x8f, 0x9e, 0xfd, 0x33, 0x00, 0x86, 0x44, 0xd8, 0xaa, 0x67, 0x09, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -477,8 +871,23 @@ const _ = grpc.SupportPackageIsVersion6
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type ProtocolClient interface {
-	PrepareDKGGroup(ctx context.Context, in *PrepareDKGPacket, opts ...grpc.CallOption) (*Empty, error)
+	// PrepareDKGGroup sends the key of a participant to the leader of a setup,
+	// which replies with its own identity
+	PrepareDKGGroup(ctx context.Context, in *PrepareDKGPacket, opts ...grpc.CallOption) (*PrepareDKGResponse, error)
 	PushDKGGroup(ctx context.Context, in *PushGroupPacket, opts ...grpc.CallOption) (*Empty, error)
+	// ConfirmGroup is used in a setup without leader: each node sends the hash
+	// of the group it holds to the others, which reply with their own. In a
+	// setup with a leader, each node acknowledges the group the leader pushed
+	// by sending its hash to the leader.
+	ConfirmGroup(ctx context.Context, in *GroupHashPacket, opts ...grpc.CallOption) (*GroupHashPacket, error)
+	// AbortSetup notifies the other nodes of a setup with a leader that this
+	// node cancelled it
+	AbortSetup(ctx context.Context, in *AbortSetupPacket, opts ...grpc.CallOption) (*Empty, error)
+	// NewGroupSilent is used by the nodes of the old group after a resharing:
+	// each node that did not see the new group produce its first beacon sends
+	// its signed statement to the other old nodes, which reply with their own.
+	// A node only goes back to the old group once a threshold of them agree.
+	NewGroupSilent(ctx context.Context, in *NewGroupSilentPacket, opts ...grpc.CallOption) (*NewGroupSilentPacket, error)
 	// Setup is doing the DKG setup phase
 	FreshDKG(ctx context.Context, in *DKGPacket, opts ...grpc.CallOption) (*Empty, error)
 	// Reshare performs the resharing phase
@@ -496,8 +905,8 @@ func NewProtocolClient(cc grpc.ClientConnInterface) ProtocolClient {
 	return &protocolClient{cc}
 }
 
-func (c *protocolClient) PrepareDKGGroup(ctx context.Context, in *PrepareDKGPacket, opts ...grpc.CallOption) (*Empty, error) {
-	out := new(Empty)
+func (c *protocolClient) PrepareDKGGroup(ctx context.Context, in *PrepareDKGPacket, opts ...grpc.CallOption) (*PrepareDKGResponse, error) {
+	out := new(PrepareDKGResponse)
 	err := c.cc.Invoke(ctx, "/drand.Protocol/PrepareDKGGroup", in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -514,6 +923,33 @@ func (c *protocolClient) PushDKGGroup(ctx context.Context, in *PushGroupPacket,
 	return out, nil
 }
 
+func (c *protocolClient) ConfirmGroup(ctx context.Context, in *GroupHashPacket, opts ...grpc.CallOption) (*GroupHashPacket, error) {
+	out := new(GroupHashPacket)
+	err := c.cc.Invoke(ctx, "/drand.Protocol/ConfirmGroup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *protocolClient) AbortSetup(ctx context.Context, in *AbortSetupPacket, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/drand.Protocol/AbortSetup", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *protocolClient) NewGroupSilent(ctx context.Context, in *NewGroupSilentPacket, opts ...grpc.CallOption) (*NewGroupSilentPacket, error) {
+	out := new(NewGroupSilentPacket)
+	err := c.cc.Invoke(ctx, "/drand.Protocol/NewGroupSilent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *protocolClient) FreshDKG(ctx context.Context, in *DKGPacket, opts ...grpc.CallOption) (*Empty, error) {
 	out := new(Empty)
 	err := c.cc.Invoke(ctx, "/drand.Protocol/FreshDKG", in, out, opts...)
@@ -575,8 +1011,23 @@ func (x *protocolSyncChainClient) Recv() (*BeaconPacket, error) {
 
 // ProtocolServer is the server API for Protocol service.
 type ProtocolServer interface {
-	PrepareDKGGroup(context.Context, *PrepareDKGPacket) (*Empty, error)
+	// PrepareDKGGroup sends the key of a participant to the leader of a setup,
+	// which replies with its own identity
+	PrepareDKGGroup(context.Context, *PrepareDKGPacket) (*PrepareDKGResponse, error)
 	PushDKGGroup(context.Context, *PushGroupPacket) (*Empty, error)
+	// ConfirmGroup is used in a setup without leader: each node sends the hash
+	// of the group it holds to the others, which reply with their own. In a
+	// setup with a leader, each node acknowledges the group the leader pushed
+	// by sending its hash to the leader.
+	ConfirmGroup(context.Context, *GroupHashPacket) (*GroupHashPacket, error)
+	// AbortSetup notifies the other nodes of a setup with a leader that this
+	// node cancelled it
+	AbortSetup(context.Context, *AbortSetupPacket) (*Empty, error)
+	// NewGroupSilent is used by the nodes of the old group after a resharing:
+	// each node that did not see the new group produce its first beacon sends
+	// its signed statement to the other old nodes, which reply with their own.
+	// A node only goes back to the old group once a threshold of them agree.
+	NewGroupSilent(context.Context, *NewGroupSilentPacket) (*NewGroupSilentPacket, error)
 	// Setup is doing the DKG setup phase
 	FreshDKG(context.Context, *DKGPacket) (*Empty, error)
 	// Reshare performs the resharing phase
@@ -590,12 +1041,21 @@ type ProtocolServer interface {
 type UnimplementedProtocolServer struct {
 }
 
-func (*UnimplementedProtocolServer) PrepareDKGGroup(ctx context.Context, req *PrepareDKGPacket) (*Empty, error) {
+func (*UnimplementedProtocolServer) PrepareDKGGroup(ctx context.Context, req *PrepareDKGPacket) (*PrepareDKGResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PrepareDKGGroup not implemented")
 }
 func (*UnimplementedProtocolServer) PushDKGGroup(ctx context.Context, req *PushGroupPacket) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PushDKGGroup not implemented")
 }
+func (*UnimplementedProtocolServer) ConfirmGroup(ctx context.Context, req *GroupHashPacket) (*GroupHashPacket, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmGroup not implemented")
+}
+func (*UnimplementedProtocolServer) AbortSetup(ctx context.Context, req *AbortSetupPacket) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AbortSetup not implemented")
+}
+func (*UnimplementedProtocolServer) NewGroupSilent(ctx context.Context, req *NewGroupSilentPacket) (*NewGroupSilentPacket, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NewGroupSilent not implemented")
+}
 func (*UnimplementedProtocolServer) FreshDKG(ctx context.Context, req *DKGPacket) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FreshDKG not implemented")
 }
@@ -649,6 +1109,60 @@ func _Protocol_PushDKGGroup_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Protocol_ConfirmGroup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GroupHashPacket)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProtocolServer).ConfirmGroup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/drand.Protocol/ConfirmGroup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProtocolServer).ConfirmGroup(ctx, req.(*GroupHashPacket))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Protocol_AbortSetup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AbortSetupPacket)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProtocolServer).AbortSetup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/drand.Protocol/AbortSetup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProtocolServer).AbortSetup(ctx, req.(*AbortSetupPacket))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Protocol_NewGroupSilent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewGroupSilentPacket)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProtocolServer).NewGroupSilent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/drand.Protocol/NewGroupSilent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProtocolServer).NewGroupSilent(ctx, req.(*NewGroupSilentPacket))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Protocol_FreshDKG_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DKGPacket)
 	if err := dec(in); err != nil {
@@ -736,6 +1250,18 @@ var _Protocol_serviceDesc = grpc.ServiceDesc{
 			MethodName: "PushDKGGroup",
 			Handler:    _Protocol_PushDKGGroup_Handler,
 		},
+		{
+			MethodName: "ConfirmGroup",
+			Handler:    _Protocol_ConfirmGroup_Handler,
+		},
+		{
+			MethodName: "AbortSetup",
+			Handler:    _Protocol_AbortSetup_Handler,
+		},
+		{
+			MethodName: "NewGroupSilent",
+			Handler:    _Protocol_NewGroupSilent_Handler,
+		},
 		{
 			MethodName: "FreshDKG",
 			Handler:    _Protocol_FreshDKG_Handler,
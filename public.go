@@ -5,6 +5,7 @@ import (
 	gonet "net"
 
 	"github.com/drand/drand/core"
+	"github.com/drand/drand/key"
 	"github.com/drand/drand/net"
 	"github.com/drand/drand/protobuf/drand"
 	"github.com/nikkolasg/slog"
@@ -113,3 +114,51 @@ func getCokeyCmd(c *cli.Context) error {
 	printJSON(dkey)
 	return nil
 }
+
+func getGroupHistoryCmd(c *cli.Context) error {
+	var client = core.NewGrpcClient()
+	if c.IsSet(tlsCertFlag.Name) {
+		defaultManager := net.NewCertManager()
+		certPath := c.String(tlsCertFlag.Name)
+		defaultManager.Add(certPath)
+		client = core.NewGrpcClientFromCert(defaultManager)
+	}
+	var history *drand.GroupHistoryResponse
+	for _, addr := range c.Args().Slice() {
+		_, _, err := gonet.SplitHostPort(addr)
+		if err != nil {
+			fatal("invalid address given: %s", err)
+		}
+		history, err = client.GroupHistory(addr, !c.Bool("tls-disable"))
+		if err == nil {
+			break
+		}
+		slog.Printf("drand: error fetching group history from %s : %s",
+			addr, err)
+	}
+	if history == nil {
+		slog.Fatalf("drand: can't retrieve group history from all nodes")
+	}
+	if !c.IsSet(trustedFlag.Name) {
+		printJSON(history)
+		return nil
+	}
+	trusted := new(key.Group)
+	if err := key.Load(c.String(trustedFlag.Name), trusted); err != nil {
+		fatal("drand: can't load trusted group: %v", err)
+	}
+	groups := make([]*key.Group, 0, len(history.GetGroups()))
+	for _, h := range history.GetGroups() {
+		g, err := core.ProtoToGroup(h.GetGroup())
+		if err != nil {
+			fatal("drand: invalid group in history: %v", err)
+		}
+		groups = append(groups, g)
+	}
+	latest, err := key.LatestTrustedGroup(trusted, groups)
+	if err != nil {
+		fatal("drand: can't trust the latest group: %v", err)
+	}
+	groupOut(c, latest)
+	return nil
+}
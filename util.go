@@ -0,0 +1,334 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/drand/drand/core"
+	"github.com/drand/drand/fs"
+	"github.com/drand/drand/key"
+	"github.com/urfave/cli/v2"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+func verifyDKGCmd(c *cli.Context) error {
+	if !c.IsSet(transcriptFlag.Name) {
+		fatal("drand: verify-dkg needs the transcript of the DKG with --%s", transcriptFlag.Name)
+	}
+	group := getGroup(c)
+	if group.PublicKey == nil {
+		fatal("drand: group file has no distributed public key")
+	}
+	transcript := new(key.Transcript)
+	if err := key.Load(c.String(transcriptFlag.Name), transcript); err != nil {
+		fatal("drand: can't load transcript: %v", err)
+	}
+	// the group file only contains the nodes that got a share, which all
+	// must have taken part in the protocol
+	for _, node := range group.Nodes {
+		var found bool
+		for _, v := range transcript.Verifiers {
+			if v.Equal(node.Key) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fatal("drand: node %s of the group is absent from the transcript", node.Address())
+		}
+	}
+	if err := transcript.Verify(group.PublicKey); err != nil {
+		fatal("drand: invalid DKG transcript: %v", err)
+	}
+	fmt.Printf("drand: transcript valid - distributed key is the combination of %d qualified dealers\n", len(transcript.QUAL))
+	return nil
+}
+
+func encryptKeysCmd(c *cli.Context) error {
+	conf := contextToConfig(c)
+	passphrase, err := passphraseFromFlags(c)
+	if err != nil {
+		fatal("drand: %v", err)
+	}
+	if passphrase == nil {
+		passphrase = promptPassphrase(true)
+	}
+	folder := conf.BeaconFolder(beaconID(c))
+	if _, err := key.EncryptFileStore(folder, passphrase); err != nil {
+		fatal("drand: can't encrypt the keys: %v", err)
+	}
+	fmt.Printf("drand: private key and share in %s are encrypted\n", folder)
+	return nil
+}
+
+func rotateKeyCmd(c *cli.Context) error {
+	conf := contextToConfig(c)
+	fs := openStore(c, conf.BeaconFolder(beaconID(c)))
+	priv, err := fs.LoadKeyPair()
+	if err != nil {
+		fatal("drand: can't load the key pair: %v", err)
+	}
+	if _, err := fs.LoadNextKeyPair(); err == nil {
+		fatal("drand: a new key pair is already waiting for the next resharing")
+	} else if err != key.ErrAbsent {
+		fatal("drand: can't load the new key pair: %v", err)
+	}
+	var next *key.Pair
+	if priv.Public.IsTLS() {
+		next = key.NewTLSKeyPair(priv.Public.Address())
+	} else {
+		next = key.NewKeyPair(priv.Public.Address())
+	}
+	if priv.Public.Operator != nil {
+		if err := next.SignOperator(priv.Public.Operator); err != nil {
+			fatal("drand: can't sign the operator metadata: %v", err)
+		}
+	}
+	if err := fs.SaveNextKeyPair(next); err != nil {
+		fatal("drand: can't save the new key pair: %v", err)
+	}
+	fmt.Println("drand: the new key pair is used in the group of the next resharing, the current one until the transition")
+	var buff bytes.Buffer
+	buff.WriteString("[[Nodes]]\n")
+	if err := toml.NewEncoder(&buff).Encode(next.Public.TOML()); err != nil {
+		panic(err)
+	}
+	fmt.Println(buff.String())
+	return nil
+}
+
+func setOperatorCmd(c *cli.Context) error {
+	op := operatorFromFlags(c)
+	if op == nil {
+		fatal("drand: set-operator needs at least one of the operator flags")
+	}
+	conf := contextToConfig(c)
+	fs := openStore(c, conf.BeaconFolder(beaconID(c)))
+	priv, err := fs.LoadKeyPair()
+	if err != nil {
+		fatal("drand: can't load the key pair: %v", err)
+	}
+	if err := priv.SignOperator(op); err != nil {
+		fatal("drand: can't sign the operator metadata: %v", err)
+	}
+	if err := fs.SaveKeyPair(priv); err != nil {
+		fatal("drand: can't save the key pair: %v", err)
+	}
+	// a new key waiting for the next resharing carries the same metadata
+	next, err := fs.LoadNextKeyPair()
+	switch {
+	case err == nil:
+		if err := next.SignOperator(op); err != nil {
+			fatal("drand: can't sign the operator metadata: %v", err)
+		}
+		if err := fs.SaveNextKeyPair(next); err != nil {
+			fatal("drand: can't save the new key pair: %v", err)
+		}
+	case err != key.ErrAbsent:
+		fatal("drand: can't load the new key pair: %v", err)
+	}
+	fmt.Printf("drand: operator of the node set to %s\n", priv.Public.Operator)
+	return nil
+}
+
+// operatorFromFlags returns the operator metadata given by the flags, or nil
+// if none is set.
+func operatorFromFlags(c *cli.Context) *key.Operator {
+	if !c.IsSet(operatorNameFlag.Name) && !c.IsSet(operatorContactFlag.Name) &&
+		!c.IsSet(operatorRegionFlag.Name) && !c.IsSet(operatorWebsiteFlag.Name) {
+		return nil
+	}
+	return &key.Operator{
+		Name:    c.String(operatorNameFlag.Name),
+		Contact: c.String(operatorContactFlag.Name),
+		Region:  c.String(operatorRegionFlag.Name),
+		Website: c.String(operatorWebsiteFlag.Name),
+	}
+}
+
+// openStore returns the store of the node. If its private key is encrypted,
+// the passphrase is read from the source given by the flags, or asked on the
+// terminal.
+func openStore(c *cli.Context, folder string) key.Store {
+	passphrase, err := passphraseFromFlags(c)
+	if err != nil {
+		fatal("drand: %v", err)
+	}
+	if passphrase == nil {
+		store := key.NewFileStore(folder)
+		if _, err := store.LoadKeyPair(); err != key.ErrEncrypted {
+			return store
+		}
+		passphrase = promptPassphrase(false)
+	}
+	store, err := key.NewEncryptedFileStore(folder, passphrase)
+	if err != nil {
+		fatal("drand: %v", err)
+	}
+	if _, err := store.LoadKeyPair(); err == key.ErrPassphrase {
+		fatal("drand: invalid passphrase")
+	} else if err == nil {
+		if encrypted, _ := key.IsEncrypted(key.PrivateKeyFile(folder)); !encrypted {
+			fmt.Println("drand: the private key is not encrypted yet, use `drand util encrypt-keys`")
+		}
+	}
+	return store
+}
+
+// passphraseFromFlags returns the passphrase from the environment variable or
+// the file descriptor given by the flags, or nil if none is set.
+func passphraseFromFlags(c *cli.Context) ([]byte, error) {
+	switch {
+	case c.IsSet(passphraseEnvFlag.Name):
+		name := c.String(passphraseEnvFlag.Name)
+		passphrase := os.Getenv(name)
+		if passphrase == "" {
+			return nil, fmt.Errorf("no passphrase in environment variable %s", name)
+		}
+		return []byte(passphrase), nil
+	case c.IsSet(passphraseFdFlag.Name):
+		fd := os.NewFile(uintptr(c.Int(passphraseFdFlag.Name)), "passphrase")
+		if fd == nil {
+			return nil, errors.New("invalid passphrase file descriptor")
+		}
+		defer fd.Close()
+		line, err := bufio.NewReader(fd).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("can't read passphrase: %v", err)
+		}
+		passphrase := strings.TrimRight(line, "\r\n")
+		if passphrase == "" {
+			return nil, errors.New("empty passphrase")
+		}
+		return []byte(passphrase), nil
+	}
+	return nil, nil
+}
+
+// promptPassphrase asks the passphrase on the terminal, twice if confirm is
+// true.
+func promptPassphrase(confirm bool) []byte {
+	fmt.Print("Passphrase of the private key and share: ")
+	passphrase, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Println()
+	if err != nil {
+		fatal("drand: can't read passphrase: %v", err)
+	}
+	if len(passphrase) == 0 {
+		fatal("drand: empty passphrase")
+	}
+	if confirm {
+		fmt.Print("Confirm the passphrase: ")
+		again, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Println()
+		if err != nil {
+			fatal("drand: can't read passphrase: %v", err)
+		}
+		if !bytes.Equal(passphrase, again) {
+			fatal("drand: passphrases don't match")
+		}
+	}
+	return passphrase
+}
+
+// backupCmd saves the backup streamed by the daemon in the output file,
+// encrypted with a passphrase if asked.
+func backupCmd(c *cli.Context) error {
+	if !c.IsSet(backupOutFlag.Name) {
+		fatal("drand: backup needs the output file with --%s", backupOutFlag.Name)
+	}
+	out := c.String(backupOutFlag.Name)
+	tmp := out + ".tmp"
+	fd, err := fs.CreateSecureFile(tmp)
+	if err != nil {
+		fatal("drand: can't create backup file: %v", err)
+	}
+	defer os.Remove(tmp)
+	var w io.WriteCloser = fd
+	if c.Bool(encryptFlag.Name) {
+		passphrase, err := passphraseFromFlags(c)
+		if err != nil {
+			fatal("drand: %v", err)
+		}
+		if passphrase == nil {
+			passphrase = promptPassphrase(true)
+		}
+		if w, err = key.NewEncryptedWriter(fd, passphrase); err != nil {
+			fatal("drand: %v", err)
+		}
+	}
+	if err := controlClient(c).Backup(w); err != nil {
+		fatal("drand: can't backup the node: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		fatal("drand: can't write backup file: %v", err)
+	}
+	if err := fd.Close(); err != nil {
+		fatal("drand: can't write backup file: %v", err)
+	}
+	if err := os.Rename(tmp, out); err != nil {
+		fatal("drand: can't write backup file: %v", err)
+	}
+	fmt.Printf("drand: backup saved in %s\n", out)
+	return nil
+}
+
+// restoreCmd installs the backup given as argument in the configuration
+// folder, once the daemon is stopped.
+func restoreCmd(c *cli.Context) error {
+	if c.Args().Len() != 1 {
+		fatal("drand: restore needs the backup file as argument")
+	}
+	if err := controlClient(c).Ping(); err == nil {
+		fatal("drand: the daemon is running, stop it before restoring a backup")
+	}
+	fd, err := os.Open(c.Args().First())
+	if err != nil {
+		fatal("drand: can't open backup: %v", err)
+	}
+	defer fd.Close()
+	r := bufio.NewReader(fd)
+	var archive io.Reader = r
+	if key.IsEncryptedStream(r) {
+		passphrase, err := passphraseFromFlags(c)
+		if err != nil {
+			fatal("drand: %v", err)
+		}
+		if passphrase == nil {
+			passphrase = promptPassphrase(false)
+		}
+		if archive, err = key.NewDecryptedReader(r, passphrase); err != nil {
+			fatal("drand: can't read backup: %v", err)
+		}
+	}
+	conf := contextToConfig(c)
+	if err := core.RestoreBackup(conf, beaconID(c), archive); err != nil {
+		fatal("drand: can't restore backup: %v", err)
+	}
+	fmt.Println("drand: backup restored, the daemon can be started")
+	return nil
+}
+
+// rollbackCmd deletes the beacons saved after the given round, once the daemon
+// is stopped.
+func rollbackCmd(c *cli.Context) error {
+	if !c.IsSet(rollbackToFlag.Name) {
+		fatal("drand: rollback needs the last round to keep with --%s", rollbackToFlag.Name)
+	}
+	if err := controlClient(c).Ping(); err == nil {
+		fatal("drand: the daemon is running, stop it before rolling back its chain")
+	}
+	round := c.Uint64(rollbackToFlag.Name)
+	deleted, err := core.RollbackChain(contextToConfig(c), beaconID(c), round)
+	if err != nil {
+		fatal("drand: can't rollback the chain: %v", err)
+	}
+	fmt.Printf("drand: deleted %d beacons after round %d\n", deleted, round)
+	return nil
+}